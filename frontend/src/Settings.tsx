@@ -77,11 +77,13 @@ export default function Settings({ onClose, askBeforeApply, onAskBeforeApplyChan
                             maxRowsPreview={settings.maxRowsPreview}
                             includeHeaders={settings.includeHeaders}
                             askBeforeApply={settings.askBeforeApply}
+                            stampProvenance={settings.stampProvenance}
                             onMaxRowsContextChange={settings.setMaxRowsContext}
                             onMaxContextCharsChange={settings.setMaxContextChars}
                             onMaxRowsPreviewChange={settings.setMaxRowsPreview}
                             onIncludeHeadersChange={settings.setIncludeHeaders}
                             onAskBeforeApplyChange={settings.onAskBeforeApplyChange}
+                            onStampProvenanceChange={settings.onStampProvenanceChange}
                         />
                     </TabsContent>
 
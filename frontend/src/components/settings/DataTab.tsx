@@ -10,11 +10,13 @@ interface DataTabProps {
     maxRowsPreview: number
     includeHeaders: boolean
     askBeforeApply: boolean
+    stampProvenance: boolean
     onMaxRowsContextChange: (value: number) => void
     onMaxContextCharsChange: (value: number) => void
     onMaxRowsPreviewChange: (value: number) => void
     onIncludeHeadersChange: (value: boolean) => void
     onAskBeforeApplyChange: (value: boolean) => void
+    onStampProvenanceChange: (value: boolean) => void
 }
 
 export function DataTab({
@@ -23,11 +25,13 @@ export function DataTab({
     maxRowsPreview,
     includeHeaders,
     askBeforeApply,
+    stampProvenance,
     onMaxRowsContextChange,
     onMaxContextCharsChange,
     onMaxRowsPreviewChange,
     onIncludeHeadersChange,
-    onAskBeforeApplyChange
+    onAskBeforeApplyChange,
+    onStampProvenanceChange
 }: DataTabProps) {
     return (
         <Card className="bg-card/60">
@@ -109,6 +113,14 @@ export function DataTab({
                     </div>
                     <Switch checked={askBeforeApply} onCheckedChange={onAskBeforeApplyChange} />
                 </div>
+
+                <div className="flex items-center justify-between p-4 bg-muted/30 border border-border rounded-lg">
+                    <div className="space-y-1">
+                        <Label>Registrar origem ao salvar</Label>
+                        <p className="text-xs text-muted-foreground">Grava a conversa, o modelo e a data nas propriedades do arquivo</p>
+                    </div>
+                    <Switch checked={stampProvenance} onCheckedChange={onStampProvenanceChange} />
+                </div>
             </CardContent>
         </Card>
     )
@@ -9,7 +9,8 @@ import {
     SetToolModel,
     GetSavedConfig,
     UpdateConfig,
-    GetAvailableModels
+    GetAvailableModels,
+    SetStampProvenance
 } from "../../wailsjs/go/app/App"
 
 // Z.AI Models
@@ -40,6 +41,7 @@ export function useSettings({ askBeforeApply, onAskBeforeApplyChange }: UseSetti
     const [maxContextChars, setMaxContextChars] = useState(6000)
     const [maxRowsPreview, setMaxRowsPreview] = useState(100)
     const [includeHeaders, setIncludeHeaders] = useState(true)
+    const [stampProvenance, setStampProvenance] = useState(true)
 
     // UI state
     const [isSaving, setIsSaving] = useState(false)
@@ -82,6 +84,7 @@ export function useSettings({ askBeforeApply, onAskBeforeApplyChange }: UseSetti
                 if (cfg.maxContextChars) setMaxContextChars(cfg.maxContextChars)
                 if (cfg.maxRowsPreview) setMaxRowsPreview(cfg.maxRowsPreview)
                 setIncludeHeaders(cfg.includeHeaders !== false)
+                setStampProvenance(cfg.stampProvenance === true)
             }
         } catch (err) {
             toast.error('Erro ao carregar configurações')
@@ -165,6 +168,17 @@ export function useSettings({ askBeforeApply, onAskBeforeApplyChange }: UseSetti
         }
     }, [apiKey, customModel, model, useCustomModel, toolModel, maxRowsContext, maxContextChars, maxRowsPreview, includeHeaders, baseUrl, loadModels])
 
+    const onStampProvenanceChange = useCallback(async (value: boolean) => {
+        setStampProvenance(value)
+        if (typeof (window as any)?.go === 'undefined') return
+        try {
+            await SetStampProvenance(value)
+        } catch (err) {
+            setStampProvenance(!value)
+            toast.error('Erro ao salvar configuração')
+        }
+    }, [])
+
     const handleProviderChange = useCallback(() => {
         // Não usado mais - Z.AI é o único provider
         toast.info('Z.AI é o único provider suportado')
@@ -197,6 +211,8 @@ export function useSettings({ askBeforeApply, onAskBeforeApplyChange }: UseSetti
         setIncludeHeaders,
         askBeforeApply,
         onAskBeforeApplyChange,
+        stampProvenance,
+        onStampProvenanceChange,
 
         // UI state
         isSaving,
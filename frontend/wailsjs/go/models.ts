@@ -318,6 +318,7 @@ export namespace storage {
 	    maxRowsPreview: number;
 	    includeHeaders: boolean;
 	    askBeforeApply: boolean;
+	    stampProvenance: boolean;
 	    detailLevel: string;
 	    customPrompt: string;
 	    language: string;
@@ -340,6 +341,7 @@ export namespace storage {
 	        this.maxRowsPreview = source["maxRowsPreview"];
 	        this.includeHeaders = source["includeHeaders"];
 	        this.askBeforeApply = source["askBeforeApply"];
+	        this.stampProvenance = source["stampProvenance"];
 	        this.detailLevel = source["detailLevel"];
 	        this.customPrompt = source["customPrompt"];
 	        this.language = source["language"];
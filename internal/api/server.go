@@ -0,0 +1,415 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"excel-ai/internal/app"
+	"excel-ai/internal/dto"
+	apperrors "excel-ai/pkg/errors"
+	"excel-ai/pkg/logger"
+)
+
+// Tamanho máximo de upload de planilha (100MB)
+const maxUploadSize = 100 << 20
+
+// Server API REST/JSON local para as operações Excel.
+// Cada requisição seleciona a sessão pelo id da URL; o mutex serializa a troca de
+// sessão e a operação, já que o serviço Excel mantém uma única sessão atual.
+type Server struct {
+	app   *app.App
+	token string
+	mux   *http.ServeMux
+
+	mu sync.Mutex
+}
+
+// NewServer cria o servidor. Toda requisição deve trazer "Authorization: Bearer <token>".
+func NewServer(a *app.App, token string) *Server {
+	s := &Server{app: a, token: token, mux: http.NewServeMux()}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/sessions", s.handleListSessions)
+	s.mux.HandleFunc("POST /api/sessions", s.handleOpenSession)
+	s.mux.HandleFunc("DELETE /api/sessions/{id}", s.handleCloseSession)
+	s.mux.HandleFunc("GET /api/sessions/{id}/download", s.handleDownload)
+	s.mux.HandleFunc("POST /api/sessions/{id}/save", s.handleSave)
+	s.mux.HandleFunc("GET /api/sessions/{id}/preview", s.handlePreview)
+	s.mux.HandleFunc("GET /api/sessions/{id}/sheets/{sheet}/data", s.handleSheetData)
+	s.mux.HandleFunc("POST /api/sessions/{id}/sheets", s.handleCreateSheet)
+	s.mux.HandleFunc("DELETE /api/sessions/{id}/sheets/{sheet}", s.handleDeleteSheet)
+	s.mux.HandleFunc("POST /api/sessions/{id}/query", s.handleQuery)
+	s.mux.HandleFunc("PUT /api/sessions/{id}/cells", s.handleUpdateCell)
+	s.mux.HandleFunc("POST /api/sessions/{id}/format", s.handleFormat)
+	s.mux.HandleFunc("POST /api/sessions/{id}/charts", s.handleCreateChart)
+	s.mux.HandleFunc("POST /api/sessions/{id}/tools/{name}", s.handleTool)
+}
+
+// ServeHTTP aplica as verificações de origem, autenticação e log antes de despachar a rota.
+// Host e Origin precisam ser locais para barrar DNS rebinding e páginas de outros sites.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isLocalHost(r.Host) {
+		writeError(w, apperrors.Forbidden("host não permitido: "+r.Host))
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !isLocalHost(u.Host) {
+			writeError(w, apperrors.Forbidden("origem não permitida: "+origin))
+			return
+		}
+	}
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+s.token)) != 1 {
+		writeError(w, apperrors.Unauthorized("token inválido ou ausente"))
+		return
+	}
+	logger.AppDebug(fmt.Sprintf("[API] %s %s", r.Method, r.URL.Path))
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe escuta em addr (somente loopback) até ctx ser cancelado
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	if err := checkLoopback(addr); err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
+
+// checkLoopback recusa endereços fora da máquina local
+func checkLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return apperrors.InvalidInput("endereço inválido: " + addr)
+	}
+	if !isLoopback(host) {
+		return apperrors.InvalidInput("o servidor só pode escutar em localhost (recebido: " + addr + ")")
+	}
+	return nil
+}
+
+// isLocalHost verifica o Host (com ou sem porta) de uma requisição
+func isLocalHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = strings.Trim(hostport, "[]")
+	}
+	return isLoopback(host)
+}
+
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ========== Sessões ==========
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.app.GetCurrentSessionID()
+	defer s.restore(current)
+
+	sessions := make([]dto.SessionInfo, 0)
+	for _, id := range s.app.ListSessions() {
+		sessions = append(sessions, s.sessionInfo(id))
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+// handleOpenSession aceita JSON {"path": "..."} ou o arquivo bruto com ?filename=
+func (s *Server) handleOpenSession(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sessionID string
+	var err error
+	if isJSON(r) {
+		var req dto.OpenSessionRequest
+		if !decodeJSON(w, r, &req) {
+			return
+		}
+		sessionID, err = s.app.LoadExcelFromPath(req.Path)
+	} else {
+		data, readErr := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+		if readErr != nil {
+			writeError(w, apperrors.InvalidInput("falha ao ler o arquivo enviado: "+readErr.Error()))
+			return
+		}
+		filename := r.URL.Query().Get("filename")
+		if filename == "" {
+			filename = "upload.xlsx"
+		}
+		sessionID, err = s.app.UploadExcel(filename, data)
+		if err != nil {
+			err = apperrors.Wrap(err, apperrors.ErrCodeInvalidInput, "arquivo inválido")
+		}
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.sessionInfo(sessionID))
+}
+
+func (s *Server) handleCloseSession(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, func(id string) (interface{}, error) {
+		return nil, s.app.CloseSession(id)
+	})
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	data, err := s.app.DownloadExcel(id)
+	if err != nil {
+		writeError(w, asAppError(err, apperrors.ErrCodeInternal))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.sessionInfo(id).FileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (s *Server) handleSave(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, func(string) (interface{}, error) {
+		if err := s.app.SaveFileNative(); err != nil {
+			return nil, asAppError(err, apperrors.ErrCodeInternal)
+		}
+		return nil, nil
+	})
+}
+
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, func(id string) (interface{}, error) {
+		preview, err := s.app.GetExcelPreview(id)
+		if err != nil {
+			return nil, err
+		}
+		preview.FileName = s.sessionInfo(id).FileName
+		return preview, nil
+	})
+}
+
+// ========== Planilhas e operações ==========
+
+func (s *Server) handleSheetData(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, func(id string) (interface{}, error) {
+		return s.app.GetSheetData(id, r.PathValue("sheet"))
+	})
+}
+
+func (s *Server) handleCreateSheet(w http.ResponseWriter, r *http.Request) {
+	var req dto.SheetRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.withSession(w, r, func(string) (interface{}, error) {
+		return nil, s.app.CreateNewSheet(req.Name)
+	})
+}
+
+func (s *Server) handleDeleteSheet(w http.ResponseWriter, r *http.Request) {
+	s.withSession(w, r, func(string) (interface{}, error) {
+		return nil, s.app.DeleteSheet(r.PathValue("sheet"))
+	})
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req dto.QueryRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.withSession(w, r, func(string) (interface{}, error) {
+		result := s.app.QueryExcel(req.Type, req.Params)
+		if !result.Success {
+			return nil, apperrors.InvalidInput(result.Error)
+		}
+		return result, nil
+	})
+}
+
+func (s *Server) handleUpdateCell(w http.ResponseWriter, r *http.Request) {
+	var req dto.CellUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.withSession(w, r, func(string) (interface{}, error) {
+		return nil, s.app.UpdateExcelCell(req.Workbook, req.Sheet, req.Cell, req.Value)
+	})
+}
+
+func (s *Server) handleFormat(w http.ResponseWriter, r *http.Request) {
+	var req dto.FormatRangeRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.withSession(w, r, func(string) (interface{}, error) {
+		return nil, s.app.FormatRange(req.Sheet, req.Range, req.Bold, req.Italic, req.FontSize, req.FontColor, req.BgColor)
+	})
+}
+
+func (s *Server) handleCreateChart(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChartRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	s.withSession(w, r, func(string) (interface{}, error) {
+		return nil, s.app.CreateChart(req.Sheet, req.Range, req.ChartType, req.Title)
+	})
+}
+
+// handleTool executa qualquer ferramenta do catálogo (consultas e operações de macro)
+func (s *Server) handleTool(w http.ResponseWriter, r *http.Request) {
+	args := map[string]interface{}{}
+	if r.ContentLength != 0 && !decodeJSON(w, r, &args) {
+		return
+	}
+	name := r.PathValue("name")
+	s.withSession(w, r, func(string) (interface{}, error) {
+		result, err := s.app.ExecuteExcelTool(name, args)
+		if err != nil {
+			return nil, err
+		}
+		return dto.ToolResult{Tool: name, Result: result}, nil
+	})
+}
+
+// ========== Helpers ==========
+
+// withSession seleciona a sessão da URL, executa fn e escreve a resposta.
+// Sem corpo de resposta, devolve 204.
+func (s *Server) withSession(w http.ResponseWriter, r *http.Request, fn func(id string) (interface{}, error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if err := s.app.UseSession(id); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	result, err := fn(id)
+	if err != nil {
+		writeError(w, asAppError(err, apperrors.ErrCodeInvalidInput))
+		return
+	}
+	if result == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// sessionInfo monta o resumo de uma sessão (altera a sessão atual; chamar com s.mu)
+func (s *Server) sessionInfo(id string) dto.SessionInfo {
+	info := dto.SessionInfo{SessionID: id}
+	if err := s.app.UseSession(id); err != nil {
+		return info
+	}
+	if status, err := s.app.RefreshWorkbooks(); err == nil && len(status.Workbooks) > 0 {
+		info.FileName = status.Workbooks[0].Name
+		info.Sheets = status.Workbooks[0].Sheets
+	}
+	return info
+}
+
+// restore volta para a sessão atual anterior, se ainda existir
+func (s *Server) restore(sessionID string) {
+	if sessionID != "" {
+		s.app.UseSession(sessionID)
+	}
+}
+
+// asAppError preserva AppErrors e classifica os demais com o código informado
+func asAppError(err error, code apperrors.ErrorCode) error {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+	return apperrors.Wrap(err, code, err.Error())
+}
+
+// decodeJSON exige Content-Type application/json, o que também impede envios de
+// formulários HTML (text/plain, form-urlencoded) de outras origens
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if !isJSON(r) {
+		writeError(w, &apperrors.AppError{
+			Code:       apperrors.ErrCodeInvalidInput,
+			Message:    "Content-Type deve ser application/json",
+			StatusCode: http.StatusUnsupportedMediaType,
+		})
+		return false
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	if err := dec.Decode(v); err != nil {
+		writeError(w, apperrors.InvalidInput("JSON inválido: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+func isJSON(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.AppError("[API] Erro ao serializar resposta: " + err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	body := dto.APIError{Code: string(apperrors.ErrCodeInternal), Message: err.Error()}
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		body.Code = string(appErr.Code)
+		body.Message = appErr.Message
+		if appErr.Cause != nil && appErr.Cause.Error() != appErr.Message {
+			body.Message += ": " + appErr.Cause.Error()
+		}
+	}
+	writeJSON(w, apperrors.HTTPStatus(err), body)
+}
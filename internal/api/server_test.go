@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeHTTPChecks(t *testing.T) {
+	// As verificações acontecem antes de qualquer acesso ao App
+	server := NewServer(nil, "segredo")
+
+	tests := []struct {
+		name        string
+		host        string
+		origin      string
+		auth        string
+		contentType string
+		wantStatus  int
+	}{
+		{name: "sem token", contentType: "application/json", wantStatus: http.StatusUnauthorized},
+		{name: "token errado", auth: "Bearer outro", contentType: "application/json", wantStatus: http.StatusUnauthorized},
+		{name: "token sem Bearer", auth: "segredo", contentType: "application/json", wantStatus: http.StatusUnauthorized},
+		{name: "host externo (DNS rebinding)", host: "evil.com:8080", auth: "Bearer segredo", contentType: "application/json", wantStatus: http.StatusForbidden},
+		{name: "origem externa", origin: "http://evil.com", auth: "Bearer segredo", contentType: "application/json", wantStatus: http.StatusForbidden},
+		{name: "Content-Type texto", auth: "Bearer segredo", contentType: "text/plain", wantStatus: http.StatusUnsupportedMediaType},
+		// JSON malformado só é rejeitado pelo handler, depois de passar pelas verificações
+		{name: "requisição local autenticada", origin: "http://localhost:5173", auth: "Bearer segredo", contentType: "application/json; charset=utf-8", wantStatus: http.StatusBadRequest},
+		{name: "host IPv6 local", host: "[::1]:8080", auth: "Bearer segredo", contentType: "application/json", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/sessions/s1/sheets", strings.NewReader("{"))
+			req.Host = "127.0.0.1:8080"
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			req.Header.Set("Content-Type", tt.contentType)
+
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, esperado %d (corpo: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServeHTTPWithoutToken(t *testing.T) {
+	// Servidor sem token configurado recusa tudo, inclusive "Bearer " vazio
+	server := NewServer(nil, "")
+	req := httptest.NewRequest(http.MethodGet, "/api/sessions", nil)
+	req.Host = "localhost:8080"
+	req.Header.Set("Authorization", "Bearer ")
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, esperado %d", rec.Code, http.StatusUnauthorized)
+	}
+}
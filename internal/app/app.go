@@ -34,6 +34,7 @@ func NewApp() *App {
 	// Carregar configurações salvas
 	if stor != nil {
 		if cfg, err := stor.LoadConfig(); err == nil && cfg != nil {
+			chatSvc.SetProvider(cfg.Provider)
 			if cfg.APIKey != "" {
 				chatSvc.SetAPIKey(cfg.APIKey)
 				logger.AppInfo("API key configurada pelo usuário")
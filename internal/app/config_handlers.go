@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"excel-ai/internal/dto"
+	"excel-ai/pkg/ai"
 	apperrors "excel-ai/pkg/errors"
 	"excel-ai/pkg/logger"
 	"excel-ai/pkg/storage"
@@ -29,7 +30,9 @@ func (a *App) SetAPIKey(apiKey string) error {
 			cfg = &storage.Config{}
 		}
 		cfg.APIKey = apiKey
-		cfg.Provider = "zai" // Sempre Z.ai
+		if cfg.Provider == "" {
+			cfg.Provider = ai.ProviderZAI
+		}
 
 		if err := a.storage.SaveConfig(cfg); err != nil {
 			logger.AppError("Erro ao salvar API key: " + err.Error())
@@ -153,6 +156,13 @@ func (a *App) UpdateConfig(maxRowsContext, maxContextChars, maxRowsPreview int,
 	if language != "" {
 		v.ValidateEnum("language", language, []string{"en", "pt", "es"}, false)
 	}
+
+	if provider != "" {
+		v.ValidateEnum("provider", provider, []string{
+			ai.ProviderZAI, ai.ProviderOpenAI, ai.ProviderOpenRouter, ai.ProviderGroq,
+			ai.ProviderGoogle, ai.ProviderAnthropic, ai.ProviderLocal, ai.ProviderCustom,
+		}, false)
+	}
 	
 	if v.HasErrors() {
 		logger.AppWarn("Validação de configuração falhou: " + v.Error().Error())
@@ -172,22 +182,21 @@ func (a *App) UpdateConfig(maxRowsContext, maxContextChars, maxRowsPreview int,
 	cfg.DetailLevel = detailLevel
 	cfg.CustomPrompt = customPrompt
 	cfg.Language = language
-	cfg.Provider = "zai" // Sempre Z.ai
+	if provider != "" {
+		cfg.Provider = ai.NormalizeProviderName(provider)
+	} else if cfg.Provider == "" {
+		cfg.Provider = ai.ProviderZAI
+	}
 	cfg.BaseURL = baseUrl
 	cfg.ToolModel = toolModel
 
-	// Atualizar serviço
-	if baseUrl != "" {
-		a.chatService.SetBaseURL(baseUrl)
-	} else {
-		// Usar Coding API por padrão
-		a.chatService.SetBaseURL("https://api.z.ai/api/coding/paas/v4/")
-	}
-
 	if err := a.storage.SaveConfig(cfg); err != nil {
 		logger.AppError("Erro ao salvar configuração: " + err.Error())
 		return apperrors.Wrap(err, apperrors.ErrCodeStorageError, "erro ao salvar configuração")
 	}
+
+	// Atualizar serviço com o provedor salvo
+	a.chatService.RefreshConfig()
 	
 	logger.AppInfo("Configuração atualizada com sucesso")
 	return nil
@@ -226,3 +235,36 @@ func (a *App) GetAskBeforeApply() (bool, error) {
 	}
 	return cfg.AskBeforeApply, nil
 }
+
+// SetStampProvenance habilita o registro da origem (conversa, modelo e data) ao salvar
+func (a *App) SetStampProvenance(value bool) error {
+	if a.storage == nil {
+		return apperrors.StorageError("storage não disponível")
+	}
+
+	cfg, _ := a.storage.LoadConfig()
+	if cfg == nil {
+		cfg = &storage.Config{}
+	}
+	cfg.StampProvenance = value
+
+	if err := a.storage.SaveConfig(cfg); err != nil {
+		logger.AppError("Erro ao salvar StampProvenance: " + err.Error())
+		return apperrors.Wrap(err, apperrors.ErrCodeStorageError, "erro ao salvar configuração")
+	}
+
+	logger.AppInfo("StampProvenance configurado: " + fmt.Sprintf("%v", value))
+	return nil
+}
+
+// GetStampProvenance indica se a origem é registrada nas propriedades ao salvar
+func (a *App) GetStampProvenance() (bool, error) {
+	if a.storage == nil {
+		return false, nil
+	}
+	cfg, err := a.storage.LoadConfig()
+	if err != nil {
+		return false, nil
+	}
+	return cfg.StampProvenance, nil
+}
@@ -365,7 +365,7 @@ func (a *App) ClearFilters(sheet string) error {
 	return nil
 }
 
-// SortRange ordena dados
+// SortRange ordena dados por uma coluna (1-based, relativa ao intervalo)
 func (a *App) SortRange(sheet, rangeAddr string, column int, ascending bool) error {
 	order := "crescente"
 	if !ascending {
@@ -373,7 +373,11 @@ func (a *App) SortRange(sheet, rangeAddr string, column int, ascending bool) err
 	}
 	logger.ExcelInfo(fmt.Sprintf("Ordenando range %s: col=%d, ordem=%s", rangeAddr, column, order))
 
-	err := a.excelService.SortRange(sheet, rangeAddr, column, ascending)
+	opts := excel.SortOptions{Keys: []excel.SortKey{{Column: column, Order: "asc"}}}
+	if !ascending {
+		opts.Keys[0].Order = "desc"
+	}
+	_, err := a.excelService.SortRange(sheet, rangeAddr, opts)
 	if err != nil {
 		logger.ExcelError("Erro ao ordenar dados: " + err.Error())
 		return apperrors.Wrap(err, apperrors.ErrCodeExcelNotFound, "falha ao ordenar dados")
@@ -384,7 +388,8 @@ func (a *App) SortRange(sheet, rangeAddr string, column int, ascending bool) err
 
 // CopyRange copia range
 func (a *App) CopyRange(sheet, sourceRange, destRange string) error {
-	return a.excelService.CopyRange(sheet, sourceRange, destRange)
+	_, err := a.excelService.CopyRange(sheet, sourceRange, destRange, excel.PasteOptions{})
+	return err
 }
 
 // ListCharts lista gráficos
@@ -471,11 +476,7 @@ func (a *App) GetUsedRange(sheetName string) (string, error) {
 }
 
 // QueryResult resultado de uma query
-type QueryResult struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data"`
-	Error   string      `json:"error,omitempty"`
-}
+type QueryResult = dto.QueryResult
 
 // QueryExcel executa uma query genérica no Excel
 func (a *App) QueryExcel(queryType string, params map[string]string) QueryResult {
@@ -551,7 +552,11 @@ func (a *App) QueryExcel(queryType string, params map[string]string) QueryResult
 		return QueryResult{Success: true, Data: cell}
 
 	case "get-range-values":
-		values, err := a.excelService.GetRangeValues(params["sheet"], params["range"])
+		getValues := a.excelService.GetRangeValues
+		if params["visible_only"] == "true" {
+			getValues = a.excelService.GetVisibleRangeValues
+		}
+		values, err := getValues(params["sheet"], params["range"])
 		if err != nil {
 			return QueryResult{Success: false, Error: err.Error()}
 		}
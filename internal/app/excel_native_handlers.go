@@ -1,6 +1,7 @@
 package app
 
 import (
+	excelService "excel-ai/internal/services/excel"
 	"excel-ai/pkg/logger"
 	"fmt"
 	"time"
@@ -56,7 +57,11 @@ func (a *App) OpenFileNative() (string, error) {
 func (a *App) SaveFileNative() error {
 	logger.AppInfo("Solicitando salvamento nativo no disco")
 
-	if err := a.excelService.SaveToDisk(); err != nil {
+	// Arquivos alterados em uma conversa registram a origem nas propriedades, se habilitado
+	convID := a.chatService.GetCurrentConversationID()
+	stamp, _ := a.GetStampProvenance()
+	opts := excelService.SaveOptions{Stamp: stamp && convID != "", ConversationID: convID, Model: a.chatService.GetModel()}
+	if err := a.excelService.SaveToDisk(opts); err != nil {
 		logger.AppError("Erro ao salvar no disco: " + err.Error())
 		return fmt.Errorf("erro ao salvar arquivo: %w", err)
 	}
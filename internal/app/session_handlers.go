@@ -0,0 +1,52 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	apperrors "excel-ai/pkg/errors"
+	"excel-ai/pkg/logger"
+)
+
+// ListSessions retorna os sessionIDs dos arquivos abertos
+func (a *App) ListSessions() []string {
+	return a.excelService.ListSessions()
+}
+
+// UseSession torna uma sessão aberta a sessão atual
+func (a *App) UseSession(sessionID string) error {
+	if err := a.excelService.UseSession(sessionID); err != nil {
+		return apperrors.NotFound("sessão não encontrada: " + sessionID)
+	}
+	return nil
+}
+
+// GetCurrentSessionID retorna a sessão atual
+func (a *App) GetCurrentSessionID() string {
+	return a.excelService.GetCurrentSessionID()
+}
+
+// LoadExcelFromPath carrega um arquivo do disco sem diálogo nativo e retorna o sessionID
+func (a *App) LoadExcelFromPath(path string) (string, error) {
+	if path == "" {
+		return "", apperrors.InvalidInput("caminho do arquivo é obrigatório")
+	}
+
+	sessionID := fmt.Sprintf("session_%d", time.Now().UnixNano())
+	if err := a.excelService.ConnectFilePath(sessionID, path); err != nil {
+		logger.AppError("Erro ao carregar arquivo do path: " + err.Error())
+		return "", apperrors.Wrap(err, apperrors.ErrCodeInvalidInput, "erro ao carregar arquivo")
+	}
+
+	logger.AppInfo("Arquivo carregado via path: " + path)
+	return sessionID, nil
+}
+
+// ExecuteExcelTool executa uma ferramenta Excel pelo nome (mesmo catálogo usado pela IA)
+func (a *App) ExecuteExcelTool(toolName string, args map[string]interface{}) (string, error) {
+	result, err := a.chatService.ExecuteToolCall(toolName, args)
+	if err != nil {
+		return "", apperrors.Wrap(err, apperrors.ErrCodeInvalidInput, "falha ao executar "+toolName)
+	}
+	return result, nil
+}
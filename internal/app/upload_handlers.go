@@ -27,6 +27,12 @@ func (a *App) UploadExcel(filename string, data []byte) (string, error) {
 func (a *App) DownloadExcel(sessionID string) ([]byte, error) {
 	logger.AppInfo("Requisitando download do arquivo. SessionID: " + sessionID)
 
+	if sessionID != "" {
+		if err := a.UseSession(sessionID); err != nil {
+			return nil, err
+		}
+	}
+
 	data, err := a.excelService.ExportFile()
 	if err != nil {
 		logger.AppError("Erro ao exportar arquivo: " + err.Error())
@@ -135,9 +141,7 @@ func (a *App) GetSheetData(sessionID, sheetName string) ([][]string, error) {
 func (a *App) CloseSession(sessionID string) error {
 	logger.AppInfo("Fechando sessão: " + sessionID)
 
-	// Implementação futura: adicionar método no Service para fechar sessão específica
-	// Por enquanto, fechamos todo o serviço
-	a.excelService.Close()
+	a.excelService.CloseSession(sessionID)
 
 	logger.AppInfo("Sessão fechada com sucesso")
 	return nil
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"excel-ai/internal/mcp"
+	chatService "excel-ai/internal/services/chat"
+	excelService "excel-ai/internal/services/excel"
+	"excel-ai/pkg/ai"
+	"excel-ai/pkg/logger"
+)
+
+// Version versão reportada pelo servidor MCP
+const Version = "1.0.0"
+
+// MCP executa `excel-ai mcp --file pasta.xlsx [--autosave]`, servindo as ferramentas
+// Excel via Model Context Protocol em stdin/stdout
+func MCP(args []string) int {
+	// stdout é o canal do protocolo: logs e prints de debug vão para stderr
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	initLogger()
+	defer logger.GetLogger().Close()
+
+	return serveMCP(args, os.Stdin, out, os.Stderr)
+}
+
+func serveMCP(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("file", "", "planilha a ser exposta (.xlsx)")
+	autosave := fs.Bool("autosave", false, "salvar no disco após cada ação")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "uso: excel-ai mcp --file pasta.xlsx [--autosave]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if *file == "" {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	excelSvc := excelService.NewService()
+	sessionID := fmt.Sprintf("session_mcp_%d", time.Now().UnixNano())
+	if err := excelSvc.ConnectFilePath(sessionID, *file); err != nil {
+		fmt.Fprintf(stderr, "erro: %v\n", err)
+		return ExitFailure
+	}
+	defer excelSvc.Close()
+
+	chatSvc := chatService.NewService(nil)
+	chatSvc.SetExcelService(excelSvc)
+
+	server := mcp.NewServer(chatSvc, Version)
+	server.AddTool(ai.Tool{
+		Type: "function",
+		Function: ai.FunctionDeclaration{
+			Name:        "save_workbook",
+			Description: "Salva as alterações no arquivo em disco.",
+			Parameters: ai.FunctionParameters{
+				Type:       "object",
+				Properties: map[string]ai.FunctionProperty{},
+			},
+		},
+	}, func(map[string]interface{}) (string, error) {
+		if err := excelSvc.SaveToDisk(excelService.SaveOptions{}); err != nil {
+			return "", err
+		}
+		return "SAVE OK: " + *file, nil
+	})
+	if *autosave {
+		server.SetAfterAction(func() error { return excelSvc.SaveToDisk(excelService.SaveOptions{}) })
+	}
+
+	if err := server.Serve(stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "erro: %v\n", err)
+		return ExitFailure
+	}
+	return ExitOK
+}
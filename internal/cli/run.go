@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	chatService "excel-ai/internal/services/chat"
+	excelService "excel-ai/internal/services/excel"
+	"excel-ai/pkg/logger"
+	"excel-ai/pkg/storage"
+)
+
+// Códigos de saída do modo headless
+const (
+	ExitOK        = 0
+	ExitToolError = 1 // alguma ferramenta reportou ERROR
+	ExitFailure   = 2 // falha ao carregar, conversar com a IA ou salvar
+	ExitUsage     = 3
+)
+
+// Run executa `excel-ai run --file in.xlsx --prompt "..." [--out out.xlsx]` sem a janela Wails.
+// O texto gerado pela IA vai para stdout; logs e mensagens de diagnóstico vão para stderr.
+func Run(args []string) int {
+	// Logs e prints de debug usam os.Stdout; redirecionar para stderr mantém stdout só com a resposta
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	initLogger()
+	defer logger.GetLogger().Close()
+
+	return run(args, out, os.Stderr)
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	file := fs.String("file", "", "planilha de entrada (.xlsx)")
+	prompt := fs.String("prompt", "", "instrução para o agente")
+	outPath := fs.String("out", "", "arquivo de saída (padrão: sobrescreve --file)")
+	stamp := fs.Bool("stamp", false, "registra conversa, modelo e data nas propriedades do arquivo (padrão: configuração do app)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, `uso: excel-ai run --file in.xlsx --prompt "..." [--out out.xlsx] [--stamp=true|false]`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if *file == "" || strings.TrimSpace(*prompt) == "" {
+		fs.Usage()
+		return ExitUsage
+	}
+
+	stor, err := storage.NewStorage()
+	if err != nil {
+		logger.AppWarn("Storage indisponível, usando configuração padrão: " + err.Error())
+		stor = nil
+	}
+
+	excelSvc := excelService.NewService()
+	if stor != nil {
+		excelSvc.SetStorage(stor)
+	}
+	chatSvc := chatService.NewService(stor)
+	chatSvc.SetExcelService(excelSvc)
+
+	sessionID := fmt.Sprintf("session_cli_%d", time.Now().UnixNano())
+	if err := excelSvc.ConnectFilePath(sessionID, *file); err != nil {
+		fmt.Fprintf(stderr, "erro: %v\n", err)
+		return ExitFailure
+	}
+	defer excelSvc.Close()
+
+	var toolErrors []string
+	chatSvc.SetToolResultListener(func(toolName, result string, err error) {
+		if err != nil {
+			toolErrors = append(toolErrors, fmt.Sprintf("%s: %v", toolName, err))
+		}
+	})
+
+	_, err = chatSvc.SendMessage(*prompt, excelSvc.GetActiveContext(), false, func(chunk string) error {
+		// Raciocínio do modelo não faz parte da resposta
+		if strings.HasPrefix(chunk, ":::reasoning:::") {
+			return nil
+		}
+		_, werr := io.WriteString(stdout, chunk)
+		return werr
+	})
+	fmt.Fprintln(stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "erro: %v\n", err)
+		return ExitFailure
+	}
+
+	// Sem --stamp, o registro da origem segue a configuração salva pelo app
+	if !flagSet(fs, "stamp") && stor != nil {
+		if cfg, cfgErr := stor.LoadConfig(); cfgErr == nil {
+			*stamp = cfg.StampProvenance
+		}
+	}
+	opts := excelService.SaveOptions{
+		Stamp:          *stamp,
+		ConversationID: chatSvc.GetCurrentConversationID(),
+		Model:          chatSvc.GetModel(),
+	}
+	if *outPath == "" || sameFile(*outPath, *file) {
+		err = excelSvc.SaveToDisk(opts)
+	} else {
+		err = excelSvc.SaveAs(*outPath, opts)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "erro: %v\n", err)
+		return ExitFailure
+	}
+
+	if len(toolErrors) > 0 {
+		fmt.Fprintf(stderr, "%d ferramenta(s) reportaram erro:\n", len(toolErrors))
+		for _, e := range toolErrors {
+			fmt.Fprintln(stderr, "  - "+e)
+		}
+		return ExitToolError
+	}
+	return ExitOK
+}
+
+// initLogger carrega logger-config.json como o modo janela, com fallback para o padrão
+func initLogger() {
+	if err := logger.InitializeFromFile("logger-config.json"); err != nil {
+		logger.InitializeWithDefaults(logger.INFO)
+	}
+}
+
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// flagSet indica se a flag foi informada na linha de comando
+func flagSet(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"excel-ai/internal/api"
+	"excel-ai/internal/app"
+	"excel-ai/pkg/logger"
+)
+
+// Serve executa `excel-ai serve [--addr 127.0.0.1:8765] [--token segredo]`, expondo as
+// operações Excel como API REST/JSON local. Sem --token, um token aleatório é gerado e exibido.
+func Serve(args []string) int {
+	initLogger()
+	defer logger.GetLogger().Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return serve(ctx, args, os.Stderr)
+}
+
+func serve(ctx context.Context, args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "127.0.0.1:8765", "endereço de escuta (somente localhost)")
+	token := fs.String("token", "", "token exigido em 'Authorization: Bearer <token>' (padrão: aleatório)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "uso: excel-ai serve [--addr 127.0.0.1:8765] [--token segredo]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	if *token == "" {
+		generated, err := randomToken()
+		if err != nil {
+			fmt.Fprintf(stderr, "erro: %v\n", err)
+			return ExitFailure
+		}
+		*token = generated
+		fmt.Fprintf(stderr, "Token de acesso: %s\n", *token)
+	}
+
+	a := app.NewApp()
+	defer a.Shutdown(ctx)
+
+	logger.AppInfo("API REST escutando em http://" + *addr)
+	fmt.Fprintf(stderr, "API REST escutando em http://%s\n", *addr)
+	if err := api.NewServer(a, *token).ListenAndServe(ctx, *addr); err != nil {
+		fmt.Fprintf(stderr, "erro: %v\n", err)
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// randomToken gera um token de 128 bits em hexadecimal
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("falha ao gerar token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
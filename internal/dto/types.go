@@ -60,3 +60,74 @@ type ModelInfo struct {
 	PricePrompt   string `json:"pricePrompt"`
 	PriceComplete string `json:"priceComplete"`
 }
+
+// QueryResult resultado de uma query
+type QueryResult struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// ========== API HTTP local ==========
+
+// SessionInfo sessão de arquivo aberta
+type SessionInfo struct {
+	SessionID string   `json:"sessionId"`
+	FileName  string   `json:"fileName,omitempty"`
+	Sheets    []string `json:"sheets,omitempty"`
+}
+
+// OpenSessionRequest abre um arquivo do disco
+type OpenSessionRequest struct {
+	Path string `json:"path"`
+}
+
+// CellUpdateRequest atualiza uma célula
+type CellUpdateRequest struct {
+	Workbook string `json:"workbook,omitempty"`
+	Sheet    string `json:"sheet"`
+	Cell     string `json:"cell"`
+	Value    string `json:"value"`
+}
+
+// FormatRangeRequest formatação de um intervalo
+type FormatRangeRequest struct {
+	Sheet     string `json:"sheet"`
+	Range     string `json:"range"`
+	Bold      bool   `json:"bold"`
+	Italic    bool   `json:"italic"`
+	FontSize  int    `json:"fontSize,omitempty"`
+	FontColor string `json:"fontColor,omitempty"`
+	BgColor   string `json:"bgColor,omitempty"`
+}
+
+// ChartRequest criação de gráfico
+type ChartRequest struct {
+	Sheet     string `json:"sheet"`
+	Range     string `json:"range"`
+	ChartType string `json:"chartType"`
+	Title     string `json:"title,omitempty"`
+}
+
+// SheetRequest criação de planilha
+type SheetRequest struct {
+	Name string `json:"name"`
+}
+
+// QueryRequest consulta genérica (mesmos tipos de QueryExcel)
+type QueryRequest struct {
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// ToolResult resultado da execução de uma ferramenta
+type ToolResult struct {
+	Tool   string `json:"tool"`
+	Result string `json:"result"`
+}
+
+// APIError corpo de erro das respostas HTTP
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,261 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"excel-ai/pkg/ai"
+	"excel-ai/pkg/logger"
+)
+
+// ProtocolVersion versão do Model Context Protocol implementada
+const ProtocolVersion = "2024-11-05"
+
+// Códigos de erro JSON-RPC
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Executor executa uma ferramenta pelo nome (implementado por chat.Service)
+type Executor interface {
+	ExecuteToolCall(toolName string, args map[string]interface{}) (string, error)
+}
+
+// Server servidor MCP sobre stdio (JSON-RPC 2.0, uma mensagem por linha)
+type Server struct {
+	executor Executor
+	tools    []ai.Tool
+	known    map[string]bool
+	handlers map[string]func(args map[string]interface{}) (string, error)
+	version  string
+
+	// Chamado após cada ferramenta de ação bem-sucedida (ex: salvar no disco)
+	afterAction func() error
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewServer cria um servidor que publica as consultas de ai.GetExcelTools e cada
+// operação de execute_macro como ferramentas MCP independentes
+func NewServer(executor Executor, version string) *Server {
+	var tools []ai.Tool
+	for _, tool := range ai.GetExcelTools() {
+		switch tool.Function.Name {
+		case "list_sheets", "query_batch", "get_range_values", "get_cell_formula":
+			tools = append(tools, tool)
+		}
+	}
+	tools = append(tools, ai.GetMacroOperations()...)
+
+	known := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		known[tool.Function.Name] = true
+	}
+
+	return &Server{
+		executor: executor,
+		tools:    tools,
+		known:    known,
+		handlers: make(map[string]func(args map[string]interface{}) (string, error)),
+		version:  version,
+	}
+}
+
+// AddTool publica uma ferramenta extra tratada por handler (ex: save_workbook)
+func (s *Server) AddTool(tool ai.Tool, handler func(args map[string]interface{}) (string, error)) {
+	s.tools = append(s.tools, tool)
+	s.known[tool.Function.Name] = true
+	s.handlers[tool.Function.Name] = handler
+}
+
+// SetAfterAction registra um callback executado após ferramentas que alteram a pasta
+func (s *Server) SetAfterAction(fn func() error) {
+	s.afterAction = fn
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callToolResult struct {
+	Content []toolContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// Serve lê requisições de in e escreve respostas em out até EOF
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			s.handleLine(line)
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (s *Server) handleLine(line []byte) {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		s.writeError(json.RawMessage("null"), codeParseError, "JSON inválido: "+err.Error())
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		s.writeError(req.ID, codeInvalidRequest, "requisição JSON-RPC inválida")
+		return
+	}
+
+	// Notificações não têm id e não recebem resposta
+	isNotification := len(req.ID) == 0
+
+	result, rpcErr := s.dispatch(req)
+	if isNotification {
+		return
+	}
+	if rpcErr != nil {
+		s.writeError(req.ID, rpcErr.Code, rpcErr.Message)
+		return
+	}
+	s.write(rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result})
+}
+
+func (s *Server) dispatch(req rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    "excel-ai",
+				"version": s.version,
+			},
+		}, nil
+
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+
+	case "ping":
+		return map[string]interface{}{}, nil
+
+	case "tools/list":
+		return map[string]interface{}{"tools": s.listTools()}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "parâmetros inválidos: " + err.Error()}
+		}
+		if !s.known[params.Name] {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "ferramenta desconhecida: " + params.Name}
+		}
+		return s.callTool(params.Name, params.Arguments), nil
+	}
+
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "método não suportado: " + req.Method}
+}
+
+func (s *Server) listTools() []map[string]interface{} {
+	list := make([]map[string]interface{}, 0, len(s.tools))
+	for _, tool := range s.tools {
+		schema := tool.Function.Parameters
+		if schema.Properties == nil {
+			schema.Properties = map[string]ai.FunctionProperty{}
+		}
+		list = append(list, map[string]interface{}{
+			"name":        tool.Function.Name,
+			"description": tool.Function.Description,
+			"inputSchema": schema,
+		})
+	}
+	return list
+}
+
+func (s *Server) callTool(name string, args map[string]interface{}) callToolResult {
+	logger.ToolsInfo(fmt.Sprintf("[MCP] tools/call %s", name))
+
+	if handler, ok := s.handlers[name]; ok {
+		result, err := handler(args)
+		if err != nil {
+			return callToolResult{Content: []toolContent{{Type: "text", Text: "ERROR: " + err.Error()}}, IsError: true}
+		}
+		return callToolResult{Content: []toolContent{{Type: "text", Text: result}}}
+	}
+
+	result, err := s.executor.ExecuteToolCall(name, args)
+	if err != nil {
+		return callToolResult{
+			Content: []toolContent{{Type: "text", Text: "ERROR: " + err.Error()}},
+			IsError: true,
+		}
+	}
+
+	if ai.IsActionTool(name) && s.afterAction != nil {
+		if err := s.afterAction(); err != nil {
+			return callToolResult{
+				Content: []toolContent{{Type: "text", Text: result + "\nERROR ao salvar: " + err.Error()}},
+				IsError: true,
+			}
+		}
+	}
+
+	return callToolResult{Content: []toolContent{{Type: "text", Text: result}}}
+}
+
+func (s *Server) writeError(id json.RawMessage, code int, message string) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	s.write(rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}})
+}
+
+func (s *Server) write(resp rpcResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		logger.ToolsError("[MCP] Erro ao serializar resposta: " + err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.out.Write(append(data, '\n'))
+}
@@ -3,9 +3,33 @@ package chat
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+
+	"excel-ai/pkg/excel"
 )
 
+// ToolFailure indica que uma etapa da ferramenta falhou (ação de macro, consulta de
+// lote ou recálculo). Output descreve tudo o que foi executado, inclusive as etapas
+// concluídas, e é também a mensagem do erro.
+type ToolFailure struct {
+	Output string
+}
+
+func (e *ToolFailure) Error() string {
+	return e.Output
+}
+
+// ExecuteToolCall executa uma ferramenta pelo nome, sem passar pela IA nem pedir confirmação.
+// Aceita as ferramentas de ai.GetExcelTools e as operações de ai.GetMacroOperations.
+// Falhas parciais são devolvidas como *ToolFailure.
+func (s *Service) ExecuteToolCall(toolName string, args map[string]interface{}) (string, error) {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	return s.executeToolCall(toolName, args, nil)
+}
+
 // executeToolCall executa uma tool call da Z.ai diretamente
 func (s *Service) executeToolCall(toolName string, args map[string]interface{}, onChunk func(string) error) (string, error) {
 	// 1. Mapear ferramentas Z.ai para o sistema interno (Consultas)
@@ -15,6 +39,8 @@ func (s *Service) executeToolCall(toolName string, args map[string]interface{},
 		"get_range_values": true,
 		"get_cell_formula": true,
 		"get_active_cell":  true,
+		"get_headers":      true,
+		"sheet_exists":     true,
 	}
 
 	// 2. Tratar query_batch especialmente (múltiplas queries)
@@ -22,6 +48,7 @@ func (s *Service) executeToolCall(toolName string, args map[string]interface{},
 		queries, ok := args["queries"].([]interface{})
 		if ok && len(queries) > 0 {
 			results := make([]string, 0, len(queries))
+			failed := false
 			sheet, _ := args["sheet"].(string)
 			sampleRows := args["sample_rows"]
 
@@ -35,6 +62,10 @@ func (s *Service) executeToolCall(toolName string, args map[string]interface{},
 					if sampleRows != nil {
 						payload["sample_rows"] = sampleRows
 					}
+					// Alvo das consultas de dependência (precedents, dependents, impact_of_change)
+					if target, ok := args["range"].(string); ok {
+						payload["range"] = target
+					}
 
 					queryCmd := ToolCommand{
 						Type:    ToolTypeQuery,
@@ -43,12 +74,17 @@ func (s *Service) executeToolCall(toolName string, args map[string]interface{},
 					result, err := s.ExecuteTool(queryCmd, onChunk)
 					if err != nil {
 						results = append(results, fmt.Sprintf("ERROR: %v", err))
+						failed = true
 					} else {
 						results = append(results, result)
 					}
 				}
 			}
-			return fmt.Sprintf("QUERY_BATCH (%d queries):\n%s", len(results), joinResults(results)), nil
+			output := fmt.Sprintf("QUERY_BATCH (%d queries):\n%s", len(results), joinResults(results))
+			if failed {
+				return "", &ToolFailure{Output: output}
+			}
+			return output, nil
 		}
 	}
 
@@ -83,8 +119,38 @@ func convertQueryType(query string) string {
 		return "has-filter"
 	case "charts":
 		return "list-charts"
+	case "images":
+		return "list-images"
+	case "shapes":
+		return "list-shapes"
+	case "pivots":
+		return "list-pivot-tables"
 	case "tables":
 		return "list-tables"
+	case "precedents":
+		return "get-precedents"
+	case "dependents":
+		return "get-dependents"
+	case "impact_of_change":
+		return "get-impact"
+	case "formats":
+		return "get-formats"
+	case "conditional_formats":
+		return "list-conditional-formats"
+	case "validations":
+		return "list-validations"
+	case "invalid_cells":
+		return "find-invalid-cells"
+	case "names":
+		return "list-names"
+	case "filter":
+		return "get-filter"
+	case "page_setup":
+		return "get-page-setup"
+	case "sheets":
+		return "get-sheets"
+	case "workbook_info":
+		return "get-workbook-info"
 	default:
 		return "get-range-values"
 	}
@@ -111,6 +177,12 @@ func convertToolArguments(toolName string, args map[string]interface{}) map[stri
 	if toolName == "get_active_cell" {
 		return map[string]interface{}{"type": "get-active-cell"}
 	}
+	if toolName == "get_headers" {
+		return map[string]interface{}{"type": "get-headers", "sheet": args["sheet"], "range": args["range"]}
+	}
+	if toolName == "sheet_exists" {
+		return map[string]interface{}{"type": "sheet-exists", "name": args["name"]}
+	}
 
 	// Para execute_macro - converter para macro
 	if toolName == "execute_macro" {
@@ -127,20 +199,13 @@ func convertToolArguments(toolName string, args map[string]interface{}) map[stri
 						}
 					}
 
+					// 2. Mesmo nome interno das operações chamadas diretamente (sort_range -> sort)
 					if opName != "" {
-						// 2. Normalizar underscores -> dashes (ex: write_cell -> write-cell)
-						opName = strings.ReplaceAll(opName, "_", "-")
-
-						// 3. Mapeamentos específicos
-						if opName == "write-cell" || opName == "write-range" {
-							opName = "write"
-						}
-
-						actionMap["op"] = opName
+						actionMap["op"] = actionOp(opName)
 						delete(actionMap, "tool")
 					}
 
-					// 4. Se a IA aninhou os argumentos em "args", traz para o nível superior
+					// 3. Se a IA aninhou os argumentos em "args", traz para o nível superior
 					if innerArgs, ok := actionMap["args"].(map[string]interface{}); ok {
 						for k, v := range innerArgs {
 							actionMap[k] = v
@@ -201,6 +266,22 @@ func (s *Service) executeQuery(params map[string]interface{}, _ func(string) err
 		}
 		return fmt.Sprintf("SHEETS: %v", sheets), nil
 
+	case "get-sheets":
+		sheets, err := s.excelService.GetSheets()
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(sheets)
+		return fmt.Sprintf("SHEETS: %s", data), nil
+
+	case "get-workbook-info":
+		info, err := s.excelService.GetWorkbookInfo()
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(info)
+		return fmt.Sprintf("WORKBOOK: %s", data), nil
+
 	case "sheet-exists":
 		name, _ := params["name"].(string)
 		exists, err := s.excelService.SheetExists(name)
@@ -253,27 +334,150 @@ func (s *Service) executeQuery(params map[string]interface{}, _ func(string) err
 
 	case "list-tables":
 		sheet, _ := params["sheet"].(string)
-		tables, err := s.excelService.ListTables(sheet)
+		tables, err := s.excelService.GetTables(sheet)
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("TABLES: %v", tables), nil
+		data, _ := json.Marshal(tables)
+		return fmt.Sprintf("TABLES: %s", data), nil
 
 	case "list-charts":
 		sheet, _ := params["sheet"].(string)
-		charts, err := s.excelService.ListCharts(sheet)
+		charts, err := s.excelService.GetCharts(sheet)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(charts)
+		return fmt.Sprintf("CHARTS: %s", data), nil
+
+	case "list-images":
+		sheet, _ := params["sheet"].(string)
+		images, err := s.excelService.GetImages(sheet)
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("CHARTS: %v", charts), nil
+		data, _ := json.Marshal(images)
+		return fmt.Sprintf("IMAGES: %s", data), nil
+
+	case "list-shapes":
+		sheet, _ := params["sheet"].(string)
+		shapes, err := s.excelService.GetShapes(sheet)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(shapes)
+		return fmt.Sprintf("SHAPES: %s", data), nil
 
 	case "list-pivot-tables":
 		sheet, _ := params["sheet"].(string)
-		pivots, err := s.excelService.ListPivotTables(sheet)
+		pivots, err := s.excelService.GetPivotTables(sheet)
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("PIVOTS: %v", pivots), nil
+		data, _ := json.Marshal(pivots)
+		return fmt.Sprintf("PIVOTS: %s", data), nil
+
+	case "get-precedents", "get-dependents", "get-impact":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+		if rng == "" {
+			rng, _ = params["cell"].(string)
+		}
+		if rng == "" {
+			return "", fmt.Errorf("%s requires 'range' (cell or range)", queryType)
+		}
+
+		switch queryType {
+		case "get-precedents":
+			precedents, err := s.excelService.GetPrecedents(sheet, rng)
+			if err != nil {
+				return "", err
+			}
+			data, _ := json.Marshal(precedents)
+			return fmt.Sprintf("PRECEDENTS (%s): %s", rng, data), nil
+		case "get-dependents":
+			dependents, err := s.excelService.GetDependents(sheet, rng)
+			if err != nil {
+				return "", err
+			}
+			data, _ := json.Marshal(dependents)
+			return fmt.Sprintf("DEPENDENTS (%s): %s", rng, data), nil
+		default:
+			impact, err := s.excelService.GetChangeImpact(sheet, rng)
+			if err != nil {
+				return "", err
+			}
+			data, _ := json.Marshal(impact)
+			return fmt.Sprintf("IMPACT: %s\n%s", impact.Summary(), data), nil
+		}
+
+	case "get-formats":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+		if rng == "" {
+			used, err := s.excelService.GetUsedRange(sheet)
+			if err != nil {
+				return "", err
+			}
+			rng = used
+		}
+		formats, err := s.excelService.GetRangeFormats(sheet, rng)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(formats)
+		return fmt.Sprintf("FORMATS: %s", data), nil
+
+	case "list-conditional-formats":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+		rules, err := s.excelService.GetConditionalFormats(sheet, rng)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(rules)
+		return fmt.Sprintf("CONDITIONAL FORMATS: %s", data), nil
+
+	case "list-validations":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+		rules, err := s.excelService.GetValidations(sheet, rng)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(rules)
+		return fmt.Sprintf("VALIDATIONS: %s", data), nil
+
+	case "find-invalid-cells":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+		violations, err := s.excelService.FindInvalidCells(sheet, rng)
+		if err != nil {
+			return "", err
+		}
+		total := len(violations)
+		if total > maxInvalidCells {
+			violations = violations[:maxInvalidCells]
+		}
+		data, _ := json.Marshal(violations)
+		return fmt.Sprintf("INVALID CELLS (%d): %s", total, data), nil
+
+	case "list-names":
+		names, err := s.excelService.ListDefinedNames()
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(names)
+		return fmt.Sprintf("NAMES: %s", data), nil
+
+	case "get-page-setup":
+		sheet, _ := params["sheet"].(string)
+		setup, err := s.excelService.GetPageSetup(sheet)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(setup)
+		return fmt.Sprintf("PAGE SETUP: %s", data), nil
 
 	case "get-range-values":
 		sheet, _ := params["sheet"].(string)
@@ -295,7 +499,13 @@ func (s *Service) executeQuery(params map[string]interface{}, _ func(string) err
 			maxRows = 20 // Default razoável
 		}
 
-		values, err := s.excelService.GetRangeValues(sheet, rng)
+		var values [][]string
+		var err error
+		if visibleOnly, _ := params["visible_only"].(bool); visibleOnly {
+			values, err = s.excelService.GetVisibleRangeValues(sheet, rng)
+		} else {
+			values, err = s.excelService.GetRangeValues(sheet, rng)
+		}
 		if err != nil {
 			return "", err
 		}
@@ -305,6 +515,18 @@ func (s *Service) executeQuery(params map[string]interface{}, _ func(string) err
 		}
 		return fmt.Sprintf("DATA (%s!%s, max %d rows): %v", sheet, rng, maxRows, values), nil
 
+	case "get-filter":
+		sheet, _ := params["sheet"].(string)
+		filter, err := s.excelService.GetAutoFilter(sheet)
+		if err != nil {
+			return "", err
+		}
+		if filter == nil {
+			return fmt.Sprintf("FILTER (%s): nenhum filtro", sheet), nil
+		}
+		data, _ := json.Marshal(filter)
+		return fmt.Sprintf("FILTER (%s): %s", sheet, data), nil
+
 	case "has-filter":
 		sheet, _ := params["sheet"].(string)
 		hasFilter, err := s.excelService.HasFilter(sheet)
@@ -344,6 +566,18 @@ func getInt(v interface{}) int {
 	return 0
 }
 
+// columnNumber posição (1 = A) da coluna no início de um endereço ("AB", "C12")
+func columnNumber(ref string) int {
+	n := 0
+	for _, r := range strings.ToUpper(strings.TrimLeft(ref, "$")) {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		n = n*26 + int(r-'A') + 1
+	}
+	return n
+}
+
 // Helper para extrair float64 de interface{}
 func getFloat(v interface{}) float64 {
 	if f, ok := v.(float64); ok {
@@ -355,6 +589,29 @@ func getFloat(v interface{}) float64 {
 	return 0.0
 }
 
+// Helper para converter números enviados pela IA em texto (ex: "value": 100)
+func getString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(val)
+	}
+	return ""
+}
+
+// decodeParams converte os argumentos da ferramenta na estrutura equivalente de
+// pkg/excel, cujos campos usam os mesmos nomes JSON
+func decodeParams(params interface{}, v interface{}) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
 // Helper para juntar resultados de macro
 func joinResults(results []string) string {
 	result := ""
@@ -364,6 +621,78 @@ func joinResults(results []string) string {
 	return result
 }
 
+// macroActionKey marca ações executadas dentro de uma macro
+const macroActionKey = "_inMacro"
+
+// maxRecalcWarnings limita os avisos de fórmulas com erro enviados à IA
+const maxRecalcWarnings = 20
+
+// maxInvalidCells limita as células inválidas listadas para a IA
+const maxInvalidCells = 50
+
+// recalcOps operações que alteram valores lidos por fórmulas
+var recalcOps = map[string]bool{
+	"write":       true,
+	"set-formula": true,
+	"clear-range": true,
+	"insert-rows": true,
+	"delete-rows": true,
+	"sort":        true,
+	"copy-range":  true,
+	"move-range":  true,
+
+	"insert-columns":    true,
+	"delete-columns":    true,
+	"move-columns":      true,
+	"append-table-rows": true,
+	"set-table-totals":  true,
+}
+
+// formatRecalcResult resume um recálculo com um aviso por fórmula com erro
+func formatRecalcResult(result *excel.RecalcResult) string {
+	summary := fmt.Sprintf("RECALC OK: %d fórmulas calculadas", result.Evaluated)
+	if len(result.Errors) == 0 {
+		return summary
+	}
+
+	var warnings []string
+	for i, e := range result.Errors {
+		if i == maxRecalcWarnings {
+			warnings = append(warnings, fmt.Sprintf("... mais %d erros", len(result.Errors)-i))
+			break
+		}
+		warnings = append(warnings, fmt.Sprintf("WARN %s!%s (=%s): %s - %s", e.Sheet, e.Cell, e.Formula, e.Error, e.Message))
+	}
+	return fmt.Sprintf("%s, %d com erro:\n%s", summary, len(result.Errors), joinResults(warnings))
+}
+
+// appendTableRows acrescenta à tabela as linhas de "row" (objeto) ou "rows" (lista
+// de objetos), indexadas pelos cabeçalhos
+func (s *Service) appendTableRows(table string, params map[string]interface{}) (string, error) {
+	var rows []map[string]interface{}
+	if row, ok := params["row"].(map[string]interface{}); ok {
+		rows = append(rows, row)
+	}
+	if list, ok := params["rows"].([]interface{}); ok {
+		for _, item := range list {
+			row, ok := item.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("cada linha da tabela deve ser um objeto {\"Cabeçalho\": valor}")
+			}
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("informe row ou rows para a tabela %s", table)
+	}
+
+	rng, err := s.excelService.AppendTableRows(table, rows)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("APPEND TABLE OK: %d linhas em %s (%s)", len(rows), table, rng), nil
+}
+
 func (s *Service) executeAction(params map[string]interface{}, onChunk func(string) error) (string, error) {
 	op, _ := params["op"].(string)
 
@@ -388,12 +717,18 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		s.excelService.StartUndoBatch()
 
 		var results []string
+		needsRecalc := false
+		failed := false
 		for i, action := range actions {
 			actionMap, ok := action.(map[string]interface{})
 			if !ok {
 				results = append(results, fmt.Sprintf("Action %d: SKIP (invalid format)", i+1))
 				continue
 			}
+			actionMap[macroActionKey] = true
+			if op, _ := actionMap["op"].(string); recalcOps[op] {
+				needsRecalc = true
+			}
 
 			// Feedback de progresso
 			onChunk(fmt.Sprintf("⏳ *[Ação %d/%d]:* %s...\n", i+1, len(actions), actionMap["op"]))
@@ -401,6 +736,7 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 			result, err := s.executeAction(actionMap, onChunk)
 			if err != nil {
 				results = append(results, fmt.Sprintf("Action %d (%s): ERROR - %v", i+1, actionMap["op"], err))
+				failed = true
 				// Stop execution on error to prevent cascading failures
 				break
 			} else {
@@ -411,14 +747,32 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		// End undo batch
 		s.excelService.EndUndoBatch()
 
+		// Fórmulas são recalculadas uma única vez ao final da macro
+		if needsRecalc {
+			if recalc, err := s.excelService.CalculateFormulas(); err != nil {
+				results = append(results, fmt.Sprintf("RECALC: ERROR - %v", err))
+				failed = true
+			} else {
+				results = append(results, formatRecalcResult(recalc))
+			}
+		}
+
+		if failed {
+			return "", &ToolFailure{Output: fmt.Sprintf("MACRO FAILED (%d actions):\n%s", len(actions), joinResults(results))}
+		}
 		fmt.Printf("[DEBUG] ✅ MACRO completed: %d actions executed\n", len(actions))
 		return fmt.Sprintf("MACRO OK (%d actions):\n%s", len(actions), joinResults(results)), nil
 
 	case "write":
-		// Suporta dois formatos:
+		// Suporta três formatos:
 		// 1. Célula única: {"op": "write", "cell": "A1", "value": "xyz"}
 		// 2. Lote (batch): {"op": "write", "cell": "A1", "data": [["a","b"],["c","d"]]}
 
+		// 3. Tabela: {"op": "write", "table": "Vendas", "row": {"Cliente": "X"}}
+		if table, ok := params["table"].(string); ok && table != "" {
+			return s.appendTableRows(table, params)
+		}
+
 		cell, _ := params["cell"].(string)
 		sheet, _ := params["sheet"].(string)
 
@@ -489,43 +843,69 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 
 	case "create-chart":
 		sheet, _ := params["sheet"].(string)
-		rng, _ := params["range"].(string)
-		cType, _ := params["chartType"].(string)
-		title, _ := params["title"].(string)
 
-		err := s.excelService.CreateChart(sheet, rng, cType, title)
+		var spec excel.ChartSpec
+		if err := decodeParams(params, &spec); err != nil {
+			return "", fmt.Errorf("especificação de gráfico inválida: %w", err)
+		}
+		if spec.Type == "" {
+			spec.Type, _ = params["type"].(string)
+		}
+
+		err := s.excelService.CreateChartSpec(sheet, spec)
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("CREATE CHART OK: %s", title), nil
+		return fmt.Sprintf("CREATE CHART OK: %s", spec.Title), nil
 
 	case "create-pivot":
-		srcSheet, _ := params["sourceSheet"].(string)
-		srcRange, _ := params["sourceRange"].(string)
-		destSheet, _ := params["destSheet"].(string)
-		destCell, _ := params["destCell"].(string)
-		tableName, _ := params["tableName"].(string)
+		var spec excel.PivotSpec
+		if err := decodeParams(params, &spec); err != nil {
+			return "", fmt.Errorf("especificação de tabela dinâmica inválida: %w", err)
+		}
 
-		err := s.excelService.CreatePivotTable(srcSheet, srcRange, destSheet, destCell, tableName)
+		err := s.excelService.CreatePivotTableWithFields(spec)
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("CREATE PIVOT OK: %s", tableName), nil
+		return fmt.Sprintf("CREATE PIVOT OK: %s", spec.Name), nil
+
+	case "update-pivot":
+		sheet, _ := params["sheet"].(string)
+		name, _ := params["name"].(string)
+
+		var changes excel.PivotSpec
+		if err := decodeParams(params, &changes); err != nil {
+			return "", fmt.Errorf("alterações de tabela dinâmica inválidas: %w", err)
+		}
+
+		err := s.excelService.UpdatePivotTable(sheet, name, changes)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("UPDATE PIVOT OK: %s", name), nil
 
 	case "format-range":
 		rng, _ := params["range"].(string)
-		bold, _ := params["bold"].(bool)
-		italic, _ := params["italic"].(bool)
-		fontSize := getFloat(params["fontSize"])
-		fontColor, _ := params["fontColor"].(string)
-		bgColor, _ := params["bgColor"].(string)
 		sheet, _ := params["sheet"].(string)
 
-		err := s.excelService.FormatRange(sheet, rng, bold, italic, int(fontSize), fontColor, bgColor)
+		var format excel.Format
+		if err := decodeParams(params, &format); err != nil {
+			return "", fmt.Errorf("formatação inválida: %w", err)
+		}
+
+		oldStyles, _ := s.excelService.GetCellStyles(sheet, rng)
+
+		err := s.excelService.FormatRangeSpec(sheet, rng, format)
 		if err != nil {
 			return "", err
 		}
-		// TODO: Implement undo for format-range (complex)
+
+		// Undo: format-range -> restaurar estilos anteriores
+		if oldStyles != nil {
+			undoData, _ := json.Marshal(oldStyles)
+			s.excelService.SaveUndoAction("format-range", "", sheet, rng, "", string(undoData))
+		}
 		return "FORMAT RANGE OK", nil
 
 	case "delete-sheet":
@@ -626,6 +1006,86 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 
 		return fmt.Sprintf("DELETE ROWS OK: %d at %d", count, row), nil
 
+	case "insert-columns":
+		sheet, _ := params["sheet"].(string)
+
+		var spec excel.ColumnInsert
+		if err := decodeParams(params, &spec); err != nil {
+			return "", fmt.Errorf("inserção de colunas inválida: %w", err)
+		}
+		inserted, err := s.excelService.InsertColumns(sheet, spec)
+		if err != nil {
+			return "", err
+		}
+		// Undo: insert-columns -> delete-columns
+		undoData, _ := json.Marshal(map[string]string{"column": inserted})
+		s.excelService.SaveUndoAction("insert-columns", "", sheet, "", "", string(undoData))
+		return fmt.Sprintf("INSERT COLUMNS OK: %s", inserted), nil
+
+	case "delete-columns":
+		sheet, _ := params["sheet"].(string)
+		column, _ := params["column"].(string)
+		count := getInt(params["count"])
+
+		// Salva os valores antes de excluir, como em delete-rows
+		used, err := s.excelService.ColumnRange(sheet, column, count)
+		if err != nil {
+			return "", err
+		}
+		values, _ := s.excelService.GetRangeValues(sheet, used)
+
+		deleted, err := s.excelService.DeleteColumns(sheet, used, 0)
+		if err != nil {
+			return "", err
+		}
+		// Undo: delete-columns -> insert-columns + write data
+		first, last, _ := strings.Cut(deleted, ":")
+		undoData, _ := json.Marshal(map[string]interface{}{
+			"column": first,
+			"count":  columnNumber(last) - columnNumber(first) + 1,
+			"data":   values,
+		})
+		s.excelService.SaveUndoAction("delete-columns", "", sheet, "", "", string(undoData))
+		return fmt.Sprintf("DELETE COLUMNS OK: %s", deleted), nil
+
+	case "move-columns":
+		sheet, _ := params["sheet"].(string)
+		column, _ := params["column"].(string)
+		before, _ := params["before"].(string)
+		after, _ := params["after"].(string)
+
+		used, err := s.excelService.ColumnRange(sheet, column, 0)
+		if err != nil {
+			return "", err
+		}
+		moved, err := s.excelService.MoveColumns(sheet, used, before, after)
+		if err != nil {
+			return "", err
+		}
+		// Undo: move de volta. Indo para a direita, as colunas voltam para antes da
+		// antiga primeira; indo para a esquerda, para depois da antiga última.
+		start, end, _ := strings.Cut(used, ":")
+		oldFirst := strings.TrimRight(start, "0123456789")
+		oldLast := strings.TrimRight(end, "0123456789")
+		newFirst, _, _ := strings.Cut(moved, ":")
+		back := map[string]string{"column": moved, "after": oldLast}
+		if columnNumber(newFirst) > columnNumber(oldFirst) {
+			back = map[string]string{"column": moved, "before": oldFirst}
+		}
+		undoData, _ := json.Marshal(back)
+		s.excelService.SaveUndoAction("move-columns", "", sheet, "", "", string(undoData))
+		return fmt.Sprintf("MOVE COLUMNS OK: %s", moved), nil
+
+	case "group-columns", "ungroup-columns":
+		sheet, _ := params["sheet"].(string)
+		column, _ := params["column"].(string)
+		end, _ := params["end"].(string)
+		err := s.excelService.GroupColumns(sheet, column, end, op == "group-columns")
+		if err != nil {
+			return "", err
+		}
+		return strings.ToUpper(strings.ReplaceAll(op, "-", " ")) + " OK", nil
+
 	case "merge-cells":
 		sheet, _ := params["sheet"].(string)
 		rng, _ := params["range"].(string)
@@ -652,10 +1112,19 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		sheet, _ := params["sheet"].(string)
 		rng, _ := params["range"].(string)
 		style, _ := params["style"].(string)
+
+		oldStyles, _ := s.excelService.GetCellStyles(sheet, rng)
+
 		err := s.excelService.SetBorders(sheet, rng, style)
 		if err != nil {
 			return "", err
 		}
+
+		// Undo: set-borders -> restaurar estilos anteriores
+		if oldStyles != nil {
+			undoData, _ := json.Marshal(oldStyles)
+			s.excelService.SaveUndoAction("set-borders", "", sheet, rng, "", string(undoData))
+		}
 		return "BORDERS OK", nil
 
 	case "set-column-width":
@@ -705,11 +1174,47 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 	case "apply-filter":
 		sheet, _ := params["sheet"].(string)
 		rng, _ := params["range"].(string)
-		err := s.excelService.ApplyFilter(sheet, rng)
+		criteriaRaw, _ := params["criteria"].([]interface{})
+		if len(criteriaRaw) == 0 {
+			if err := s.excelService.ApplyFilter(sheet, rng); err != nil {
+				return "", err
+			}
+			return "FILTER APPLIED OK", nil
+		}
+
+		// Valores numéricos enviados pela IA viram texto antes da decodificação
+		for _, item := range criteriaRaw {
+			criterion, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, key := range []string{"column", "value", "minValue", "maxValue"} {
+				if v, ok := criterion[key]; ok {
+					criterion[key] = getString(v)
+				}
+			}
+			if values, ok := criterion["values"].([]interface{}); ok {
+				for i, v := range values {
+					values[i] = getString(v)
+				}
+			}
+			if conditions, ok := criterion["conditions"].([]interface{}); ok {
+				for _, cond := range conditions {
+					if condition, ok := cond.(map[string]interface{}); ok {
+						condition["value"] = getString(condition["value"])
+					}
+				}
+			}
+		}
+		var criteria []excel.FilterCriterion
+		if err := decodeParams(criteriaRaw, &criteria); err != nil {
+			return "", fmt.Errorf("critérios de filtro inválidos: %w", err)
+		}
+		visible, err := s.excelService.ApplyAutoFilter(sheet, rng, criteria)
 		if err != nil {
 			return "", err
 		}
-		return "FILTER APPLIED OK", nil
+		return fmt.Sprintf("FILTER APPLIED OK: %d linhas visíveis", visible), nil
 
 	case "clear-filters":
 		sheet, _ := params["sheet"].(string)
@@ -722,44 +1227,80 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 	case "sort":
 		sheet, _ := params["sheet"].(string)
 		rng, _ := params["range"].(string)
-		col := getInt(params["column"])
-		asc, _ := params["ascending"].(bool)
 
-		// Sort é complexo. Undo data = save range data before sort.
-		oldData, _ := s.excelService.GetRangeValues(sheet, rng)
+		// Chave única legada (column/ascending) ou lista de chaves com o mesmo formato de excel.SortKey
+		var opts excel.SortOptions
+		if err := decodeParams(params, &opts); err != nil {
+			return "", fmt.Errorf("critérios de ordenação inválidos: %w", err)
+		}
+		if len(opts.Keys) == 0 {
+			key := excel.SortKey{Column: getInt(params["column"]), Order: "asc"}
+			if key.Column == 0 {
+				key.Column = 1
+			}
+			if asc, ok := params["ascending"].(bool); ok && !asc {
+				key.Order = "desc"
+			}
+			if t, ok := params["type"].(string); ok {
+				key.Type = t
+			}
+			opts.Keys = []excel.SortKey{key}
+		}
 
-		err := s.excelService.SortRange(sheet, rng, col, asc)
+		order, err := s.excelService.SortRange(sheet, rng, opts)
 		if err != nil {
 			return "", err
 		}
 
-		// Undo: restore original data order
-		if len(oldData) > 0 {
-			undoMap := map[string]interface{}{
-				"data": oldData,
-			}
-			undoData, _ := json.Marshal(undoMap)
-			s.excelService.SaveUndoAction("sort", "", sheet, rng, "", string(undoData))
-		}
+		// Undo: reposicionar as linhas na ordem original
+		undoData, _ := json.Marshal(map[string]interface{}{"order": order})
+		s.excelService.SaveUndoAction("sort", "", sheet, rng, "", string(undoData))
 		return "SORT OK", nil
 
 	case "copy-range":
 		sheet, _ := params["sheet"].(string)
 		src, _ := params["source"].(string)
 		dest, _ := params["dest"].(string)
-		err := s.excelService.CopyRange(sheet, src, dest)
+		var opts excel.PasteOptions
+		opts.Mode, _ = params["mode"].(string)
+		opts.Transpose, _ = params["transpose"].(bool)
+		target, err := s.excelService.CopyRange(sheet, src, dest, opts)
 		if err != nil {
 			return "", err
 		}
-		return "COPY OK", nil
+		return fmt.Sprintf("COPY OK: %s", target), nil
+
+	case "move-range":
+		sheet, _ := params["sheet"].(string)
+		src, _ := params["source"].(string)
+		dest, _ := params["dest"].(string)
+		target, err := s.excelService.MoveRange(sheet, src, dest)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("MOVE OK: %s", target), nil
+
+	case "copy-sheet":
+		src, _ := params["sheet"].(string)
+		name, _ := params["name"].(string)
+		session, _ := params["session"].(string)
+		created, err := s.excelService.CopySheet(session, src, name)
+		if err != nil {
+			return "", err
+		}
+		// Undo: igual a create-sheet, basta deletar a cópia
+		undoData, _ := json.Marshal(map[string]string{"sheetName": created})
+		s.excelService.SaveUndoAction("create-sheet", "", created, "", "", string(undoData))
+		return fmt.Sprintf("COPY SHEET OK: %s", created), nil
 
 	case "list-charts":
 		sheet, _ := params["sheet"].(string)
-		charts, err := s.excelService.ListCharts(sheet)
+		charts, err := s.excelService.GetCharts(sheet)
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("CHARTS: %v", charts), nil
+		data, _ := json.Marshal(charts)
+		return fmt.Sprintf("CHARTS: %s", data), nil
 
 	case "delete-chart":
 		sheet, _ := params["sheet"].(string)
@@ -770,6 +1311,65 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		}
 		return "DELETE CHART OK", nil
 
+	case "list-images":
+		sheet, _ := params["sheet"].(string)
+		images, err := s.excelService.GetImages(sheet)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(images)
+		return fmt.Sprintf("IMAGES: %s", data), nil
+
+	case "list-shapes":
+		sheet, _ := params["sheet"].(string)
+		shapes, err := s.excelService.GetShapes(sheet)
+		if err != nil {
+			return "", err
+		}
+		data, _ := json.Marshal(shapes)
+		return fmt.Sprintf("SHAPES: %s", data), nil
+
+	case "insert-image":
+		sheet, _ := params["sheet"].(string)
+
+		var spec excel.ImageSpec
+		if err := decodeParams(params, &spec); err != nil {
+			return "", fmt.Errorf("especificação de imagem inválida: %w", err)
+		}
+		if err := s.excelService.InsertImage(sheet, spec); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("INSERT IMAGE OK: %s", spec.Cell), nil
+
+	case "delete-image":
+		sheet, _ := params["sheet"].(string)
+		cell, _ := params["cell"].(string)
+		if err := s.excelService.DeleteImage(sheet, cell); err != nil {
+			return "", err
+		}
+		return "DELETE IMAGE OK", nil
+
+	case "add-shape":
+		sheet, _ := params["sheet"].(string)
+
+		var spec excel.ShapeSpec
+		if err := decodeParams(params, &spec); err != nil {
+			return "", fmt.Errorf("especificação de forma inválida: %w", err)
+		}
+		name, err := s.excelService.AddShape(sheet, spec)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ADD SHAPE OK: %s", name), nil
+
+	case "delete-shape":
+		sheet, _ := params["sheet"].(string)
+		name, _ := params["name"].(string)
+		if err := s.excelService.DeleteShape(sheet, name); err != nil {
+			return "", err
+		}
+		return "DELETE SHAPE OK", nil
+
 	case "create-table":
 		sheet, _ := params["sheet"].(string)
 		rng, _ := params["range"].(string)
@@ -783,11 +1383,12 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 
 	case "list-tables":
 		sheet, _ := params["sheet"].(string)
-		tables, err := s.excelService.ListTables(sheet)
+		tables, err := s.excelService.GetTables(sheet)
 		if err != nil {
 			return "", err
 		}
-		return fmt.Sprintf("TABLES: %v", tables), nil
+		data, _ := json.Marshal(tables)
+		return fmt.Sprintf("TABLES: %s", data), nil
 
 	case "delete-table":
 		sheet, _ := params["sheet"].(string)
@@ -798,22 +1399,132 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		}
 		return "DELETE TABLE OK", nil
 
+	case "append-table-rows":
+		name, _ := params["name"].(string)
+		if table, ok := params["table"].(string); ok && table != "" {
+			name = table
+		}
+		return s.appendTableRows(name, params)
+
+	case "resize-table":
+		name, _ := params["name"].(string)
+		rng, _ := params["range"].(string)
+		ref, err := s.excelService.ResizeTable(name, rng)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("RESIZE TABLE OK: %s = %s", name, ref), nil
+
+	case "set-table-totals":
+		name, _ := params["name"].(string)
+		show := true
+		if v, ok := params["show"].(bool); ok {
+			show = v
+		}
+		var totals []excel.TableTotal
+		if raw, ok := params["totals"]; ok {
+			data, _ := json.Marshal(raw)
+			if err := json.Unmarshal(data, &totals); err != nil {
+				return "", fmt.Errorf("totais inválidos: %w", err)
+			}
+		}
+		if err := s.excelService.SetTableTotals(name, show, totals); err != nil {
+			return "", err
+		}
+		if !show {
+			return "TABLE TOTALS OFF", nil
+		}
+		return "TABLE TOTALS OK", nil
+
 	// ==================== NEW ADVANCED ACTIONS ====================
 
 	case "add-dropdown", "add_dropdown":
 		sheet, _ := params["sheet"].(string)
 		rng, _ := params["range"].(string)
+		source, _ := params["source"].(string)
 		optionsRaw, _ := params["options"].([]interface{})
 		options := make([]string, len(optionsRaw))
 		for i, opt := range optionsRaw {
 			options[i] = fmt.Sprintf("%v", opt)
 		}
-		err := s.excelService.AddDropdownList(sheet, rng, options)
+		var err error
+		if source != "" {
+			err = s.excelService.AddValidation(sheet, rng, excel.DataValidationRule{Type: "list", Source: source})
+		} else {
+			err = s.excelService.AddDropdownList(sheet, rng, options)
+		}
 		if err != nil {
 			return "", err
 		}
 		return "DROPDOWN OK", nil
 
+	case "add-validation":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+
+		// Limites numéricos chegam como número, mas a regra guarda texto
+		for _, key := range []string{"value", "minValue", "maxValue"} {
+			if v, ok := params[key]; ok {
+				params[key] = getString(v)
+			}
+		}
+		if optionsRaw, ok := params["options"].([]interface{}); ok {
+			options := make([]string, len(optionsRaw))
+			for i, opt := range optionsRaw {
+				options[i] = getString(opt)
+			}
+			params["options"] = options
+		}
+		var rule excel.DataValidationRule
+		if err := decodeParams(params, &rule); err != nil {
+			return "", fmt.Errorf("regra de validação inválida: %w", err)
+		}
+		if err := s.excelService.AddValidation(sheet, rng, rule); err != nil {
+			return "", err
+		}
+		return "VALIDATION OK", nil
+
+	case "remove-validation":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+		removed, err := s.excelService.RemoveValidations(sheet, rng)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("REMOVE VALIDATION OK: %d validações alteradas", removed), nil
+
+	case "create-name":
+		sheet, _ := params["sheet"].(string)
+		var name excel.DefinedName
+		if err := decodeParams(params, &name); err != nil {
+			return "", fmt.Errorf("nome definido inválido: %w", err)
+		}
+		if err := s.excelService.CreateDefinedName(sheet, name); err != nil {
+			return "", err
+		}
+		return "NAME OK", nil
+
+	case "update-name":
+		sheet, _ := params["sheet"].(string)
+		name, _ := params["name"].(string)
+		scope, _ := params["scope"].(string)
+		newName, _ := params["newName"].(string)
+		refersTo, _ := params["refersTo"].(string)
+		comment, _ := params["comment"].(string)
+		changes := excel.DefinedName{Name: newName, RefersTo: refersTo, Comment: comment}
+		if err := s.excelService.UpdateDefinedName(sheet, name, scope, changes); err != nil {
+			return "", err
+		}
+		return "UPDATE NAME OK", nil
+
+	case "delete-name":
+		name, _ := params["name"].(string)
+		scope, _ := params["scope"].(string)
+		if err := s.excelService.DeleteDefinedName(name, scope); err != nil {
+			return "", err
+		}
+		return "DELETE NAME OK", nil
+
 	case "add-comment", "add_comment":
 		sheet, _ := params["sheet"].(string)
 		cell, _ := params["cell"].(string)
@@ -873,8 +1584,57 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		}
 		return "UNFREEZE PANE OK", nil
 
+	case "set-page-setup":
+		sheet, _ := params["sheet"].(string)
+
+		var setup excel.PageSetup
+		if err := decodeParams(params, &setup); err != nil {
+			return "", fmt.Errorf("configuração de página inválida: %w", err)
+		}
+		if err := s.excelService.SetPageSetup(sheet, setup); err != nil {
+			return "", err
+		}
+		return "PAGE SETUP OK", nil
+
+	case "insert-page-break", "remove-page-break":
+		sheet, _ := params["sheet"].(string)
+		cell, _ := params["cell"].(string)
+		// Atalhos: quebra antes de uma linha ou de uma coluna
+		if cell == "" {
+			row := getInt(params["row"])
+			column, _ := params["column"].(string)
+			switch {
+			case row > 0 && column != "":
+				cell = fmt.Sprintf("%s%d", column, row)
+			case row > 0:
+				cell = fmt.Sprintf("A%d", row)
+			case column != "":
+				cell = column + "1"
+			}
+		}
+		if op == "insert-page-break" {
+			if cell == "" {
+				return "", fmt.Errorf("informe cell, row ou column")
+			}
+			if err := s.excelService.InsertPageBreak(sheet, cell); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("INSERT PAGE BREAK OK: %s", cell), nil
+		}
+		if err := s.excelService.RemovePageBreak(sheet, cell); err != nil {
+			return "", err
+		}
+		return "REMOVE PAGE BREAK OK", nil
+
 	case "hide-sheet", "hide_sheet":
 		sheet, _ := params["sheet"].(string)
+		// veryHidden: a aba não aparece na lista de reexibir do Excel
+		if veryHidden, _ := params["veryHidden"].(bool); veryHidden {
+			if err := s.excelService.SetSheetVisibility(sheet, "veryHidden"); err != nil {
+				return "", err
+			}
+			return "HIDE SHEET OK: veryHidden", nil
+		}
 		err := s.excelService.HideSheet(sheet)
 		if err != nil {
 			return "", err
@@ -889,6 +1649,80 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		}
 		return "SHOW SHEET OK", nil
 
+	case "move-sheet":
+		sheet, _ := params["sheet"].(string)
+		position := getInt(params["position"])
+		// Alternativa à posição: antes ou depois de outra aba
+		before, _ := params["before"].(string)
+		after, _ := params["after"].(string)
+		if position == 0 && (before != "" || after != "") {
+			sheets, err := s.excelService.ListSheets()
+			if err != nil {
+				return "", err
+			}
+			from, target := -1, -1
+			for i, name := range sheets {
+				if strings.EqualFold(name, sheet) {
+					from = i
+				}
+				if strings.EqualFold(name, before) || strings.EqualFold(name, after) {
+					target = i
+				}
+			}
+			if from < 0 || target < 0 {
+				return "", fmt.Errorf("planilha não encontrada")
+			}
+			// Posição 1-based depois de retirar a aba movida da lista
+			position = target + 1
+			if after != "" {
+				position++
+			}
+			if from < target {
+				position--
+			}
+		}
+		if err := s.excelService.MoveSheet(sheet, position); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("MOVE SHEET OK: %s -> %d", sheet, position), nil
+
+	case "set-active-sheet":
+		sheet, _ := params["sheet"].(string)
+		if err := s.excelService.SetActiveSheet(sheet); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ACTIVE SHEET OK: %s", sheet), nil
+
+	case "set-tab-color":
+		sheet, _ := params["sheet"].(string)
+		color, _ := params["color"].(string)
+		if err := s.excelService.SetTabColor(sheet, color); err != nil {
+			return "", err
+		}
+		return "TAB COLOR OK", nil
+
+	case "set-sheet-view":
+		sheet, _ := params["sheet"].(string)
+
+		var view excel.SheetView
+		if err := decodeParams(params, &view); err != nil {
+			return "", fmt.Errorf("configuração de exibição inválida: %w", err)
+		}
+		if err := s.excelService.SetSheetView(sheet, view); err != nil {
+			return "", err
+		}
+		return "SHEET VIEW OK", nil
+
+	case "set-properties":
+		var props excel.WorkbookProperties
+		if err := decodeParams(params, &props); err != nil {
+			return "", fmt.Errorf("propriedades inválidas: %w", err)
+		}
+		if err := s.excelService.SetWorkbookProperties(props); err != nil {
+			return "", err
+		}
+		return "PROPERTIES OK", nil
+
 	case "protect-sheet", "protect_sheet":
 		sheet, _ := params["sheet"].(string)
 		password, _ := params["password"].(string)
@@ -928,23 +1762,70 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 		if err != nil {
 			return "", err
 		}
-		return "FORMULA SET OK", nil
+		if inMacro, _ := params[macroActionKey].(bool); inMacro {
+			return "FORMULA SET OK", nil
+		}
+
+		recalc, err := s.excelService.CalculateFormulas()
+		if err != nil {
+			return "", err
+		}
+		value := ""
+		if values, err := s.excelService.GetRangeValues(sheet, cell+":"+cell); err == nil && len(values) > 0 && len(values[0]) > 0 {
+			value = values[0][0]
+		}
+		return fmt.Sprintf("FORMULA SET OK: %s = %s\n%s", cell, value, formatRecalcResult(recalc)), nil
+
+	case "recalculate":
+		recalc, err := s.excelService.CalculateFormulas()
+		if err != nil {
+			return "", err
+		}
+		return formatRecalcResult(recalc), nil
 
 	case "conditional-format", "conditional_format":
 		sheet, _ := params["sheet"].(string)
 		rng, _ := params["range"].(string)
-		criteria, _ := params["criteria"].(string)
-		value, _ := params["value"].(string)
 		bgColor, _ := params["bgColor"].(string)
-		if bgColor == "" {
-			bgColor = "#FFFF00" // Yellow default
+
+		// Valores e limites da regra são texto, mesmo quando a IA envia números
+		for _, key := range []string{"value", "minValue", "midValue", "maxValue"} {
+			if v, ok := params[key]; ok {
+				params[key] = getString(v)
+			}
+		}
+		var rule excel.ConditionalFormatRule
+		if err := decodeParams(params, &rule); err != nil {
+			return "", fmt.Errorf("regra de formatação condicional inválida: %w", err)
+		}
+
+		var err error
+		if rule.Type == "" {
+			// Formato antigo: apenas critério, valor e cor de fundo
+			if bgColor == "" {
+				bgColor = "#FFFF00" // Yellow default
+			}
+			err = s.excelService.AddSimpleConditionalFormat(sheet, rng, rule.Criteria, rule.Value, bgColor)
+		} else {
+			if rule.Format == nil && bgColor != "" {
+				rule.Format = &excel.Format{BgColor: bgColor}
+			}
+			err = s.excelService.AddConditionalFormat(sheet, rng, rule)
 		}
-		err := s.excelService.AddSimpleConditionalFormat(sheet, rng, criteria, value, bgColor)
 		if err != nil {
 			return "", err
 		}
 		return "CONDITIONAL FORMAT OK", nil
 
+	case "remove-conditional-format":
+		sheet, _ := params["sheet"].(string)
+		rng, _ := params["range"].(string)
+		removed, err := s.excelService.RemoveConditionalFormats(sheet, rng)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("REMOVE CONDITIONAL FORMAT OK: %d regras removidas ou ajustadas", removed), nil
+
 	case "delete-pivot", "delete_pivot":
 		sheet, _ := params["sheet"].(string)
 		name, _ := params["name"].(string)
@@ -966,42 +1847,46 @@ func (s *Service) executeAction(params map[string]interface{}, onChunk func(stri
 	return "", fmt.Errorf("unknown action op: %s", op)
 }
 
-// normalizeAction converte uma ferramenta individual para o formato interno de operação (op)
-func normalizeAction(toolName string, args map[string]interface{}) map[string]interface{} {
-	if toolName == "" {
-		if args == nil {
-			return make(map[string]interface{})
-		}
-		return args
-	}
+// actionOp converte o nome de uma operação (ferramenta ou ação de macro) no op do executor
+func actionOp(name string) string {
+	// Normalizar nome (underscores -> dashes)
+	op := strings.ReplaceAll(name, "_", "-")
 
-	// 1. Normalizar nome (underscores -> dashes)
-	op := strings.ReplaceAll(toolName, "_", "-")
-
-	// 2. Mapeamentos específicos para o switch do executor
+	// Mapeamentos específicos para o switch do executor
 	switch op {
-	case "write-cell", "write-range", "write_cell", "write_range":
+	case "write-cell", "write-range":
 		op = "write"
 	case "autofit-columns":
 		op = "autofit"
 	case "create-pivot-table":
 		op = "create-pivot"
-	case "apply-filter":
-		op = "apply-filter"
+	case "sort-range":
+		op = "sort"
 	case "clear-filter":
 		op = "clear-filters"
 	}
+	return op
+}
+
+// normalizeAction converte uma ferramenta individual para o formato interno de operação (op)
+func normalizeAction(toolName string, args map[string]interface{}) map[string]interface{} {
+	if toolName == "" {
+		if args == nil {
+			return make(map[string]interface{})
+		}
+		return args
+	}
 
-	// 3. Construir payload
+	// 1. Construir payload
 	result := make(map[string]interface{})
-	result["op"] = op
+	result["op"] = actionOp(toolName)
 
 	// Se args for nil, não há nada para copiar ou aplanar
 	if args == nil {
 		return result
 	}
 
-	// 4. Se a IA mandou argumentos dentro de "args", aplanar
+	// 2. Se a IA mandou argumentos dentro de "args", aplanar
 	if innerArgs, ok := args["args"].(map[string]interface{}); ok {
 		for k, v := range innerArgs {
 			result[k] = v
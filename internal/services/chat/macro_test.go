@@ -0,0 +1,182 @@
+package chat
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/png"
+	"regexp"
+	"strings"
+	"testing"
+
+	"excel-ai/internal/services/excel"
+	"excel-ai/pkg/ai"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// macroExamples ações de execute_macro (no formato {tool, args} anunciado à IA) para cada
+// operação da descrição; as primeiras ações preparam o objeto usado pela última
+var macroExamples = map[string]string{
+	"create_sheet":              `[{"tool":"create_sheet","args":{"name":"Nova"}}]`,
+	"delete_sheet":              `[{"tool":"delete_sheet","args":{"name":"Resumo"}}]`,
+	"rename_sheet":              `[{"tool":"rename_sheet","args":{"oldName":"Resumo","newName":"Sumário"}}]`,
+	"copy_sheet":                `[{"tool":"copy_sheet","args":{"sheet":"Dados","name":"Cópia"}}]`,
+	"write_cell":                `[{"tool":"write_cell","args":{"sheet":"Dados","cell":"D1","value":"Total"}}]`,
+	"write_range":               `[{"tool":"write_range","args":{"sheet":"Dados","cell":"E1","data":[["Meta","Real"],["10","12"]]}}]`,
+	"clear_range":               `[{"tool":"clear_range","args":{"sheet":"Dados","range":"A2:C2"}}]`,
+	"copy_range":                `[{"tool":"copy_range","args":{"sheet":"Dados","source":"A1:C3","dest":"Resumo!A1"}}]`,
+	"move_range":                `[{"tool":"move_range","args":{"sheet":"Dados","source":"A1:C5","dest":"E1"}}]`,
+	"format_range":              `[{"tool":"format_range","args":{"sheet":"Dados","range":"A1:C1","bold":true,"bgColor":"#DDEBF7"}}]`,
+	"autofit_columns":           `[{"tool":"autofit_columns","args":{"sheet":"Dados","range":"A:C"}}]`,
+	"set_borders":               `[{"tool":"set_borders","args":{"sheet":"Dados","range":"A1:C5","style":"thin"}}]`,
+	"merge_cells":               `[{"tool":"merge_cells","args":{"sheet":"Resumo","range":"A1:B1"}}]`,
+	"conditional_format":        `[{"tool":"conditional_format","args":{"sheet":"Dados","range":"C2:C5","type":"cellValue","criteria":"greaterThan","value":"5"}}]`,
+	"remove_conditional_format": `[{"tool":"conditional_format","args":{"sheet":"Dados","range":"C2:C5","type":"colorScale"}},{"tool":"remove_conditional_format","args":{"sheet":"Dados","range":"C3"}}]`,
+	"insert_rows":               `[{"tool":"insert_rows","args":{"sheet":"Dados","row":2,"count":1}}]`,
+	"delete_rows":               `[{"tool":"delete_rows","args":{"sheet":"Dados","row":2,"count":1}}]`,
+	"insert_columns":            `[{"tool":"insert_columns","args":{"sheet":"Dados","after":"Cidade","headers":["Estado"]}}]`,
+	"delete_columns":            `[{"tool":"delete_columns","args":{"sheet":"Dados","column":"Cidade"}}]`,
+	"move_columns":              `[{"tool":"move_columns","args":{"sheet":"Dados","column":"Valor","before":"Produto"}}]`,
+	"group_columns":             `[{"tool":"group_columns","args":{"sheet":"Dados","column":"B:C"}}]`,
+	"ungroup_columns":           `[{"tool":"group_columns","args":{"sheet":"Dados","column":"B:C"}},{"tool":"ungroup_columns","args":{"sheet":"Dados","column":"B:C"}}]`,
+	"freeze_pane":               `[{"tool":"freeze_pane","args":{"sheet":"Dados","rows":1}}]`,
+	"unfreeze_pane":             `[{"tool":"freeze_pane","args":{"sheet":"Dados","rows":1}},{"tool":"unfreeze_pane","args":{"sheet":"Dados"}}]`,
+	"hide_sheet":                `[{"tool":"hide_sheet","args":{"sheet":"Resumo","veryHidden":true}}]`,
+	"show_sheet":                `[{"tool":"hide_sheet","args":{"sheet":"Resumo"}},{"tool":"show_sheet","args":{"sheet":"Resumo"}}]`,
+	"move_sheet":                `[{"tool":"move_sheet","args":{"sheet":"Resumo","position":1}}]`,
+	"set_active_sheet":          `[{"tool":"set_active_sheet","args":{"sheet":"Resumo"}}]`,
+	"set_tab_color":             `[{"tool":"set_tab_color","args":{"sheet":"Dados","color":"#1F4E78"}}]`,
+	"set_sheet_view":            `[{"tool":"set_sheet_view","args":{"sheet":"Dados","zoom":120,"gridlines":false}}]`,
+	"create_chart":              `[{"tool":"create_chart","args":{"sheet":"Dados","chartType":"column","title":"Vendas","series":[{"name":"C1","categories":"A2:A5","values":"C2:C5"}]}}]`,
+	"delete_chart":              `[{"tool":"create_chart","args":{"sheet":"Dados","chartType":"line","title":"Vendas","range":"A1:A5"}},{"tool":"delete_chart","args":{"sheet":"Dados","name":"Vendas"}}]`,
+	"create_pivot":              `[{"tool":"create_pivot","args":{"sourceSheet":"Dados","sourceRange":"A1:C5","destSheet":"Resumo","destCell":"A1","tableName":"Dinamica","rows":["Cidade"],"dataFields":[{"field":"Valor"}]}}]`,
+	"update_pivot":              `[{"tool":"create_pivot","args":{"sourceSheet":"Dados","sourceRange":"A1:C5","destSheet":"Resumo","destCell":"A1","tableName":"Dinamica","rows":["Cidade"],"dataFields":[{"field":"Valor"}]}},{"tool":"update_pivot","args":{"sheet":"Resumo","name":"Dinamica","rows":["Produto"]}}]`,
+	"delete_pivot":              `[{"tool":"create_pivot","args":{"sourceSheet":"Dados","sourceRange":"A1:C5","destSheet":"Resumo","destCell":"A1","tableName":"Dinamica","rows":["Cidade"],"dataFields":[{"field":"Valor"}]}},{"tool":"delete_pivot","args":{"sheet":"Resumo","name":"Dinamica"}}]`,
+	"insert_image":              `[{"tool":"insert_image","args":{"sheet":"Dados","cell":"F1","base64":"{{png}}"}}]`,
+	"delete_image":              `[{"tool":"insert_image","args":{"sheet":"Dados","cell":"F1","base64":"{{png}}"}},{"tool":"delete_image","args":{"sheet":"Dados","cell":"F1"}}]`,
+	"add_shape":                 `[{"tool":"add_shape","args":{"sheet":"Dados","cell":"F2","text":"Nota"}}]`,
+	"delete_shape":              `[{"tool":"add_shape","args":{"sheet":"Dados","cell":"F2","text":"Nota"}},{"tool":"delete_shape","args":{"sheet":"Dados","name":"F2"}}]`,
+	"create_table":              `[{"tool":"create_table","args":{"sheet":"Dados","range":"A1:C5","name":"Vendas"}}]`,
+	"delete_table":              `[{"tool":"create_table","args":{"sheet":"Dados","range":"A1:C5","name":"Vendas"}},{"tool":"delete_table","args":{"sheet":"Dados","name":"Vendas"}}]`,
+	"append_table_rows":         `[{"tool":"create_table","args":{"sheet":"Dados","range":"A1:C5","name":"Vendas"}},{"tool":"append_table_rows","args":{"table":"Vendas","row":{"Produto":"Régua","Valor":7}}}]`,
+	"resize_table":              `[{"tool":"create_table","args":{"sheet":"Dados","range":"A1:C5","name":"Vendas"}},{"tool":"resize_table","args":{"name":"Vendas","range":"A1:C4"}}]`,
+	"set_table_totals":          `[{"tool":"create_table","args":{"sheet":"Dados","range":"A1:C5","name":"Vendas"}},{"tool":"set_table_totals","args":{"name":"Vendas"}}]`,
+	"apply_filter":              `[{"tool":"apply_filter","args":{"sheet":"Dados","range":"A1:C5","criteria":[{"column":"Cidade","operator":"equals","value":"SP"}]}}]`,
+	"clear_filter":              `[{"tool":"apply_filter","args":{"sheet":"Dados","range":"A1:C5","criteria":[{"column":"Cidade","operator":"equals","value":"SP"}]}},{"tool":"clear_filter","args":{"sheet":"Dados"}}]`,
+	"sort_range":                `[{"tool":"sort_range","args":{"sheet":"Dados","range":"A1:C5","header":true,"keys":[{"column":3,"order":"desc"}]}}]`,
+	"add_dropdown":              `[{"tool":"add_dropdown","args":{"sheet":"Dados","range":"B2:B5","options":["SP","RJ","MG"]}}]`,
+	"add_validation":            `[{"tool":"add_validation","args":{"sheet":"Dados","range":"C2:C5","type":"decimal","operator":"greaterThan","value":0}}]`,
+	"remove_validation":         `[{"tool":"add_validation","args":{"sheet":"Dados","range":"C2:C5","type":"decimal","operator":"greaterThan","value":0}},{"tool":"remove_validation","args":{"sheet":"Dados","range":"C2:C5"}}]`,
+	"create_name":               `[{"tool":"create_name","args":{"sheet":"Dados","name":"Valores","refersTo":"C2:C5"}}]`,
+	"update_name":               `[{"tool":"create_name","args":{"sheet":"Dados","name":"Valores","refersTo":"C2:C5"}},{"tool":"update_name","args":{"name":"Valores","newName":"Precos"}}]`,
+	"delete_name":               `[{"tool":"create_name","args":{"sheet":"Dados","name":"Valores","refersTo":"C2:C5"}},{"tool":"delete_name","args":{"name":"Valores"}}]`,
+	"add_comment":               `[{"tool":"add_comment","args":{"sheet":"Dados","cell":"A1","author":"Ana","text":"Revisar"}}]`,
+	"delete_comment":            `[{"tool":"add_comment","args":{"sheet":"Dados","cell":"A1","author":"Ana","text":"Revisar"}},{"tool":"delete_comment","args":{"sheet":"Dados","cell":"A1"}}]`,
+	"add_hyperlink":             `[{"tool":"add_hyperlink","args":{"sheet":"Dados","cell":"A2","url":"https://example.com","display":"Site"}}]`,
+	"protect_sheet":             `[{"tool":"protect_sheet","args":{"sheet":"Dados"}}]`,
+	"unprotect_sheet":           `[{"tool":"protect_sheet","args":{"sheet":"Dados","password":"123"}},{"tool":"unprotect_sheet","args":{"sheet":"Dados","password":"123"}}]`,
+	"lock_cell":                 `[{"tool":"lock_cell","args":{"sheet":"Dados","cell":"A1","locked":true}}]`,
+	"set_page_setup":            `[{"tool":"set_page_setup","args":{"sheet":"Dados","orientation":"landscape","paperSize":"A4","titleRows":"1:1"}}]`,
+	"insert_page_break":         `[{"tool":"insert_page_break","args":{"sheet":"Dados","row":3}}]`,
+	"remove_page_break":         `[{"tool":"insert_page_break","args":{"sheet":"Dados","row":3}},{"tool":"remove_page_break","args":{"sheet":"Dados","row":3}}]`,
+	"set_properties":            `[{"tool":"set_properties","args":{"title":"Vendas","author":"Ana","custom":{"Projeto":"Orçamento"}}}]`,
+	"set_formula":               `[{"tool":"set_formula","args":{"sheet":"Dados","cell":"C6","formula":"=SUM(C2:C5)"}}]`,
+	"recalculate":               `[{"tool":"set_formula","args":{"sheet":"Dados","cell":"C6","formula":"=SUM(C2:C5)"}},{"tool":"recalculate"}]`,
+}
+
+// macroOperationList operações listadas na descrição de execute_macro (sem os comentários entre parênteses)
+func macroOperationList(t *testing.T) []string {
+	t.Helper()
+	var description string
+	for _, tool := range ai.GetExcelTools() {
+		if tool.Function.Name == "execute_macro" {
+			description = tool.Function.Description
+		}
+	}
+	if description == "" {
+		t.Fatal("execute_macro não encontrada em ai.GetExcelTools")
+	}
+
+	var ops []string
+	for _, line := range strings.Split(description, "\n")[1:] {
+		group := strings.SplitN(line, ": ", 2)
+		if len(group) != 2 || strings.ToUpper(group[0]) != group[0] {
+			continue
+		}
+		for _, op := range strings.Split(regexp.MustCompile(`\([^)]*\)`).ReplaceAllString(group[1], ""), ",") {
+			ops = append(ops, strings.TrimSpace(op))
+		}
+	}
+	return ops
+}
+
+// newMacroTestService conecta o chat a uma pasta com as planilhas Dados (tabela de vendas) e Resumo
+func newMacroTestService(t *testing.T) *Service {
+	t.Helper()
+	file := excelize.NewFile()
+	file.SetSheetName("Sheet1", "Dados")
+	file.NewSheet("Resumo")
+	rows := [][]interface{}{
+		{"Produto", "Cidade", "Valor"},
+		{"Caneta", "SP", 10},
+		{"Lápis", "RJ", 5},
+		{"Caderno", "SP", 20},
+		{"Borracha", "MG", 3},
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := file.SetSheetRow("Dados", cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buffer, err := file.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	excelSvc := excel.NewService()
+	if err := excelSvc.ConnectFileWithName("teste", "vendas.xlsx", buffer.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(excelSvc.Close)
+	// Sem NewService: o executor não usa o orquestrador nem o cache em disco
+	chatSvc := &Service{}
+	chatSvc.SetExcelService(excelSvc)
+	return chatSvc
+}
+
+func TestExecuteMacroOperations(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	pngData := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	ops := macroOperationList(t)
+	if len(ops) < len(macroExamples) {
+		t.Errorf("descrição de execute_macro lista %d operações, esperado ao menos %d", len(ops), len(macroExamples))
+	}
+	for _, op := range ops {
+		t.Run(op, func(t *testing.T) {
+			example, ok := macroExamples[op]
+			if !ok {
+				t.Fatalf("sem exemplo de execute_macro para %s", op)
+			}
+			var actions []interface{}
+			if err := json.Unmarshal([]byte(strings.ReplaceAll(example, "{{png}}", pngData)), &actions); err != nil {
+				t.Fatalf("exemplo inválido: %v", err)
+			}
+
+			s := newMacroTestService(t)
+			result, err := s.ExecuteToolCall("execute_macro", map[string]interface{}{"actions": actions})
+			if err != nil {
+				t.Fatalf("execute_macro falhou: %v", err)
+			}
+			if !strings.Contains(result, "MACRO") {
+				t.Errorf("resultado inesperado: %s", result)
+			}
+		})
+	}
+}
@@ -1,16 +1,49 @@
 package chat
 
 import (
+	"excel-ai/internal/domain"
 	"excel-ai/internal/dto"
+	"excel-ai/pkg/ai"
+	"excel-ai/pkg/logger"
 )
 
+// GetAvailableModels lista os modelos do provedor atual.
+// apiKey e baseURL vazios usam os valores já configurados.
 func (s *Service) GetAvailableModels(apiKey, baseURL string) []dto.ModelInfo {
-	// Apenas Z.AI é suportado
-	return []dto.ModelInfo{
-		{ID: "glm-4.7", Name: "GLM-4.7", Description: "Latest flagship model - optimized for coding", ContextLength: 128000, PricePrompt: "¥2.5/1M", PriceComplete: "¥10/1M"},
-		{ID: "glm-4.6v", Name: "GLM-4.6V", Description: "Vision model with multimodal capabilities", ContextLength: 128000, PricePrompt: "¥2/1M", PriceComplete: "¥8/1M"},
-		{ID: "glm-4.6", Name: "GLM-4.6", Description: "Balanced model with native function calling", ContextLength: 128000, PricePrompt: "¥1.5/1M", PriceComplete: "¥6/1M"},
-		{ID: "glm-4.5", Name: "GLM-4.5", Description: "Cost-effective model with function calling", ContextLength: 128000, PricePrompt: "¥1/1M", PriceComplete: "¥4/1M"},
-		{ID: "glm-4.5-air", Name: "GLM-4.5 Air", Description: "Lightweight model for fast responses", ContextLength: 128000, PricePrompt: "¥0.5/1M", PriceComplete: "¥2/1M"},
+	s.mu.Lock()
+	cfg := s.providerCfg
+	s.mu.Unlock()
+
+	if apiKey != "" {
+		cfg.APIKey = apiKey
+	}
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+
+	var models []domain.ModelInfo
+	provider, err := ai.NewProvider(cfg)
+	if err == nil {
+		models, err = provider.GetAvailableModels()
+	}
+	if err != nil {
+		logger.ChatError("Erro ao listar modelos: " + err.Error())
+	}
+	if len(models) == 0 && cfg.Provider == ai.ProviderZAI {
+		// Z.AI não tem endpoint /models funcional
+		models, _ = ai.NewZAIClient("", "").GetAvailableModels()
+	}
+
+	result := make([]dto.ModelInfo, 0, len(models))
+	for _, m := range models {
+		result = append(result, dto.ModelInfo{
+			ID:            m.ID,
+			Name:          m.Name,
+			Description:   m.Description,
+			ContextLength: m.ContextLength,
+			PricePrompt:   m.Pricing.Prompt,
+			PriceComplete: m.Pricing.Completion,
+		})
 	}
+	return result
 }
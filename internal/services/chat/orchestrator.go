@@ -90,7 +90,8 @@ type Orchestrator struct {
 
 	// Estado
 	running      bool
-	pendingTasks map[string]*Task
+	pendingTasks map[string]*Task // Chave: ID da tarefa com o prefixo da execução
+	runSeq       int              // Execuções do grafo de tarefas (prefixo dos IDs)
 
 	// Balanceamento dinâmico
 	activeWorkers int
@@ -138,12 +139,18 @@ type Orchestrator struct {
 
 // Task representa uma tarefa a ser executada
 type Task struct {
-	ID        string
-	Type      TaskType
-	ToolName  string
-	Arguments map[string]interface{}
-	Priority  int // Menor = maior prioridade
-	CreatedAt time.Time
+	ID          string
+	Type        TaskType
+	ToolName    string
+	Arguments   map[string]interface{}
+	Priority    int // Menor = maior prioridade
+	Description string
+	DependsOn   []string // IDs das tarefas que precisam terminar antes desta
+	CreatedAt   time.Time
+
+	// Preenchidos apenas quando a tarefa é despachada por executeTaskGraph
+	done       chan *TaskResult
+	onProgress func(string) error
 }
 
 // TaskType define o tipo da tarefa
@@ -302,7 +309,8 @@ func (o *Orchestrator) Stop() {
 	fmt.Println("[ORCHESTRATOR] 🛑 Parado")
 }
 
-// OrchestrateMessage processa uma mensagem do usuário usando múltiplos modelos
+// OrchestrateMessage processa uma mensagem do usuário usando múltiplos modelos.
+// Chamado por Service.SendMessage, que já mantém s.mu.
 func (o *Orchestrator) OrchestrateMessage(
 	message string,
 	contextStr string,
@@ -321,22 +329,20 @@ func (o *Orchestrator) OrchestrateMessage(
 	if len(tasks) == 0 {
 		// Nenhuma tarefa específica - delegar para modelo principal
 		onChunk("\n💬 [Orquestrador] Nenhuma tarefa específica, usando modelo principal...\n")
-		return o.service.SendMessage(message, contextStr, askBeforeApply, onChunk)
+		return o.service.sendMessageLocked(message, contextStr, askBeforeApply, onChunk)
 	}
 
-	// Passo 2: Enviar tarefas para execução paralela
+	// Passo 2: Enviar tarefas para execução respeitando as dependências
 	onChunk(fmt.Sprintf("\n📋 [Orquestrador] %d tarefas identificadas para execução paralela\n", len(tasks)))
 
 	var wg sync.WaitGroup
-	results := make([]*TaskResult, len(tasks))
+	var results []*TaskResult
 
-	for i, task := range tasks {
-		wg.Add(1)
-		go func(idx int, t *Task) {
-			defer wg.Done()
-			results[idx] = o.executeTask(t, onChunk)
-		}(i, task)
-	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results = o.executeTaskGraph(tasks, onChunk)
+	}()
 
 	// Passo 3: Enviar mensagens do orquestrador enquanto aguarda
 	o.sendOrchestrationMessages(orchestrationPrompt, onChunk)
@@ -395,10 +401,12 @@ SUA FUNÇÃO:
 FORMATO DE RETORNO (JSON ARRAY):
 [
   {
+    "id": "t1",
     "tool": "nome_da_ferramenta",
     "args": {parâmetros},
     "priority": 1,
-    "description": "o que fazer"
+    "description": "o que fazer",
+    "depends_on": []
   }
 ]
 
@@ -413,6 +421,7 @@ REGRAS:
 - Consultas (query_*) podem rodar em paralelo
 - Ações (write_*, create_*, delete_*) podem rodar em paralelo se forem em células/planilhas diferentes
 - Prioridade 1 = urgente, 2 = normal, 3 = baixa
+- Use "depends_on" com os "id" das tarefas que precisam terminar antes (ex: ler dados antes de escrever o resumo)
 
 RETORNE APENAS O JSON ARRAY, sem explicações adicionais.
 `, message, contextStr)
@@ -426,40 +435,35 @@ RETORNE APENAS O JSON ARRAY, sem explicações adicionais.
 		{Role: "user", Content: orchestrationPrompt},
 	}
 
-	var responseBuilder strings.Builder
-	response, err := o.service.zaiClient.ChatStream(ctx, messages, func(chunk string) error {
-		responseBuilder.WriteString(chunk)
-		return nil
-	})
-	if err != nil {
-		return nil, orchestrationPrompt, err
-	}
-
-	// Parsear resposta para extrair tarefas
-	tasks, err := o.parseTasks(response)
-	if err != nil {
-		onChunk(fmt.Sprintf("⚠️ [Orquestrador] Erro ao parsear tarefas: %v\n", err))
-		return nil, orchestrationPrompt, nil
-	}
-
-	return tasks, orchestrationPrompt, nil
-}
+	// Entradas inválidas voltam para o modelo como erro estruturado para correção
+	for attempt := 0; attempt <= maxTaskParseRetries; attempt++ {
+		response, err := o.service.provider.ChatStream(ctx, messages, func(chunk string) error {
+			return nil
+		})
+		if err != nil {
+			return nil, orchestrationPrompt, err
+		}
 
-// parseTasks extrai tarefas da resposta do orquestrador
-func (o *Orchestrator) parseTasks(response string) ([]*Task, error) {
-	// Implementação simplificada - em produção usaria JSON parser robusto
-	var tasks []*Task
+		// Parsear resposta para extrair tarefas
+		tasks, err := o.parseTasks(response)
+		if err == nil {
+			return tasks, orchestrationPrompt, nil
+		}
 
-	// Exemplo: extrair tool calls do JSON da resposta
-	// Aqui seria o código para parsear o JSON array
+		parseErr, ok := err.(*TaskParseError)
+		if !ok || attempt == maxTaskParseRetries {
+			onChunk(fmt.Sprintf("⚠️ [Orquestrador] Erro ao parsear tarefas: %v\n", err))
+			return nil, orchestrationPrompt, nil
+		}
 
-	// Por enquanto, vamos extrair manualmente com regex ou substring
-	if strings.Contains(response, "[") && strings.Contains(response, "]") {
-		// Tem formato JSON - implementar parseamento real
-		// ...
+		onChunk(fmt.Sprintf("⚠️ [Orquestrador] %d tarefa(s) inválida(s), solicitando correção...\n", len(parseErr.Issues)))
+		messages = append(messages,
+			ai.Message{Role: "assistant", Content: response},
+			ai.Message{Role: "user", Content: parseErr.Feedback()},
+		)
 	}
 
-	return tasks, nil
+	return nil, orchestrationPrompt, nil
 }
 
 // executeTask executa uma única tarefa com suporte a cache
@@ -503,6 +507,17 @@ func (o *Orchestrator) executeTask(task *Task, reportProgress func(string) error
 		o.setInCache(task.ToolName, task.Arguments, result)
 	}
 
+	// Ações alteram a planilha (mesmo quando falham no meio de uma macro): consultas
+	// em cache das abas afetadas ficam obsoletas
+	if task.Type == TaskTypeAction {
+		o.invalidateCacheForAction(task.ToolName, task.Arguments)
+		for _, fp := range taskFootprints(task) {
+			if fp.sheet != "" {
+				o.cache.Invalidate([]string{fmt.Sprintf("sheet:%s", fp.sheet)})
+			}
+		}
+	}
+
 	// Atualizar estatísticas
 	o.muStats.Lock()
 	o.activeWorkers--
@@ -617,11 +632,21 @@ func (o *Orchestrator) worker(id int) {
 	for task := range o.taskChan {
 		fmt.Printf("[ORCHESTRATOR] Worker %d processando tarefa %s\n", id, task.ID)
 
-		result := o.executeTask(task, func(msg string) error {
-			o.messageChan <- msg
-			return nil
-		})
+		reportProgress := task.onProgress
+		if reportProgress == nil {
+			reportProgress = func(msg string) error {
+				o.messageChan <- msg
+				return nil
+			}
+		}
+
+		result := o.executeTask(task, reportProgress)
 		o.resultChan <- result
+
+		// Tarefas despachadas pelo grafo aguardam o resultado diretamente
+		if task.done != nil {
+			task.done <- result
+		}
 	}
 
 	fmt.Printf("[ORCHESTRATOR] Worker %d finalizado\n", id)
@@ -716,7 +741,7 @@ REGRAS:
 	}
 
 	var responseBuilder strings.Builder
-	response, err := o.service.zaiClient.ChatStream(ctx, messages, func(chunk string) error {
+	response, err := o.service.provider.ChatStream(ctx, messages, func(chunk string) error {
 		responseBuilder.WriteString(chunk)
 		return onChunk(chunk)
 	})
@@ -12,7 +12,8 @@ import (
 )
 
 type Service struct {
-	zaiClient     *ai.ZAIClient    // Cliente nativo Z.AI para GLM models
+	provider      ai.Provider       // Cliente LLM do provedor configurado
+	providerCfg   ai.ProviderConfig // Configuração usada para construir o provider
 	storage       *storage.Storage
 	mu            sync.Mutex
 	cancelMu      sync.Mutex // Mutex separado para cancelFunc (evita deadlock)
@@ -29,13 +30,17 @@ type Service struct {
 	pendingAction     *ToolCommand
 	pendingContextStr string
 	pendingOnChunk    func(string) error
+
+	// Observador opcional dos resultados de ferramentas (usado pelo modo CLI)
+	toolResultListener func(toolName, result string, err error)
 }
 
 func NewService(storage *storage.Storage) *Service {
 	logger.ChatInfo("Inicializando chat service com Z.AI")
-	
+
 	svc := &Service{
-		zaiClient:        ai.NewZAIClient("", ""),    // Cliente nativo Z.AI
+		provider:         ai.NewZAIClient("", ""), // Z.AI até a configuração ser carregada
+		providerCfg:      ai.ProviderConfig{Provider: ai.ProviderZAI},
 		storage:          storage,
 		chatHistory:      []domain.Message{},
 		useOrchestration: false, // Desabilitado por padrão
@@ -55,29 +60,75 @@ func NewService(storage *storage.Storage) *Service {
 }
 
 func (s *Service) SetAPIKey(apiKey string) {
-	logger.ChatInfo("Atualizando API key Z.AI")
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.zaiClient.SetAPIKey(apiKey)
+	logger.ChatInfo("Atualizando API key do provedor " + s.providerCfg.Provider)
+	s.providerCfg.APIKey = apiKey
+	s.rebuildProvider()
 }
 
 func (s *Service) SetModel(modelID string) {
-	logger.ChatInfo("Atualizando modelo Z.AI: " + modelID)
+	logger.ChatInfo("Atualizando modelo: " + modelID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providerCfg.Model = modelID
+	s.rebuildProvider()
+}
+
+// GetModel retorna o modelo configurado
+func (s *Service) GetModel() string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.zaiClient.SetModel(modelID)
+	return s.providerCfg.Model
 }
 
 func (s *Service) SetBaseURL(url string) {
-	logger.ChatInfo("Atualizando base URL Z.AI: " + url)
+	logger.ChatInfo("Atualizando base URL: " + url)
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.zaiClient.SetBaseURL(url)
+	s.providerCfg.BaseURL = url
+	s.rebuildProvider()
+}
+
+// SetProvider troca o provedor de LLM ("zai", "openai", "openrouter", "groq", "google", "anthropic", "custom")
+func (s *Service) SetProvider(name string) {
+	logger.ChatInfo("Atualizando provedor: " + name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providerCfg.Provider = ai.NormalizeProviderName(name)
+	s.rebuildProvider()
+}
+
+// rebuildProvider recria o cliente a partir de providerCfg (chamar com s.mu travado).
+// Em caso de erro mantém o cliente anterior.
+func (s *Service) rebuildProvider() {
+	provider, err := ai.NewProvider(s.providerCfg)
+	if err != nil {
+		logger.ChatError("Erro ao configurar provedor: " + err.Error())
+		return
+	}
+	s.provider = provider
+}
+
+// SetToolResultListener registra uma função chamada após cada execução de ferramenta
+func (s *Service) SetToolResultListener(listener func(toolName, result string, err error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolResultListener = listener
+}
+
+// notifyToolResult repassa o resultado ao listener, se houver (chamar com s.mu travado)
+func (s *Service) notifyToolResult(toolName, result string, err error) {
+	if s.toolResultListener != nil {
+		s.toolResultListener(toolName, result, err)
+	}
 }
 
 // RefreshConfig recarrega configurações do storage
 func (s *Service) RefreshConfig() {
 	logger.ChatInfo("Recarregando configurações")
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.refreshConfig()
 }
 
@@ -19,12 +19,16 @@ func (s *Service) SendMessage(message string, contextStr string, askBeforeApply
 	if s.useOrchestration {
 		return s.orchestrator.OrchestrateMessage(message, contextStr, askBeforeApply, onChunk)
 	}
+	return s.sendMessageLocked(message, contextStr, askBeforeApply, onChunk)
+}
 
+// sendMessageLocked conversa com o modelo principal, sem o orquestrador (chamar com s.mu)
+func (s *Service) sendMessageLocked(message string, contextStr string, askBeforeApply bool, onChunk func(string) error) (string, error) {
 	s.refreshConfig()
 
-	// Verificar API key Z.AI
-	if s.zaiClient.GetAPIKey() == "" {
-		return "", fmt.Errorf("API key não configurada. Vá em Configurações e configure sua chave de API do Z.AI")
+	// Verificar API key do provedor
+	if !s.hasCredentials() {
+		return "", fmt.Errorf("API key não configurada. Vá em Configurações e configure sua chave de API do provedor %s", s.providerCfg.Provider)
 	}
 
 	if s.currentConvID == "" {
@@ -88,7 +92,7 @@ func (s *Service) SendMessage(message string, contextStr string, askBeforeApply
 		}
 
 		// Usar cliente nativo Z.AI
-		currentResponse, toolCalls, err = s.zaiClient.ChatStreamWithTools(ctx, aiHistory, tools, chunkWrapper)
+		currentResponse, toolCalls, err = s.provider.ChatStreamWithTools(ctx, aiHistory, tools, chunkWrapper)
 
 		if err != nil {
 			return finalResponse, err
@@ -147,6 +151,7 @@ func (s *Service) SendMessage(message string, contextStr string, askBeforeApply
 			args, parseErr := tc.ParseArguments()
 			if parseErr != nil {
 				executionResults = append(executionResults, fmt.Sprintf("ERROR parsing %s: %v", tc.Function.Name, parseErr))
+				s.notifyToolResult(tc.Function.Name, "", parseErr)
 				continue
 			}
 
@@ -175,6 +180,7 @@ func (s *Service) SendMessage(message string, contextStr string, askBeforeApply
 
 			// Executar ferramenta
 			result, execErr := s.executeToolCall(tc.Function.Name, args, onChunk)
+			s.notifyToolResult(tc.Function.Name, result, execErr)
 			if execErr != nil {
 				executionResults = append(executionResults, fmt.Sprintf("ERROR %s: %v", tc.Function.Name, execErr))
 				onChunk(fmt.Sprintf("\n❌ Erro em %s: %v\n", tc.Function.Name, execErr))
@@ -225,7 +231,7 @@ func (s *Service) SendErrorFeedback(errorMessage string, onChunk func(string) er
 	var response string
 	var err error
 
-	response, err = s.zaiClient.ChatStream(ctx, aiHistory, func(c string) error {
+	response, err = s.provider.ChatStream(ctx, aiHistory, func(c string) error {
 		response += c
 		return onChunk(c)
 	})
@@ -306,9 +312,11 @@ func (s *Service) ConfirmPendingAction(onChunk func(string) error) (string, erro
 			// Fallback para sistema antigo (para compatibilidade)
 			result, err = s.ExecuteTool(*cmd, onChunk)
 		}
+		s.notifyToolResult(toolName, result, err)
 	} else {
 		// Fallback para sistema antigo
 		result, err = s.ExecuteTool(*cmd, onChunk)
+		s.notifyToolResult("", result, err)
 	}
 
 	var executionResults string
@@ -356,7 +364,7 @@ func (s *Service) ConfirmPendingAction(onChunk func(string) error) (string, erro
 		return onChunk(chunk)
 	}
 
-	currentResponse, toolCalls, err = s.zaiClient.ChatStreamWithTools(ctx, aiHistory, tools, chunkWrapper)
+	currentResponse, toolCalls, err = s.provider.ChatStreamWithTools(ctx, aiHistory, tools, chunkWrapper)
 
 	if err != nil {
 		return currentResponse, err
@@ -431,42 +439,45 @@ func (s *Service) RejectPendingAction() {
 func (s *Service) refreshConfig() {
 	if s.storage != nil {
 		if cfg, err := s.storage.LoadConfig(); err == nil && cfg != nil {
-			fmt.Printf("[DEBUG refreshConfig] Provider: zai, APIKey presente: %v, Model: %s, ToolModel: %s, BaseURL: %s\n",
-				cfg.APIKey != "", cfg.Model, cfg.ToolModel, cfg.BaseURL)
+			provider := ai.NormalizeProviderName(cfg.Provider)
+			fmt.Printf("[DEBUG refreshConfig] Provider: %s, APIKey presente: %v, Model: %s, ToolModel: %s, BaseURL: %s\n",
+				provider, cfg.APIKey != "", cfg.Model, cfg.ToolModel, cfg.BaseURL)
 
-			// Z.AI (GLM Models) - Usar cliente nativo com Coding API
-			if cfg.APIKey != "" {
-				s.zaiClient.SetAPIKey(cfg.APIKey)
-			}
+			model := cfg.Model
 			if cfg.ToolModel != "" {
-				s.zaiClient.SetModel(cfg.ToolModel)
-			} else if cfg.Model != "" {
-				s.zaiClient.SetModel(cfg.Model)
+				model = cfg.ToolModel
 			}
 
-			// SEMPRE usar Coding API
-			baseURL := cfg.BaseURL
-			if baseURL == "" || baseURL == "https://api.z.ai/api/paas/v4" || baseURL == "https://api.z.ai/api/paas/v4/" {
-				baseURL = "https://api.z.ai/api/coding/paas/v4/"
-			}
-			// Garantir que a URL termina com barra
-			if !strings.HasSuffix(baseURL, "/") {
-				baseURL += "/"
+			baseURL := strings.TrimSpace(cfg.BaseURL)
+			maxTokens := 0
+			if provider == ai.ProviderZAI {
+				// Z.AI: SEMPRE usar Coding API
+				if baseURL == "" || baseURL == "https://api.z.ai/api/paas/v4" || baseURL == "https://api.z.ai/api/paas/v4/" {
+					baseURL = "https://api.z.ai/api/coding/paas/v4/"
+				}
+				maxTokens = 128000 // GLM models have 128k context
 			}
-			s.zaiClient.SetBaseURL(baseURL)
-			s.zaiClient.SetMaxInputTokens(128000) // GLM models have 128k context
 
-			// Log detalhado para debug
-			apiKeyLen := 0
-			if cfg.APIKey != "" {
-				apiKeyLen = len(cfg.APIKey)
+			s.providerCfg = ai.ProviderConfig{
+				Provider:       provider,
+				APIKey:         cfg.APIKey,
+				Model:          model,
+				BaseURL:        baseURL,
+				MaxInputTokens: maxTokens,
 			}
-			fmt.Printf("[Z.AI] Configurado com URL: %s, Context: 128k tokens\n", baseURL)
-			fmt.Printf("[Z.AI] API Key presente: %v, Comprimento: %d, Model: %s\n", cfg.APIKey != "", apiKeyLen, cfg.Model)
+			s.rebuildProvider()
+
+			fmt.Printf("[%s] Configurado com URL: %s, Model: %s, API Key presente: %v\n",
+				provider, baseURL, model, cfg.APIKey != "")
 		}
 	}
 }
 
+// hasCredentials indica se o provedor atual pode ser chamado (local/custom aceitam endpoints sem chave)
+func (s *Service) hasCredentials() bool {
+	return s.providerCfg.APIKey != "" || !ai.RequiresAPIKey(s.providerCfg.Provider)
+}
+
 func (s *Service) ensureSystemPrompt() {
 	systemPrompt := `Você é um AGENTE Excel profissional e direto. Seu objetivo é ajudar o usuário com planilhas de forma eficiente.
 
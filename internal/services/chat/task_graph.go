@@ -0,0 +1,683 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTaskParseRetries limita quantas vezes o modelo pode corrigir uma lista de tarefas inválida
+const maxTaskParseRetries = 1
+
+// orchestratorTools lista as ferramentas aceitas em uma tarefa do orquestrador
+var orchestratorTools = map[string]TaskType{
+	"list_sheets":        TaskTypeQuery,
+	"sheet_exists":       TaskTypeQuery,
+	"get_headers":        TaskTypeQuery,
+	"get_range_values":   TaskTypeQuery,
+	"get_cell_formula":   TaskTypeQuery,
+	"get_active_cell":    TaskTypeQuery,
+	"query_batch":        TaskTypeQuery,
+	"execute_macro":      TaskTypeAction,
+	"write_cell":         TaskTypeAction,
+	"write_range":        TaskTypeAction,
+	"create_sheet":       TaskTypeAction,
+	"delete_sheet":       TaskTypeAction,
+	"rename_sheet":       TaskTypeAction,
+	"format_range":       TaskTypeAction,
+	"autofit_columns":    TaskTypeAction,
+	"clear_range":        TaskTypeAction,
+	"insert_rows":        TaskTypeAction,
+	"delete_rows":        TaskTypeAction,
+	"merge_cells":        TaskTypeAction,
+	"set_borders":        TaskTypeAction,
+	"create_chart":       TaskTypeAction,
+	"create_pivot_table": TaskTypeAction,
+	"apply_filter":       TaskTypeAction,
+	"sort_range":         TaskTypeAction,
+}
+
+// sheetLevelTools são ações que afetam a aba inteira (ou a lista de abas)
+var sheetLevelTools = map[string]bool{
+	"create_sheet":   true,
+	"delete_sheet":   true,
+	"rename_sheet":   true,
+	"insert_rows":    true,
+	"delete_rows":    true,
+	"insert_columns": true,
+	"delete_columns": true,
+	"move_columns":   true,
+	"hide_sheet":     true,
+	"show_sheet":     true,
+}
+
+// TaskIssue descreve um problema em uma entrada da lista de tarefas
+type TaskIssue struct {
+	Index   int    `json:"index"` // Posição no array (base 0), -1 para o array inteiro
+	ID      string `json:"id,omitempty"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// TaskParseError agrupa os problemas encontrados ao interpretar a lista de tarefas
+type TaskParseError struct {
+	Issues []TaskIssue
+}
+
+func (e *TaskParseError) Error() string {
+	parts := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		parts = append(parts, fmt.Sprintf("[%d].%s: %s", issue.Index, issue.Field, issue.Message))
+	}
+	return fmt.Sprintf("%d problema(s) na lista de tarefas: %s", len(e.Issues), strings.Join(parts, "; "))
+}
+
+// Feedback monta a mensagem devolvida ao modelo para que ele corrija a lista
+func (e *TaskParseError) Feedback() string {
+	payload, _ := json.MarshalIndent(map[string]interface{}{
+		"error":  "invalid_tasks",
+		"issues": e.Issues,
+	}, "", "  ")
+
+	return fmt.Sprintf(`A lista de tarefas contém entradas inválidas:
+
+%s
+
+Corrija os problemas e retorne novamente o JSON ARRAY COMPLETO de tarefas, sem explicações adicionais.`, string(payload))
+}
+
+// rawTask representa uma entrada do JSON retornado pelo modelo antes da validação
+type rawTask struct {
+	id        string
+	tool      string
+	args      map[string]interface{}
+	priority  int
+	desc      string
+	dependsOn []interface{}
+}
+
+// parseTasks extrai tarefas da resposta do orquestrador.
+// Cada entrada aceita tool, args, priority, description e os campos opcionais id e depends_on.
+// depends_on referencia ids de outras tarefas (números são tratados como posições base 1).
+// Ações que tocam ranges sobrepostos recebem dependências implícitas na ordem do array.
+func (o *Orchestrator) parseTasks(response string) ([]*Task, error) {
+	entries, err := extractTaskArray(response)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	var issues []TaskIssue
+	raws := make([]rawTask, len(entries))
+	ids := make(map[string]int, len(entries))
+
+	for i, entry := range entries {
+		raw, entryIssues := o.validateTaskEntry(i, entry)
+		issues = append(issues, entryIssues...)
+
+		if prev, dup := ids[raw.id]; dup {
+			issues = append(issues, TaskIssue{Index: i, ID: raw.id, Field: "id", Message: fmt.Sprintf("id duplicado (já usado na posição %d)", prev)})
+		} else {
+			ids[raw.id] = i
+		}
+		raws[i] = raw
+	}
+
+	// Resolver dependências explícitas
+	deps := make([][]int, len(raws))
+	for i, raw := range raws {
+		for _, ref := range raw.dependsOn {
+			target, ok := resolveTaskRef(ref, ids, len(raws))
+			switch {
+			case !ok:
+				issues = append(issues, TaskIssue{Index: i, ID: raw.id, Field: "depends_on", Message: fmt.Sprintf("dependência desconhecida: %v", ref)})
+			case target == i:
+				issues = append(issues, TaskIssue{Index: i, ID: raw.id, Field: "depends_on", Message: "a tarefa não pode depender de si mesma"})
+			default:
+				deps[i] = appendUnique(deps[i], target)
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		return nil, &TaskParseError{Issues: issues}
+	}
+
+	now := time.Now()
+	tasks := make([]*Task, len(raws))
+	for i, raw := range raws {
+		tasks[i] = &Task{
+			ID:          raw.id,
+			Type:        orchestratorTools[raw.tool],
+			ToolName:    raw.tool,
+			Arguments:   raw.args,
+			Priority:    raw.priority,
+			Description: raw.desc,
+			CreatedAt:   now,
+		}
+	}
+
+	// Dependências implícitas: escritas em ranges sobrepostos rodam na ordem do array
+	for j := range tasks {
+		for i := 0; i < j; i++ {
+			if tasksConflict(tasks[i], tasks[j]) {
+				deps[j] = appendUnique(deps[j], i)
+			}
+		}
+	}
+
+	if cycle := findDependencyCycle(deps); len(cycle) > 0 {
+		for _, idx := range cycle {
+			issues = append(issues, TaskIssue{Index: idx, ID: tasks[idx].ID, Field: "depends_on", Message: "dependência circular"})
+		}
+		return nil, &TaskParseError{Issues: issues}
+	}
+
+	for i, task := range tasks {
+		sort.Ints(deps[i])
+		for _, d := range deps[i] {
+			task.DependsOn = append(task.DependsOn, tasks[d].ID)
+		}
+	}
+
+	return tasks, nil
+}
+
+// extractTaskArray localiza o primeiro JSON array válido na resposta (com ou sem bloco ```json)
+func extractTaskArray(response string) ([]json.RawMessage, error) {
+	var firstErr error
+
+	for offset := 0; offset < len(response); {
+		idx := strings.Index(response[offset:], "[")
+		if idx == -1 {
+			break
+		}
+		start := offset + idx
+
+		var entries []json.RawMessage
+		decoder := json.NewDecoder(strings.NewReader(response[start:]))
+		if err := decoder.Decode(&entries); err == nil {
+			return entries, nil
+		} else if firstErr == nil {
+			firstErr = err
+		}
+		offset = start + 1
+	}
+
+	if firstErr != nil {
+		return nil, &TaskParseError{Issues: []TaskIssue{{Index: -1, Field: "$", Message: "JSON inválido: " + firstErr.Error()}}}
+	}
+	return nil, fmt.Errorf("resposta do orquestrador não contém um JSON array")
+}
+
+// validateTaskEntry valida uma entrada do array e aplica valores padrão
+func (o *Orchestrator) validateTaskEntry(index int, entry json.RawMessage) (rawTask, []TaskIssue) {
+	raw := rawTask{id: fmt.Sprintf("t%d", index+1)}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(entry, &fields); err != nil || fields == nil {
+		return raw, []TaskIssue{{Index: index, Field: "$", Message: "a entrada deve ser um objeto JSON"}}
+	}
+
+	var issues []TaskIssue
+	addIssue := func(field, msg string) {
+		issues = append(issues, TaskIssue{Index: index, ID: raw.id, Field: field, Message: msg})
+	}
+
+	switch id := fields["id"].(type) {
+	case nil:
+	case string:
+		if strings.TrimSpace(id) != "" {
+			raw.id = strings.TrimSpace(id)
+		}
+	case float64:
+		raw.id = strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		addIssue("id", "deve ser uma string")
+	}
+
+	tool, _ := fields["tool"].(string)
+	raw.tool = strings.TrimSpace(tool)
+	if raw.tool == "" {
+		addIssue("tool", "campo obrigatório")
+	} else if _, ok := orchestratorTools[raw.tool]; !ok {
+		addIssue("tool", fmt.Sprintf("ferramenta desconhecida: %s", raw.tool))
+	}
+
+	switch args := fields["args"].(type) {
+	case nil:
+		raw.args = map[string]interface{}{}
+	case map[string]interface{}:
+		raw.args = args
+	default:
+		addIssue("args", "deve ser um objeto")
+	}
+
+	switch p := fields["priority"].(type) {
+	case nil:
+		raw.priority = o.analyzeTaskPriority(raw.tool, raw.args)
+	case float64:
+		if p != math.Trunc(p) || p < 1 || p > 3 {
+			addIssue("priority", "deve ser 1, 2 ou 3")
+		}
+		raw.priority = int(p)
+	default:
+		addIssue("priority", "deve ser um número (1, 2 ou 3)")
+	}
+
+	if desc, ok := fields["description"]; ok && desc != nil {
+		if s, ok := desc.(string); ok {
+			raw.desc = s
+		} else {
+			addIssue("description", "deve ser uma string")
+		}
+	}
+
+	switch d := fields["depends_on"].(type) {
+	case nil:
+	case []interface{}:
+		raw.dependsOn = d
+	case string, float64:
+		raw.dependsOn = []interface{}{d}
+	default:
+		addIssue("depends_on", "deve ser uma lista de ids")
+	}
+
+	return raw, issues
+}
+
+// resolveTaskRef converte uma referência de depends_on no índice da tarefa
+func resolveTaskRef(ref interface{}, ids map[string]int, count int) (int, bool) {
+	switch v := ref.(type) {
+	case string:
+		idx, ok := ids[strings.TrimSpace(v)]
+		return idx, ok
+	case float64:
+		pos := int(v)
+		if float64(pos) != v || pos < 1 || pos > count {
+			return 0, false
+		}
+		return pos - 1, true
+	}
+	return 0, false
+}
+
+// findDependencyCycle retorna os índices envolvidos em um ciclo (vazio se o grafo é um DAG)
+func findDependencyCycle(deps [][]int) []int {
+	remaining := make([]int, len(deps))
+	dependents := make([][]int, len(deps))
+	for i, list := range deps {
+		remaining[i] = len(list)
+		for _, d := range list {
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	queue := make([]int, 0, len(deps))
+	for i, n := range remaining {
+		if n == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	visited := 0
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, next := range dependents[cur] {
+			remaining[next]--
+			if remaining[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if visited == len(deps) {
+		return nil
+	}
+
+	var cycle []int
+	for i, n := range remaining {
+		if n > 0 {
+			cycle = append(cycle, i)
+		}
+	}
+	return cycle
+}
+
+func appendUnique(list []int, v int) []int {
+	for _, x := range list {
+		if x == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+// ============================================
+// EXECUÇÃO DO GRAFO DE TAREFAS
+// ============================================
+
+// executeTaskGraph executa as tarefas respeitando depends_on.
+// Tarefas prontas são despachadas para o pool de workers (ou goroutines, se o pool não estiver rodando);
+// tarefas cuja dependência falhou não são executadas.
+func (o *Orchestrator) executeTaskGraph(tasks []*Task, onChunk func(string) error) []*TaskResult {
+	results := make([]*TaskResult, len(tasks))
+	if len(tasks) == 0 {
+		return results
+	}
+
+	// Os IDs padrão ("t1", "t2"...) se repetem a cada mensagem; o prefixo da execução
+	// evita colisões em o.pendingTasks entre grafos executados ao mesmo tempo
+	o.mu.Lock()
+	o.runSeq++
+	prefix := fmt.Sprintf("r%d.", o.runSeq)
+	o.mu.Unlock()
+	for _, t := range tasks {
+		t.ID = prefix + t.ID
+		for i, dep := range t.DependsOn {
+			t.DependsOn[i] = prefix + dep
+		}
+	}
+
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	remaining := make([]int, len(tasks))
+	blockedBy := make([]string, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i, t := range tasks {
+		for _, depID := range t.DependsOn {
+			if d, ok := index[depID]; ok {
+				remaining[i]++
+				dependents[d] = append(dependents[d], i)
+			}
+		}
+	}
+
+	o.mu.Lock()
+	useWorkers := o.running
+	ctx := o.ctx
+	o.mu.Unlock()
+
+	doneChan := make(chan *TaskResult, len(tasks))
+	inFlight := 0
+
+	dispatch := func(ready []int) {
+		sort.SliceStable(ready, func(a, b int) bool {
+			return tasks[ready[a]].Priority < tasks[ready[b]].Priority
+		})
+		for _, idx := range ready {
+			task := tasks[idx]
+			task.done = doneChan
+			task.onProgress = onChunk
+			inFlight++
+
+			if useWorkers {
+				o.mu.Lock()
+				o.pendingTasks[task.ID] = task
+				o.mu.Unlock()
+				o.addTaskWithPriority(task)
+			} else {
+				go func(t *Task) {
+					doneChan <- o.executeTask(t, onChunk)
+				}(task)
+			}
+		}
+	}
+
+	// complete registra o resultado e libera (ou bloqueia) as tarefas dependentes
+	var complete func(idx int, result *TaskResult) []int
+	complete = func(idx int, result *TaskResult) []int {
+		results[idx] = result
+
+		var ready []int
+		for _, next := range dependents[idx] {
+			if !result.Success && blockedBy[next] == "" {
+				blockedBy[next] = tasks[idx].ID
+			}
+			remaining[next]--
+			if remaining[next] > 0 {
+				continue
+			}
+			if blockedBy[next] != "" {
+				ready = append(ready, complete(next, &TaskResult{
+					TaskID:  tasks[next].ID,
+					Success: false,
+					Error:   fmt.Errorf("não executada: dependência %s falhou", blockedBy[next]),
+				})...)
+				continue
+			}
+			ready = append(ready, next)
+		}
+		return ready
+	}
+
+	var initial []int
+	for i := range tasks {
+		if remaining[i] == 0 {
+			initial = append(initial, i)
+		}
+	}
+	dispatch(initial)
+
+	var cancelled <-chan struct{}
+	if useWorkers && ctx != nil {
+		cancelled = ctx.Done()
+	}
+
+	for inFlight > 0 {
+		select {
+		case result := <-doneChan:
+			inFlight--
+			idx, ok := index[result.TaskID]
+			if !ok {
+				continue
+			}
+			dispatch(complete(idx, result))
+		case <-cancelled:
+			for i, r := range results {
+				if r == nil {
+					results[i] = &TaskResult{TaskID: tasks[i].ID, Success: false, Error: fmt.Errorf("orquestrador parado")}
+				}
+			}
+			return results
+		}
+	}
+
+	return results
+}
+
+// ============================================
+// CONFLITOS ENTRE TAREFAS
+// ============================================
+
+// taskFootprint representa a área da planilha tocada por uma tarefa (linhas/colunas base 1, inclusivas)
+type taskFootprint struct {
+	sheet                  string // Vazio = qualquer aba
+	row1, col1, row2, col2 int
+}
+
+func wholeSheet(sheet string) taskFootprint {
+	return taskFootprint{sheet: sheet, row1: 1, col1: 1, row2: math.MaxInt32, col2: math.MaxInt32}
+}
+
+func (f taskFootprint) overlaps(other taskFootprint) bool {
+	if f.sheet != "" && other.sheet != "" && !strings.EqualFold(f.sheet, other.sheet) {
+		return false
+	}
+	return f.row1 <= other.row2 && other.row1 <= f.row2 && f.col1 <= other.col2 && other.col1 <= f.col2
+}
+
+// tasksConflict indica se duas tarefas precisam rodar em ordem (ao menos uma escreve em área comum)
+func tasksConflict(a, b *Task) bool {
+	if a.Type == TaskTypeQuery && b.Type == TaskTypeQuery {
+		return false
+	}
+	for _, fa := range taskFootprints(a) {
+		for _, fb := range taskFootprints(b) {
+			if fa.overlaps(fb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// taskFootprints calcula as áreas tocadas por uma tarefa a partir dos seus argumentos
+func taskFootprints(task *Task) []taskFootprint {
+	if task.ToolName != "execute_macro" {
+		return argsFootprints(task.ToolName, task.Arguments)
+	}
+
+	actions, _ := task.Arguments["actions"].([]interface{})
+	var result []taskFootprint
+	for _, act := range actions {
+		actionMap, ok := act.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		args := actionMap
+		if inner, ok := actionMap["args"].(map[string]interface{}); ok {
+			args = inner
+		}
+		name, _ := actionMap["tool"].(string)
+		if name == "" {
+			name, _ = actionMap["op"].(string)
+		}
+		result = append(result, argsFootprints(strings.ReplaceAll(name, "-", "_"), args)...)
+	}
+
+	if len(result) == 0 {
+		return []taskFootprint{wholeSheet("")}
+	}
+	return result
+}
+
+func argsFootprints(toolName string, args map[string]interface{}) []taskFootprint {
+	str := func(key string) string {
+		v, _ := args[key].(string)
+		return strings.TrimSpace(v)
+	}
+
+	if sheetLevelTools[toolName] {
+		var result []taskFootprint
+		for _, key := range []string{"sheet", "name", "oldName", "newName"} {
+			if name := str(key); name != "" {
+				result = append(result, wholeSheet(name))
+			}
+		}
+		if len(result) == 0 {
+			result = append(result, wholeSheet(""))
+		}
+		return result
+	}
+
+	sheet := str("sheet")
+	var result []taskFootprint
+
+	// Tabelas dinâmicas leem a origem e escrevem no destino
+	if src := str("sourceSheet"); src != "" {
+		result = append(result, refFootprint(src, str("sourceRange")))
+	}
+	if dest := str("destSheet"); dest != "" {
+		result = append(result, refFootprint(dest, str("destCell")))
+	}
+
+	for _, key := range []string{"range", "source", "dest"} {
+		if ref := str(key); ref != "" {
+			result = append(result, refFootprint(sheet, ref))
+		}
+	}
+
+	if cell := str("cell"); cell != "" {
+		fp := refFootprint(sheet, cell)
+		// Escrita em lote a partir de uma célula ocupa o tamanho do array
+		if data, ok := args["data"].([]interface{}); ok && len(data) > 0 && fp.row1 == fp.row2 && fp.col1 == fp.col2 {
+			cols := 1
+			for _, row := range data {
+				if rowArr, ok := row.([]interface{}); ok && len(rowArr) > cols {
+					cols = len(rowArr)
+				}
+			}
+			fp.row2 = fp.row1 + len(data) - 1
+			fp.col2 = fp.col1 + cols - 1
+		}
+		result = append(result, fp)
+	}
+
+	if len(result) == 0 {
+		result = append(result, wholeSheet(sheet))
+	}
+	return result
+}
+
+// refFootprint converte uma referência A1 ("A1", "A1:C10", "A:C", "2:5", "Aba!A1:B2") em área.
+// Referências não reconhecidas ocupam a aba inteira.
+func refFootprint(sheet, ref string) taskFootprint {
+	ref = strings.ReplaceAll(strings.TrimSpace(ref), "$", "")
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		sheet = strings.Trim(ref[:idx], "'")
+		ref = ref[idx+1:]
+	}
+	if ref == "" {
+		return wholeSheet(sheet)
+	}
+
+	parts := strings.SplitN(ref, ":", 2)
+	r1, c1, ok1 := parseCellRef(parts[0])
+	r2, c2, ok2 := r1, c1, ok1
+	if len(parts) == 2 {
+		r2, c2, ok2 = parseCellRef(parts[1])
+	}
+	if !ok1 || !ok2 {
+		return wholeSheet(sheet)
+	}
+
+	fp := taskFootprint{sheet: sheet, row1: r1, col1: c1, row2: r2, col2: c2}
+	// Linha ou coluna ausente significa a linha/coluna inteira
+	if fp.row1 == 0 || fp.row2 == 0 {
+		fp.row1, fp.row2 = 1, math.MaxInt32
+	}
+	if fp.col1 == 0 || fp.col2 == 0 {
+		fp.col1, fp.col2 = 1, math.MaxInt32
+	}
+	if fp.row1 > fp.row2 {
+		fp.row1, fp.row2 = fp.row2, fp.row1
+	}
+	if fp.col1 > fp.col2 {
+		fp.col1, fp.col2 = fp.col2, fp.col1
+	}
+	return fp
+}
+
+// parseCellRef interpreta "B12", "B" ou "12"; componentes ausentes retornam 0
+func parseCellRef(ref string) (row, col int, ok bool) {
+	ref = strings.ToUpper(strings.TrimSpace(ref))
+	i := 0
+	for i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z' {
+		col = col*26 + int(ref[i]-'A'+1)
+		i++
+	}
+	if i < len(ref) {
+		n, err := strconv.Atoi(ref[i:])
+		if err != nil || n < 1 {
+			return 0, 0, false
+		}
+		row = n
+	}
+	if row == 0 && col == 0 {
+		return 0, 0, false
+	}
+	return row, col, true
+}
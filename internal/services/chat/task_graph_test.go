@@ -0,0 +1,95 @@
+package chat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTasksBuildsDependencies(t *testing.T) {
+	o := &Orchestrator{}
+
+	response := "```json\n" + `[
+  {"id": "ler", "tool": "get_range_values", "args": {"sheet": "Vendas", "range": "A1:C10"}, "priority": 2, "description": "ler dados"},
+  {"id": "cab", "tool": "query_batch", "args": {"sheet": "Vendas", "queries": ["headers"]}},
+  {"id": "total", "tool": "write_cell", "args": {"sheet": "Resumo", "cell": "B2", "value": "=SUM(Vendas!C2:C10)"}, "depends_on": ["ler"]},
+  {"id": "fmt", "tool": "format_range", "args": {"sheet": "Resumo", "range": "A1:C3", "bold": true}},
+  {"id": "outra", "tool": "write_range", "args": {"sheet": "Resumo", "cell": "E1", "data": [["a", "b"]]}}
+]` + "\n```"
+
+	tasks, err := o.parseTasks(response)
+	if err != nil {
+		t.Fatalf("parseTasks retornou erro: %v", err)
+	}
+	if len(tasks) != 5 {
+		t.Fatalf("esperava 5 tarefas, obteve %d", len(tasks))
+	}
+
+	want := map[string][]string{
+		"ler":   nil,
+		"cab":   nil,
+		"total": {"ler"},
+		"fmt":   {"total"}, // B2 está dentro de A1:C3
+		"outra": nil,       // E1:F1 não sobrepõe
+	}
+	for _, task := range tasks {
+		if !reflect.DeepEqual(task.DependsOn, want[task.ID]) {
+			t.Errorf("%s: DependsOn = %v, esperado %v", task.ID, task.DependsOn, want[task.ID])
+		}
+	}
+
+	if tasks[0].Type != TaskTypeQuery || tasks[2].Type != TaskTypeAction {
+		t.Errorf("tipos incorretos: %v, %v", tasks[0].Type, tasks[2].Type)
+	}
+	if tasks[2].Priority != 1 {
+		t.Errorf("prioridade padrão de write_cell deveria ser 1, obteve %d", tasks[2].Priority)
+	}
+}
+
+func TestParseTasksReportsInvalidEntries(t *testing.T) {
+	o := &Orchestrator{}
+
+	tests := []struct {
+		name     string
+		response string
+		fields   []string
+	}{
+		{
+			name:     "ferramenta desconhecida e args inválido",
+			response: `[{"tool": "drop_database"}, {"tool": "list_sheets", "args": "x"}]`,
+			fields:   []string{"tool", "args"},
+		},
+		{
+			name:     "dependência inexistente e prioridade fora do intervalo",
+			response: `[{"tool": "list_sheets", "priority": 7, "depends_on": ["nada"]}]`,
+			fields:   []string{"priority", "depends_on"},
+		},
+		{
+			name:     "ciclo",
+			response: `[{"id": "a", "tool": "list_sheets", "depends_on": ["b"]}, {"id": "b", "tool": "list_sheets", "depends_on": ["a"]}]`,
+			fields:   []string{"depends_on", "depends_on"},
+		},
+		{
+			name:     "JSON truncado",
+			response: `[{"tool": "list_sheets"`,
+			fields:   []string{"$"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := o.parseTasks(tt.response)
+			parseErr, ok := err.(*TaskParseError)
+			if !ok {
+				t.Fatalf("esperava *TaskParseError, obteve %T (%v)", err, err)
+			}
+
+			var fields []string
+			for _, issue := range parseErr.Issues {
+				fields = append(fields, issue.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("campos com erro = %v, esperado %v", fields, tt.fields)
+			}
+		})
+	}
+}
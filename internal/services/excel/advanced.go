@@ -3,6 +3,8 @@ package excel
 // advanced.go - Métodos avançados do Excel Service
 // Wrappers para features avançadas do Excelize
 
+import "excel-ai/pkg/excel"
+
 // AddDropdownList adiciona uma lista dropdown a um range
 func (s *Service) AddDropdownList(sheet, rng string, options []string) error {
 	s.mu.Lock()
@@ -15,6 +17,74 @@ func (s *Service) AddDropdownList(sheet, rng string, options []string) error {
 	return client.AddDropdownList(sheet, rng, options)
 }
 
+// AddValidation aplica uma regra de validação de dados a um range
+func (s *Service) AddValidation(sheet, rng string, rule excel.DataValidationRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.AddValidation(sheet, rng, rule)
+}
+
+// GetValidations lista as validações de dados de uma planilha
+func (s *Service) GetValidations(sheet, rng string) ([]excel.DataValidationRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetValidations(sheet, rng)
+}
+
+// RemoveValidations remove as validações de dados de um range
+func (s *Service) RemoveValidations(sheet, rng string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return 0, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.RemoveValidations(sheet, rng)
+}
+
+// FindInvalidCells lista as células que violam suas validações de dados
+func (s *Service) FindInvalidCells(sheet, rng string) ([]excel.ValidationViolation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.FindInvalidCells(sheet, rng)
+}
+
 // AddCellComment adiciona um comentário a uma célula
 func (s *Service) AddCellComment(sheet, cell, author, text string) error {
 	s.mu.Lock()
@@ -147,6 +217,18 @@ func (s *Service) SetCellFormula(sheet, cell, formula string) error {
 	return client.SetCellFormula(sheet, cell, formula)
 }
 
+// CalculateFormulas recalcula todas as fórmulas e retorna os erros por célula
+func (s *Service) CalculateFormulas() (*excel.RecalcResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+	return client.CalculateFormulas()
+}
+
 // AddSimpleConditionalFormat adiciona formatação condicional simplificada
 func (s *Service) AddSimpleConditionalFormat(sheet, rng, criteria, value, bgColor string) error {
 	s.mu.Lock()
@@ -159,6 +241,57 @@ func (s *Service) AddSimpleConditionalFormat(sheet, rng, criteria, value, bgColo
 	return client.AddSimpleConditionalFormat(sheet, rng, criteria, value, bgColor)
 }
 
+// AddConditionalFormat adiciona uma regra de formatação condicional
+func (s *Service) AddConditionalFormat(sheet, rng string, rule excel.ConditionalFormatRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.AddConditionalFormat(sheet, rng, rule)
+}
+
+// GetConditionalFormats lista as regras de formatação condicional de uma planilha
+func (s *Service) GetConditionalFormats(sheet, rng string) ([]excel.ConditionalFormatRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetConditionalFormats(sheet, rng)
+}
+
+// RemoveConditionalFormats remove as regras de formatação condicional de um range
+func (s *Service) RemoveConditionalFormats(sheet, rng string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return 0, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.RemoveConditionalFormats(sheet, rng)
+}
+
 // DeletePivotTable remove uma tabela dinâmica
 func (s *Service) DeletePivotTable(sheet, name string) error {
 	s.mu.Lock()
@@ -170,3 +303,95 @@ func (s *Service) DeletePivotTable(sheet, name string) error {
 	}
 	return client.DeletePivotTable(sheet, name)
 }
+
+// SetPageSetup altera a configuração de impressão de uma planilha
+func (s *Service) SetPageSetup(sheet string, setup excel.PageSetup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.SetPageSetup(sheet, setup)
+}
+
+// GetPageSetup retorna a configuração de impressão de uma planilha
+func (s *Service) GetPageSetup(sheet string) (*excel.PageSetup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetPageSetup(sheet)
+}
+
+// InsertPageBreak insere uma quebra de página antes da linha e/ou coluna da célula
+func (s *Service) InsertPageBreak(sheet, cell string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.InsertPageBreak(sheet, cell)
+}
+
+// RemovePageBreak remove as quebras de página de uma célula (vazia remove todas)
+func (s *Service) RemovePageBreak(sheet, cell string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.RemovePageBreak(sheet, cell)
+}
+
+// GetWorkbookInfo retorna arquivo, abas, propriedades e origem da pasta de trabalho
+func (s *Service) GetWorkbookInfo() (*excel.WorkbookInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetWorkbookInfo()
+}
+
+// SetWorkbookProperties altera as propriedades do documento (título, autor, personalizadas, ...)
+func (s *Service) SetWorkbookProperties(props excel.WorkbookProperties) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+	return client.SetWorkbookProperties(props)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"excel-ai/internal/dto"
+	"excel-ai/pkg/excel"
 )
 
 // UpdateCell atualiza o valor de uma célula
@@ -202,6 +203,37 @@ func (s *Service) UndoByConversation(convID string) (int, error) {
 					err = client.WriteRange(action.Sheet, startCell, batchData)
 				}
 			}
+		case "insert-columns":
+			var data map[string]string
+			if jsonErr := json.Unmarshal([]byte(action.UndoData), &data); jsonErr == nil {
+				_, err = client.DeleteColumns(action.Sheet, data["column"], 0)
+			}
+		case "delete-columns":
+			var data struct {
+				Column string     `json:"column"`
+				Count  int        `json:"count"`
+				Data   [][]string `json:"data"`
+			}
+			if jsonErr := json.Unmarshal([]byte(action.UndoData), &data); jsonErr == nil {
+				if _, insErr := client.InsertColumns(action.Sheet, excel.ColumnInsert{Before: data.Column, Count: data.Count}); insErr != nil {
+					err = insErr
+				} else if len(data.Data) > 0 {
+					batchData := make([][]interface{}, len(data.Data))
+					for i, rowVals := range data.Data {
+						rowInterface := make([]interface{}, len(rowVals))
+						for j, val := range rowVals {
+							rowInterface[j] = val
+						}
+						batchData[i] = rowInterface
+					}
+					err = client.WriteRange(action.Sheet, data.Column+"1", batchData)
+				}
+			}
+		case "move-columns":
+			var data map[string]string
+			if jsonErr := json.Unmarshal([]byte(action.UndoData), &data); jsonErr == nil {
+				_, err = client.MoveColumns(action.Sheet, data["column"], data["before"], data["after"])
+			}
 		case "clear-range":
 			var data map[string][][]string
 			if jsonErr := json.Unmarshal([]byte(action.UndoData), &data); jsonErr == nil {
@@ -219,6 +251,21 @@ func (s *Service) UndoByConversation(convID string) (int, error) {
 				}
 			}
 		case "sort":
+			var order struct {
+				Order []int `json:"order"`
+			}
+			if jsonErr := json.Unmarshal([]byte(action.UndoData), &order); jsonErr == nil && len(order.Order) > 0 {
+				// A linha i veio de order[i]: devolvê-la para lá
+				inverse := make([]int, len(order.Order))
+				for i, from := range order.Order {
+					if from >= 0 && from < len(inverse) {
+						inverse[from] = i
+					}
+				}
+				err = client.ReorderRows(action.Sheet, action.Cell, inverse)
+				break
+			}
+			// Ações antigas guardavam os valores do intervalo
 			var data map[string][][]string
 			if jsonErr := json.Unmarshal([]byte(action.UndoData), &data); jsonErr == nil {
 				if oldData, ok := data["data"]; ok && len(oldData) > 0 {
@@ -234,6 +281,11 @@ func (s *Service) UndoByConversation(convID string) (int, error) {
 					err = client.WriteRange(action.Sheet, startCell, interfaceData)
 				}
 			}
+		case "format-range", "set-borders":
+			var snapshot excel.StyleSnapshot
+			if jsonErr := json.Unmarshal([]byte(action.UndoData), &snapshot); jsonErr == nil {
+				err = client.SetCellStyles(action.Sheet, snapshot)
+			}
 		case "set-column-width":
 			var data map[string]float64
 			if jsonErr := json.Unmarshal([]byte(action.UndoData), &data); jsonErr == nil {
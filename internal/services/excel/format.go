@@ -1,9 +1,6 @@
 package excel
 
-import (
-	"excel-ai/pkg/excel"
-	"fmt"
-)
+import "excel-ai/pkg/excel"
 
 // FormatRange formata um range de células
 func (s *Service) FormatRange(sheet, rangeAddr string, bold, italic bool, fontSize int, fontColor, bgColor string) error {
@@ -19,13 +16,35 @@ func (s *Service) FormatRange(sheet, rangeAddr string, bold, italic bool, fontSi
 		sheet = s.getFirstSheet()
 	}
 
+	// Valores falsos/vazios mantêm o estilo atual das células
 	format := excel.Format{
-		Bold:      bold,
-		Italic:    italic,
-		FontSize:  fontSize,
+		FontSize:  float64(fontSize),
 		FontColor: fontColor,
 		BgColor:   bgColor,
 	}
+	if bold {
+		format.Bold = &bold
+	}
+	if italic {
+		format.Italic = &italic
+	}
+
+	return client.FormatRange(sheet, rangeAddr, format)
+}
+
+// FormatRangeSpec aplica uma formatação completa a um range, preservando o restante do estilo
+func (s *Service) FormatRangeSpec(sheet, rangeAddr string, format excel.Format) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
 
 	return client.FormatRange(sheet, rangeAddr, format)
 }
@@ -98,6 +117,23 @@ func (s *Service) ApplyFilter(sheet, rangeAddr string) error {
 	return client.ApplyFilter(sheet, rangeAddr)
 }
 
+// ApplyAutoFilter aplica filtro com critérios e retorna o número de linhas visíveis
+func (s *Service) ApplyAutoFilter(sheet, rangeAddr string, criteria []excel.FilterCriterion) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return 0, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.ApplyAutoFilter(sheet, rangeAddr, criteria)
+}
+
 // ClearFilters limpa filtros de uma planilha
 func (s *Service) ClearFilters(sheet string) error {
 	s.mu.Lock()
@@ -115,103 +151,138 @@ func (s *Service) ClearFilters(sheet string) error {
 	return client.ClearFilters(sheet)
 }
 
-// SortRange ordena um range
-func (s *Service) SortRange(sheet, rangeAddr string, column int, ascending bool) error {
+// SortRange ordena um range e retorna a posição original de cada linha
+func (s *Service) SortRange(sheet, rangeAddr string, opts excel.SortOptions) ([]int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	client, err := s.getClientLocked()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if sheet == "" {
 		sheet = s.getFirstSheet()
 	}
 
-	return client.SortRange(sheet, rangeAddr, column, ascending)
+	return client.SortRange(sheet, rangeAddr, opts)
 }
 
-// CopyRange copia um range para outro destino
-func (s *Service) CopyRange(sheet, sourceRange, destRange string) error {
+// CopyRange copia um range para outro destino conforme o modo de colagem
+func (s *Service) CopyRange(sheet, sourceRange, destRange string, opts excel.PasteOptions) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	client, err := s.getClientLocked()
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.CopyRange(sheet, sourceRange, destRange, opts)
+}
+
+// MoveRange move um range para outro destino, atualizando as fórmulas que o leem
+func (s *Service) MoveRange(sheet, sourceRange, destRange string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
 	}
 
 	if sheet == "" {
 		sheet = s.getFirstSheet()
 	}
 
-	// Ler dados do range de origem
-	data, err := client.GetRangeValues(sheet, sourceRange)
+	return client.MoveRange(sheet, sourceRange, destRange)
+}
+
+// GetFormat retorna a formatação da primeira célula de um range
+func (s *Service) GetFormat(sheet, rangeAddr string) (*excel.Format, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
 	if err != nil {
-		return fmt.Errorf("erro ao ler range de origem: %w", err)
+		return nil, err
 	}
 
-	// Converter para [][]interface{}
-	interfaceData := make([][]interface{}, len(data))
-	for i, row := range data {
-		interfaceData[i] = make([]interface{}, len(row))
-		for j, val := range row {
-			interfaceData[i][j] = val
-		}
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetFormat(sheet, rangeAddr)
+}
+
+// GetRangeFormats retorna as formatações existentes em um range, agrupadas por estilo
+func (s *Service) GetRangeFormats(sheet, rangeAddr string) (*excel.RangeFormats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
 	}
 
-	// Escrever no destino (primeira célula do range de destino)
-	destCell := destRange
-	if idx := len(destRange); idx > 0 {
-		// Se for range "A1:B2", pegar só primeira célula
-		for i, c := range destRange {
-			if c == ':' {
-				destCell = destRange[:i]
-				break
-			}
-		}
+	if sheet == "" {
+		sheet = s.getFirstSheet()
 	}
 
-	return client.WriteRange(sheet, destCell, interfaceData)
+	return client.GetRangeFormats(sheet, rangeAddr)
 }
 
-// GetFormat retorna formato de um range (não suportado totalmente no Excelize)
-func (s *Service) GetFormat(sheet, rangeAddr string) (*excel.Format, error) {
+// GetCellStyles guarda os estilos de um range para permitir desfazer formatações
+func (s *Service) GetCellStyles(sheet, rangeAddr string) (*excel.StyleSnapshot, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.currentSessionID == "" {
-		return nil, fmt.Errorf("nenhum arquivo carregado")
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
 	}
 
-	// Excelize não tem método direto para ler formato
-	// Retornar formato vazio
-	return &excel.Format{}, nil
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetCellStyles(sheet, rangeAddr)
 }
 
-// GetColumnWidth retorna largura de coluna (não suportado totalmente)
+// GetColumnWidth retorna largura de coluna
 func (s *Service) GetColumnWidth(sheet, col string) (float64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.currentSessionID == "" {
-		return 0, fmt.Errorf("nenhum arquivo carregado")
+	client, err := s.getClientLocked()
+	if err != nil {
+		return 0, err
 	}
 
-	// Retornar valor padrão - Excelize não expõe isso facilmente
-	return 8.43, nil // Largura padrão do Excel
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetColumnWidth(sheet, col)
 }
 
-// GetRowHeight retorna altura de linha (não suportado totalmente)
+// GetRowHeight retorna altura de linha
 func (s *Service) GetRowHeight(sheet, row string) (float64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.currentSessionID == "" {
-		return 0, fmt.Errorf("nenhum arquivo carregado")
+	client, err := s.getClientLocked()
+	if err != nil {
+		return 0, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
 	}
 
-	// Retornar valor padrão
-	return 15, nil // Altura padrão do Excel
+	return client.GetRowHeight(sheet, row)
 }
@@ -1,6 +1,6 @@
 package excel
 
-import "fmt"
+import "excel-ai/pkg/excel"
 
 // CreateChart cria um gráfico
 func (s *Service) CreateChart(sheet, rangeAddr, chartType, title string) error {
@@ -19,6 +19,23 @@ func (s *Service) CreateChart(sheet, rangeAddr, chartType, title string) error {
 	return client.CreateChart(sheet, rangeAddr, chartType, title)
 }
 
+// CreateChartSpec cria um gráfico a partir de uma especificação completa
+func (s *Service) CreateChartSpec(sheet string, spec excel.ChartSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.CreateChartSpec(sheet, spec)
+}
+
 // CreatePivotTable cria uma tabela dinâmica
 func (s *Service) CreatePivotTable(sourceSheet, sourceRange, destSheet, destCell, tableName string) error {
 	s.mu.Lock()
@@ -36,20 +53,78 @@ func (s *Service) CreatePivotTable(sourceSheet, sourceRange, destSheet, destCell
 	return client.CreatePivotTable(sourceSheet, sourceRange, destSheet, destCell, tableName)
 }
 
-// ConfigurePivotFields configura campos da tabela dinâmica (limitado no Excelize)
-func (s *Service) ConfigurePivotFields(sheetName, tableName string, rowFields []string, dataFields []map[string]string) error {
+// CreatePivotTableWithFields cria uma tabela dinâmica com campos configurados
+func (s *Service) CreatePivotTableWithFields(spec excel.PivotSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if spec.SourceSheet == "" {
+		spec.SourceSheet = s.getFirstSheet()
+	}
+
+	return client.CreatePivotTableWithFields(spec)
+}
+
+// GetPivotTables lê a configuração das tabelas dinâmicas de uma planilha
+func (s *Service) GetPivotTables(sheet string) ([]excel.PivotSpec, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetPivotTables(sheet)
+}
+
+// UpdatePivotTable altera campos de uma tabela dinâmica existente
+func (s *Service) UpdatePivotTable(sheet, name string, changes excel.PivotSpec) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.currentSessionID == "" {
-		return fmt.Errorf("nenhum arquivo carregado")
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
 	}
 
-	// Excelize não suporta configuração avançada de pivot tables
-	return fmt.Errorf("configuração de campos de pivot table não suportada no modo Excelize")
+	return client.UpdatePivotTable(sheet, name, changes)
 }
 
-// ListCharts lista gráficos (limitação do Excelize)
+// ConfigurePivotFields configura linhas e valores de uma tabela dinâmica existente.
+// Cada campo de valores usa as chaves "field", "function", "name" e "showAs".
+func (s *Service) ConfigurePivotFields(sheetName, tableName string, rowFields []string, dataFields []map[string]string) error {
+	changes := excel.PivotSpec{Rows: rowFields}
+	if rowFields == nil {
+		changes.Rows = []string{}
+	}
+	changes.DataFields = make([]excel.PivotDataField, 0, len(dataFields))
+	for _, df := range dataFields {
+		changes.DataFields = append(changes.DataFields, excel.PivotDataField{
+			Field:    df["field"],
+			Function: df["function"],
+			Name:     df["name"],
+			ShowAs:   df["showAs"],
+		})
+	}
+
+	return s.UpdatePivotTable(sheetName, tableName, changes)
+}
+
+// ListCharts lista os nomes dos gráficos
 func (s *Service) ListCharts(sheet string) ([]string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -66,7 +141,24 @@ func (s *Service) ListCharts(sheet string) ([]string, error) {
 	return client.ListCharts(sheet)
 }
 
-// DeleteChart deleta um gráfico
+// GetCharts lista os gráficos com âncora, tipo, título e séries
+func (s *Service) GetCharts(sheet string) ([]excel.ChartInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetCharts(sheet)
+}
+
+// DeleteChart deleta um gráfico pelo nome, título ou célula de ancoragem
 func (s *Service) DeleteChart(sheet, chartName string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -83,6 +175,108 @@ func (s *Service) DeleteChart(sheet, chartName string) error {
 	return client.DeleteChart(sheet, chartName)
 }
 
+// InsertImage insere uma imagem de um arquivo ou base64
+func (s *Service) InsertImage(sheet string, spec excel.ImageSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.InsertImage(sheet, spec)
+}
+
+// GetImages lista as imagens de uma planilha
+func (s *Service) GetImages(sheet string) ([]excel.ImageInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetImages(sheet)
+}
+
+// DeleteImage remove as imagens ancoradas em uma célula
+func (s *Service) DeleteImage(sheet, cell string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.DeleteImage(sheet, cell)
+}
+
+// AddShape adiciona uma forma ou caixa de texto e retorna seu nome
+func (s *Service) AddShape(sheet string, spec excel.ShapeSpec) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.AddShape(sheet, spec)
+}
+
+// GetShapes lista as formas e caixas de texto de uma planilha
+func (s *Service) GetShapes(sheet string) ([]excel.ShapeInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetShapes(sheet)
+}
+
+// DeleteShape remove uma forma pelo nome, célula de ancoragem ou texto
+func (s *Service) DeleteShape(sheet, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.DeleteShape(sheet, name)
+}
+
 // CreateTable cria uma tabela
 func (s *Service) CreateTable(sheet, rangeAddr, tableName, style string) error {
 	s.mu.Lock()
@@ -133,3 +327,117 @@ func (s *Service) DeleteTable(sheet, tableName string) error {
 
 	return client.DeleteTable(sheet, tableName)
 }
+
+// GetTables descreve as tabelas da planilha
+func (s *Service) GetTables(sheet string) ([]excel.TableInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.GetTables(sheet)
+}
+
+// ResizeTable altera o intervalo de uma tabela (vazio estende até os dados)
+func (s *Service) ResizeTable(tableName, rangeAddr string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	return client.ResizeTable(tableName, rangeAddr)
+}
+
+// SetTableTotals mostra ou oculta a linha de totais de uma tabela
+func (s *Service) SetTableTotals(tableName string, show bool, totals []excel.TableTotal) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	return client.SetTableTotals(tableName, show, totals)
+}
+
+// AppendTableRows acrescenta linhas a uma tabela pelos nomes dos cabeçalhos
+func (s *Service) AppendTableRows(tableName string, rows []map[string]interface{}) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	return client.AppendTableRows(tableName, rows)
+}
+
+// ListDefinedNames lista os nomes definidos da pasta de trabalho
+func (s *Service) ListDefinedNames() ([]excel.DefinedName, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetDefinedNames()
+}
+
+// CreateDefinedName cria um nome definido; referências sem planilha usam sheet
+func (s *Service) CreateDefinedName(sheet string, name excel.DefinedName) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.CreateDefinedName(sheet, name)
+}
+
+// UpdateDefinedName altera um nome definido existente
+func (s *Service) UpdateDefinedName(sheet, name, scope string, changes excel.DefinedName) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.UpdateDefinedName(sheet, name, scope, changes)
+}
+
+// DeleteDefinedName remove um nome definido
+func (s *Service) DeleteDefinedName(name, scope string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+	return client.DeleteDefinedName(name, scope)
+}
@@ -1,6 +1,10 @@
 package excel
 
-import "fmt"
+import (
+	"fmt"
+
+	"excel-ai/pkg/excel"
+)
 
 // ListSheets retorna lista de planilhas do arquivo atual
 func (s *Service) ListSheets() ([]string, error) {
@@ -123,6 +127,57 @@ func (s *Service) GetCellFormula(sheetName, cellAddress string) (string, error)
 	return client.GetCellFormula(sheetName, cellAddress)
 }
 
+// GetPrecedents lista os intervalos lidos pelas fórmulas de um intervalo
+func (s *Service) GetPrecedents(sheetName, rng string) ([]excel.FormulaPrecedents, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheetName == "" {
+		sheetName = s.getFirstSheet()
+	}
+
+	return client.GetPrecedents(sheetName, rng)
+}
+
+// GetDependents lista as fórmulas que leem um intervalo
+func (s *Service) GetDependents(sheetName, rng string) ([]excel.FormulaDependent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheetName == "" {
+		sheetName = s.getFirstSheet()
+	}
+
+	return client.GetDependents(sheetName, rng)
+}
+
+// GetChangeImpact calcula quantas fórmulas uma alteração no intervalo afeta
+func (s *Service) GetChangeImpact(sheetName, rng string) (*excel.ChangeImpact, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheetName == "" {
+		sheetName = s.getFirstSheet()
+	}
+
+	return client.GetChangeImpact(sheetName, rng)
+}
+
 // HasFilter verifica se a planilha tem filtro aplicado
 func (s *Service) HasFilter(sheetName string) (bool, error) {
 	s.mu.Lock()
@@ -140,6 +195,23 @@ func (s *Service) HasFilter(sheetName string) (bool, error) {
 	return client.HasFilter(sheetName)
 }
 
+// GetAutoFilter retorna o filtro automático da planilha (nil se não houver)
+func (s *Service) GetAutoFilter(sheetName string) (*excel.AutoFilterInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheetName == "" {
+		sheetName = s.getFirstSheet()
+	}
+
+	return client.GetAutoFilter(sheetName)
+}
+
 // GetActiveCell retorna célula ativa (sempre A1 no modo Excelize)
 func (s *Service) GetActiveCell() (string, error) {
 	s.mu.Lock()
@@ -169,3 +241,20 @@ func (s *Service) GetRangeValues(sheetName, rangeAddr string) ([][]string, error
 
 	return client.GetRangeValues(sheetName, rangeAddr)
 }
+
+// GetVisibleRangeValues retorna os valores das linhas visíveis de um range
+func (s *Service) GetVisibleRangeValues(sheetName, rangeAddr string) ([][]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	if sheetName == "" {
+		sheetName = s.getFirstSheet()
+	}
+
+	return client.GetVisibleRangeValues(sheetName, rangeAddr)
+}
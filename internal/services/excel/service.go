@@ -6,6 +6,7 @@ import (
 	"excel-ai/pkg/logger"
 	"excel-ai/pkg/storage"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 )
@@ -232,8 +233,15 @@ func (s *Service) ConnectFilePath(sessionID, path string) error {
 	return nil
 }
 
+// SaveOptions opções de gravação em disco
+type SaveOptions struct {
+	Stamp          bool   // Registra nas propriedades que o arquivo foi gerado pela IA (conversa, modelo e data)
+	ConversationID string // Conversa registrada na origem (padrão: a conversa vinculada ao undo)
+	Model          string // Modelo registrado na origem
+}
+
 // SaveToDisk salva as alterações de volta ao disco
-func (s *Service) SaveToDisk() error {
+func (s *Service) SaveToDisk(opts SaveOptions) error {
 	logger.ExcelInfo("Salvando alterações no disco")
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -252,6 +260,10 @@ func (s *Service) SaveToDisk() error {
 		return fmt.Errorf("o arquivo não tem um caminho de disco associado. Use ExportFile em vez disso.")
 	}
 
+	if err := s.stampLocked(client, opts); err != nil {
+		return err
+	}
+
 	if err := client.SaveAs(path); err != nil {
 		logger.ExcelError("Erro ao salvar no disco: " + err.Error())
 		// Fornecer mensagem mais amigável se o arquivo estiver bloqueado
@@ -266,6 +278,51 @@ func (s *Service) SaveToDisk() error {
 	return nil
 }
 
+// SaveAs salva o arquivo atual em outro caminho no disco
+func (s *Service) SaveAs(path string, opts SaveOptions) error {
+	logger.ExcelInfo("Salvando arquivo como: " + path)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fileManager == nil || s.currentSessionID == "" {
+		return fmt.Errorf("nenhum arquivo carregado")
+	}
+
+	client, err := s.fileManager.GetClient(s.currentSessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.stampLocked(client, opts); err != nil {
+		return err
+	}
+
+	if err := client.SaveAs(path); err != nil {
+		logger.ExcelError("Erro ao salvar arquivo: " + err.Error())
+		return fmt.Errorf("erro ao salvar em %s: %w", path, err)
+	}
+
+	logger.ExcelInfo("Arquivo salvo com sucesso em: " + path)
+	return nil
+}
+
+// stampLocked grava a origem do arquivo quando solicitado nas opções (chamar com s.mu)
+func (s *Service) stampLocked(client *excel.ExcelizeClient, opts SaveOptions) error {
+	if !opts.Stamp {
+		return nil
+	}
+	convID := opts.ConversationID
+	if convID == "" {
+		convID = s.currentConvID
+	}
+	err := client.StampProvenance(excel.Provenance{ConversationID: convID, Model: opts.Model})
+	if err != nil {
+		return fmt.Errorf("erro ao registrar a origem do arquivo: %w", err)
+	}
+	logger.ExcelDebug("Origem registrada nas propriedades do arquivo")
+	return nil
+}
+
 // ExportFile exporta o arquivo atual como bytes
 func (s *Service) ExportFile() ([]byte, error) {
 	logger.ExcelInfo("Exportando arquivo")
@@ -338,3 +395,56 @@ func (s *Service) CloseFile() {
 	s.currentFileName = ""
 	s.currentSheet = ""
 }
+
+// ===== Sessões múltiplas (usado pelo servidor HTTP) =====
+
+// GetCurrentSessionID retorna o sessionID do arquivo atual
+func (s *Service) GetCurrentSessionID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentSessionID
+}
+
+// ListSessions retorna os sessionIDs abertos no FileManager
+func (s *Service) ListSessions() []string {
+	return s.fileManager.ListSessions()
+}
+
+// UseSession torna uma sessão já carregada a sessão atual
+func (s *Service) UseSession(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.currentSessionID == sessionID {
+		return nil
+	}
+
+	client, err := s.fileManager.GetClient(sessionID)
+	if err != nil {
+		return err
+	}
+
+	s.currentSessionID = sessionID
+	s.currentFileName = ""
+	if path := client.GetFilePath(); path != "" {
+		s.currentFileName = filepath.Base(path)
+	}
+	s.currentSheet = ""
+	if sheets := client.ListSheets(); len(sheets) > 0 {
+		s.currentSheet = sheets[0]
+	}
+	return nil
+}
+
+// CloseSession fecha uma sessão específica
+func (s *Service) CloseSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fileManager.Close(sessionID)
+	if s.currentSessionID == sessionID {
+		s.currentSessionID = ""
+		s.currentFileName = ""
+		s.currentSheet = ""
+	}
+}
@@ -1,6 +1,10 @@
 package excel
 
-import "fmt"
+import (
+	"fmt"
+
+	"excel-ai/pkg/excel"
+)
 
 // CreateNewWorkbook cria um novo arquivo Excel em memória
 func (s *Service) CreateNewWorkbook() (string, error) {
@@ -38,6 +42,23 @@ func (s *Service) DeleteSheet(sheetName string) error {
 	return client.DeleteSheet(sheetName)
 }
 
+// CopySheet duplica uma planilha no arquivo atual. Com srcSession, a planilha é
+// copiada de outro arquivo aberto. Retorna o nome da cópia.
+func (s *Service) CopySheet(srcSession, srcSheet, newName string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	if srcSession == "" || srcSession == s.currentSessionID {
+		return client.CopySheet(srcSheet, newName)
+	}
+	return s.fileManager.CopySheet(srcSession, srcSheet, s.currentSessionID, newName)
+}
+
 // RenameSheet renomeia uma planilha
 func (s *Service) RenameSheet(oldName, newName string) error {
 	s.mu.Lock()
@@ -119,6 +140,94 @@ func (s *Service) DeleteRows(sheet string, rowNumber, count int) error {
 	return client.DeleteRows(sheet, rowNumber, count)
 }
 
+// InsertColumns insere colunas antes ou depois de uma coluna (letra ou cabeçalho)
+func (s *Service) InsertColumns(sheet string, spec excel.ColumnInsert) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.InsertColumns(sheet, spec)
+}
+
+// DeleteColumns exclui colunas (letra, intervalo ou cabeçalho)
+func (s *Service) DeleteColumns(sheet, column string, count int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.DeleteColumns(sheet, column, count)
+}
+
+// MoveColumns move colunas para antes ou depois de outra coluna
+func (s *Service) MoveColumns(sheet, column, before, after string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.MoveColumns(sheet, column, before, after)
+}
+
+// ColumnRange intervalo usado das colunas (ex: "C1:D120")
+func (s *Service) ColumnRange(sheet, column string, count int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return "", err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.ColumnRange(sheet, column, count)
+}
+
+// GroupColumns agrupa (ou desagrupa) as colunas de startCol a endCol
+func (s *Service) GroupColumns(sheet, startCol, endCol string, group bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	if group {
+		return client.GroupColumns(sheet, startCol, endCol)
+	}
+	return client.UngroupColumns(sheet, startCol, endCol)
+}
+
 // MergeCells mescla células
 func (s *Service) MergeCells(sheet, rangeAddr string) error {
 	s.mu.Lock()
@@ -152,3 +261,89 @@ func (s *Service) UnmergeCells(sheet, rangeAddr string) error {
 
 	return client.UnmergeCells(sheet, rangeAddr)
 }
+
+// GetSheets lista as planilhas com posição, visibilidade, cor da aba e exibição
+func (s *Service) GetSheets() ([]excel.SheetInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSheets()
+}
+
+// MoveSheet move uma planilha para a posição indicada (1 = primeira aba)
+func (s *Service) MoveSheet(sheet string, position int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	return client.MoveSheet(sheet, position)
+}
+
+// SetActiveSheet define a aba exibida ao abrir o arquivo
+func (s *Service) SetActiveSheet(sheet string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	return client.SetActiveSheet(sheet)
+}
+
+// SetSheetVisibility exibe ou oculta uma planilha (visible, hidden, veryHidden)
+func (s *Service) SetSheetVisibility(sheet, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	return client.SetSheetVisibility(sheet, state)
+}
+
+// SetTabColor define a cor da aba (vazio remove)
+func (s *Service) SetTabColor(sheet, color string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.SetTabColor(sheet, color)
+}
+
+// SetSheetView altera zoom, linhas de grade, cabeçalhos e modo de exibição
+func (s *Service) SetSheetView(sheet string, view excel.SheetView) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, err := s.getClientLocked()
+	if err != nil {
+		return err
+	}
+
+	if sheet == "" {
+		sheet = s.getFirstSheet()
+	}
+
+	return client.SetSheetView(sheet, view)
+}
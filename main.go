@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	"excel-ai/internal/app" // Novo import
+	"excel-ai/internal/cli"
 	"excel-ai/pkg/logger"
 
 	"github.com/wailsapp/wails/v2"
@@ -19,6 +20,20 @@ import (
 var assets embed.FS
 
 func main() {
+	// Modos headless: excel-ai run --file in.xlsx --prompt "..." --out out.xlsx
+	//                  excel-ai mcp --file pasta.xlsx
+	//                  excel-ai serve --addr 127.0.0.1:8765
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "run":
+			os.Exit(cli.Run(os.Args[2:]))
+		case "mcp":
+			os.Exit(cli.MCP(os.Args[2:]))
+		case "serve":
+			os.Exit(cli.Serve(os.Args[2:]))
+		}
+	}
+
 	// Inicializar logger
 	if err := logger.InitializeFromFile("logger-config.json"); err != nil {
 		// Se falhar ao carregar config, usar padrão
@@ -0,0 +1,277 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"excel-ai/internal/domain"
+	"excel-ai/pkg/logger"
+)
+
+const anthropicVersion = "2023-06-01"
+
+// AnthropicClient cliente para a API Messages da Anthropic
+type AnthropicClient struct {
+	apiKey       string
+	model        string
+	baseURL      string
+	httpClient   *http.Client
+	maxTokens    int
+	maxOutTokens int
+}
+
+// NewAnthropicClient cria um cliente Anthropic
+func NewAnthropicClient(apiKey, model, baseURL string, maxTokens int) *AnthropicClient {
+	if maxTokens <= 0 {
+		maxTokens = 200000
+	}
+	return &AnthropicClient{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: ensureTrailingSlash(baseURL),
+		httpClient: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+		maxTokens:    maxTokens,
+		maxOutTokens: 8192,
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatStream envia mensagens com streaming
+func (c *AnthropicClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string) error) (string, error) {
+	content, _, err := c.stream(ctx, messages, nil, onChunk)
+	return content, err
+}
+
+// ChatStreamWithTools envia mensagens com tools
+func (c *AnthropicClient) ChatStreamWithTools(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	return c.stream(ctx, messages, tools, onChunk)
+}
+
+func (c *AnthropicClient) stream(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	if c.apiKey == "" {
+		return "", nil, fmt.Errorf("API key não configurada")
+	}
+	if c.model == "" {
+		return "", nil, fmt.Errorf("modelo não configurado para o provedor anthropic")
+	}
+
+	prunedMessages := PruneMessages(messages, c.maxTokens)
+	system, converted := toAnthropicMessages(prunedMessages)
+
+	reqBody := struct {
+		Model     string                   `json:"model"`
+		System    string                   `json:"system,omitempty"`
+		Messages  []anthropicMessage       `json:"messages"`
+		MaxTokens int                      `json:"max_tokens"`
+		Stream    bool                     `json:"stream"`
+		Tools     []map[string]interface{} `json:"tools,omitempty"`
+	}{
+		Model:     c.model,
+		System:    system,
+		Messages:  converted,
+		MaxTokens: c.maxOutTokens,
+		Stream:    true,
+	}
+	if len(tools) > 0 {
+		reqBody.Tools = ToAnthropicTools(tools)
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", nil, err
+	}
+
+	url := c.baseURL + "messages"
+	logger.AIDebug(fmt.Sprintf("[ANTHROPIC] POST %s (model=%s, messages=%d, tools=%d)", url, c.model, len(converted), len(tools)))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", nil, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.AIError(fmt.Sprintf("[ANTHROPIC] Erro na requisição: %v", err))
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := providerHTTPError("Anthropic", resp)
+		logger.AIError(fmt.Sprintf("[ANTHROPIC] %v", err))
+		return "", nil, err
+	}
+
+	var fullResponse strings.Builder
+	blocks := make(map[int]*ToolCall)
+	var order []int
+
+	err = readSSE(resp.Body, func(data []byte) (bool, error) {
+		var event struct {
+			Type         string `json:"type"`
+			Index        int    `json:"index"`
+			ContentBlock struct {
+				Type string `json:"type"`
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"content_block"`
+			Delta struct {
+				Type        string `json:"type"`
+				Text        string `json:"text"`
+				Thinking    string `json:"thinking"`
+				PartialJSON string `json:"partial_json"`
+			} `json:"delta"`
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return false, nil
+		}
+
+		switch event.Type {
+		case "content_block_start":
+			if event.ContentBlock.Type == "tool_use" {
+				blocks[event.Index] = &ToolCall{
+					ID:       event.ContentBlock.ID,
+					Type:     "function",
+					Function: FunctionCall{Name: event.ContentBlock.Name},
+				}
+				order = append(order, event.Index)
+			}
+		case "content_block_delta":
+			switch event.Delta.Type {
+			case "text_delta":
+				fullResponse.WriteString(event.Delta.Text)
+				if err := onChunk(event.Delta.Text); err != nil {
+					return false, err
+				}
+			case "thinking_delta":
+				if err := onChunk(":::reasoning:::" + event.Delta.Thinking); err != nil {
+					return false, err
+				}
+			case "input_json_delta":
+				if tc, ok := blocks[event.Index]; ok {
+					tc.Function.Arguments += event.Delta.PartialJSON
+				}
+			}
+		case "message_stop":
+			return true, nil
+		case "error":
+			return false, fmt.Errorf("erro Anthropic: %s", event.Error.Message)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var toolCalls []ToolCall
+	for _, idx := range order {
+		tc := blocks[idx]
+		if tc.Function.Arguments == "" {
+			tc.Function.Arguments = "{}"
+		}
+		toolCalls = append(toolCalls, *tc)
+	}
+
+	return fullResponse.String(), toolCalls, nil
+}
+
+// toAnthropicMessages extrai o system prompt e garante alternância user/assistant
+// começando por user, como exige a API Messages
+func toAnthropicMessages(messages []Message) (string, []anthropicMessage) {
+	var system []string
+	var result []anthropicMessage
+
+	for _, m := range messages {
+		text := m.Content
+		if len(m.ToolCalls) > 0 {
+			text = strings.TrimSpace(text + "\n" + toolCallsSummary(m.ToolCalls))
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		role := "user"
+		switch m.Role {
+		case "system":
+			system = append(system, text)
+			continue
+		case "assistant":
+			role = "assistant"
+		}
+
+		if len(result) == 0 && role == "assistant" {
+			result = append(result, anthropicMessage{Role: "user", Content: "(continuação)"})
+		}
+		if n := len(result); n > 0 && result[n-1].Role == role {
+			result[n-1].Content += "\n\n" + text
+			continue
+		}
+		result = append(result, anthropicMessage{Role: role, Content: text})
+	}
+
+	return strings.Join(system, "\n\n"), result
+}
+
+func (c *AnthropicClient) setHeaders(req *http.Request) {
+	req.Header.Set("x-api-key", c.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+}
+
+// GetAvailableModels consulta GET {baseURL}models
+func (c *AnthropicClient) GetAvailableModels() ([]domain.ModelInfo, error) {
+	req, err := http.NewRequest("GET", c.baseURL+"models?limit=100", nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, providerHTTPError("Anthropic", resp)
+	}
+
+	var payload struct {
+		Data []struct {
+			ID          string `json:"id"`
+			DisplayName string `json:"display_name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("resposta de modelos inválida: %w", err)
+	}
+
+	models := make([]domain.ModelInfo, 0, len(payload.Data))
+	for _, m := range payload.Data {
+		name := m.DisplayName
+		if name == "" {
+			name = m.ID
+		}
+		models = append(models, domain.ModelInfo{
+			ID:            m.ID,
+			Name:          name,
+			ContextLength: 200000,
+		})
+	}
+	return models, nil
+}
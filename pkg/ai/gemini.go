@@ -0,0 +1,255 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"excel-ai/internal/domain"
+	"excel-ai/pkg/logger"
+)
+
+// GeminiClient cliente para a API Google Gemini (generativelanguage)
+type GeminiClient struct {
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+	maxTokens  int
+}
+
+// NewGeminiClient cria um cliente Gemini
+func NewGeminiClient(apiKey, model, baseURL string, maxTokens int) *GeminiClient {
+	if model == "" {
+		model = "gemini-2.5-flash"
+	}
+	if maxTokens <= 0 {
+		maxTokens = 1000000 // Gemini tem janela de contexto de ~1M tokens
+	}
+	return &GeminiClient{
+		apiKey:  apiKey,
+		model:   strings.TrimPrefix(model, "models/"),
+		baseURL: ensureTrailingSlash(baseURL),
+		httpClient: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+		maxTokens: maxTokens,
+	}
+}
+
+type geminiPart struct {
+	Text         string          `json:"text,omitempty"`
+	Thought      bool            `json:"thought,omitempty"`
+	FunctionCall *geminiFuncCall `json:"functionCall,omitempty"`
+}
+
+type geminiFuncCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+// ChatStream envia mensagens com streaming
+func (c *GeminiClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string) error) (string, error) {
+	content, _, err := c.stream(ctx, messages, nil, onChunk)
+	return content, err
+}
+
+// ChatStreamWithTools envia mensagens com tools
+func (c *GeminiClient) ChatStreamWithTools(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	return c.stream(ctx, messages, tools, onChunk)
+}
+
+func (c *GeminiClient) stream(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	if c.apiKey == "" {
+		return "", nil, fmt.Errorf("API key não configurada")
+	}
+
+	prunedMessages := PruneMessages(messages, c.maxTokens)
+	system, contents := toGeminiContents(prunedMessages)
+
+	reqBody := struct {
+		SystemInstruction *geminiContent           `json:"systemInstruction,omitempty"`
+		Contents          []geminiContent          `json:"contents"`
+		Tools             []map[string]interface{} `json:"tools,omitempty"`
+	}{
+		SystemInstruction: system,
+		Contents:          contents,
+	}
+	if len(tools) > 0 {
+		reqBody.Tools = ToGeminiTools(tools)
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", nil, err
+	}
+
+	url := fmt.Sprintf("%smodels/%s:streamGenerateContent?alt=sse", c.baseURL, c.model)
+	logger.AIDebug(fmt.Sprintf("[GEMINI] POST %s (messages=%d, tools=%d)", url, len(prunedMessages), len(tools)))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.AIError(fmt.Sprintf("[GEMINI] Erro na requisição: %v", err))
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := providerHTTPError("Gemini", resp)
+		logger.AIError(fmt.Sprintf("[GEMINI] %v", err))
+		return "", nil, err
+	}
+
+	var fullResponse strings.Builder
+	var toolCalls []ToolCall
+
+	err = readSSE(resp.Body, func(data []byte) (bool, error) {
+		var chunk struct {
+			Candidates []struct {
+				Content geminiContent `json:"content"`
+			} `json:"candidates"`
+		}
+		if err := json.Unmarshal(data, &chunk); err != nil || len(chunk.Candidates) == 0 {
+			return false, nil
+		}
+
+		for _, part := range chunk.Candidates[0].Content.Parts {
+			switch {
+			case part.FunctionCall != nil:
+				args := string(part.FunctionCall.Args)
+				if args == "" || args == "null" {
+					args = "{}"
+				}
+				toolCalls = append(toolCalls, ToolCall{
+					ID:   generateToolCallID(),
+					Type: "function",
+					Function: FunctionCall{
+						Name:      part.FunctionCall.Name,
+						Arguments: args,
+					},
+				})
+			case part.Thought && part.Text != "":
+				if err := onChunk(":::reasoning:::" + part.Text); err != nil {
+					return false, err
+				}
+			case part.Text != "":
+				fullResponse.WriteString(part.Text)
+				if err := onChunk(part.Text); err != nil {
+					return false, err
+				}
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fullResponse.String(), toolCalls, nil
+}
+
+// toGeminiContents separa o system prompt e converte papéis (assistant → model).
+// Mensagens consecutivas do mesmo papel são unidas, pois o Gemini exige alternância.
+func toGeminiContents(messages []Message) (*geminiContent, []geminiContent) {
+	var systemParts []geminiPart
+	var contents []geminiContent
+
+	for _, m := range messages {
+		text := m.Content
+		if len(m.ToolCalls) > 0 {
+			text = strings.TrimSpace(text + "\n" + toolCallsSummary(m.ToolCalls))
+		}
+		if text == "" {
+			continue
+		}
+
+		role := "user"
+		switch m.Role {
+		case "system":
+			systemParts = append(systemParts, geminiPart{Text: text})
+			continue
+		case "assistant":
+			role = "model"
+		}
+
+		if n := len(contents); n > 0 && contents[n-1].Role == role {
+			contents[n-1].Parts = append(contents[n-1].Parts, geminiPart{Text: text})
+			continue
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: []geminiPart{{Text: text}}})
+	}
+
+	if len(systemParts) == 0 {
+		return nil, contents
+	}
+	return &geminiContent{Parts: systemParts}, contents
+}
+
+// GetAvailableModels consulta GET {baseURL}models e filtra os modelos de geração
+func (c *GeminiClient) GetAvailableModels() ([]domain.ModelInfo, error) {
+	req, err := http.NewRequest("GET", c.baseURL+"models?pageSize=1000", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-goog-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, providerHTTPError("Gemini", resp)
+	}
+
+	var payload struct {
+		Models []struct {
+			Name                       string   `json:"name"`
+			DisplayName                string   `json:"displayName"`
+			Description                string   `json:"description"`
+			InputTokenLimit            int      `json:"inputTokenLimit"`
+			SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("resposta de modelos inválida: %w", err)
+	}
+
+	var models []domain.ModelInfo
+	for _, m := range payload.Models {
+		supportsChat := false
+		for _, method := range m.SupportedGenerationMethods {
+			if method == "generateContent" {
+				supportsChat = true
+				break
+			}
+		}
+		if !supportsChat {
+			continue
+		}
+		models = append(models, domain.ModelInfo{
+			ID:            strings.TrimPrefix(m.Name, "models/"),
+			Name:          m.DisplayName,
+			Description:   m.Description,
+			ContextLength: m.InputTokenLimit,
+		})
+	}
+	return models, nil
+}
@@ -0,0 +1,197 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"excel-ai/internal/domain"
+	"excel-ai/pkg/logger"
+)
+
+// LocalClient cliente para modelos rodando na própria máquina (Ollama, llama.cpp server).
+// Usa o endpoint OpenAI-compatível /v1 e, para modelos sem function calling nativo,
+// descreve as ferramentas no prompt e extrai as chamadas do texto.
+type LocalClient struct {
+	*OpenAIClient
+
+	mu          sync.Mutex
+	textToolsOf map[string]bool // modelos que rejeitaram o parâmetro tools
+}
+
+// NewLocalClient cria um cliente para um servidor local
+func NewLocalClient(apiKey, model, baseURL string, maxTokens int) *LocalClient {
+	if maxTokens <= 0 {
+		maxTokens = 8192 // Modelos locais costumam rodar com contexto reduzido
+	}
+	inner := NewOpenAIClient(ProviderLocal, apiKey, model, baseURL, maxTokens)
+	inner.httpClient.Timeout = 10 * time.Minute // Inferência em CPU pode ser lenta
+	return &LocalClient{
+		OpenAIClient: inner,
+		textToolsOf:  make(map[string]bool),
+	}
+}
+
+// ChatStreamWithTools tenta function calling nativo e cai para tool calls em texto
+// quando o servidor informa que o modelo não suporta tools
+func (c *LocalClient) ChatStreamWithTools(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	if len(tools) > 0 && !c.usesTextTools() {
+		content, toolCalls, err := c.stream(ctx, messages, tools, onChunk)
+		if err == nil {
+			if len(toolCalls) == 0 {
+				toolCalls, content = ParseToolCallsFromText(content)
+			}
+			return content, toolCalls, nil
+		}
+		if !isToolsUnsupportedError(err) {
+			return "", nil, err
+		}
+		logger.AIWarn(fmt.Sprintf("[local] Modelo %s não suporta function calling nativo, usando tool calls em texto", c.model))
+		c.mu.Lock()
+		c.textToolsOf[c.model] = true
+		c.mu.Unlock()
+	}
+
+	return c.chatWithTextTools(ctx, messages, tools, onChunk)
+}
+
+func (c *LocalClient) usesTextTools() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.textToolsOf[c.model]
+}
+
+// chatWithTextTools envia as ferramentas como instruções no system prompt.
+// O texto é repassado ao onChunk até começar um JSON de tool call, que fica retido.
+func (c *LocalClient) chatWithTextTools(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	withTools := make([]Message, 0, len(messages)+1)
+	if len(tools) > 0 {
+		withTools = append(withTools, Message{Role: "system", Content: textToolsPrompt(tools)})
+	}
+	withTools = append(withTools, messages...)
+
+	var full strings.Builder
+	sent := 0
+	holding := false
+	content, _, err := c.stream(ctx, withTools, nil, func(chunk string) error {
+		if strings.HasPrefix(chunk, ":::reasoning:::") {
+			return onChunk(chunk)
+		}
+		full.WriteString(chunk)
+		if !holding && IsPartialToolCallJSON(full.String()) {
+			holding = true
+		}
+		if holding {
+			return nil
+		}
+		sent = full.Len()
+		return onChunk(chunk)
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	toolCalls, cleaned := ParseToolCallsFromText(content)
+	if len(toolCalls) == 0 && holding {
+		// Não era tool call: liberar o texto retido
+		if err := onChunk(full.String()[sent:]); err != nil {
+			return "", nil, err
+		}
+	}
+	return cleaned, toolCalls, nil
+}
+
+// textToolsPrompt descreve as ferramentas e o formato JSON esperado
+func textToolsPrompt(tools []Tool) string {
+	var sb strings.Builder
+	sb.WriteString("Você tem acesso às ferramentas abaixo. Para usar uma ferramenta, responda SOMENTE com um bloco JSON no formato:\n")
+	sb.WriteString("```json\n{\"name\": \"nome_da_ferramenta\", \"arguments\": {...}}\n```\n")
+	sb.WriteString("Use uma ferramenta por bloco. Sem ferramenta, responda normalmente em texto.\n\nFERRAMENTAS:\n")
+	for _, tool := range tools {
+		params, _ := json.Marshal(tool.Function.Parameters)
+		sb.WriteString(fmt.Sprintf("- %s: %s\n  parâmetros: %s\n", tool.Function.Name, tool.Function.Description, params))
+	}
+	return sb.String()
+}
+
+// isToolsUnsupportedError reconhece as mensagens do Ollama ("does not support tools")
+// e do llama.cpp ("tools param requires --jinja flag")
+func isToolsUnsupportedError(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	return strings.Contains(strings.ToLower(httpErr.Message), "tool")
+}
+
+// GetAvailableModels consulta /api/tags (Ollama) e, se indisponível, /v1/models (llama.cpp)
+func (c *LocalClient) GetAvailableModels() ([]domain.ModelInfo, error) {
+	models, err := c.ollamaTags()
+	if err == nil && len(models) > 0 {
+		return models, nil
+	}
+	if err != nil {
+		logger.AIDebug(fmt.Sprintf("[local] /api/tags indisponível (%v), tentando /v1/models", err))
+	}
+	return c.OpenAIClient.GetAvailableModels()
+}
+
+func (c *LocalClient) ollamaTags() ([]domain.ModelInfo, error) {
+	root := strings.TrimSuffix(strings.TrimSuffix(c.baseURL, "/"), "/v1")
+	req, err := http.NewRequest("GET", root+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, providerHTTPError("local", resp)
+	}
+
+	var payload struct {
+		Models []struct {
+			Name    string `json:"name"`
+			Size    int64  `json:"size"`
+			Details struct {
+				Family            string `json:"family"`
+				ParameterSize     string `json:"parameter_size"`
+				QuantizationLevel string `json:"quantization_level"`
+			} `json:"details"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("resposta de /api/tags inválida: %w", err)
+	}
+
+	models := make([]domain.ModelInfo, 0, len(payload.Models))
+	for _, m := range payload.Models {
+		var details []string
+		for _, d := range []string{m.Details.Family, m.Details.ParameterSize, m.Details.QuantizationLevel} {
+			if d != "" {
+				details = append(details, d)
+			}
+		}
+		if m.Size > 0 {
+			details = append(details, fmt.Sprintf("%.1f GB", float64(m.Size)/(1<<30)))
+		}
+		models = append(models, domain.ModelInfo{
+			ID:          m.Name,
+			Name:        m.Name,
+			Description: "Modelo local (" + strings.Join(details, ", ") + ")",
+			Pricing:     domain.ModelPricing{Prompt: "0", Completion: "0"},
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
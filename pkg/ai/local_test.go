@@ -0,0 +1,35 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIsToolsUnsupportedError(t *testing.T) {
+	response := func(status int, body string) error {
+		return providerHTTPError("local", &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Ollama", response(http.StatusBadRequest, `{"error":"registry.ollama.ai/library/gemma:2b does not support tools"}`), true},
+		{"llama.cpp", response(http.StatusBadRequest, `{"error":{"message":"tools param requires --jinja flag"}}`), true},
+		{"encapsulado", fmt.Errorf("stream: %w", response(http.StatusBadRequest, "does not support tools")), true},
+		{"outro erro 400", response(http.StatusBadRequest, `{"error":"invalid model"}`), false},
+		{"status diferente", response(http.StatusInternalServerError, "tool call failed"), false},
+		{"erro sem status", errors.New("requisição inválida: does not support tools"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isToolsUnsupportedError(tt.err); got != tt.want {
+				t.Errorf("isToolsUnsupportedError(%v) = %v, esperado %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
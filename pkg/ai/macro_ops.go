@@ -0,0 +1,591 @@
+package ai
+
+// =============================================================================
+// Catálogo das operações aceitas por execute_macro
+// =============================================================================
+
+// Propriedades reutilizadas pelas operações
+var (
+	propSheet = FunctionProperty{Type: "string", Description: "Nome da planilha"}
+	propRange = FunctionProperty{Type: "string", Description: "Intervalo de células, nome definido ou tabela (ex: 'A1:C10', 'Vendas', 'Pedidos[Valor]')"}
+	propCell  = FunctionProperty{Type: "string", Description: "Endereço da célula (ex: 'A1')"}
+	propName  = FunctionProperty{Type: "string", Description: "Nome do objeto"}
+
+	propPivotDataFields = FunctionProperty{Type: "array", Description: "Campos de valores", Items: &FunctionProperty{
+		Type: "object",
+		Properties: map[string]FunctionProperty{
+			"field":    {Type: "string", Description: "Cabeçalho da coluna de origem"},
+			"function": {Type: "string", Description: "Agregação (padrão sum)", Enum: []string{"sum", "count", "average", "max", "min"}},
+			"name":     {Type: "string", Description: "Rótulo exibido"},
+			"showAs":   {Type: "string", Description: "Mostrar valores como", Enum: []string{"percent_of_total", "percent_of_row", "percent_of_column"}},
+		},
+		Required: []string{"field"},
+	}}
+
+	chartTypes = []string{"column", "bar", "line", "area", "pie", "doughnut", "radar", "scatter", "bubble", "combo"}
+)
+
+// pivotFieldList lista de cabeçalhos de uma área da tabela dinâmica
+func pivotFieldList(description string) FunctionProperty {
+	return FunctionProperty{Type: "array", Description: description, Items: &FunctionProperty{Type: "string"}}
+}
+
+// macroOp cria a declaração de uma operação de macro
+func macroOp(name, description string, props map[string]FunctionProperty, required ...string) Tool {
+	if props == nil {
+		props = map[string]FunctionProperty{}
+	}
+	return Tool{
+		Type: "function",
+		Function: FunctionDeclaration{
+			Name:        name,
+			Description: description,
+			Parameters: FunctionParameters{
+				Type:       "object",
+				Properties: props,
+				Required:   required,
+			},
+		},
+	}
+}
+
+// GetMacroOperations retorna cada operação de execute_macro como uma ferramenta independente.
+// Os nomes usam underscore; o executor normaliza para o formato interno (write_cell -> write).
+func GetMacroOperations() []Tool {
+	return []Tool{
+		// BÁSICO
+		macroOp("create_sheet", "Cria uma nova planilha.", map[string]FunctionProperty{
+			"name": {Type: "string", Description: "Nome da nova planilha"},
+		}, "name"),
+		macroOp("delete_sheet", "Exclui uma planilha.", map[string]FunctionProperty{
+			"name": {Type: "string", Description: "Nome da planilha"},
+		}, "name"),
+		macroOp("rename_sheet", "Renomeia uma planilha.", map[string]FunctionProperty{
+			"oldName": {Type: "string", Description: "Nome atual"},
+			"newName": {Type: "string", Description: "Novo nome"},
+		}, "oldName", "newName"),
+		macroOp("copy_sheet", "Duplica uma planilha com dados, fórmulas, formatação, mesclagens, validações, comentários, imagens e tabelas.", map[string]FunctionProperty{
+			"sheet":   {Type: "string", Description: "Planilha de origem"},
+			"name":    {Type: "string", Description: "Nome da cópia (padrão: 'Origem (2)')"},
+			"session": {Type: "string", Description: "Sessão de outro arquivo aberto de onde copiar a planilha (padrão: arquivo atual)"},
+		}, "sheet"),
+		macroOp("write_cell", "Escreve um valor ou fórmula (iniciando com '=') em uma célula.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"cell":  propCell,
+			"value": {Type: "string", Description: "Valor ou fórmula"},
+		}, "sheet", "cell", "value"),
+		macroOp("write_range", "Escreve uma matriz de valores a partir de uma célula.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"cell":  {Type: "string", Description: "Célula inicial (ex: 'A1')"},
+			"data": {Type: "array", Description: "Linhas de valores", Items: &FunctionProperty{
+				Type: "array", Items: &FunctionProperty{Type: "string"},
+			}},
+		}, "sheet", "cell", "data"),
+		macroOp("clear_range", "Limpa os valores de um intervalo.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+		}, "sheet", "range"),
+		macroOp("set_formula", "Define a fórmula de uma célula.", map[string]FunctionProperty{
+			"sheet":   propSheet,
+			"cell":    propCell,
+			"formula": {Type: "string", Description: "Fórmula (ex: '=SUM(A1:A10)')"},
+		}, "sheet", "cell", "formula"),
+		macroOp("recalculate", "Recalcula todas as fórmulas da pasta, grava os resultados e lista as células com erro (#DIV/0!, #REF!, referências circulares).", nil),
+
+		// FORMATAÇÃO
+		macroOp("format_range", "Aplica formatação de fonte, preenchimento, número e alinhamento. Apenas os campos informados mudam; o restante do estilo das células é mantido.", map[string]FunctionProperty{
+			"sheet":           propSheet,
+			"range":           propRange,
+			"bold":            {Type: "boolean", Description: "Negrito (false remove)"},
+			"italic":          {Type: "boolean", Description: "Itálico (false remove)"},
+			"underline":       {Type: "string", Description: "Sublinhado", Enum: []string{"single", "double", "none"}},
+			"strikethrough":   {Type: "boolean", Description: "Tachado"},
+			"fontFamily":      {Type: "string", Description: "Fonte (ex: 'Calibri', 'Arial')"},
+			"fontSize":        {Type: "number", Description: "Tamanho da fonte"},
+			"fontColor":       {Type: "string", Description: "Cor da fonte em hex (ex: '#FF0000')"},
+			"bgColor":         {Type: "string", Description: "Cor de fundo em hex ('none' remove)"},
+			"numberFormat":    {Type: "string", Description: "Formato numérico: currency (R$), accounting, number, integer, percent, percent0, date, datetime, time, scientific, text, general ou código personalizado (ex: '#,##0.000')"},
+			"horizontalAlign": {Type: "string", Description: "Alinhamento horizontal", Enum: []string{"left", "center", "right", "justify", "fill", "distributed", "centerContinuous"}},
+			"verticalAlign":   {Type: "string", Description: "Alinhamento vertical", Enum: []string{"top", "center", "bottom", "justify", "distributed"}},
+			"wrapText":        {Type: "boolean", Description: "Quebrar texto automaticamente"},
+			"indent":          {Type: "integer", Description: "Recuo (níveis)"},
+			"textRotation":    {Type: "integer", Description: "Rotação do texto em graus (-90 a 90, 255 = vertical)"},
+		}, "sheet", "range"),
+		macroOp("autofit_columns", "Ajusta a largura das colunas ao conteúdo.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+		}, "sheet"),
+		macroOp("set_borders", "Aplica bordas a um intervalo.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+			"style": {Type: "string", Description: "Estilo da borda", Enum: []string{"thin", "medium"}},
+		}, "sheet", "range"),
+		macroOp("merge_cells", "Mescla as células de um intervalo.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+		}, "sheet", "range"),
+		macroOp("unmerge_cells", "Desfaz a mesclagem de um intervalo.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+		}, "sheet", "range"),
+		macroOp("set_column_width", "Define a largura das colunas.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": {Type: "string", Description: "Colunas (ex: 'A:C')"},
+			"width": {Type: "number", Description: "Largura"},
+		}, "sheet", "range", "width"),
+		macroOp("set_row_height", "Define a altura das linhas.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"range":  {Type: "string", Description: "Linhas (ex: '1:5')"},
+			"height": {Type: "number", Description: "Altura"},
+		}, "sheet", "range", "height"),
+		macroOp("conditional_format", "Adiciona uma regra de formatação condicional. Regras de destaque (cellValue, text, formula, top, bottom, aboveAverage, belowAverage, duplicate, unique, blanks, errors...) usam 'format'; colorScale, dataBar e iconSet usam os próprios campos.", map[string]FunctionProperty{
+			"sheet":    propSheet,
+			"range":    {Type: "string", Description: "Intervalo (ex: 'B2:B100'; vários separados por vírgula)"},
+			"type":     {Type: "string", Description: "Tipo de regra", Enum: []string{"cellValue", "text", "formula", "top", "bottom", "aboveAverage", "belowAverage", "duplicate", "unique", "blanks", "noBlanks", "errors", "noErrors", "colorScale", "dataBar", "iconSet"}},
+			"criteria": {Type: "string", Description: "Operador de cellValue (greaterThan, greaterThanOrEqual, lessThan, lessThanOrEqual, equal, notEqual, between, notBetween) ou de text (containsText, notContains, beginsWith, endsWith)"},
+			"value":    {Type: "string", Description: "Valor comparado (número, texto ou '=referência'); em top/bottom, a quantidade N (padrão 10)"},
+			"minValue": {Type: "string", Description: "Limite inferior de between/notBetween ou valor do ponto mínimo em escalas e barras"},
+			"midValue": {Type: "string", Description: "Valor do ponto médio da escala de 3 cores"},
+			"maxValue": {Type: "string", Description: "Limite superior de between/notBetween ou valor do ponto máximo em escalas e barras"},
+			"formula":  {Type: "string", Description: "Fórmula verdadeira/falsa relativa à primeira célula do intervalo (ex: '=$C2>$D2')"},
+			"percent":  {Type: "boolean", Description: "top/bottom em percentual em vez de quantidade"},
+			"format": {Type: "object", Description: "Destaque das regras de célula (padrão: fundo vermelho claro)", Properties: map[string]FunctionProperty{
+				"bold":         {Type: "boolean", Description: "Negrito"},
+				"italic":       {Type: "boolean", Description: "Itálico"},
+				"fontColor":    {Type: "string", Description: "Cor da fonte em hex"},
+				"bgColor":      {Type: "string", Description: "Cor de fundo em hex"},
+				"numberFormat": {Type: "string", Description: "Formato numérico (mesmos atalhos de format_range)"},
+			}},
+			"minType":    {Type: "string", Description: "Tipo do ponto mínimo", Enum: []string{"min", "num", "percent", "percentile", "formula"}},
+			"midType":    {Type: "string", Description: "Tipo do ponto médio (padrão percentile 50)", Enum: []string{"num", "percent", "percentile", "formula"}},
+			"maxType":    {Type: "string", Description: "Tipo do ponto máximo", Enum: []string{"max", "num", "percent", "percentile", "formula"}},
+			"minColor":   {Type: "string", Description: "Cor do mínimo da escala (hex)"},
+			"midColor":   {Type: "string", Description: "Cor do ponto médio (hex); informe para escala de 3 cores"},
+			"maxColor":   {Type: "string", Description: "Cor do máximo da escala (hex)"},
+			"barColor":   {Type: "string", Description: "Cor da barra de dados (hex)"},
+			"barOnly":    {Type: "boolean", Description: "Exibe só a barra, sem o valor"},
+			"iconStyle":  {Type: "string", Description: "Conjunto de ícones", Enum: []string{"3Arrows", "3ArrowsGray", "3Flags", "3Signs", "3Symbols", "3Symbols2", "3TrafficLights1", "3TrafficLights2", "4Arrows", "4ArrowsGray", "4Rating", "4RedToBlack", "4TrafficLights", "5Arrows", "5ArrowsGray", "5Quarters", "5Rating"}},
+			"reverse":    {Type: "boolean", Description: "Inverte a ordem dos ícones"},
+			"iconsOnly":  {Type: "boolean", Description: "Exibe só os ícones, sem o valor"},
+			"stopIfTrue": {Type: "boolean", Description: "Não avalia as regras seguintes quando esta for verdadeira"},
+		}, "sheet", "range", "type"),
+		macroOp("remove_conditional_format", "Retira o intervalo das regras de formatação condicional: regras cobertas por inteiro são removidas e as demais passam a valer só para o restante. Consulte as regras existentes com query_batch 'conditional_formats'.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+		}, "sheet", "range"),
+
+		// ESTRUTURA
+		macroOp("insert_rows", "Insere linhas.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"row":   {Type: "integer", Description: "Linha inicial (1-based)"},
+			"count": {Type: "integer", Description: "Quantidade de linhas"},
+		}, "sheet", "row", "count"),
+		macroOp("delete_rows", "Exclui linhas.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"row":   {Type: "integer", Description: "Linha inicial (1-based)"},
+			"count": {Type: "integer", Description: "Quantidade de linhas"},
+		}, "sheet", "row", "count"),
+		macroOp("insert_columns", "Insere colunas antes ou depois de uma coluna (letra ou cabeçalho, ex: after 'Preço'). Fórmulas, tabelas, mesclagens, formatação condicional e validações acompanham as colunas deslocadas.", map[string]FunctionProperty{
+			"sheet":   propSheet,
+			"before":  {Type: "string", Description: "Coluna antes da qual inserir (letra, cabeçalho ou Tabela[Coluna])"},
+			"after":   {Type: "string", Description: "Coluna depois da qual inserir. Sem before/after, insere depois da última coluna usada"},
+			"count":   {Type: "integer", Description: "Quantidade de colunas (padrão: 1 ou a quantidade de cabeçalhos)"},
+			"headers": {Type: "array", Description: "Cabeçalhos das novas colunas", Items: &FunctionProperty{Type: "string"}},
+			"formula": {Type: "string", Description: "Fórmula da primeira linha de dados, com as colunas já na nova posição (ex: '=D2-C2'); preenchida até a última linha"},
+		}, "sheet"),
+		macroOp("delete_columns", "Exclui colunas. Referências às colunas excluídas viram #REF!.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"column": {Type: "string", Description: "Coluna: letra ('C'), intervalo ('C:E') ou cabeçalho"},
+			"count":  {Type: "integer", Description: "Quantidade de colunas a partir da indicada"},
+		}, "sheet", "column"),
+		macroOp("move_columns", "Move colunas para antes ou depois de outra, como recortar e inserir no Excel. As fórmulas continuam apontando para as mesmas células.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"column": {Type: "string", Description: "Coluna a mover: letra, intervalo ('C:E') ou cabeçalho"},
+			"before": {Type: "string", Description: "Coluna de destino (inserir antes dela)"},
+			"after":  {Type: "string", Description: "Coluna de destino (inserir depois dela)"},
+		}, "sheet", "column"),
+		macroOp("group_columns", "Agrupa colunas (estrutura de tópicos).", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"column": {Type: "string", Description: "Primeira coluna ou intervalo ('C:E'); aceita cabeçalhos"},
+			"end":    {Type: "string", Description: "Última coluna (opcional)"},
+		}, "sheet", "column"),
+		macroOp("ungroup_columns", "Desfaz o agrupamento de colunas.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"column": {Type: "string", Description: "Primeira coluna ou intervalo ('C:E'); aceita cabeçalhos"},
+			"end":    {Type: "string", Description: "Última coluna (opcional)"},
+		}, "sheet", "column"),
+		macroOp("freeze_pane", "Congela linhas e/ou colunas.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"cell":  {Type: "string", Description: "Célula de referência do congelamento (ex: 'B2')"},
+			"rows":  {Type: "integer", Description: "Linhas a congelar"},
+			"cols":  {Type: "integer", Description: "Colunas a congelar"},
+		}, "sheet"),
+		macroOp("unfreeze_pane", "Remove o congelamento de painéis.", map[string]FunctionProperty{
+			"sheet": propSheet,
+		}, "sheet"),
+		macroOp("hide_sheet", "Oculta uma planilha. Se for a aba ativa, outra aba visível passa a ser a ativa.", map[string]FunctionProperty{
+			"sheet":      propSheet,
+			"veryHidden": {Type: "boolean", Description: "Muito oculta: não aparece em Reexibir do Excel (ex: planilhas de apoio)"},
+		}, "sheet"),
+		macroOp("show_sheet", "Reexibe uma planilha oculta.", map[string]FunctionProperty{
+			"sheet": propSheet,
+		}, "sheet"),
+		macroOp("move_sheet", "Muda a posição de uma aba. Informe position ou before/after.", map[string]FunctionProperty{
+			"sheet":    propSheet,
+			"position": {Type: "integer", Description: "Nova posição (1 = primeira aba)"},
+			"before":   {Type: "string", Description: "Colocar antes desta aba"},
+			"after":    {Type: "string", Description: "Colocar depois desta aba"},
+		}, "sheet"),
+		macroOp("set_active_sheet", "Define a aba exibida ao abrir o arquivo.", map[string]FunctionProperty{
+			"sheet": propSheet,
+		}, "sheet"),
+		macroOp("set_tab_color", "Define a cor da aba.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"color": {Type: "string", Description: "Cor hex (ex: '#1F4E78'); vazio ou 'none' remove"},
+		}, "sheet", "color"),
+		macroOp("set_sheet_view", "Ajusta a exibição da planilha na tela. Informe só o que deve mudar; consulte a exibição atual com a query 'sheets'.", map[string]FunctionProperty{
+			"sheet":       propSheet,
+			"zoom":        {Type: "integer", Description: "Zoom em % (10-400)"},
+			"gridlines":   {Type: "boolean", Description: "Exibir linhas de grade"},
+			"headings":    {Type: "boolean", Description: "Exibir cabeçalhos de linha e coluna"},
+			"showZeros":   {Type: "boolean", Description: "Exibir valores zero"},
+			"rightToLeft": {Type: "boolean", Description: "Planilha da direita para a esquerda"},
+			"view":        {Type: "string", Description: "Modo de exibição", Enum: []string{"normal", "pageLayout", "pageBreakPreview"}},
+			"topLeftCell": {Type: "string", Description: "Primeira célula visível (rolagem)"},
+		}, "sheet"),
+
+		// OBJETOS
+		macroOp("create_chart", "Cria um gráfico. Use 'range' (1ª coluna = categorias, demais = séries) ou 'series' para controle total. Séries com 'type' diferente ou 'secondaryAxis' formam um gráfico combinado.", map[string]FunctionProperty{
+			"sheet":     propSheet,
+			"range":     {Type: "string", Description: "Intervalo de dados com cabeçalho (ex: 'A1:C10')"},
+			"chartType": {Type: "string", Description: "Tipo do gráfico", Enum: chartTypes},
+			"title":     {Type: "string", Description: "Título do gráfico"},
+			"series": {Type: "array", Description: "Séries explícitas", Items: &FunctionProperty{
+				Type: "object",
+				Properties: map[string]FunctionProperty{
+					"name":          {Type: "string", Description: "Nome ou célula do nome (ex: 'B1')"},
+					"categories":    {Type: "string", Description: "Intervalo das categorias (ex: 'A2:A10')"},
+					"values":        {Type: "string", Description: "Intervalo dos valores (ex: 'B2:B10')"},
+					"sizes":         {Type: "string", Description: "Tamanho das bolhas (bubble)"},
+					"type":          {Type: "string", Description: "Tipo desta série (gráfico combinado)", Enum: chartTypes},
+					"secondaryAxis": {Type: "boolean", Description: "Plotar no eixo secundário"},
+				},
+				Required: []string{"values"},
+			}},
+			"anchor":             {Type: "string", Description: "Célula do canto superior esquerdo (padrão: à direita dos dados)"},
+			"width":              {Type: "integer", Description: "Largura em pixels (padrão 480)"},
+			"height":             {Type: "integer", Description: "Altura em pixels (padrão 290)"},
+			"xAxisTitle":         {Type: "string", Description: "Título do eixo X"},
+			"yAxisTitle":         {Type: "string", Description: "Título do eixo Y"},
+			"secondaryAxisTitle": {Type: "string", Description: "Título do eixo Y secundário"},
+			"legend":             {Type: "string", Description: "Posição da legenda", Enum: []string{"top", "bottom", "left", "right", "top_right", "none"}},
+			"dataLabels":         {Type: "boolean", Description: "Exibir rótulos de dados"},
+			"stacked":            {Type: "boolean", Description: "Empilhado (column, bar, area)"},
+			"percent":            {Type: "boolean", Description: "Empilhado 100% (column, bar, area)"},
+		}, "sheet", "chartType"),
+		macroOp("delete_chart", "Exclui um gráfico. Consulte os gráficos existentes com a query 'charts'.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"name":  {Type: "string", Description: "Nome (ex: 'Chart 2'), título ou célula de ancoragem (ex: 'E1')"},
+		}, "sheet", "name"),
+		macroOp("insert_image", "Insere uma imagem (ex: logotipo) a partir de um arquivo ou de base64. Informe width ou height para redimensionar mantendo a proporção.", map[string]FunctionProperty{
+			"sheet":       propSheet,
+			"cell":        {Type: "string", Description: "Célula do canto superior esquerdo (padrão A1)"},
+			"path":        {Type: "string", Description: "Caminho do arquivo de imagem, dentro da pasta da planilha aberta"},
+			"base64":      {Type: "string", Description: "Conteúdo em base64 ou data URI (data:image/png;base64,...)"},
+			"format":      {Type: "string", Description: "Formato quando não dá para deduzir (png, jpeg, gif, bmp, svg)"},
+			"width":       {Type: "integer", Description: "Largura em pixels"},
+			"height":      {Type: "integer", Description: "Altura em pixels"},
+			"scale":       {Type: "number", Description: "Escala quando width/height não são informados (1 = 100%)"},
+			"offsetX":     {Type: "integer", Description: "Deslocamento horizontal em pixels dentro da célula"},
+			"offsetY":     {Type: "integer", Description: "Deslocamento vertical em pixels dentro da célula"},
+			"altText":     {Type: "string", Description: "Texto alternativo"},
+			"lockAspect":  {Type: "boolean", Description: "Manter a proporção (padrão true)"},
+			"positioning": {Type: "string", Description: "oneCell: move com as células (padrão); twoCell: move e redimensiona; absolute: fixa", Enum: []string{"oneCell", "twoCell", "absolute"}},
+		}, "sheet"),
+		macroOp("delete_image", "Exclui as imagens ancoradas em uma célula. Consulte as imagens com a query 'images'.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"cell":  {Type: "string", Description: "Célula de ancoragem da imagem"},
+		}, "sheet", "cell"),
+		macroOp("add_shape", "Adiciona uma forma ou caixa de texto (anotações, destaques).", map[string]FunctionProperty{
+			"sheet":     propSheet,
+			"cell":      {Type: "string", Description: "Célula do canto superior esquerdo (padrão A1)"},
+			"type":      {Type: "string", Description: "Forma (padrão textbox)", Enum: []string{"textbox", "rect", "roundRect", "ellipse", "triangle", "diamond", "rightArrow", "leftArrow", "upArrow", "downArrow", "chevron", "star5", "wedgeRectCallout", "wedgeRoundRectCallout", "cloudCallout"}},
+			"text":      {Type: "string", Description: "Texto; quebras de linha viram parágrafos"},
+			"width":     {Type: "integer", Description: "Largura em pixels (padrão 160)"},
+			"height":    {Type: "integer", Description: "Altura em pixels (padrão 60)"},
+			"fillColor": {Type: "string", Description: "Cor de preenchimento (hex)"},
+			"lineColor": {Type: "string", Description: "Cor da borda (hex)"},
+			"lineWidth": {Type: "number", Description: "Espessura da borda em pontos"},
+			"fontSize":  {Type: "number", Description: "Tamanho da fonte"},
+			"fontColor": {Type: "string", Description: "Cor da fonte (hex)"},
+			"bold":      {Type: "boolean", Description: "Negrito"},
+			"italic":    {Type: "boolean", Description: "Itálico"},
+		}, "sheet"),
+		macroOp("delete_shape", "Exclui uma forma ou caixa de texto. Consulte as formas com a query 'shapes'.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"name":  {Type: "string", Description: "Nome (ex: 'Shape 3'), célula de ancoragem ou texto"},
+		}, "sheet", "name"),
+		macroOp("create_table", "Cria uma tabela formatada.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+			"name":  {Type: "string", Description: "Nome da tabela"},
+			"style": {Type: "string", Description: "Estilo (ex: 'TableStyleMedium9')"},
+		}, "sheet", "range", "name"),
+		macroOp("delete_table", "Exclui uma tabela (mantém os dados).", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"name":  {Type: "string", Description: "Nome da tabela"},
+		}, "sheet", "name"),
+		macroOp("append_table_rows", "Acrescenta linhas ao final de uma tabela, com valores pelo nome do cabeçalho. A tabela é estendida e colunas calculadas são preenchidas.", map[string]FunctionProperty{
+			"table": {Type: "string", Description: "Nome da tabela"},
+			"row":   {Type: "object", Description: "Uma linha: {\"Cabeçalho\": valor} (ex: {\"Cliente\": \"X\", \"Valor\": 10})"},
+			"rows": {Type: "array", Description: "Várias linhas no mesmo formato de row", Items: &FunctionProperty{
+				Type: "object",
+			}},
+		}, "table"),
+		macroOp("resize_table", "Altera o intervalo de uma tabela mantendo a linha de cabeçalho. Sem range, estende a tabela até os dados preenchidos abaixo e à direita.", map[string]FunctionProperty{
+			"name":  {Type: "string", Description: "Nome da tabela"},
+			"range": {Type: "string", Description: "Novo intervalo incluindo o cabeçalho (ex: 'A1:F50')"},
+		}, "name"),
+		macroOp("set_table_totals", "Mostra ou oculta a linha de totais de uma tabela. Sem totals, usa 'Total' na primeira coluna e soma na última.", map[string]FunctionProperty{
+			"name": {Type: "string", Description: "Nome da tabela"},
+			"show": {Type: "boolean", Description: "false remove a linha de totais (padrão: true)"},
+			"totals": {Type: "array", Description: "Totais por coluna", Items: &FunctionProperty{
+				Type: "object",
+				Properties: map[string]FunctionProperty{
+					"column":   {Type: "string", Description: "Cabeçalho da coluna"},
+					"function": {Type: "string", Description: "Função de agregação", Enum: []string{"sum", "average", "count", "countNums", "max", "min", "stdDev", "var", "none"}},
+					"label":    {Type: "string", Description: "Texto exibido no lugar de uma função (ex: 'Total')"},
+				},
+			}},
+		}, "name"),
+		macroOp("create_pivot", "Cria uma tabela dinâmica com linhas, colunas, filtros e campos de valores.", map[string]FunctionProperty{
+			"sourceSheet":    {Type: "string", Description: "Planilha de origem"},
+			"sourceRange":    {Type: "string", Description: "Intervalo de origem com cabeçalhos (ex: 'A1:E100' ou 'A:E')"},
+			"destSheet":      {Type: "string", Description: "Planilha de destino"},
+			"destCell":       {Type: "string", Description: "Célula de destino"},
+			"tableName":      {Type: "string", Description: "Nome da tabela dinâmica"},
+			"rows":           pivotFieldList("Cabeçalhos usados como linhas"),
+			"columns":        pivotFieldList("Cabeçalhos usados como colunas"),
+			"filters":        pivotFieldList("Cabeçalhos usados como filtros de página"),
+			"dataFields":     propPivotDataFields,
+			"rowGrandTotals": {Type: "boolean", Description: "Total geral das linhas (padrão true)"},
+			"colGrandTotals": {Type: "boolean", Description: "Total geral das colunas (padrão true)"},
+			"style":          {Type: "string", Description: "Estilo (ex: 'PivotStyleMedium9')"},
+		}, "sourceSheet", "sourceRange", "destSheet", "destCell"),
+		macroOp("update_pivot", "Altera uma tabela dinâmica existente. Campos omitidos mantêm a configuração atual (consulte com a query 'pivots').", map[string]FunctionProperty{
+			"sheet":          {Type: "string", Description: "Planilha onde está a tabela dinâmica"},
+			"name":           {Type: "string", Description: "Nome atual da tabela dinâmica"},
+			"tableName":      {Type: "string", Description: "Novo nome (opcional)"},
+			"rows":           pivotFieldList("Cabeçalhos usados como linhas"),
+			"columns":        pivotFieldList("Cabeçalhos usados como colunas"),
+			"filters":        pivotFieldList("Cabeçalhos usados como filtros de página"),
+			"dataFields":     propPivotDataFields,
+			"rowGrandTotals": {Type: "boolean", Description: "Total geral das linhas"},
+			"colGrandTotals": {Type: "boolean", Description: "Total geral das colunas"},
+			"style":          {Type: "string", Description: "Estilo"},
+		}, "sheet", "name"),
+		macroOp("delete_pivot", "Exclui uma tabela dinâmica.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"name":  {Type: "string", Description: "Nome da tabela dinâmica"},
+		}, "sheet", "name"),
+
+		// FILTROS
+		macroOp("apply_filter", "Aplica AutoFiltro a um intervalo (primeira linha como cabeçalho) e oculta as linhas que não atendem aos critérios. Critérios de colunas diferentes são combinados com E.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+			"criteria": {Type: "array", Description: "Critérios por coluna (vazio: apenas ativa as setas do filtro)", Items: &FunctionProperty{
+				Type: "object",
+				Properties: map[string]FunctionProperty{
+					"column":   {Type: "string", Description: "Cabeçalho ou letra da coluna (ex: 'Cidade', 'C')"},
+					"operator": {Type: "string", Description: "Operador", Enum: []string{"equals", "notEquals", "contains", "notContains", "beginsWith", "endsWith", "greaterThan", "greaterThanOrEqual", "lessThan", "lessThanOrEqual", "between", "blanks", "nonBlanks", "top", "bottom", "datePeriod", "custom"}},
+					"values":   {Type: "array", Description: "equals: valores aceitos (qualquer um)", Items: &FunctionProperty{Type: "string"}},
+					"value":    {Type: "string", Description: "Valor dos operadores de um valor: texto, número ou data ('2024-01-31')"},
+					"minValue": {Type: "string", Description: "between: limite inferior"},
+					"maxValue": {Type: "string", Description: "between: limite superior"},
+					"count":    {Type: "integer", Description: "top/bottom: quantidade de itens (padrão 10)"},
+					"percent":  {Type: "boolean", Description: "top/bottom: count é porcentagem"},
+					"period":   {Type: "string", Description: "datePeriod: período relativo a hoje", Enum: []string{"today", "yesterday", "tomorrow", "thisWeek", "lastWeek", "nextWeek", "thisMonth", "lastMonth", "nextMonth", "thisQuarter", "lastQuarter", "nextQuarter", "thisYear", "lastYear", "nextYear", "yearToDate"}},
+					"conditions": {Type: "array", Description: "custom: uma ou duas condições {operator, value}", Items: &FunctionProperty{
+						Type: "object",
+						Properties: map[string]FunctionProperty{
+							"operator": {Type: "string", Description: "Operador", Enum: []string{"equals", "notEquals", "contains", "notContains", "beginsWith", "endsWith", "greaterThan", "greaterThanOrEqual", "lessThan", "lessThanOrEqual"}},
+							"value":    {Type: "string", Description: "Valor"},
+						},
+					}},
+					"join": {Type: "string", Description: "custom: combinação das condições (padrão and)", Enum: []string{"and", "or"}},
+				},
+				Required: []string{"column", "operator"},
+			}},
+		}, "sheet", "range"),
+		macroOp("clear_filter", "Limpa os critérios de filtro da planilha e mostra todas as linhas.", map[string]FunctionProperty{
+			"sheet": propSheet,
+		}, "sheet"),
+		macroOp("sort_range", "Ordena as linhas de um intervalo por uma ou mais colunas, de forma estável. Fórmulas e formatação acompanham as linhas.", map[string]FunctionProperty{
+			"sheet":     propSheet,
+			"range":     propRange,
+			"column":    {Type: "integer", Description: "Coluna de ordenação (1-based, relativa ao intervalo), quando há uma única chave"},
+			"ascending": {Type: "boolean", Description: "Ordem crescente (padrão true), quando há uma única chave"},
+			"keys": {Type: "array", Description: "Chaves de ordenação em ordem de prioridade", Items: &FunctionProperty{
+				Type: "object",
+				Properties: map[string]FunctionProperty{
+					"column":     {Type: "integer", Description: "Coluna (1-based, relativa ao intervalo)"},
+					"order":      {Type: "string", Description: "Direção (padrão asc)", Enum: []string{"asc", "desc"}},
+					"type":       {Type: "string", Description: "Tipo dos valores (padrão auto); custom usa customList", Enum: []string{"auto", "number", "date", "text", "custom"}},
+					"customList": {Type: "array", Description: "custom: ordem dos valores (ex: ['Baixa', 'Média', 'Alta'])", Items: &FunctionProperty{Type: "string"}},
+				},
+			}},
+			"header": {Type: "boolean", Description: "A primeira linha é cabeçalho e não é ordenada (padrão true para tabelas, false para intervalos)"},
+		}, "sheet", "range"),
+		macroOp("copy_range", "Copia um intervalo para outro local com valores, fórmulas (referências relativas ajustadas), estilos, mesclagens e comentários.", map[string]FunctionProperty{
+			"sheet":     propSheet,
+			"source":    {Type: "string", Description: "Intervalo de origem"},
+			"dest":      {Type: "string", Description: "Célula de destino, opcionalmente em outra planilha (ex: 'H1', 'Resumo!A1')"},
+			"mode":      {Type: "string", Description: "Colar especial (padrão all): values cola só os resultados, formulas mantém a formatação do destino, formats só a formatação", Enum: []string{"all", "values", "formulas", "formats"}},
+			"transpose": {Type: "boolean", Description: "Transpor: linhas viram colunas"},
+		}, "sheet", "source", "dest"),
+		macroOp("move_range", "Move um intervalo (recortar e colar). As fórmulas que leem o intervalo passam a apontar para o novo local.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"source": {Type: "string", Description: "Intervalo de origem"},
+			"dest":   {Type: "string", Description: "Célula de destino, opcionalmente em outra planilha (ex: 'H1', 'Resumo!A1')"},
+		}, "sheet", "source", "dest"),
+
+		// VALIDAÇÃO
+		macroOp("add_dropdown", "Cria uma lista suspensa de validação.", map[string]FunctionProperty{
+			"sheet":   propSheet,
+			"range":   propRange,
+			"options": {Type: "array", Description: "Opções da lista", Items: &FunctionProperty{Type: "string"}},
+			"source":  {Type: "string", Description: "Origem das opções em vez de 'options': intervalo, nome definido ou coluna de tabela (ex: 'Listas!A2:A20', 'Categorias', 'Produtos[Nome]')"},
+		}, "sheet", "range"),
+		macroOp("add_validation", "Adiciona uma validação de dados, substituindo a validação existente nas células. Números, datas, horas e comprimento de texto usam 'operator' com 'value' ou 'minValue'/'maxValue'.", map[string]FunctionProperty{
+			"sheet":        propSheet,
+			"range":        {Type: "string", Description: "Intervalo (ex: 'B2:B100' ou 'B:B'; vários separados por vírgula)"},
+			"type":         {Type: "string", Description: "Tipo de validação", Enum: []string{"list", "whole", "decimal", "date", "time", "textLength", "custom"}},
+			"operator":     {Type: "string", Description: "Operador (padrão between)", Enum: []string{"between", "notBetween", "equal", "notEqual", "greaterThan", "greaterThanOrEqual", "lessThan", "lessThanOrEqual"}},
+			"value":        {Type: "string", Description: "Valor comparado pelos operadores de um valor: número, data ('2024-01-31'), hora ('14:30') ou fórmula ('=TODAY()', '=$B$1')"},
+			"minValue":     {Type: "string", Description: "Limite inferior de between/notBetween"},
+			"maxValue":     {Type: "string", Description: "Limite superior de between/notBetween"},
+			"options":      {Type: "array", Description: "list: opções fixas", Items: &FunctionProperty{Type: "string"}},
+			"source":       {Type: "string", Description: "list: intervalo, nome definido ou coluna de tabela com as opções"},
+			"formula":      {Type: "string", Description: "custom: fórmula verdadeira/falsa relativa à primeira célula (ex: '=COUNTIF($A:$A,A2)=1')"},
+			"allowBlank":   {Type: "boolean", Description: "Aceita células vazias (padrão true)"},
+			"inputTitle":   {Type: "string", Description: "Título da mensagem exibida ao selecionar a célula"},
+			"inputMessage": {Type: "string", Description: "Mensagem exibida ao selecionar a célula"},
+			"errorTitle":   {Type: "string", Description: "Título do alerta de valor inválido"},
+			"errorMessage": {Type: "string", Description: "Texto do alerta de valor inválido"},
+			"errorStyle":   {Type: "string", Description: "stop bloqueia o valor; warning e information apenas avisam", Enum: []string{"stop", "warning", "information"}},
+		}, "sheet", "range", "type"),
+		macroOp("remove_validation", "Remove a validação de dados das células do intervalo. Consulte as validações existentes com query_batch 'validations'.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"range": propRange,
+		}, "sheet", "range"),
+
+		// NOMES DEFINIDOS
+		macroOp("create_name", "Cria um nome definido para um intervalo, constante ou fórmula. O nome pode ser usado no lugar do intervalo em qualquer operação e em fórmulas.", map[string]FunctionProperty{
+			"sheet":    {Type: "string", Description: "Planilha usada nas referências sem planilha"},
+			"name":     {Type: "string", Description: "Nome (ex: 'Vendas', 'TaxaJuros'; sem espaços, não pode parecer uma célula)"},
+			"refersTo": {Type: "string", Description: "Intervalo, constante ou fórmula (ex: 'A2:D100', 'Dados!B2:B50', '0,05')"},
+			"scope":    {Type: "string", Description: "Planilha do escopo; vazio para nome da pasta de trabalho"},
+			"comment":  {Type: "string", Description: "Comentário"},
+		}, "name", "refersTo"),
+		macroOp("update_name", "Altera um nome definido. Ao renomear, as fórmulas que usam o nome são atualizadas. Consulte os nomes com query_batch 'names'.", map[string]FunctionProperty{
+			"sheet":    {Type: "string", Description: "Planilha usada nas referências sem planilha"},
+			"name":     {Type: "string", Description: "Nome atual"},
+			"scope":    {Type: "string", Description: "Planilha do escopo do nome; vazio para nome da pasta de trabalho"},
+			"newName":  {Type: "string", Description: "Novo nome"},
+			"refersTo": {Type: "string", Description: "Nova referência"},
+			"comment":  {Type: "string", Description: "Novo comentário"},
+		}, "name"),
+		macroOp("delete_name", "Remove um nome definido. Fórmulas que o usam passam a exibir #NAME?.", map[string]FunctionProperty{
+			"name":  {Type: "string", Description: "Nome"},
+			"scope": {Type: "string", Description: "Planilha do escopo; vazio para nome da pasta de trabalho"},
+		}, "name"),
+
+		// COMENTÁRIOS E HYPERLINKS
+		macroOp("add_comment", "Adiciona um comentário a uma célula.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"cell":   propCell,
+			"author": {Type: "string", Description: "Autor"},
+			"text":   {Type: "string", Description: "Texto do comentário"},
+		}, "sheet", "cell", "text"),
+		macroOp("delete_comment", "Remove o comentário de uma célula.", map[string]FunctionProperty{
+			"sheet": propSheet,
+			"cell":  propCell,
+		}, "sheet", "cell"),
+		macroOp("add_hyperlink", "Adiciona um hyperlink a uma célula.", map[string]FunctionProperty{
+			"sheet":   propSheet,
+			"cell":    propCell,
+			"url":     {Type: "string", Description: "URL"},
+			"display": {Type: "string", Description: "Texto exibido"},
+		}, "sheet", "cell", "url"),
+
+		// PROTEÇÃO
+		macroOp("protect_sheet", "Protege uma planilha.", map[string]FunctionProperty{
+			"sheet":    propSheet,
+			"password": {Type: "string", Description: "Senha (opcional)"},
+		}, "sheet"),
+		macroOp("unprotect_sheet", "Remove a proteção de uma planilha.", map[string]FunctionProperty{
+			"sheet":    propSheet,
+			"password": {Type: "string", Description: "Senha"},
+		}, "sheet"),
+		macroOp("lock_cell", "Bloqueia ou desbloqueia uma célula.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"cell":   propCell,
+			"locked": {Type: "boolean", Description: "true para bloquear"},
+		}, "sheet", "cell"),
+
+		// IMPRESSÃO
+		macroOp("set_page_setup", "Configura a impressão/PDF da planilha. Informe só o que deve mudar; consulte a configuração atual com a query 'page_setup'.", map[string]FunctionProperty{
+			"sheet":       propSheet,
+			"orientation": {Type: "string", Description: "Orientação", Enum: []string{"portrait", "landscape"}},
+			"paperSize":   {Type: "string", Description: "Tamanho do papel", Enum: []string{"A4", "A3", "A5", "B4", "B5", "letter", "legal", "tabloid", "executive"}},
+			"scale":       {Type: "integer", Description: "Escala em % (10-400); não combinar com fitToWidth/fitToHeight"},
+			"fitToWidth":  {Type: "integer", Description: "Ajustar a N páginas de largura (0 = automático)"},
+			"fitToHeight": {Type: "integer", Description: "Ajustar a N páginas de altura (0 = automático)"},
+			"margins": {Type: "object", Description: "Margens em centímetros", Properties: map[string]FunctionProperty{
+				"top":    {Type: "number"},
+				"bottom": {Type: "number"},
+				"left":   {Type: "number"},
+				"right":  {Type: "number"},
+				"header": {Type: "number"},
+				"footer": {Type: "number"},
+			}},
+			"centerHorizontally": {Type: "boolean", Description: "Centralizar horizontalmente na página"},
+			"centerVertically":   {Type: "boolean", Description: "Centralizar verticalmente na página"},
+			"gridlines":          {Type: "boolean", Description: "Imprimir linhas de grade"},
+			"headings":           {Type: "boolean", Description: "Imprimir cabeçalhos de linha e coluna"},
+			"blackAndWhite":      {Type: "boolean", Description: "Imprimir em preto e branco"},
+			"firstPageNumber":    {Type: "integer", Description: "Número da primeira página"},
+			"pageOrder":          {Type: "string", Description: "Ordem das páginas", Enum: []string{"downThenOver", "overThenDown"}},
+			"printArea":          {Type: "string", Description: "Área de impressão (ex: 'A1:H40'); vazio remove"},
+			"titleRows":          {Type: "string", Description: "Linhas repetidas no topo de cada página (ex: '1:2'); vazio remove"},
+			"titleColumns":       {Type: "string", Description: "Colunas repetidas à esquerda (ex: 'A:B'); vazio remove"},
+			"header":             {Type: "string", Description: "Cabeçalho. Campos: {page}, {pages}, {date}, {time}, {file}, {sheet}; 'esquerda|centro|direita' divide em seções (ex: 'Relatório Mensal||{date}'); vazio remove"},
+			"footer":             {Type: "string", Description: "Rodapé, no mesmo formato do cabeçalho (ex: 'Página {page} de {pages}')"},
+		}, "sheet"),
+		macroOp("insert_page_break", "Insere uma quebra de página manual antes de uma linha e/ou coluna.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"row":    {Type: "integer", Description: "Linha que inicia a nova página"},
+			"column": {Type: "string", Description: "Coluna que inicia a nova página (ex: 'F')"},
+			"cell":   {Type: "string", Description: "Alternativa: quebra antes da linha e da coluna da célula"},
+		}, "sheet"),
+		macroOp("remove_page_break", "Remove quebras de página manuais. Sem row/column/cell, remove todas.", map[string]FunctionProperty{
+			"sheet":  propSheet,
+			"row":    {Type: "integer", Description: "Linha da quebra"},
+			"column": {Type: "string", Description: "Coluna da quebra"},
+			"cell":   {Type: "string", Description: "Célula da quebra"},
+		}, "sheet"),
+
+		// DOCUMENTO
+		macroOp("set_properties", "Altera as propriedades do arquivo. Informe só o que deve mudar; consulte as atuais com a query 'workbook_info'.", map[string]FunctionProperty{
+			"title":          {Type: "string", Description: "Título"},
+			"subject":        {Type: "string", Description: "Assunto"},
+			"author":         {Type: "string", Description: "Autor"},
+			"keywords":       {Type: "string", Description: "Palavras-chave"},
+			"description":    {Type: "string", Description: "Comentários"},
+			"category":       {Type: "string", Description: "Categoria"},
+			"company":        {Type: "string", Description: "Empresa"},
+			"lastModifiedBy": {Type: "string", Description: "Modificado por"},
+			"custom":         {Type: "object", Description: "Propriedades personalizadas: {\"Nome\": valor} com texto, número ou booleano; null remove (ex: {\"Projeto\": \"Orçamento 2025\"})"},
+		}),
+	}
+}
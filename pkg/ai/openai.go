@@ -0,0 +1,272 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"excel-ai/internal/domain"
+	"excel-ai/pkg/logger"
+)
+
+// OpenAIClient cliente para APIs compatíveis com OpenAI (OpenAI, OpenRouter, Groq, endpoints custom)
+type OpenAIClient struct {
+	provider   string
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+	maxTokens  int
+}
+
+// NewOpenAIClient cria um cliente OpenAI-compatível
+func NewOpenAIClient(provider, apiKey, model, baseURL string, maxTokens int) *OpenAIClient {
+	if maxTokens <= 0 {
+		maxTokens = 128000
+	}
+	return &OpenAIClient{
+		provider: provider,
+		apiKey:   apiKey,
+		model:    model,
+		baseURL:  ensureTrailingSlash(baseURL),
+		httpClient: &http.Client{
+			Timeout: 2 * time.Minute,
+		},
+		maxTokens: maxTokens,
+	}
+}
+
+// openAIToolCallDelta fragmento de tool call recebido no stream
+type openAIToolCallDelta struct {
+	Index    int    `json:"index"`
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name,omitempty"`
+		Arguments string `json:"arguments,omitempty"`
+	} `json:"function,omitempty"`
+}
+
+// ChatStream envia mensagens com streaming
+func (c *OpenAIClient) ChatStream(ctx context.Context, messages []Message, onChunk func(string) error) (string, error) {
+	content, _, err := c.stream(ctx, messages, nil, onChunk)
+	return content, err
+}
+
+// ChatStreamWithTools envia mensagens com tools
+func (c *OpenAIClient) ChatStreamWithTools(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	return c.stream(ctx, messages, tools, onChunk)
+}
+
+func (c *OpenAIClient) stream(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error) {
+	if c.apiKey == "" && RequiresAPIKey(c.provider) {
+		return "", nil, fmt.Errorf("API key não configurada")
+	}
+	if c.model == "" {
+		return "", nil, fmt.Errorf("modelo não configurado para o provedor %s", c.provider)
+	}
+
+	prunedMessages := flattenToolCalls(PruneMessages(messages, c.maxTokens))
+
+	reqBody := struct {
+		Model      string    `json:"model"`
+		Messages   []Message `json:"messages"`
+		Stream     bool      `json:"stream"`
+		Tools      []Tool    `json:"tools,omitempty"`
+		ToolChoice string    `json:"tool_choice,omitempty"`
+	}{
+		Model:    c.model,
+		Messages: prunedMessages,
+		Stream:   true,
+		Tools:    tools,
+	}
+	if len(tools) > 0 {
+		reqBody.ToolChoice = "auto"
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", nil, err
+	}
+
+	url := c.baseURL + "chat/completions"
+	logger.AIDebug(fmt.Sprintf("[%s] POST %s (model=%s, messages=%d, tools=%d)", c.provider, url, c.model, len(prunedMessages), len(tools)))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.provider == ProviderOpenRouter {
+		req.Header.Set("X-Title", "Excel-AI")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.AIError(fmt.Sprintf("[%s] Erro na requisição: %v", c.provider, err))
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := providerHTTPError(c.provider, resp)
+		logger.AIError(fmt.Sprintf("[%s] %v", c.provider, err))
+		return "", nil, err
+	}
+
+	var fullResponse strings.Builder
+	toolCallsMap := make(map[int]*ToolCall)
+
+	err = readSSE(resp.Body, func(data []byte) (bool, error) {
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content          string                `json:"content,omitempty"`
+					Reasoning        string                `json:"reasoning,omitempty"`
+					ReasoningContent string                `json:"reasoning_content,omitempty"`
+					ToolCalls        []openAIToolCallDelta `json:"tool_calls,omitempty"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal(data, &chunk); err != nil || len(chunk.Choices) == 0 {
+			return false, nil
+		}
+		delta := chunk.Choices[0].Delta
+
+		reasoning := delta.ReasoningContent
+		if reasoning == "" {
+			reasoning = delta.Reasoning
+		}
+		if reasoning != "" {
+			if err := onChunk(":::reasoning:::" + reasoning); err != nil {
+				return false, err
+			}
+		}
+
+		if delta.Content != "" {
+			fullResponse.WriteString(delta.Content)
+			if err := onChunk(delta.Content); err != nil {
+				return false, err
+			}
+		}
+
+		for _, tc := range delta.ToolCalls {
+			if existing, ok := toolCallsMap[tc.Index]; ok {
+				existing.Function.Arguments += tc.Function.Arguments
+				continue
+			}
+			toolType := tc.Type
+			if toolType == "" {
+				toolType = "function"
+			}
+			toolCallsMap[tc.Index] = &ToolCall{
+				ID:   tc.ID,
+				Type: toolType,
+				Function: FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	indexes := make([]int, 0, len(toolCallsMap))
+	for i := range toolCallsMap {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	var toolCalls []ToolCall
+	for _, i := range indexes {
+		tc := toolCallsMap[i]
+		if tc.ID == "" {
+			tc.ID = generateToolCallID()
+		}
+		toolCalls = append(toolCalls, *tc)
+	}
+
+	return fullResponse.String(), toolCalls, nil
+}
+
+// GetAvailableModels consulta GET {baseURL}models
+func (c *OpenAIClient) GetAvailableModels() ([]domain.ModelInfo, error) {
+	req, err := http.NewRequest("GET", c.baseURL+"models", nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, providerHTTPError(c.provider, resp)
+	}
+
+	var payload struct {
+		Data []struct {
+			ID            string `json:"id"`
+			Name          string `json:"name"`
+			Description   string `json:"description"`
+			ContextLength int    `json:"context_length"`
+			Pricing       struct {
+				Prompt     string `json:"prompt"`
+				Completion string `json:"completion"`
+			} `json:"pricing"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("resposta de modelos inválida: %w", err)
+	}
+
+	models := make([]domain.ModelInfo, 0, len(payload.Data))
+	for _, m := range payload.Data {
+		name := m.Name
+		if name == "" {
+			name = m.ID
+		}
+		models = append(models, domain.ModelInfo{
+			ID:            m.ID,
+			Name:          name,
+			Description:   m.Description,
+			ContextLength: m.ContextLength,
+			Pricing: domain.ModelPricing{
+				Prompt:     m.Pricing.Prompt,
+				Completion: m.Pricing.Completion,
+			},
+		})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
+// flattenToolCalls converte tool calls do histórico em texto quando não há
+// mensagens "tool" correspondentes (o chat devolve resultados como mensagens do usuário)
+func flattenToolCalls(messages []Message) []Message {
+	result := make([]Message, 0, len(messages))
+	for i, m := range messages {
+		if len(m.ToolCalls) > 0 && (i+1 >= len(messages) || messages[i+1].Role != "tool") {
+			m.Content = strings.TrimSpace(m.Content + "\n" + toolCallsSummary(m.ToolCalls))
+			m.ToolCalls = nil
+		}
+		result = append(result, m)
+	}
+	return result
+}
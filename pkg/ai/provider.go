@@ -0,0 +1,207 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"excel-ai/internal/domain"
+)
+
+// Provider define a interface comum dos clientes de LLM usados pelo chat
+type Provider interface {
+	// ChatStream envia mensagens sem ferramentas, repassando o texto gerado para onChunk
+	ChatStream(ctx context.Context, messages []Message, onChunk func(string) error) (string, error)
+	// ChatStreamWithTools envia mensagens com function calling e retorna as tool calls pedidas pelo modelo
+	ChatStreamWithTools(ctx context.Context, messages []Message, tools []Tool, onChunk func(string) error) (string, []ToolCall, error)
+	// GetAvailableModels lista os modelos oferecidos pelo provedor
+	GetAvailableModels() ([]domain.ModelInfo, error)
+}
+
+// Nomes de provedores aceitos em storage.Config.Provider
+const (
+	ProviderZAI        = "zai"
+	ProviderOpenAI     = "openai"
+	ProviderOpenRouter = "openrouter"
+	ProviderGroq       = "groq"
+	ProviderCustom     = "custom"
+	ProviderGoogle     = "google"
+	ProviderAnthropic  = "anthropic"
+	ProviderLocal      = "local" // Ollama, llama.cpp server e afins rodando na máquina
+)
+
+// ProviderConfig reúne o necessário para construir um Provider
+type ProviderConfig struct {
+	Provider       string
+	APIKey         string
+	Model          string
+	BaseURL        string
+	MaxInputTokens int
+}
+
+// NormalizeProviderName converte apelidos comuns no nome canônico do provedor
+func NormalizeProviderName(name string) string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "zai", "z.ai", "glm":
+		return ProviderZAI
+	case "openai":
+		return ProviderOpenAI
+	case "openrouter":
+		return ProviderOpenRouter
+	case "groq":
+		return ProviderGroq
+	case "google", "gemini":
+		return ProviderGoogle
+	case "anthropic", "claude":
+		return ProviderAnthropic
+	case "local", "ollama", "llamacpp", "llama.cpp", "lmstudio":
+		return ProviderLocal
+	default:
+		return ProviderCustom
+	}
+}
+
+// DefaultBaseURL retorna a URL padrão da API de um provedor (vazio para "custom")
+func DefaultBaseURL(provider string) string {
+	switch NormalizeProviderName(provider) {
+	case ProviderZAI:
+		return "https://api.z.ai/api/coding/paas/v4/"
+	case ProviderOpenAI:
+		return "https://api.openai.com/v1/"
+	case ProviderOpenRouter:
+		return "https://openrouter.ai/api/v1/"
+	case ProviderGroq:
+		return "https://api.groq.com/openai/v1/"
+	case ProviderGoogle:
+		return "https://generativelanguage.googleapis.com/v1beta/"
+	case ProviderAnthropic:
+		return "https://api.anthropic.com/v1/"
+	case ProviderLocal:
+		return "http://localhost:11434/v1/" // Ollama; llama.cpp usa http://localhost:8080/v1/
+	}
+	return ""
+}
+
+// NewProvider cria o cliente adequado para o provedor configurado
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	name := NormalizeProviderName(cfg.Provider)
+
+	baseURL := strings.TrimSpace(cfg.BaseURL)
+	if baseURL == "" {
+		baseURL = DefaultBaseURL(name)
+	}
+	if baseURL == "" {
+		return nil, fmt.Errorf("provedor %q requer uma URL base", cfg.Provider)
+	}
+
+	switch name {
+	case ProviderZAI:
+		client := NewZAIClient(cfg.APIKey, cfg.Model)
+		client.SetBaseURL(baseURL)
+		if cfg.MaxInputTokens > 0 {
+			client.SetMaxInputTokens(cfg.MaxInputTokens)
+		}
+		return client, nil
+	case ProviderGoogle:
+		return NewGeminiClient(cfg.APIKey, cfg.Model, baseURL, cfg.MaxInputTokens), nil
+	case ProviderAnthropic:
+		return NewAnthropicClient(cfg.APIKey, cfg.Model, baseURL, cfg.MaxInputTokens), nil
+	case ProviderLocal:
+		return NewLocalClient(cfg.APIKey, cfg.Model, baseURL, cfg.MaxInputTokens), nil
+	default:
+		return NewOpenAIClient(name, cfg.APIKey, cfg.Model, baseURL, cfg.MaxInputTokens), nil
+	}
+}
+
+// RequiresAPIKey indica se o provedor exige API key (endpoints locais e custom não exigem)
+func RequiresAPIKey(provider string) bool {
+	switch NormalizeProviderName(provider) {
+	case ProviderLocal, ProviderCustom:
+		return false
+	}
+	return true
+}
+
+// ensureTrailingSlash garante que a URL base termina com /
+func ensureTrailingSlash(url string) string {
+	if !strings.HasSuffix(url, "/") {
+		return url + "/"
+	}
+	return url
+}
+
+// readSSE lê um stream Server-Sent Events e chama onData para cada linha "data:".
+// onData retorna true para encerrar a leitura antes do fim do stream.
+func readSSE(body io.Reader, onData func(data []byte) (bool, error)) error {
+	reader := bufio.NewReader(body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			line = bytes.TrimSpace(line)
+			if bytes.HasPrefix(line, []byte("data:")) {
+				data := bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data:")))
+				if string(data) == "[DONE]" {
+					return nil
+				}
+				stop, cbErr := onData(data)
+				if cbErr != nil {
+					return cbErr
+				}
+				if stop {
+					return nil
+				}
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// HTTPError resposta de erro de um provedor, com o status HTTP original
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// providerHTTPError converte uma resposta HTTP de erro em mensagem amigável
+func providerHTTPError(label string, resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	errorMsg := string(body)
+
+	var msg string
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		msg = fmt.Sprintf("API key inválida ou expirada (%s)", label)
+	case http.StatusTooManyRequests:
+		msg = fmt.Sprintf("limite de requisições excedido no %s: %s", label, errorMsg)
+	case http.StatusBadRequest:
+		msg = fmt.Sprintf("requisição inválida: %s", errorMsg)
+	default:
+		msg = fmt.Sprintf("erro %s (%d): %s", label, resp.StatusCode, errorMsg)
+	}
+	return &HTTPError{StatusCode: resp.StatusCode, Message: msg}
+}
+
+// toolCallsSummary descreve tool calls em texto para provedores que exigem pares call/result estritos
+func toolCallsSummary(calls []ToolCall) string {
+	if len(calls) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(calls))
+	for _, tc := range calls {
+		parts = append(parts, fmt.Sprintf("%s(%s)", tc.Function.Name, tc.Function.Arguments))
+	}
+	return "[Ferramentas chamadas: " + strings.Join(parts, "; ") + "]"
+}
@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sseServer responde a qualquer requisição com os eventos informados e guarda o caminho pedido
+func sseServer(t *testing.T, path *string, events ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*path = r.URL.Path
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			io.WriteString(w, "data: "+event+"\n\n")
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProviderStreams(t *testing.T) {
+	tools := []Tool{{Type: "function", Function: FunctionDeclaration{
+		Name:       "write_cell",
+		Parameters: FunctionParameters{Type: "object", Properties: map[string]FunctionProperty{"cell": {Type: "string"}}},
+	}}}
+
+	tests := []struct {
+		name          string
+		events        []string
+		newClient     func(baseURL string) Provider
+		wantPath      string
+		wantText      string
+		wantReasoning string
+		wantCalls     []FunctionCall
+		wantIDs       []string
+	}{
+		{
+			name: "OpenAI",
+			events: []string{
+				`{"choices":[{"delta":{"content":"Vou "}}]}`,
+				`{"choices":[{"delta":{"reasoning_content":"pensando"}}]}`,
+				`{"choices":[{"delta":{"content":"escrever."}}]}`,
+				`{"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_b","function":{"name":"list_sheets","arguments":""}}]}}]}`,
+				`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"write_cell","arguments":"{\"cell\":"}}]}}]}`,
+				`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"A1\"}"}}]}}]}`,
+				`[DONE]`,
+				`{"choices":[{"delta":{"content":" ignorado"}}]}`,
+			},
+			newClient: func(baseURL string) Provider {
+				return NewOpenAIClient(ProviderOpenAI, "chave", "gpt-test", baseURL, 0)
+			},
+			wantPath:      "/v1/chat/completions",
+			wantText:      "Vou escrever.",
+			wantReasoning: "pensando",
+			wantCalls:     []FunctionCall{{Name: "write_cell", Arguments: `{"cell":"A1"}`}, {Name: "list_sheets"}},
+			wantIDs:       []string{"call_a", "call_b"},
+		},
+		{
+			name: "Gemini",
+			events: []string{
+				`{"candidates":[{"content":{"role":"model","parts":[{"text":"pensando","thought":true}]}}]}`,
+				`{"candidates":[{"content":{"role":"model","parts":[{"text":"Vou "}]}}]}`,
+				`{"candidates":[{"content":{"role":"model","parts":[{"text":"escrever."},{"functionCall":{"name":"write_cell","args":{"cell":"A1"}}}]}}]}`,
+				`{"candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"list_sheets"}}]}}]}`,
+			},
+			newClient: func(baseURL string) Provider {
+				return NewGeminiClient("chave", "models/gemini-test", baseURL, 0)
+			},
+			wantPath:      "/v1/models/gemini-test:streamGenerateContent",
+			wantText:      "Vou escrever.",
+			wantReasoning: "pensando",
+			wantCalls:     []FunctionCall{{Name: "write_cell", Arguments: `{"cell":"A1"}`}, {Name: "list_sheets", Arguments: "{}"}},
+		},
+		{
+			name: "Anthropic",
+			events: []string{
+				`{"type":"message_start","message":{"id":"msg_1"}}`,
+				`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Vou "}}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"escrever."}}`,
+				`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_a","name":"write_cell"}}`,
+				`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"cell\""}}`,
+				`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":":\"A1\"}"}}`,
+				`{"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_b","name":"list_sheets"}}`,
+				`{"type":"message_stop"}`,
+				`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" ignorado"}}`,
+			},
+			newClient: func(baseURL string) Provider {
+				return NewAnthropicClient("chave", "claude-test", baseURL, 0)
+			},
+			wantPath:  "/v1/messages",
+			wantText:  "Vou escrever.",
+			wantCalls: []FunctionCall{{Name: "write_cell", Arguments: `{"cell":"A1"}`}, {Name: "list_sheets", Arguments: "{}"}},
+			wantIDs:   []string{"toolu_a", "toolu_b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path string
+			server := sseServer(t, &path, tt.events...)
+			client := tt.newClient(server.URL + "/v1")
+
+			var chunks []string
+			text, calls, err := client.ChatStreamWithTools(context.Background(),
+				[]Message{{Role: "system", Content: "Você edita planilhas"}, {Role: "user", Content: "Escreva em A1"}},
+				tools, func(chunk string) error {
+					chunks = append(chunks, chunk)
+					return nil
+				})
+			if err != nil {
+				t.Fatalf("ChatStreamWithTools retornou erro: %v", err)
+			}
+			if path != tt.wantPath {
+				t.Errorf("caminho = %q, esperado %q", path, tt.wantPath)
+			}
+			if text != tt.wantText {
+				t.Errorf("texto = %q, esperado %q", text, tt.wantText)
+			}
+			var streamed, reasoning []string
+			for _, chunk := range chunks {
+				if strings.HasPrefix(chunk, ":::reasoning:::") {
+					reasoning = append(reasoning, chunk)
+				} else {
+					streamed = append(streamed, chunk)
+				}
+			}
+			if got := strings.Join(streamed, ""); got != tt.wantText {
+				t.Errorf("chunks de texto = %q, esperado %q", got, tt.wantText)
+			}
+			if tt.wantReasoning != "" && !reflect.DeepEqual(reasoning, []string{":::reasoning:::" + tt.wantReasoning}) {
+				t.Errorf("raciocínio = %q, esperado %q", reasoning, tt.wantReasoning)
+			}
+
+			var got []FunctionCall
+			for i, call := range calls {
+				got = append(got, call.Function)
+				if call.ID == "" || call.Type != "function" {
+					t.Errorf("tool call %d sem ID ou tipo: %+v", i, call)
+				}
+				if tt.wantIDs != nil && call.ID != tt.wantIDs[i] {
+					t.Errorf("ID da tool call %d = %q, esperado %q", i, call.ID, tt.wantIDs[i])
+				}
+				if call.Function.Arguments != "" && !json.Valid([]byte(call.Function.Arguments)) {
+					t.Errorf("argumentos inválidos na tool call %d: %s", i, call.Function.Arguments)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantCalls) {
+				t.Errorf("tool calls = %+v, esperado %+v", got, tt.wantCalls)
+			}
+		})
+	}
+}
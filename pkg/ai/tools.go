@@ -35,6 +35,9 @@ type FunctionProperty struct {
 	Description string            `json:"description,omitempty"`
 	Enum        []string          `json:"enum,omitempty"`
 	Items       *FunctionProperty `json:"items,omitempty"` // For array types
+	// For object types (ex: items de um array de objetos)
+	Properties map[string]FunctionProperty `json:"properties,omitempty"`
+	Required   []string                    `json:"required,omitempty"`
 }
 
 // ToolCall represents a function call requested by the AI
@@ -100,12 +103,16 @@ func GetExcelTools() []Tool {
 						},
 						"queries": {
 							Type:        "array",
-							Description: "Lista de consultas: 'headers', 'row_count', 'used_range', 'sample_data', 'column_count', 'has_filter', 'charts', 'tables'",
+							Description: "Lista de consultas: 'headers', 'row_count', 'used_range', 'sample_data', 'column_count', 'has_filter', 'charts', 'images', 'shapes', 'tables', 'pivots', 'precedents', 'dependents', 'impact_of_change', 'formats', 'conditional_formats', 'validations', 'invalid_cells', 'names', 'filter', 'page_setup', 'sheets', 'workbook_info'. Use impact_of_change antes de alterar células lidas por fórmulas, formats para copiar o estilo existente (ex: 'igual ao cabeçalho'), invalid_cells para achar valores que violam a validação de dados e workbook_info para propriedades e origem do arquivo (quem o criou).",
 							Items: &FunctionProperty{
 								Type: "string",
-								Enum: []string{"headers", "row_count", "used_range", "sample_data", "column_count", "has_filter", "charts", "tables"},
+								Enum: []string{"headers", "row_count", "used_range", "sample_data", "column_count", "has_filter", "charts", "images", "shapes", "tables", "pivots", "precedents", "dependents", "impact_of_change", "formats", "conditional_formats", "validations", "invalid_cells", "names", "filter", "page_setup", "sheets", "workbook_info"},
 							},
 						},
+						"range": {
+							Type:        "string",
+							Description: "Célula, intervalo, nome definido ou tabela analisada por precedents, dependents, impact_of_change, formats, conditional_formats, validations e invalid_cells (ex: 'B2', 'Dados!B2:B10'). Em formats, o padrão é o intervalo usado; nas demais, a planilha inteira",
+						},
 						"sample_rows": {
 							Type:        "integer",
 							Description: "Número de linhas de amostra (default: 5)",
@@ -129,7 +136,7 @@ func GetExcelTools() []Tool {
 						},
 						"range": {
 							Type:        "string",
-							Description: "Intervalo de células (ex: 'A1:C10')",
+							Description: "Intervalo de células, nome definido ou tabela (ex: 'A1:C10', 'Vendas')",
 						},
 						"max_rows": {
 							Type:        "integer",
@@ -143,6 +150,10 @@ func GetExcelTools() []Tool {
 							Type:        "string",
 							Description: "Valor do filtro",
 						},
+						"visible_only": {
+							Type:        "boolean",
+							Description: "Retorna apenas as linhas visíveis (omite as ocultas por filtro)",
+						},
 					},
 					Required: []string{"sheet", "range"},
 				},
@@ -189,16 +200,23 @@ func GetExcelTools() []Tool {
 			Function: FunctionDeclaration{
 				Name: "execute_macro",
 				Description: `Executa ações no Excel. Operações disponíveis:
-BÁSICO: create_sheet, delete_sheet, rename_sheet, write_cell, write_range, clear_range
-FORMATAÇÃO: format_range, autofit_columns, set_borders, merge_cells, conditional_format
-ESTRUTURA: insert_rows, delete_rows, freeze_pane, unfreeze_pane, hide_sheet, show_sheet
-OBJETOS: create_chart, delete_chart, create_table, delete_table, create_pivot, delete_pivot
+BÁSICO: create_sheet, delete_sheet, rename_sheet, copy_sheet, write_cell, write_range, clear_range, copy_range, move_range
+FORMATAÇÃO: format_range, autofit_columns, set_borders, merge_cells, conditional_format, remove_conditional_format
+ESTRUTURA: insert_rows, delete_rows, insert_columns, delete_columns, move_columns (colunas pela letra ou pelo cabeçalho), group_columns, ungroup_columns, freeze_pane, unfreeze_pane
+ABAS E EXIBIÇÃO: hide_sheet (aceita veryHidden), show_sheet, move_sheet, set_active_sheet, set_tab_color, set_sheet_view (zoom, linhas de grade, cabeçalhos)
+OBJETOS: create_chart, delete_chart, create_pivot, update_pivot, delete_pivot
+IMAGENS E FORMAS: insert_image, delete_image, add_shape (formas e caixas de texto), delete_shape
+TABELAS: create_table, delete_table, append_table_rows, resize_table, set_table_totals
 FILTROS: apply_filter, clear_filter, sort_range
-VALIDAÇÃO: add_dropdown (cria lista dropdown), add_validation
+VALIDAÇÃO: add_dropdown (cria lista dropdown), add_validation, remove_validation
+NOMES: create_name, update_name, delete_name
 COMENTÁRIOS: add_comment, delete_comment
 HYPERLINKS: add_hyperlink
 PROTEÇÃO: protect_sheet, unprotect_sheet, lock_cell
-FÓRMULAS: set_formula`,
+IMPRESSÃO: set_page_setup (orientação, papel, margens, ajuste a páginas, títulos, cabeçalho/rodapé, área de impressão), insert_page_break, remove_page_break
+DOCUMENTO: set_properties (título, autor, assunto, palavras-chave, empresa e propriedades personalizadas)
+FÓRMULAS: set_formula, recalculate (fórmulas são recalculadas ao final de cada macro)
+Intervalos aceitam endereços A1, nomes definidos, nomes de tabela e colunas de tabela (Tabela[Coluna]).`,
 				Parameters: FunctionParameters{
 					Type: "object",
 					Properties: map[string]FunctionProperty{
@@ -220,17 +238,24 @@ FÓRMULAS: set_formula`,
 
 // GetGeminiTools converts Tools to Gemini-specific format
 func GetGeminiTools() []map[string]interface{} {
-	tools := GetExcelTools()
+	return ToGeminiTools(GetExcelTools())
+}
 
+// ToGeminiTools converts any tool list to Gemini's functionDeclarations format
+func ToGeminiTools(tools []Tool) []map[string]interface{} {
 	// Gemini uses a single object with functionDeclarations array
 	functionDeclarations := make([]map[string]interface{}, len(tools))
 
 	for i, tool := range tools {
-		functionDeclarations[i] = map[string]interface{}{
+		declaration := map[string]interface{}{
 			"name":        tool.Function.Name,
 			"description": tool.Function.Description,
-			"parameters":  tool.Function.Parameters,
 		}
+		// Gemini rejeita schemas OBJECT sem propriedades
+		if len(tool.Function.Parameters.Properties) > 0 {
+			declaration["parameters"] = tool.Function.Parameters
+		}
+		functionDeclarations[i] = declaration
 	}
 
 	return []map[string]interface{}{
@@ -240,6 +265,26 @@ func GetGeminiTools() []map[string]interface{} {
 	}
 }
 
+// ToAnthropicTools converts tools to Anthropic's format (name, description, input_schema)
+func ToAnthropicTools(tools []Tool) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(tools))
+	for i, tool := range tools {
+		schema := tool.Function.Parameters
+		if schema.Type == "" {
+			schema.Type = "object"
+		}
+		if schema.Properties == nil {
+			schema.Properties = map[string]FunctionProperty{}
+		}
+		result[i] = map[string]interface{}{
+			"name":         tool.Function.Name,
+			"description":  tool.Function.Description,
+			"input_schema": schema,
+		}
+	}
+	return result
+}
+
 // IsQueryTool returns true if the tool is a read-only query
 func IsQueryTool(name string) bool {
 	queryTools := map[string]bool{
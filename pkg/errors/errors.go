@@ -1,7 +1,9 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
+	"net/http"
 )
 
 // ErrorCode representa códigos de erro específicos
@@ -196,6 +198,10 @@ func Unauthorized(msg string) *AppError {
 	return New(ErrCodeUnauthorized, msg)
 }
 
+func Forbidden(msg string) *AppError {
+	return New(ErrCodeForbidden, msg)
+}
+
 func RateLimit(msg string) *AppError {
 	return New(ErrCodeRateLimit, msg)
 }
@@ -265,3 +271,35 @@ func GetUserFriendlyMessage(err error) string {
 
 	return err.Error()
 }
+
+// HTTPStatus retorna o status HTTP correspondente a um erro.
+// Usa AppError.StatusCode quando definido (também em erros encapsulados); erros comuns viram 500.
+func HTTPStatus(err error) int {
+	var appErr *AppError
+	if !stderrors.As(err, &appErr) {
+		return http.StatusInternalServerError
+	}
+	if appErr.StatusCode != 0 {
+		return appErr.StatusCode
+	}
+
+	switch appErr.Code {
+	case ErrCodeInvalidInput, ErrCodeInvalidRange, ErrCodeInvalidSheet, ErrCodeAIModelInvalid:
+		return http.StatusBadRequest
+	case ErrCodeUnauthorized, ErrCodeAIAPIKeyMissing, ErrCodeAIAPIKeyInvalid, ErrCodeLicenseInvalid:
+		return http.StatusUnauthorized
+	case ErrCodeForbidden, ErrCodeLicenseExpired:
+		return http.StatusForbidden
+	case ErrCodeNotFound, ErrCodeExcelNotFound:
+		return http.StatusNotFound
+	case ErrCodeConflict, ErrCodeExcelBusy, ErrCodeExcelNotConnected:
+		return http.StatusConflict
+	case ErrCodeRateLimit, ErrCodeAIQuotaExceeded:
+		return http.StatusTooManyRequests
+	case ErrCodeTimeout:
+		return http.StatusGatewayTimeout
+	case ErrCodeAIStreamError:
+		return http.StatusBadGateway
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,116 @@
+package excel
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// cellContent conteúdo de uma célula movida ou copiada: valor bruto com tipo,
+// fórmula e estilo
+type cellContent struct {
+	Value   string
+	Type    excelize.CellType
+	Formula string
+	Style   int
+}
+
+// readCellLocked lê o conteúdo de uma célula (chamar com c.mu)
+func (c *ExcelizeClient) readCellLocked(sheet, cell string) (cellContent, error) {
+	var content cellContent
+	var err error
+	if content.Value, err = c.file.GetCellValue(sheet, cell, excelize.Options{RawCellValue: true}); err != nil {
+		return content, err
+	}
+	if content.Type, err = c.file.GetCellType(sheet, cell); err != nil {
+		return content, err
+	}
+	if content.Formula, err = c.file.GetCellFormula(sheet, cell); err != nil {
+		return content, err
+	}
+	content.Style, err = c.file.GetCellStyle(sheet, cell)
+	return content, err
+}
+
+// writeCellLocked grava o conteúdo em uma célula, substituindo valor, fórmula e
+// estilo anteriores (chamar com c.mu)
+func (c *ExcelizeClient) writeCellLocked(sheet, cell string, content cellContent) error {
+	var err error
+	switch {
+	case content.Value == "":
+		err = c.file.SetCellValue(sheet, cell, nil)
+	case content.Type == excelize.CellTypeBool:
+		err = c.file.SetCellBool(sheet, cell, content.Value == "1" || strings.EqualFold(content.Value, "TRUE"))
+	case content.Type == excelize.CellTypeUnset || content.Type == excelize.CellTypeNumber:
+		if num, parseErr := strconv.ParseFloat(content.Value, 64); parseErr == nil {
+			err = c.file.SetCellFloat(sheet, cell, num, -1, 64)
+		} else {
+			err = c.file.SetCellStr(sheet, cell, content.Value)
+		}
+	default:
+		err = c.file.SetCellStr(sheet, cell, content.Value)
+	}
+	if err != nil {
+		return err
+	}
+
+	// O valor gravado acima fica como resultado em cache até o recálculo
+	if content.Formula != "" {
+		if err := c.file.SetCellFormula(sheet, cell, content.Formula); err != nil {
+			return err
+		}
+	}
+	return c.file.SetCellStyle(sheet, cell, cell, content.Style)
+}
+
+// unshareFormulasLocked converte as fórmulas compartilhadas da planilha em fórmulas
+// comuns. Alterar a célula principal de um grupo compartilhado apaga as fórmulas
+// de todo o grupo no Excelize (chamar com c.mu).
+func (c *ExcelizeClient) unshareFormulasLocked(sheet string) error {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+	var ws struct {
+		Cells []struct {
+			R string `xml:"r,attr"`
+			F *struct {
+				T string `xml:"t,attr"`
+			} `xml:"f"`
+		} `xml:"sheetData>row>c"`
+	}
+	if err := pkg.decode(part, &ws); err != nil {
+		return err
+	}
+
+	shared := make(map[string]string)
+	for _, cell := range ws.Cells {
+		if cell.F == nil || cell.F.T != "shared" {
+			continue
+		}
+		formula, err := c.file.GetCellFormula(sheet, cell.R)
+		if err != nil {
+			return err
+		}
+		shared[cell.R] = formula
+	}
+	for cell := range shared {
+		if err := c.file.SetCellFormula(sheet, cell, ""); err != nil {
+			return err
+		}
+	}
+	for cell, formula := range shared {
+		if formula == "" {
+			continue
+		}
+		if err := c.file.SetCellFormula(sheet, cell, formula); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,558 @@
+package excel
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Variantes (agrupado, empilhado, empilhado 100%) dos tipos que suportam empilhamento
+var stackableChartTypes = map[string][3]excelize.ChartType{
+	"column": {excelize.Col, excelize.ColStacked, excelize.ColPercentStacked},
+	"bar":    {excelize.Bar, excelize.BarStacked, excelize.BarPercentStacked},
+	"area":   {excelize.Area, excelize.AreaStacked, excelize.AreaPercentStacked},
+}
+
+// Tipos sem variantes empilhadas
+var simpleChartTypes = map[string]excelize.ChartType{
+	"line":     excelize.Line,
+	"pie":      excelize.Pie,
+	"doughnut": excelize.Doughnut,
+	"radar":    excelize.Radar,
+	"scatter":  excelize.Scatter,
+	"bubble":   excelize.Bubble,
+}
+
+var chartLegendPositions = map[string]bool{
+	"": true, "top": true, "bottom": true, "left": true, "right": true, "top_right": true, "none": true,
+}
+
+// CreateChart cria um gráfico a partir de um intervalo
+// (1ª coluna = categorias, demais colunas = séries, cabeçalho = nomes)
+func (c *ExcelizeClient) CreateChart(sheet, rng, chartType, title string) error {
+	return c.CreateChartSpec(sheet, ChartSpec{Type: chartType, Title: title, Range: rng})
+}
+
+// CreateChartSpec cria um gráfico com várias séries, eixos, legenda e combinação de tipos
+func (c *ExcelizeClient) CreateChartSpec(sheet string, spec ChartSpec) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mainType := normalizeChartType(spec.Type)
+	if mainType == "combo" {
+		mainType = "column"
+	}
+	if _, err := chartTypeOf(mainType, spec.Stacked, spec.Percent); err != nil {
+		return err
+	}
+	if !chartLegendPositions[spec.Legend] {
+		return fmt.Errorf("unsupported legend position: %s", spec.Legend)
+	}
+
+	series := spec.Series
+	if len(series) == 0 {
+		if spec.Range == "" {
+			return fmt.Errorf("chart requires range or series")
+		}
+		var err error
+		series, err = c.seriesFromRange(sheet, spec.Range)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Agrupar séries por tipo e eixo: o primeiro grupo é o gráfico principal,
+	// os demais viram gráficos combinados
+	type group struct {
+		typeName  string
+		secondary bool
+		series    []excelize.ChartSeries
+	}
+	var groups []*group
+	for i, s := range series {
+		if s.Values == "" {
+			return fmt.Errorf("series %d has no values", i+1)
+		}
+		typeName := normalizeChartType(s.Type)
+		if typeName == "" {
+			typeName = mainType
+		}
+
+		cs := excelize.ChartSeries{
+			Name:       qualifyChartRef(sheet, s.Name, true),
+			Categories: qualifyChartRef(sheet, s.Categories, false),
+			Values:     qualifyChartRef(sheet, s.Values, false),
+			Sizes:      qualifyChartRef(sheet, s.Sizes, false),
+		}
+		if typeName == "bubble" && cs.Sizes == "" {
+			cs.Sizes = cs.Values
+		}
+
+		var g *group
+		for _, existing := range groups {
+			if existing.typeName == typeName && existing.secondary == s.SecondaryAxis {
+				g = existing
+				break
+			}
+		}
+		if g == nil {
+			g = &group{typeName: typeName, secondary: s.SecondaryAxis}
+			if typeName == mainType && !s.SecondaryAxis {
+				groups = append([]*group{g}, groups...)
+			} else {
+				groups = append(groups, g)
+			}
+		}
+		g.series = append(g.series, cs)
+	}
+
+	plotArea := excelize.ChartPlotArea{ShowVal: spec.DataLabels}
+
+	charts := make([]*excelize.Chart, 0, len(groups))
+	for i, g := range groups {
+		chartType, err := chartTypeOf(g.typeName, spec.Stacked, spec.Percent)
+		if err != nil {
+			return err
+		}
+		chart := &excelize.Chart{
+			Type:     chartType,
+			Series:   g.series,
+			PlotArea: plotArea,
+		}
+		if g.secondary && i > 0 {
+			chart.YAxis = excelize.ChartAxis{Secondary: true, Title: richText(spec.SecondaryAxisTitle)}
+		}
+		charts = append(charts, chart)
+	}
+
+	main := charts[0]
+	main.Title = richText(spec.Title)
+	main.Legend = excelize.ChartLegend{Position: spec.Legend}
+	main.Dimension = excelize.ChartDimension{Width: spec.Width, Height: spec.Height}
+	main.XAxis.Title = richText(spec.XAxisTitle)
+	main.YAxis.Title = richText(spec.YAxisTitle)
+	main.Format.AltText = spec.Title
+
+	anchor := spec.Anchor
+	if anchor == "" {
+		// Nomes definidos e tabelas são resolvidos para o intervalo que representam
+		_, dataRange := c.resolveRangeLocked(sheet, spec.Range)
+		anchor = defaultChartAnchor(series[0].Values, dataRange)
+	}
+
+	if err := c.file.AddChart(sheet, anchor, main, charts[1:]...); err != nil {
+		return fmt.Errorf("failed to create chart: %w", err)
+	}
+	return nil
+}
+
+// seriesFromRange deriva as séries de um intervalo retangular
+func (c *ExcelizeClient) seriesFromRange(sheet, rng string) ([]ChartSeriesSpec, error) {
+	dataSheet, ref := c.resolveRangeLocked(sheet, rng)
+	startCell, endCell, err := parseRange(ref)
+	if err != nil {
+		return nil, err
+	}
+	col1, row1, err := excelize.CellNameToCoordinates(strings.ReplaceAll(startCell, "$", ""))
+	if err != nil {
+		return nil, err
+	}
+	col2, row2, err := excelize.CellNameToCoordinates(strings.ReplaceAll(endCell, "$", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	// Cabeçalho: primeira linha com algum texto não numérico nas colunas de valores
+	firstValueCol := col1
+	if col2 > col1 {
+		firstValueCol = col1 + 1
+	}
+	hasHeader := false
+	if row2 > row1 {
+		for col := firstValueCol; col <= col2; col++ {
+			cell, _ := excelize.CoordinatesToCellName(col, row1)
+			v, _ := c.file.GetCellValue(dataSheet, cell)
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); v != "" && err != nil {
+				hasHeader = true
+				break
+			}
+		}
+	}
+	dataRow := row1
+	if hasHeader {
+		dataRow = row1 + 1
+	}
+
+	categories := ""
+	if col2 > col1 {
+		categories = absoluteRef(dataSheet, col1, dataRow, col1, row2)
+	}
+
+	var series []ChartSeriesSpec
+	for col := firstValueCol; col <= col2; col++ {
+		s := ChartSeriesSpec{
+			Categories: categories,
+			Values:     absoluteRef(dataSheet, col, dataRow, col, row2),
+		}
+		if hasHeader {
+			s.Name = absoluteRef(dataSheet, col, row1, col, row1)
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+// normalizeChartType converte aliases (col, donut, ...) para o nome canônico
+func normalizeChartType(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "col", "columns":
+		return "column"
+	case "donut":
+		return "doughnut"
+	case "lines":
+		return "line"
+	}
+	return name
+}
+
+// chartTypeOf mapeia o nome do tipo para o tipo Excelize
+func chartTypeOf(name string, stacked, percent bool) (excelize.ChartType, error) {
+	if variants, ok := stackableChartTypes[name]; ok {
+		switch {
+		case percent:
+			return variants[2], nil
+		case stacked:
+			return variants[1], nil
+		}
+		return variants[0], nil
+	}
+	if t, ok := simpleChartTypes[name]; ok {
+		return t, nil
+	}
+	return 0, fmt.Errorf("unsupported chart type: %s", name)
+}
+
+// qualifyChartRef prefixa a planilha em referências sem '!'.
+// Nomes de série que não são referências de célula são mantidos como texto.
+func qualifyChartRef(sheet, ref string, isName bool) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.Contains(ref, "!") {
+		return ref
+	}
+	if isName {
+		if _, _, err := excelize.CellNameToCoordinates(strings.ReplaceAll(ref, "$", "")); err != nil {
+			return ref
+		}
+	}
+	return quoteSheetName(sheet) + "!" + ref
+}
+
+// splitSheetRef separa 'Plan1!A1:B2' em planilha e intervalo
+func splitSheetRef(sheet, ref string) (string, string) {
+	if idx := strings.LastIndex(ref, "!"); idx >= 0 {
+		name := strings.Trim(ref[:idx], "'")
+		return strings.ReplaceAll(name, "''", "'"), ref[idx+1:]
+	}
+	return sheet, ref
+}
+
+// absoluteRef monta 'Plan1'!$A$1:$A$10
+func absoluteRef(sheet string, col1, row1, col2, row2 int) string {
+	start, _ := excelize.CoordinatesToCellName(col1, row1, true)
+	if col1 == col2 && row1 == row2 {
+		return quoteSheetName(sheet) + "!" + start
+	}
+	end, _ := excelize.CoordinatesToCellName(col2, row2, true)
+	return quoteSheetName(sheet) + "!" + start + ":" + end
+}
+
+// quoteSheetName coloca aspas simples em nomes com espaços ou símbolos, nomes vazios,
+// iniciados por dígito ou que se confundem com referências (A1, R1C1)
+func quoteSheetName(sheet string) string {
+	if sheetNeedsQuotes(sheet) {
+		return "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+	}
+	return sheet
+}
+
+func sheetNeedsQuotes(sheet string) bool {
+	if sheet == "" || (sheet[0] >= '0' && sheet[0] <= '9') || r1c1Name.MatchString(sheet) {
+		return true
+	}
+	if _, _, err := excelize.CellNameToCoordinates(sheet); err == nil {
+		return true
+	}
+	for _, r := range sheet {
+		if !(r == '_' || r == '.' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 127) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultChartAnchor posiciona o gráfico duas colunas à direita dos dados
+func defaultChartAnchor(values, rng string) string {
+	ref := rng
+	if ref == "" {
+		ref = values
+	}
+	_, ref = splitSheetRef("", ref)
+	startCell, endCell, err := parseRange(ref)
+	if err != nil {
+		return "H2"
+	}
+	_, row, err1 := excelize.CellNameToCoordinates(strings.ReplaceAll(startCell, "$", ""))
+	col, _, err2 := excelize.CellNameToCoordinates(strings.ReplaceAll(endCell, "$", ""))
+	if err1 != nil || err2 != nil {
+		return "H2"
+	}
+	anchor, err := excelize.CoordinatesToCellName(col+2, row)
+	if err != nil {
+		return "H2"
+	}
+	return anchor
+}
+
+func richText(text string) []excelize.RichTextRun {
+	if text == "" {
+		return nil
+	}
+	return []excelize.RichTextRun{{Text: text}}
+}
+
+// xmlChartSpace subconjunto de xl/charts/chartN.xml
+type xmlChartSpace struct {
+	Title *struct {
+		Runs   []string `xml:"tx>rich>p>r>t"`
+		StrRef string   `xml:"tx>strRef>f"`
+	} `xml:"chart>title"`
+	PlotArea struct {
+		ValAx []struct {
+			AxID  xmlVal `xml:"axId"`
+			AxPos xmlVal `xml:"axPos"`
+		} `xml:"valAx"`
+		Groups []xmlChartGroup `xml:",any"`
+	} `xml:"chart>plotArea"`
+}
+
+type xmlChartGroup struct {
+	XMLName  xml.Name
+	BarDir   *xmlVal       `xml:"barDir"`
+	Grouping *xmlVal       `xml:"grouping"`
+	AxIDs    []xmlVal      `xml:"axId"`
+	Series   []xmlChartSer `xml:"ser"`
+}
+
+type xmlChartSer struct {
+	TxRef      string `xml:"tx>strRef>f"`
+	TxValue    string `xml:"tx>v"`
+	Cat        string `xml:"cat>numRef>f"`
+	CatStr     string `xml:"cat>strRef>f"`
+	Val        string `xml:"val>numRef>f"`
+	XVal       string `xml:"xVal>numRef>f"`
+	XValStr    string `xml:"xVal>strRef>f"`
+	YVal       string `xml:"yVal>numRef>f"`
+	BubbleSize string `xml:"bubbleSize>numRef>f"`
+}
+
+// ListCharts lista os nomes dos gráficos da planilha
+func (c *ExcelizeClient) ListCharts(sheet string) ([]string, error) {
+	charts, err := c.GetCharts(sheet)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(charts))
+	for _, chart := range charts {
+		names = append(names, chart.Name)
+	}
+	return names, nil
+}
+
+// GetCharts lê os gráficos da planilha a partir das partes de desenho
+func (c *ExcelizeClient) GetCharts(sheet string) ([]ChartInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.getChartsLocked(sheet)
+}
+
+func (c *ExcelizeClient) getChartsLocked(sheet string) ([]ChartInfo, error) {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return nil, err
+	}
+	sheetPart, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return nil, err
+	}
+	drawingPart := pkg.drawingPart(sheetPart)
+	if drawingPart == "" {
+		return []ChartInfo{}, nil
+	}
+	anchors, err := pkg.drawingAnchors(drawingPart)
+	if err != nil {
+		return nil, err
+	}
+	rels := pkg.rels(drawingPart)
+
+	charts := []ChartInfo{}
+	for _, anchor := range anchors {
+		frame := anchor.GraphicFrame
+		if frame == nil || frame.Chart == nil {
+			continue
+		}
+		info := ChartInfo{
+			Name:   frame.CNvPr.Name,
+			Sheet:  sheet,
+			Anchor: anchor.anchorCell(),
+			Series: []ChartSeriesSpec{},
+		}
+
+		var space xmlChartSpace
+		if err := pkg.decode(rels[frame.Chart.RID], &space); err != nil {
+			charts = append(charts, info)
+			continue
+		}
+		if space.Title != nil {
+			info.Title = strings.Join(space.Title.Runs, "")
+			if info.Title == "" {
+				info.Title = space.Title.StrRef
+			}
+		}
+		if info.Title == "" {
+			info.Title = frame.CNvPr.Descr
+		}
+		fillChartInfo(&info, &space)
+		charts = append(charts, info)
+	}
+	return charts, nil
+}
+
+// fillChartInfo extrai tipo, empilhamento e séries dos grupos do plotArea
+func fillChartInfo(info *ChartInfo, space *xmlChartSpace) {
+	secondaryAxes := make(map[string]bool)
+	for _, ax := range space.PlotArea.ValAx {
+		if ax.AxPos.Val == "r" {
+			secondaryAxes[ax.AxID.Val] = true
+		}
+	}
+
+	types := make(map[string]bool)
+	for _, group := range space.PlotArea.Groups {
+		local := group.XMLName.Local
+		if !strings.HasSuffix(local, "Chart") {
+			continue
+		}
+		typeName := chartTypeName(local, group.BarDir)
+		types[typeName] = true
+
+		if group.Grouping != nil {
+			switch group.Grouping.Val {
+			case "stacked":
+				info.Stacked = true
+			case "percentStacked":
+				info.Percent = true
+			}
+		}
+		secondary := false
+		for _, id := range group.AxIDs {
+			if secondaryAxes[id.Val] {
+				secondary = true
+			}
+		}
+
+		for _, ser := range group.Series {
+			s := ChartSeriesSpec{
+				Name:          firstNonEmpty(ser.TxRef, ser.TxValue),
+				Categories:    firstNonEmpty(ser.Cat, ser.CatStr, ser.XVal, ser.XValStr),
+				Values:        firstNonEmpty(ser.Val, ser.YVal),
+				Sizes:         ser.BubbleSize,
+				Type:          typeName,
+				SecondaryAxis: secondary,
+			}
+			info.Series = append(info.Series, s)
+		}
+	}
+
+	switch {
+	case len(types) > 1:
+		info.Type = "combo"
+	case len(info.Series) > 0:
+		info.Type = info.Series[0].Type
+	}
+	// Tipo por série só é relevante em gráficos combinados
+	if len(types) == 1 {
+		for i := range info.Series {
+			info.Series[i].Type = ""
+		}
+	}
+}
+
+// chartTypeName converte o elemento do plotArea (barChart, line3DChart, ...) no nome usado em ChartSpec
+func chartTypeName(local string, barDir *xmlVal) string {
+	name := strings.TrimSuffix(strings.Replace(local, "3D", "", 1), "Chart")
+	switch name {
+	case "bar":
+		if barDir != nil && barDir.Val == "bar" {
+			return "bar"
+		}
+		return "column"
+	case "ofPie":
+		return "pie"
+	}
+	return strings.ToLower(name)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// DeleteChart exclui um gráfico pelo nome (ex: 'Chart 2'), título ou célula de ancoragem
+func (c *ExcelizeClient) DeleteChart(sheet, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	charts, err := c.getChartsLocked(sheet)
+	if err != nil {
+		return err
+	}
+
+	// Prioridade: nome, célula de ancoragem, título. Nomes e títulos podem se repetir.
+	target := strings.TrimSpace(name)
+	var anchors []string
+	for _, match := range []func(ChartInfo) bool{
+		func(ci ChartInfo) bool { return strings.EqualFold(ci.Name, target) },
+		func(ci ChartInfo) bool { return strings.EqualFold(ci.Anchor, strings.ReplaceAll(target, "$", "")) },
+		func(ci ChartInfo) bool { return ci.Title != "" && strings.EqualFold(ci.Title, target) },
+	} {
+		for _, chart := range charts {
+			if match(chart) && chart.Anchor != "" {
+				anchors = append(anchors, chart.Anchor)
+			}
+		}
+		if len(anchors) > 0 {
+			break
+		}
+	}
+	switch {
+	case len(anchors) == 0:
+		return fmt.Errorf("chart not found: %s", name)
+	case len(anchors) > 1:
+		return fmt.Errorf("more than one chart matches %q, use the anchor cell (%s)", name, strings.Join(anchors, ", "))
+	}
+	anchor := anchors[0]
+
+	if err := c.file.DeleteChart(sheet, anchor); err != nil {
+		return fmt.Errorf("failed to delete chart: %w", err)
+	}
+	return nil
+}
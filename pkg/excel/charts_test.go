@@ -0,0 +1,83 @@
+package excel
+
+import "testing"
+
+func TestQuoteSheetName(t *testing.T) {
+	tests := []struct {
+		name  string
+		sheet string
+		want  string
+	}{
+		{"simples", "Vendas", "Vendas"},
+		{"acentos e ponto", "Relatório.2024", "Relatório.2024"},
+		{"espaço", "Plano de Contas", "'Plano de Contas'"},
+		{"apóstrofo", "O'Brien", "'O''Brien'"},
+		{"vazio", "", "''"},
+		{"só dígitos", "2024", "'2024'"},
+		{"começa com dígito", "1T", "'1T'"},
+		{"referência A1", "A1", "'A1'"},
+		{"referência A1 minúscula", "xfd10", "'xfd10'"},
+		{"referência R1C1", "R1C1", "'R1C1'"},
+		{"linha R1C1", "R2", "'R2'"},
+		{"coluna R1C1", "C", "'C'"},
+		{"parece A1 mas não é", "ABCD1", "ABCD1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quoteSheetName(tt.sheet); got != tt.want {
+				t.Errorf("quoteSheetName(%q) = %q, esperado %q", tt.sheet, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemapFormulaColumnsKeepsQuotedSheet(t *testing.T) {
+	got := remapFormulaColumns("='2024'!A1*2", "2024", "2024", insertedColumns(0, 1))
+	if want := "='2024'!B1*2"; got != want {
+		t.Errorf("remapFormulaColumns = %q, esperado %q", got, want)
+	}
+}
+
+func TestDefaultChartAnchor(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ChartSpec
+		want string
+	}{
+		{"intervalo", ChartSpec{Range: "D3:E6"}, "G3"},
+		{"nome definido", ChartSpec{Range: "Dados"}, "G3"},
+		{"tabela", ChartSpec{Range: "Vendas"}, "G3"},
+		{"séries explícitas", ChartSpec{Series: []ChartSeriesSpec{{Values: "Sheet1!$E$4:$E$6"}}}, "G4"},
+		{"âncora informada", ChartSpec{Range: "Dados", Anchor: "A10"}, "A10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t)
+			data := [][]interface{}{{"Mês", "Valor"}, {"Jan", 10}, {"Fev", 20}, {"Mar", 30}}
+			if err := c.WriteRange("Sheet1", "D3", data); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.CreateDefinedName("Sheet1", DefinedName{Name: "Dados", RefersTo: "Sheet1!$D$3:$E$6"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.CreateTable("Sheet1", "D3:E6", "Vendas", ""); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.spec.Type = "column"
+			if err := c.CreateChartSpec("Sheet1", tt.spec); err != nil {
+				t.Fatalf("CreateChartSpec retornou erro: %v", err)
+			}
+			charts, err := c.GetCharts("Sheet1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(charts) != 1 {
+				t.Fatalf("%d gráficos, esperado 1", len(charts))
+			}
+			if charts[0].Anchor != tt.want {
+				t.Errorf("âncora = %q, esperado %q", charts[0].Anchor, tt.want)
+			}
+		})
+	}
+}
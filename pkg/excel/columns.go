@@ -0,0 +1,967 @@
+package excel
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	// conditionalBlock bloco de formatação condicional da planilha
+	conditionalBlock = regexp.MustCompile(`(?s)<conditionalFormatting\b[^>]*>.*?</conditionalFormatting>`)
+	// dataValidationElem regra de validação de dados
+	dataValidationElem = regexp.MustCompile(`(?s)<dataValidation\b[^>]*?(?:/>|>.*?</dataValidation>)`)
+	// dataValidationsTag etiqueta de abertura da lista de validações
+	dataValidationsTag = regexp.MustCompile(`<dataValidations\b[^>]*>`)
+	// emptyDataValidations lista de validações que ficou vazia
+	emptyDataValidations = regexp.MustCompile(`<dataValidations\b[^>]*>\s*</dataValidations>`)
+	// sheetAutoFilterElem filtro automático da planilha
+	sheetAutoFilterElem = regexp.MustCompile(`(?s)<autoFilter\b[^>]*?(?:/>|>.*?</autoFilter>)`)
+	// filterColumnElem critério do filtro automático para uma coluna
+	filterColumnElem = regexp.MustCompile(`(?s)<filterColumn\b[^>]*?(?:/>|>.*?</filterColumn>)`)
+	// ruleFormulaElem fórmula de uma regra de formatação condicional ou validação
+	ruleFormulaElem = regexp.MustCompile(`(<(?:formula[12]?|xm:f)>)([^<]*)(</(?:formula[12]?|xm:f)>)`)
+	// colElem largura, formato e estrutura de um grupo de colunas
+	colElem = regexp.MustCompile(`<col\b[^>]*?(?:/>|></col>)`)
+	// colMinAttr e colMaxAttr primeira e última coluna (base 1) de um <col>
+	colMinAttr = regexp.MustCompile(`\smin="([0-9]+)"`)
+	colMaxAttr = regexp.MustCompile(`\smax="([0-9]+)"`)
+	// sqrefAttr, refAttr e colIDAttr atributos das etiquetas alteradas
+	sqrefAttr = regexp.MustCompile(`\ssqref="([^"]*)"`)
+	refAttr   = regexp.MustCompile(`\sref="([^"]*)"`)
+	colIDAttr = regexp.MustCompile(`\scolId="([0-9]+)"`)
+)
+
+// columnMap nova posição de um intervalo de colunas [first, last] (base 0) após
+// uma operação; ok falso quando todas as colunas do intervalo deixam de existir
+type columnMap func(first, last int) (int, int, bool)
+
+// insertedColumns count colunas inseridas antes da coluna at
+func insertedColumns(at, count int) columnMap {
+	return func(first, last int) (int, int, bool) {
+		if first >= at {
+			first += count
+		}
+		if last >= at {
+			last += count
+		}
+		return first, last, true
+	}
+}
+
+// deletedColumns colunas from a to excluídas
+func deletedColumns(from, to int) columnMap {
+	count := to - from + 1
+	return func(first, last int) (int, int, bool) {
+		if first >= from && last <= to {
+			return 0, 0, false
+		}
+		switch {
+		case first > to:
+			first -= count
+		case first >= from:
+			first = from
+		}
+		switch {
+		case last > to:
+			last -= count
+		case last >= from:
+			last = from - 1
+		}
+		return first, last, true
+	}
+}
+
+// clampedColumns afasta das colunas from a to as extremidades que caem nelas. É
+// aplicado antes da exclusão feita pelo Excelize, que desloca para a esquerda toda
+// extremidade a partir da coluna excluída, inclusive o início de um intervalo que
+// começa nela.
+func clampedColumns(from, to int) columnMap {
+	return func(first, last int) (int, int, bool) {
+		if first >= from && last <= to {
+			return 0, 0, false
+		}
+		if first >= from && first <= to {
+			first = to + 1
+		}
+		if last >= from && last <= to {
+			last = from - 1
+		}
+		return first, last, true
+	}
+}
+
+// movedColumns desloca em offset os intervalos contidos nas colunas from a to
+func movedColumns(from, to, offset int) columnMap {
+	return func(first, last int) (int, int, bool) {
+		if first >= from && last <= to {
+			return first + offset, last + offset, true
+		}
+		return first, last, true
+	}
+}
+
+// InsertColumns insere colunas antes ou depois de uma coluna (letra ou cabeçalho;
+// sem posição, depois da última usada). Fórmulas, mesclagens, tabelas, formatação
+// condicional, validações, filtros, nomes e comentários acompanham as colunas
+// deslocadas. Retorna as colunas inseridas ("E:F").
+func (c *ExcelizeClient) InsertColumns(sheet string, spec ColumnInsert) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.sheetIndexLocked(sheet); err != nil {
+		return "", err
+	}
+	at, err := c.insertPositionLocked(sheet, spec.Before, spec.After)
+	if err != nil {
+		return "", err
+	}
+	count := spec.Count
+	if count == 0 {
+		count = max(1, len(spec.Headers))
+	}
+	if count < 1 || at+count > excelize.MaxColumns {
+		return "", fmt.Errorf("invalid column count: %d", count)
+	}
+	if len(spec.Headers) > count {
+		return "", fmt.Errorf("got %d headers for %d columns", len(spec.Headers), count)
+	}
+	headerRow, _, err := c.headerRowLocked(sheet)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.insertColumnsLocked(sheet, at, count, spec.Headers); err != nil {
+		return "", err
+	}
+
+	// Cabeçalhos de colunas fora de tabelas vão para a primeira linha preenchida
+	resolver := c.newRefResolverLocked()
+	for i, header := range spec.Headers {
+		if _, ok := sheetTableAt(resolver, sheet, at+i); ok {
+			continue
+		}
+		if err := c.file.SetCellStr(sheet, indicesToCell(max(headerRow, 0), at+i), strings.TrimSpace(header)); err != nil {
+			return "", err
+		}
+	}
+
+	if formula := strings.TrimPrefix(strings.TrimSpace(spec.Formula), "="); formula != "" {
+		first, last := headerRow+1, -1
+		if t, ok := sheetTableAt(resolver, sheet, at); ok {
+			body := t.body()
+			first, last = body.StartRow, body.EndRow
+		} else if rows, err := c.file.GetRows(sheet); err == nil {
+			last = len(rows) - 1
+		}
+		for row := first; row <= last; row++ {
+			for j := 0; j < count; j++ {
+				if err := c.file.SetCellFormula(sheet, indicesToCell(row, at+j), shiftFormula(formula, row-first, j)); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+	return columnsText(at, at+count-1), nil
+}
+
+// DeleteColumns exclui colunas (letra, intervalo "C:E" ou cabeçalho; count > 0
+// exclui count colunas a partir da primeira). Referências às colunas excluídas
+// viram #REF! e o restante é ajustado como em InsertColumns. Retorna as colunas
+// excluídas.
+func (c *ExcelizeClient) DeleteColumns(sheet, column string, count int) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.sheetIndexLocked(sheet); err != nil {
+		return "", err
+	}
+	first, last, err := c.columnSpanLocked(sheet, column, count)
+	if err != nil {
+		return "", err
+	}
+	if err := c.deleteColumnsLocked(sheet, first, last); err != nil {
+		return "", err
+	}
+	return columnsText(first, last), nil
+}
+
+// MoveColumns move colunas (letra, intervalo ou cabeçalho) para antes ou depois de
+// outra coluna, como recortar e inserir no Excel: as fórmulas continuam apontando
+// para as mesmas células. Retorna a nova posição das colunas.
+func (c *ExcelizeClient) MoveColumns(sheet, column, before, after string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.sheetIndexLocked(sheet); err != nil {
+		return "", err
+	}
+	first, last, err := c.columnSpanLocked(sheet, column, 0)
+	if err != nil {
+		return "", err
+	}
+	if before == "" && after == "" {
+		return "", fmt.Errorf("before or after is required")
+	}
+	at, err := c.insertPositionLocked(sheet, before, after)
+	if err != nil {
+		return "", err
+	}
+	count := last - first + 1
+	if at >= first && at <= last+1 {
+		return columnsText(first, last), nil
+	}
+	if at+count > excelize.MaxColumns {
+		return "", fmt.Errorf("cannot move columns beyond the last column")
+	}
+
+	// Abre espaço no destino; as colunas de origem se deslocam se estiverem depois dele
+	if err := c.insertColumnsLocked(sheet, at, count, nil); err != nil {
+		return "", err
+	}
+	if at < first {
+		first, last = first+count, last+count
+	}
+
+	// Move as células e o que está preso a elas
+	area := cellRef{Sheet: sheet, StartCol: first, EndRow: excelize.TotalRows - 1, EndCol: last}
+	c.clampToUsedAreaLocked(&area)
+	area.StartCol, area.EndCol = first, last
+	if _, err := c.moveAreaLocked(area, cellRef{Sheet: sheet, StartCol: at, EndCol: at}); err != nil {
+		return "", err
+	}
+	moved := movedColumns(first, last, at-first)
+	names := c.file.GetDefinedName()
+	if err := c.remapCellFormulasLocked(sheet, moved); err != nil {
+		return "", err
+	}
+	if err := c.remapNamesLocked(names, sheet, moved); err != nil {
+		return "", err
+	}
+	if err := c.patchColumnsXMLLocked(sheet, moved, moved); err != nil {
+		return "", err
+	}
+	for i := 0; i < count; i++ {
+		if err := c.copyColumnLayoutLocked(sheet, first+i, at+i); err != nil {
+			return "", err
+		}
+	}
+
+	if err := c.deleteColumnsLocked(sheet, first, last); err != nil {
+		return "", err
+	}
+	if at > first {
+		at -= count
+	}
+	return columnsText(at, at+count-1), nil
+}
+
+// ColumnRange intervalo usado das colunas indicadas (ex: "C1:D120"), para ler os
+// valores antes de excluí-las
+func (c *ExcelizeClient) ColumnRange(sheet, column string, count int) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.sheetIndexLocked(sheet); err != nil {
+		return "", err
+	}
+	first, last, err := c.columnSpanLocked(sheet, column, count)
+	if err != nil {
+		return "", err
+	}
+	area := cellRef{Sheet: sheet, StartCol: first, EndRow: excelize.TotalRows - 1, EndCol: last}
+	c.clampToUsedAreaLocked(&area)
+	return indicesToCell(0, first) + ":" + indicesToCell(area.EndRow, last), nil
+}
+
+// insertColumnsLocked insere count colunas antes da coluna at (base 0). As novas
+// colunas dentro de tabelas recebem os cabeçalhos de headers (ou "ColunaN"); com
+// cabeçalhos, a tabela que termina logo antes de at é estendida (chamar com c.mu).
+func (c *ExcelizeClient) insertColumnsLocked(sheet string, at, count int, headers []string) error {
+	m := insertedColumns(at, count)
+	tables, err := c.sheetTablesLocked(sheet)
+	if err != nil {
+		return err
+	}
+	names := c.file.GetDefinedName()
+	if err := c.patchColumnsXMLLocked(sheet, nil, m); err != nil {
+		return err
+	}
+
+	col, err := excelize.ColumnNumberToName(at + 1)
+	if err != nil {
+		return err
+	}
+	if err := c.file.InsertCols(sheet, col, count); err != nil {
+		return fmt.Errorf("failed to insert columns: %w", err)
+	}
+
+	for _, t := range tables {
+		area := t.Area
+		area.StartCol, area.EndCol, _ = m(area.StartCol, area.EndCol)
+		if len(headers) > 0 && at == t.Area.EndCol+1 {
+			area.EndCol += count
+		}
+		// O Excelize grava "ColumnN" nos cabeçalhos das colunas inseridas na tabela
+		for i := 0; i < count; i++ {
+			if at+i < area.StartCol || at+i > area.EndCol {
+				continue
+			}
+			header := ""
+			if i < len(headers) {
+				header = strings.TrimSpace(headers[i])
+			}
+			if err := c.file.SetCellStr(sheet, indicesToCell(area.StartRow, at+i), header); err != nil {
+				return err
+			}
+		}
+		if err := c.restoreTableLocked(t, area); err != nil {
+			return err
+		}
+	}
+	if err := c.remapNamesLocked(names, sheet, m); err != nil {
+		return err
+	}
+	return c.remapCommentsLocked(sheet, m)
+}
+
+// deleteColumnsLocked exclui as colunas first a last (base 0) (chamar com c.mu)
+func (c *ExcelizeClient) deleteColumnsLocked(sheet string, first, last int) error {
+	pre, post := clampedColumns(first, last), deletedColumns(first, last)
+	tables, err := c.sheetTablesLocked(sheet)
+	if err != nil {
+		return err
+	}
+	kept := tables[:0]
+	for _, t := range tables {
+		if _, _, ok := post(t.Area.StartCol, t.Area.EndCol); ok {
+			kept = append(kept, t)
+			continue
+		}
+		if err := c.file.DeleteTable(t.Name); err != nil {
+			return fmt.Errorf("failed to delete table %s: %w", t.Name, err)
+		}
+	}
+	names := c.file.GetDefinedName()
+	if err := c.remapCellFormulasLocked(sheet, pre); err != nil {
+		return err
+	}
+
+	// As tabelas são encolhidas antes para que o Excelize não grave cabeçalhos fora delas
+	docs := c.tableDocsLocked()
+	for _, t := range kept {
+		area := t.Area
+		area.StartCol, area.EndCol, _ = pre(area.StartCol, area.EndCol)
+		if area == t.Area {
+			continue
+		}
+		if err := c.rewriteTableLocked(docs[strings.ToLower(t.Name)], area, t.Totals, nil); err != nil {
+			return err
+		}
+	}
+	if err := c.patchColumnsXMLLocked(sheet, pre, post); err != nil {
+		return err
+	}
+
+	col, err := excelize.ColumnNumberToName(first + 1)
+	if err != nil {
+		return err
+	}
+	for i := first; i <= last; i++ {
+		if err := c.file.RemoveCol(sheet, col); err != nil {
+			return fmt.Errorf("failed to delete column %s: %w", col, err)
+		}
+	}
+
+	for _, t := range kept {
+		area := t.Area
+		area.StartCol, area.EndCol, _ = post(area.StartCol, area.EndCol)
+		if err := c.restoreTableLocked(t, area); err != nil {
+			return err
+		}
+	}
+	if err := c.remapNamesLocked(names, sheet, post); err != nil {
+		return err
+	}
+	return c.remapCommentsLocked(sheet, post)
+}
+
+// columnSpanLocked localiza colunas pelo cabeçalho (Tabela[Coluna], coluna de uma
+// tabela da planilha ou da primeira linha preenchida), pela letra ("C") ou por um
+// intervalo ("C:E"). Com count > 0, são count colunas a partir da primeira.
+// Retorna as posições base 0 (chamar com c.mu).
+func (c *ExcelizeClient) columnSpanLocked(sheet, column string, count int) (int, int, error) {
+	first, last, err := c.findColumnsLocked(sheet, column)
+	if err != nil {
+		return 0, 0, err
+	}
+	if count > 0 {
+		last = first + count - 1
+	}
+	if last >= excelize.MaxColumns {
+		return 0, 0, fmt.Errorf("invalid column count: %d", count)
+	}
+	return first, last, nil
+}
+
+// findColumnsLocked resolve o texto de uma coluna para posições base 0 (chamar com c.mu)
+func (c *ExcelizeClient) findColumnsLocked(sheet, column string) (int, int, error) {
+	text := strings.TrimSpace(column)
+	if text == "" {
+		return 0, 0, fmt.Errorf("column is required")
+	}
+	resolver := c.newRefResolverLocked()
+
+	if open := strings.Index(text, "["); open > 0 && strings.HasSuffix(text, "]") {
+		t, ok := resolver.table(text[:open])
+		if !ok {
+			return 0, 0, fmt.Errorf("table not found: %s", text[:open])
+		}
+		if !strings.EqualFold(t.Area.Sheet, sheet) {
+			return 0, 0, fmt.Errorf("table %s is on sheet %s", t.Name, t.Area.Sheet)
+		}
+		header := strings.TrimSpace(text[open+1 : len(text)-1])
+		for i, h := range t.Headers {
+			if strings.EqualFold(strings.TrimSpace(h), header) {
+				return t.Area.StartCol + i, t.Area.StartCol + i, nil
+			}
+		}
+		return 0, 0, fmt.Errorf("column %s not found in table %s", header, t.Name)
+	}
+
+	for _, t := range resolver.tables {
+		if !strings.EqualFold(t.Area.Sheet, sheet) {
+			continue
+		}
+		for i, h := range t.Headers {
+			if strings.EqualFold(strings.TrimSpace(h), text) {
+				return t.Area.StartCol + i, t.Area.StartCol + i, nil
+			}
+		}
+	}
+	_, headers, err := c.headerRowLocked(sheet)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, h := range headers {
+		if strings.EqualFold(strings.TrimSpace(h), text) {
+			return i, i, nil
+		}
+	}
+
+	// Letra, intervalo de colunas ou intervalo A1
+	ref := strings.ReplaceAll(text, "$", "")
+	if !strings.Contains(ref, ":") {
+		ref += ":" + ref
+	}
+	if area, ok := parseCellRef(sheet, ref); ok {
+		return area.StartCol, area.EndCol, nil
+	}
+	return 0, 0, fmt.Errorf("column not found: %s", column)
+}
+
+// insertPositionLocked posição (base 0) das colunas inseridas antes ou depois de
+// uma coluna; sem nenhuma das duas, depois da última coluna usada (chamar com c.mu)
+func (c *ExcelizeClient) insertPositionLocked(sheet, before, after string) (int, error) {
+	switch {
+	case before != "" && after != "":
+		return 0, fmt.Errorf("use before or after, not both")
+	case before != "":
+		first, _, err := c.findColumnsLocked(sheet, before)
+		return first, err
+	case after != "":
+		_, last, err := c.findColumnsLocked(sheet, after)
+		return last + 1, err
+	}
+	rows, err := c.file.GetRows(sheet)
+	if err != nil {
+		return 0, err
+	}
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	return width, nil
+}
+
+// headerRowLocked primeira linha preenchida da planilha, usada como cabeçalho
+// (-1 se a planilha estiver vazia) (chamar com c.mu)
+func (c *ExcelizeClient) headerRowLocked(sheet string) (int, []string, error) {
+	rows, err := c.file.GetRows(sheet)
+	if err != nil {
+		return -1, nil, err
+	}
+	for i, row := range rows {
+		for _, value := range row {
+			if strings.TrimSpace(value) != "" {
+				return i, row, nil
+			}
+		}
+	}
+	return -1, nil, nil
+}
+
+// sheetTableAt tabela da planilha que contém a coluna
+func sheetTableAt(resolver *refResolver, sheet string, col int) (tableArea, bool) {
+	for _, t := range resolver.tables {
+		if strings.EqualFold(t.Area.Sheet, sheet) && col >= t.Area.StartCol && col <= t.Area.EndCol {
+			return t, true
+		}
+	}
+	return tableArea{}, false
+}
+
+// columnTable tabela da planilha com o XML de antes de uma operação em colunas.
+// O Excelize refaz as colunas da tabela e zera a linha de totais ao inserir ou
+// excluir colunas; o XML guardado é regravado com o novo intervalo.
+type columnTable struct {
+	tableArea
+	part    string
+	content []byte
+}
+
+// sheetTablesLocked guarda as tabelas da planilha (chamar com c.mu)
+func (c *ExcelizeClient) sheetTablesLocked(sheet string) ([]columnTable, error) {
+	docs := c.tableDocsLocked()
+	var tables []columnTable
+	for _, t := range c.newRefResolverLocked().tables {
+		if !strings.EqualFold(t.Area.Sheet, sheet) {
+			continue
+		}
+		doc, ok := docs[strings.ToLower(t.Name)]
+		if !ok {
+			return nil, fmt.Errorf("table part not found: %s", t.Name)
+		}
+		content, _ := c.file.Pkg.Load(doc.part)
+		data, _ := content.([]byte)
+		tables = append(tables, columnTable{tableArea: t, part: doc.part, content: data})
+	}
+	return tables, nil
+}
+
+// restoreTableLocked regrava o XML guardado da tabela com o novo intervalo (chamar com c.mu)
+func (c *ExcelizeClient) restoreTableLocked(t columnTable, area cellRef) error {
+	c.file.Pkg.Store(t.part, t.content)
+	doc, ok := c.tableDocsLocked()[strings.ToLower(t.Name)]
+	if !ok {
+		return fmt.Errorf("table part not found: %s", t.Name)
+	}
+	return c.rewriteTableLocked(doc, area, t.Totals, nil)
+}
+
+// remapCellFormulasLocked aplica m às referências à planilha nas fórmulas de todas
+// as planilhas (chamar com c.mu)
+func (c *ExcelizeClient) remapCellFormulasLocked(sheet string, m columnMap) error {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	graph, err := c.buildFormulaGraphLocked(pkg)
+	if err != nil {
+		return err
+	}
+	updates := make(map[*formulaCell]string)
+	sheets := make(map[string]bool)
+	for _, n := range graph.Cells {
+		if formula := remapFormulaColumns(n.Formula, n.Sheet, sheet, m); formula != n.Formula {
+			updates[n] = formula
+			sheets[n.Sheet] = true
+		}
+	}
+	for s := range sheets {
+		if err := c.unshareFormulasLocked(s); err != nil {
+			return err
+		}
+	}
+	for n, formula := range updates {
+		if err := c.file.SetCellFormula(n.Sheet, n.Cell, formula); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remapNamesLocked regrava os nomes definidos a partir dos valores lidos antes da
+// operação (o Excelize só ajusta as partes absolutas). Nomes internos do Excel
+// (área de impressão, títulos) que perderam o intervalo são removidos (chamar com c.mu).
+func (c *ExcelizeClient) remapNamesLocked(names []excelize.DefinedName, sheet string, m columnMap) error {
+	current := make(map[string]excelize.DefinedName)
+	for _, dn := range c.file.GetDefinedName() {
+		current[strings.ToLower(dn.Scope+"!"+dn.Name)] = dn
+	}
+	for _, dn := range names {
+		now, ok := current[strings.ToLower(dn.Scope+"!"+dn.Name)]
+		refersTo := remapFormulaColumns(dn.RefersTo, "", sheet, m)
+		if !ok || now.RefersTo == refersTo {
+			continue
+		}
+		if now.Scope == workbookScope {
+			now.Scope = ""
+		}
+		if err := c.file.DeleteDefinedName(&now); err != nil {
+			return err
+		}
+		if strings.HasPrefix(strings.ToLower(now.Name), "_xlnm.") && strings.Contains(refersTo, "#REF!") {
+			continue
+		}
+		now.RefersTo = refersTo
+		if err := c.file.SetDefinedName(&now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remapCommentsLocked move os comentários da planilha conforme m; o Excelize não
+// os desloca ao inserir ou excluir colunas (chamar com c.mu)
+func (c *ExcelizeClient) remapCommentsLocked(sheet string, m columnMap) error {
+	comments, err := c.file.GetComments(sheet)
+	if err != nil {
+		return err
+	}
+	var moved []excelize.Comment
+	for _, comment := range comments {
+		row, col := cellToIndices(comment.Cell)
+		newCol, _, ok := m(col, col)
+		if ok && newCol == col {
+			continue
+		}
+		if err := c.file.DeleteComment(sheet, comment.Cell); err != nil {
+			return err
+		}
+		if ok {
+			comment.Cell = indicesToCell(row, newCol)
+			moved = append(moved, comment)
+		}
+	}
+	for _, comment := range moved {
+		if err := c.file.AddComment(sheet, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyColumnLayoutLocked copia largura, estilo, visibilidade e nível de estrutura
+// de uma coluna para outra (chamar com c.mu)
+func (c *ExcelizeClient) copyColumnLayoutLocked(sheet string, from, to int) error {
+	src, _ := excelize.ColumnNumberToName(from + 1)
+	dest, _ := excelize.ColumnNumberToName(to + 1)
+	width, err := c.file.GetColWidth(sheet, src)
+	if err != nil {
+		return err
+	}
+	if err := c.file.SetColWidth(sheet, dest, dest, width); err != nil {
+		return err
+	}
+	if style, err := c.file.GetColStyle(sheet, src); err == nil && style != 0 {
+		if err := c.file.SetColStyle(sheet, dest, style); err != nil {
+			return err
+		}
+	}
+	visible, err := c.file.GetColVisible(sheet, src)
+	if err != nil {
+		return err
+	}
+	if err := c.file.SetColVisible(sheet, dest, visible); err != nil {
+		return err
+	}
+	level, err := c.file.GetColOutlineLevel(sheet, src)
+	if err != nil || level == 0 {
+		return err
+	}
+	return c.file.SetColOutlineLevel(sheet, dest, level)
+}
+
+// clearColumnsOutlineLocked remove o nível de estrutura das colunas first a last
+// (base 0). O Excelize só aceita níveis de 1 a 7, então as definições <col> são
+// divididas e alteradas no XML (chamar com c.mu).
+func (c *ExcelizeClient) clearColumnsOutlineLocked(sheet string, first, last int) error {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+	content, ok := c.file.Pkg.Load(part)
+	if !ok {
+		return fmt.Errorf("part not found: %s", part)
+	}
+	patched := colElem.ReplaceAllStringFunc(string(content.([]byte)), func(elem string) string {
+		tag := openingTag(elem)
+		if !strings.Contains(tag, "outlineLevel=") {
+			return elem
+		}
+		minMatch, maxMatch := colMinAttr.FindStringSubmatch(tag), colMaxAttr.FindStringSubmatch(tag)
+		if minMatch == nil || maxMatch == nil {
+			return elem
+		}
+		lo, _ := strconv.Atoi(minMatch[1])
+		hi, _ := strconv.Atoi(maxMatch[1])
+		if hi < first+1 || lo > last+1 {
+			return elem
+		}
+		piece := func(from, to int, clear bool) string {
+			t := setXMLAttr(setXMLAttr(tag, "min", strconv.Itoa(from)), "max", strconv.Itoa(to))
+			if clear {
+				t = setXMLAttr(setXMLAttr(t, "outlineLevel", ""), "collapsed", "")
+			}
+			return t + elem[len(tag):]
+		}
+		var out strings.Builder
+		if lo < first+1 {
+			out.WriteString(piece(lo, first, false))
+		}
+		out.WriteString(piece(max(lo, first+1), min(hi, last+1), true))
+		if hi > last+1 {
+			out.WriteString(piece(last+2, hi, false))
+		}
+		return out.String()
+	})
+	c.file.Pkg.Store(part, []byte(patched))
+	// Descarta a versão carregada para que a próxima leitura use o XML alterado
+	c.file.Sheet.Delete(part)
+	return nil
+}
+
+// patchColumnsXMLLocked ajusta no XML as estruturas ligadas às colunas da planilha.
+// pre é aplicado ao que o Excelize ajusta em seguida (intervalos da formatação
+// condicional e das validações, fórmulas das validações e o filtro automático);
+// post, ao que ele não ajusta (fórmulas da formatação condicional, regras x14 e
+// colunas do filtro). Qualquer um pode ser nil (chamar com c.mu).
+func (c *ExcelizeClient) patchColumnsXMLLocked(sheet string, pre, post columnMap) error {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	for _, name := range c.file.GetSheetList() {
+		part, err := pkg.sheetPart(name)
+		if err != nil {
+			return err
+		}
+		content, ok := c.file.Pkg.Load(part)
+		if !ok {
+			continue
+		}
+		data := string(content.([]byte))
+		patched := data
+		if strings.EqualFold(name, sheet) {
+			patched = patchSheetColumns(patched, sheet, pre, post)
+		}
+		if pre != nil {
+			// Validações de qualquer planilha podem ler a planilha alterada
+			patched = dataValidationElem.ReplaceAllStringFunc(patched, func(elem string) string {
+				return remapFormulaXML(elem, name, sheet, pre)
+			})
+		}
+		if patched == data {
+			continue
+		}
+		c.file.Pkg.Store(part, []byte(patched))
+		// Descarta a versão carregada para que a próxima leitura use o XML alterado
+		c.file.Sheet.Delete(part)
+	}
+	return nil
+}
+
+// patchSheetColumns aplica pre e post ao XML da planilha alterada
+func patchSheetColumns(data, sheet string, pre, post columnMap) string {
+	data = conditionalBlock.ReplaceAllStringFunc(data, func(block string) string {
+		if pre != nil {
+			tag := openingTag(block)
+			if m := sqrefAttr.FindStringSubmatch(tag); m != nil {
+				sqref := remapSqref(m[1], pre)
+				if sqref == "" {
+					return ""
+				}
+				block = setXMLAttr(tag, "sqref", sqref) + block[len(tag):]
+			}
+		}
+		if post != nil {
+			block = remapFormulaXML(block, sheet, sheet, post)
+		}
+		return block
+	})
+
+	if post != nil && strings.Contains(data, "<x14:conditionalFormatting") {
+		data = conditionalExtBlock.ReplaceAllStringFunc(data, func(block string) string {
+			if m := conditionalExtSqref.FindStringSubmatch(block); m != nil {
+				sqref := remapSqref(m[1], post)
+				if sqref == "" {
+					return ""
+				}
+				block = strings.Replace(block, m[0], "<xm:sqref>"+sqref+"</xm:sqref>", 1)
+			}
+			return remapFormulaXML(block, sheet, sheet, post)
+		})
+		data = emptyConditionalExt.ReplaceAllString(data, "")
+		data = strings.ReplaceAll(data, "<extLst></extLst>", "")
+	}
+
+	if pre != nil {
+		count := 0
+		data = dataValidationElem.ReplaceAllStringFunc(data, func(elem string) string {
+			tag := openingTag(elem)
+			if m := sqrefAttr.FindStringSubmatch(tag); m != nil {
+				sqref := remapSqref(m[1], pre)
+				if sqref == "" {
+					return ""
+				}
+				elem = setXMLAttr(tag, "sqref", sqref) + elem[len(tag):]
+			}
+			count++
+			return elem
+		})
+		data = dataValidationsTag.ReplaceAllStringFunc(data, func(tag string) string {
+			return setXMLAttr(tag, "count", strconv.Itoa(count))
+		})
+		data = emptyDataValidations.ReplaceAllString(data, "")
+	}
+
+	return sheetAutoFilterElem.ReplaceAllStringFunc(data, func(elem string) string {
+		return patchAutoFilter(elem, pre, post)
+	})
+}
+
+// patchAutoFilter ajusta o intervalo do filtro automático com pre e as colunas com
+// critérios (colId, relativo ao início do filtro) com post
+func patchAutoFilter(elem string, pre, post columnMap) string {
+	tag := openingTag(elem)
+	rest := elem[len(tag):]
+	m := refAttr.FindStringSubmatch(tag)
+	if m == nil {
+		return elem
+	}
+	area, ok := parseCellRef("", m[1])
+	if !ok {
+		return elem
+	}
+	if pre != nil {
+		first, last, ok := pre(area.StartCol, area.EndCol)
+		if !ok {
+			return ""
+		}
+		tag = setXMLAttr(tag, "ref", indicesToCell(area.StartRow, first)+":"+indicesToCell(area.EndRow, last))
+	}
+	if post != nil {
+		start, end, ok := post(area.StartCol, area.EndCol)
+		rest = filterColumnElem.ReplaceAllStringFunc(rest, func(filter string) string {
+			filterTag := openingTag(filter)
+			id := colIDAttr.FindStringSubmatch(filterTag)
+			if id == nil {
+				return filter
+			}
+			n, _ := strconv.Atoi(id[1])
+			col, _, kept := post(area.StartCol+n, area.StartCol+n)
+			if !ok || !kept || col < start || col > end {
+				return ""
+			}
+			return setXMLAttr(filterTag, "colId", strconv.Itoa(col-start)) + filter[len(filterTag):]
+		})
+	}
+	return tag + rest
+}
+
+// remapFormulaXML aplica m às fórmulas (escapadas) de regras em um trecho do XML
+func remapFormulaXML(data, home, sheet string, m columnMap) string {
+	return ruleFormulaElem.ReplaceAllStringFunc(data, func(elem string) string {
+		g := ruleFormulaElem.FindStringSubmatch(elem)
+		formula := xmlAttrUnescaper.Replace(g[2])
+		remapped := remapFormulaColumns(formula, home, sheet, m)
+		if remapped == formula {
+			return elem
+		}
+		return g[1] + xmlAttrEscaper.Replace(remapped) + g[3]
+	})
+}
+
+// remapFormulaColumns aplica m às referências à planilha sheet de uma fórmula
+// escrita em home ("" para nomes definidos, que sempre indicam a planilha)
+func remapFormulaColumns(formula, home, sheet string, m columnMap) string {
+	if formula == "" {
+		return formula
+	}
+	return rewriteFormulaRefs(formula, func(refSheet, ref string) string {
+		target := refSheet
+		if target == "" {
+			target = home
+		}
+		if strings.EqualFold(target, sheet) {
+			ref = remapColumnsRef(ref, m)
+		}
+		if refSheet == "" || ref == "#REF!" {
+			return ref
+		}
+		return quoteSheetName(refSheet) + "!" + ref
+	})
+}
+
+// remapSqref aplica m às áreas de um sqref, descartando as que deixam de existir
+func remapSqref(sqref string, m columnMap) string {
+	var areas []string
+	for _, area := range strings.Fields(sqref) {
+		if ref := remapColumnsRef(area, m); ref != "#REF!" {
+			areas = append(areas, ref)
+		}
+	}
+	return strings.Join(areas, " ")
+}
+
+// remapColumnsRef aplica m às colunas de uma referência A1 sem planilha.
+// Referências a linhas inteiras não mudam; as que deixam de existir viram #REF!.
+func remapColumnsRef(ref string, m columnMap) string {
+	parts := strings.Split(ref, ":")
+	if len(parts) > 2 {
+		return ref
+	}
+	cols := make([]int, len(parts))
+	matches := make([][]string, len(parts))
+	for i, part := range parts {
+		matches[i] = refEndpoint.FindStringSubmatch(part)
+		if matches[i] == nil || matches[i][2] == "" {
+			return ref
+		}
+		n, err := excelize.ColumnNameToNumber(matches[i][2])
+		if err != nil {
+			return ref
+		}
+		cols[i] = n - 1
+	}
+	// Extremos invertidos ("D2:B5") são mapeados na ordem em que aparecem
+	first, last := cols[0], cols[len(cols)-1]
+	swapped := first > last
+	if swapped {
+		first, last = last, first
+	}
+	newFirst, newLast, ok := m(first, last)
+	if !ok {
+		return "#REF!"
+	}
+	if newFirst == first && newLast == last {
+		return ref
+	}
+	for i, g := range matches {
+		col := newFirst
+		if len(matches) == 2 && (i == 1) != swapped {
+			col = newLast
+		}
+		name, err := excelize.ColumnNumberToName(col + 1)
+		if err != nil {
+			return "#REF!"
+		}
+		parts[i] = g[1] + name + g[3] + g[4]
+	}
+	return strings.Join(parts, ":")
+}
+
+// columnsText intervalo de colunas ("C:D")
+func columnsText(first, last int) string {
+	start, _ := excelize.ColumnNumberToName(first + 1)
+	end, _ := excelize.ColumnNumberToName(last + 1)
+	return start + ":" + end
+}
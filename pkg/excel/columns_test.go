@@ -0,0 +1,110 @@
+package excel
+
+import "testing"
+
+func TestColumnMaps(t *testing.T) {
+	type span struct {
+		first, last int
+		ok          bool
+	}
+	tests := []struct {
+		name        string
+		m           columnMap
+		first, last int
+		want        span
+	}{
+		// Exclusão de B:C (colunas 1 e 2)
+		{"exclusão: antes", deletedColumns(1, 2), 0, 0, span{0, 0, true}},
+		{"exclusão: depois", deletedColumns(1, 2), 3, 5, span{1, 3, true}},
+		{"exclusão: contido", deletedColumns(1, 2), 1, 2, span{0, 0, false}},
+		{"exclusão: abrange", deletedColumns(1, 2), 0, 4, span{0, 2, true}},
+		{"exclusão: começa dentro", deletedColumns(1, 2), 2, 4, span{1, 2, true}},
+		{"exclusão: termina dentro", deletedColumns(1, 2), 0, 1, span{0, 0, true}},
+		{"exclusão da primeira coluna", deletedColumns(0, 0), 0, 3, span{0, 2, true}},
+
+		// Extremidades afastadas das colunas B:C antes da exclusão do Excelize
+		{"ajuste: fora", clampedColumns(1, 2), 3, 4, span{3, 4, true}},
+		{"ajuste: contido", clampedColumns(1, 2), 2, 2, span{0, 0, false}},
+		{"ajuste: começa dentro", clampedColumns(1, 2), 1, 4, span{3, 4, true}},
+		{"ajuste: termina dentro", clampedColumns(1, 2), 0, 2, span{0, 0, true}},
+		{"ajuste: abrange", clampedColumns(1, 2), 0, 4, span{0, 4, true}},
+
+		// Inserção de duas colunas antes de C
+		{"inserção: antes", insertedColumns(2, 2), 0, 1, span{0, 1, true}},
+		{"inserção: na posição", insertedColumns(2, 2), 2, 2, span{4, 4, true}},
+		{"inserção: abrange", insertedColumns(2, 2), 1, 3, span{1, 5, true}},
+
+		// B:C movidas duas colunas à direita
+		{"movidas: contido", movedColumns(1, 2, 2), 1, 2, span{3, 4, true}},
+		{"movidas: parcial não muda", movedColumns(1, 2, 2), 0, 2, span{0, 2, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, ok := tt.m(tt.first, tt.last)
+			got := span{first, last, ok}
+			if !ok {
+				got = span{0, 0, false}
+			}
+			if got != tt.want {
+				t.Errorf("(%d, %d) = %+v, esperado %+v", tt.first, tt.last, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemapColumnsRef(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+		m    columnMap
+		want string
+	}{
+		{"antes da exclusão", "A1", deletedColumns(1, 2), "A1"},
+		{"depois da exclusão", "D5", deletedColumns(1, 2), "B5"},
+		{"absoluta acompanha a coluna", "$D$5", deletedColumns(1, 2), "$B$5"},
+		{"coluna absoluta", "$E3:F$9", deletedColumns(1, 2), "$C3:D$9"},
+		{"célula excluída", "B2", deletedColumns(1, 2), "#REF!"},
+		{"intervalo excluído", "B2:C9", deletedColumns(1, 2), "#REF!"},
+		{"intervalo encolhe", "A1:D1", deletedColumns(1, 2), "A1:B1"},
+		{"começa na coluna excluída", "B1:E1", deletedColumns(1, 2), "B1:C1"},
+		{"termina na coluna excluída", "A1:B1", deletedColumns(1, 2), "A1:A1"},
+		{"coluna inteira excluída", "C:C", deletedColumns(1, 2), "#REF!"},
+		{"colunas inteiras deslocadas", "D:F", deletedColumns(1, 2), "B:D"},
+		{"linha inteira não muda", "3:5", deletedColumns(1, 2), "3:5"},
+		{"extremos invertidos", "D2:A2", deletedColumns(1, 2), "B2:A2"},
+		{"inserção desloca", "$C$1", insertedColumns(2, 2), "$E$1"},
+		{"inserção amplia", "A1:C1", insertedColumns(2, 2), "A1:E1"},
+		{"inserção além da última coluna", "XFD1", insertedColumns(0, 1), "#REF!"},
+		{"movidas", "B1:C1", movedColumns(1, 2, 2), "D1:E1"},
+		{"texto que não é referência", "Tabela1[Valor]", deletedColumns(0, 0), "Tabela1[Valor]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remapColumnsRef(tt.ref, tt.m); got != tt.want {
+				t.Errorf("remapColumnsRef(%q) = %q, esperado %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemapFormulaColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		home    string
+		want    string
+	}{
+		{"referências locais", "=SUM(A1:D1)+D2", "Dados", "=SUM(A1:B1)+B2"},
+		{"planilha explícita", "=Dados!D1+'Outra Aba'!D1", "Resumo", "=Dados!B1+'Outra Aba'!D1"},
+		{"outra planilha não muda", "=D1*2", "Resumo", "=D1*2"},
+		{"referência excluída", "=SUM(B1:C1)+1", "Dados", "=SUM(#REF!)+1"},
+		{"texto preservado", `=IF(D1="D1",1,0)`, "Dados", `=IF(B1="D1",1,0)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remapFormulaColumns(tt.formula, tt.home, "Dados", deletedColumns(1, 2)); got != tt.want {
+				t.Errorf("remapFormulaColumns(%q) = %q, esperado %q", tt.formula, got, tt.want)
+			}
+		})
+	}
+}
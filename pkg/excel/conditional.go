@@ -0,0 +1,603 @@
+package excel
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// conditionalTypes tipos de regra aceitos e o tipo correspondente no Excelize
+var conditionalTypes = map[string]string{
+	"cellValue":    "cell",
+	"text":         "text",
+	"formula":      "formula",
+	"top":          "top",
+	"bottom":       "bottom",
+	"aboveAverage": "average",
+	"belowAverage": "average",
+	"duplicate":    "duplicate",
+	"unique":       "unique",
+	"blanks":       "blanks",
+	"noBlanks":     "no_blanks",
+	"errors":       "errors",
+	"noErrors":     "no_errors",
+	"colorScale":   "2_color_scale",
+	"dataBar":      "data_bar",
+	"iconSet":      "icon_set",
+}
+
+// cellValueCriteria operadores das regras cellValue e o nome usado pelo Excelize
+var cellValueCriteria = map[string]string{
+	"greaterThan":        "greater than",
+	"greaterThanOrEqual": "greater than or equal to",
+	"lessThan":           "less than",
+	"lessThanOrEqual":    "less than or equal to",
+	"equal":              "equal to",
+	"notEqual":           "not equal to",
+	"between":            "between",
+	"notBetween":         "not between",
+}
+
+// textCriteria operadores das regras text e o nome usado pelo Excelize
+var textCriteria = map[string]string{
+	"containsText": "containing",
+	"notContains":  "not containing",
+	"beginsWith":   "begins with",
+	"endsWith":     "ends with",
+}
+
+var (
+	iconStyles = map[string]bool{
+		"3Arrows": true, "3ArrowsGray": true, "3Flags": true, "3Signs": true, "3Symbols": true, "3Symbols2": true,
+		"3TrafficLights1": true, "3TrafficLights2": true, "4Arrows": true, "4ArrowsGray": true, "4Rating": true,
+		"4RedToBlack": true, "4TrafficLights": true, "5Arrows": true, "5ArrowsGray": true, "5Quarters": true, "5Rating": true,
+	}
+	scaleValueTypes = map[string]bool{"min": true, "max": true, "num": true, "percent": true, "percentile": true, "formula": true}
+)
+
+// conditionalExtBlock regra de formatação condicional gravada na extensão x14
+// (barras de dados e ícones criados pelo Excel 2010+)
+var conditionalExtBlock = regexp.MustCompile(`(?s)<x14:conditionalFormatting\b[^>]*>.*?</x14:conditionalFormatting>`)
+
+// conditionalExtSqref intervalo de uma regra da extensão x14
+var conditionalExtSqref = regexp.MustCompile(`<xm:sqref>([^<]*)</xm:sqref>`)
+
+// emptyConditionalExt extensão x14 que ficou sem regras (o esquema exige ao menos uma)
+var emptyConditionalExt = regexp.MustCompile(`<ext\b[^>]*>\s*<x14:conditionalFormattings>\s*</x14:conditionalFormattings>\s*</ext>`)
+
+// AddConditionalFormat adiciona uma regra de formatação condicional a um intervalo.
+// Regras no mesmo intervalo são mantidas em um único bloco, na ordem em que foram criadas.
+func (c *ExcelizeClient) AddConditionalFormat(sheet, rng string, rule ConditionalFormatRule) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sheet, sqref, areas, err := c.rangeAreasLocked(sheet, rng)
+	if err != nil {
+		return err
+	}
+	opts, err := c.conditionalOptionsLocked(rule)
+	if err != nil {
+		return err
+	}
+
+	formats, err := c.file.GetConditionalFormats(sheet)
+	if err != nil {
+		return err
+	}
+	existing := formats[sqref]
+	if len(existing) > 0 {
+		if err := c.unsetConditionalFormatLocked(sheet, sqref, areas); err != nil {
+			return err
+		}
+	}
+
+	return c.file.SetConditionalFormat(sheet, sqref, append(existing, opts))
+}
+
+// AddSimpleConditionalFormat destaca com uma cor de fundo as células que atendem
+// ao critério (greaterThan, lessThan, equal, between, containsText, duplicate, unique)
+func (c *ExcelizeClient) AddSimpleConditionalFormat(sheet, rng, criteria, value, bgColor string) error {
+	rule := ConditionalFormatRule{Type: "cellValue", Criteria: criteria, Value: value}
+	switch criteria {
+	case "containsText":
+		rule.Type = "text"
+	case "duplicate", "unique":
+		rule.Type, rule.Criteria = criteria, ""
+	case "between", "notBetween":
+		// Limites no formato "10,20" ou "10;20"
+		bounds := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+		if len(bounds) == 2 {
+			rule.MinValue, rule.MaxValue = strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+		}
+	}
+	if bgColor != "" {
+		rule.Format = &Format{BgColor: bgColor}
+	}
+	return c.AddConditionalFormat(sheet, rng, rule)
+}
+
+// GetConditionalFormats lista as regras de formatação condicional da planilha;
+// com rng, apenas as que se sobrepõem ao intervalo
+func (c *ExcelizeClient) GetConditionalFormats(sheet, rng string) ([]ConditionalFormatRule, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var targets []cellRef
+	if rng != "" {
+		var err error
+		if sheet, _, targets, err = c.rangeAreasLocked(sheet, rng); err != nil {
+			return nil, err
+		}
+	}
+
+	formats, err := c.file.GetConditionalFormats(sheet)
+	if err != nil {
+		return nil, err
+	}
+	sqrefs := make([]string, 0, len(formats))
+	for sqref := range formats {
+		if targets == nil || intersectsAny(sqrefAreas(sheet, sqref), targets) {
+			sqrefs = append(sqrefs, sqref)
+		}
+	}
+	sort.Strings(sqrefs)
+
+	rules := []ConditionalFormatRule{}
+	for _, sqref := range sqrefs {
+		for _, opts := range formats[sqref] {
+			rules = append(rules, c.conditionalRuleLocked(sqref, opts))
+		}
+	}
+	return rules, nil
+}
+
+// RemoveConditionalFormats retira o intervalo das regras de formatação condicional
+// que se sobrepõem a ele: regras cobertas por inteiro são removidas e as demais
+// passam a valer só para o restante ("A1:A4" menos A2 vira "A1 A3:A4").
+// Retorna quantas regras foram alteradas.
+func (c *ExcelizeClient) RemoveConditionalFormats(sheet, rng string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sheet, _, targets, err := c.rangeAreasLocked(sheet, rng)
+	if err != nil {
+		return 0, err
+	}
+	formats, err := c.file.GetConditionalFormats(sheet)
+	if err != nil {
+		return 0, err
+	}
+	sqrefs := make([]string, 0, len(formats))
+	for sqref := range formats {
+		sqrefs = append(sqrefs, sqref)
+	}
+	sort.Strings(sqrefs)
+
+	changed := 0
+	replaced := map[string]bool{}
+	for _, sqref := range sqrefs {
+		areas := sqrefAreas(sheet, sqref)
+		if !intersectsAny(areas, targets) {
+			continue
+		}
+		if err := c.unsetConditionalFormatLocked(sheet, sqref, nil); err != nil {
+			return changed, err
+		}
+		replaced[sqref] = true
+		changed += len(formats[sqref])
+
+		rest := subtractAll(areas, targets)
+		if len(rest) == 0 {
+			continue
+		}
+		// Referências relativas das fórmulas partem da primeira célula do intervalo
+		rules := formats[sqref]
+		dRow, dCol := rest[0].StartRow-areas[0].StartRow, rest[0].StartCol-areas[0].StartCol
+		for i := range rules {
+			shiftConditionalRule(&rules[i], dRow, dCol)
+		}
+		if err := c.file.SetConditionalFormat(sheet, sqrefText(rest), rules); err != nil {
+			return changed, err
+		}
+	}
+	if err := c.trimConditionalExtLocked(sheet, targets, replaced); err != nil {
+		return changed, err
+	}
+	return changed, nil
+}
+
+// shiftConditionalRule desloca as fórmulas da regra quando o intervalo passa a
+// começar em outra célula
+func shiftConditionalRule(opts *excelize.ConditionalFormatOptions, dRow, dCol int) {
+	switch opts.Type {
+	case "formula":
+		opts.Criteria = shiftFormula(opts.Criteria, dRow, dCol)
+	case "cell":
+		opts.Value = shiftFormula(opts.Value, dRow, dCol)
+		opts.MinValue = shiftFormula(opts.MinValue, dRow, dCol)
+		opts.MaxValue = shiftFormula(opts.MaxValue, dRow, dCol)
+	default:
+		if opts.MinType == "formula" {
+			opts.MinValue = shiftFormula(opts.MinValue, dRow, dCol)
+		}
+		if opts.MidType == "formula" {
+			opts.MidValue = shiftFormula(opts.MidValue, dRow, dCol)
+		}
+		if opts.MaxType == "formula" {
+			opts.MaxValue = shiftFormula(opts.MaxValue, dRow, dCol)
+		}
+	}
+}
+
+// unsetConditionalFormatLocked remove todos os blocos com o intervalo exato; com
+// areas, também remove as regras x14 desses intervalos (chamar com c.mu)
+func (c *ExcelizeClient) unsetConditionalFormatLocked(sheet, sqref string, areas []cellRef) error {
+	for {
+		formats, err := c.file.GetConditionalFormats(sheet)
+		if err != nil {
+			return err
+		}
+		if _, ok := formats[sqref]; !ok {
+			break
+		}
+		if err := c.file.UnsetConditionalFormat(sheet, sqref); err != nil {
+			return err
+		}
+	}
+	if areas == nil {
+		return nil
+	}
+	return c.removeConditionalExtLocked(sheet, areas)
+}
+
+// removeConditionalExtLocked apaga as regras da extensão x14 que se sobrepõem aos
+// intervalos (chamar com c.mu)
+func (c *ExcelizeClient) removeConditionalExtLocked(sheet string, areas []cellRef) error {
+	return c.patchConditionalExtLocked(sheet, func(block []byte, sqref string) []byte {
+		if intersectsAny(sqrefAreas(sheet, sqref), areas) {
+			return nil
+		}
+		return block
+	})
+}
+
+// trimConditionalExtLocked retira os alvos das regras da extensão x14. Blocos cujo
+// sqref está em replaced foram regravados pelo Excelize e são apagados (chamar com c.mu).
+func (c *ExcelizeClient) trimConditionalExtLocked(sheet string, targets []cellRef, replaced map[string]bool) error {
+	return c.patchConditionalExtLocked(sheet, func(block []byte, sqref string) []byte {
+		areas := sqrefAreas(sheet, sqref)
+		if !intersectsAny(areas, targets) {
+			return block
+		}
+		rest := subtractAll(areas, targets)
+		if replaced[sqref] || len(rest) == 0 {
+			return nil
+		}
+		dRow, dCol := rest[0].StartRow-areas[0].StartRow, rest[0].StartCol-areas[0].StartCol
+		block = ruleFormulaElem.ReplaceAllFunc(block, func(elem []byte) []byte {
+			g := ruleFormulaElem.FindSubmatch(elem)
+			formula := shiftFormula(xmlAttrUnescaper.Replace(string(g[2])), dRow, dCol)
+			return []byte(string(g[1]) + xmlAttrEscaper.Replace(formula) + string(g[3]))
+		})
+		return conditionalExtSqref.ReplaceAll(block, []byte("<xm:sqref>"+sqrefText(rest)+"</xm:sqref>"))
+	})
+}
+
+// patchConditionalExtLocked aplica patch a cada regra da extensão x14 (nil apaga a
+// regra). O Excelize não expõe essas regras, por isso o XML serializado é alterado
+// diretamente (chamar com c.mu).
+func (c *ExcelizeClient) patchConditionalExtLocked(sheet string, patch func(block []byte, sqref string) []byte) error {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+	content, ok := c.file.Pkg.Load(part)
+	if !ok || !bytes.Contains(content.([]byte), []byte("<x14:conditionalFormatting")) {
+		return nil
+	}
+
+	patched := conditionalExtBlock.ReplaceAllFunc(content.([]byte), func(block []byte) []byte {
+		m := conditionalExtSqref.FindSubmatch(block)
+		if m == nil {
+			return block
+		}
+		return patch(block, string(m[1]))
+	})
+	patched = emptyConditionalExt.ReplaceAll(patched, nil)
+	patched = bytes.ReplaceAll(patched, []byte("<extLst></extLst>"), nil)
+
+	c.file.Pkg.Store(part, patched)
+	// Descarta a versão carregada para que a próxima leitura use o XML alterado
+	c.file.Sheet.Delete(part)
+	return nil
+}
+
+// conditionalOptionsLocked valida a regra e a converte para o Excelize (chamar com c.mu)
+func (c *ExcelizeClient) conditionalOptionsLocked(rule ConditionalFormatRule) (excelize.ConditionalFormatOptions, error) {
+	opts := excelize.ConditionalFormatOptions{Criteria: "=", StopIfTrue: rule.StopIfTrue}
+	var ok bool
+	if opts.Type, ok = conditionalTypes[rule.Type]; !ok {
+		return opts, fmt.Errorf("invalid conditional format type: %s", rule.Type)
+	}
+
+	highlight := true
+	switch rule.Type {
+	case "cellValue":
+		if opts.Criteria, ok = cellValueCriteria[rule.Criteria]; !ok {
+			return opts, fmt.Errorf("invalid criteria for cellValue: %s", rule.Criteria)
+		}
+		if rule.Criteria == "between" || rule.Criteria == "notBetween" {
+			if rule.MinValue == "" || rule.MaxValue == "" {
+				return opts, fmt.Errorf("%s requires minValue and maxValue", rule.Criteria)
+			}
+			opts.MinValue, opts.MaxValue = conditionalOperand(rule.MinValue), conditionalOperand(rule.MaxValue)
+		} else {
+			if rule.Value == "" {
+				return opts, fmt.Errorf("%s requires value", rule.Criteria)
+			}
+			opts.Value = conditionalOperand(rule.Value)
+		}
+
+	case "text":
+		if rule.Criteria == "" {
+			rule.Criteria = "containsText"
+		}
+		if opts.Criteria, ok = textCriteria[rule.Criteria]; !ok {
+			return opts, fmt.Errorf("invalid criteria for text: %s", rule.Criteria)
+		}
+		if rule.Value == "" {
+			return opts, fmt.Errorf("text rule requires value")
+		}
+		opts.Value = rule.Value
+
+	case "formula":
+		if rule.Formula == "" {
+			return opts, fmt.Errorf("formula rule requires formula")
+		}
+		opts.Criteria = strings.TrimPrefix(rule.Formula, "=")
+
+	case "top", "bottom":
+		opts.Value = rule.Value
+		if opts.Value == "" {
+			opts.Value = "10"
+		}
+		opts.Percent = rule.Percent
+
+	case "aboveAverage":
+		opts.AboveAverage = true
+
+	case "colorScale":
+		highlight = false
+		opts.MinType, opts.MinValue, opts.MinColor = rule.MinType, rule.MinValue, rule.MinColor
+		opts.MaxType, opts.MaxValue, opts.MaxColor = rule.MaxType, rule.MaxValue, rule.MaxColor
+		if opts.MinColor == "" {
+			opts.MinColor = "#F8696B"
+		}
+		if opts.MaxColor == "" {
+			opts.MaxColor = "#63BE7B"
+		}
+		if rule.MidColor != "" || rule.MidType != "" || rule.MidValue != "" {
+			opts.Type = "3_color_scale"
+			opts.MidType, opts.MidValue, opts.MidColor = rule.MidType, rule.MidValue, rule.MidColor
+			if opts.MidType == "" {
+				opts.MidType, opts.MidValue = "percentile", "50"
+			}
+			if opts.MidColor == "" {
+				opts.MidColor = "#FFEB84"
+			}
+		}
+
+	case "dataBar":
+		highlight = false
+		opts.MinType, opts.MinValue = rule.MinType, rule.MinValue
+		opts.MaxType, opts.MaxValue = rule.MaxType, rule.MaxValue
+		opts.BarColor, opts.BarOnly = rule.BarColor, rule.BarOnly
+		if opts.BarColor == "" {
+			opts.BarColor = "#638EC6"
+		}
+
+	case "iconSet":
+		highlight = false
+		opts.IconStyle, opts.ReverseIcons, opts.IconsOnly = rule.IconStyle, rule.Reverse, rule.IconsOnly
+		if opts.IconStyle == "" {
+			opts.IconStyle = "3TrafficLights1"
+		}
+		if !iconStyles[opts.IconStyle] {
+			return opts, fmt.Errorf("invalid icon style: %s", opts.IconStyle)
+		}
+	}
+
+	if opts.Type == "2_color_scale" || opts.Type == "3_color_scale" || opts.Type == "data_bar" {
+		if opts.MinType == "" {
+			opts.MinType = "min"
+		}
+		if opts.MaxType == "" {
+			opts.MaxType = "max"
+		}
+		for _, t := range []string{opts.MinType, opts.MidType, opts.MaxType} {
+			if t != "" && !scaleValueTypes[t] {
+				return opts, fmt.Errorf("invalid value type: %s", t)
+			}
+		}
+		for _, color := range []*string{&opts.MinColor, &opts.MidColor, &opts.MaxColor, &opts.BarColor} {
+			if *color != "" && !strings.HasPrefix(*color, "#") {
+				*color = "#" + *color
+			}
+		}
+	}
+
+	if highlight {
+		// Sem formato informado vale o destaque padrão do Excel (vermelho claro)
+		format := Format{BgColor: "#FFC7CE", FontColor: "#9C0006"}
+		if rule.Format != nil {
+			format = *rule.Format
+		}
+		if err := format.validate(); err != nil {
+			return opts, err
+		}
+		style := &excelize.Style{}
+		format.apply(style)
+		styleID, err := c.file.NewConditionalStyle(style)
+		if err != nil {
+			return opts, fmt.Errorf("failed to create conditional style: %w", err)
+		}
+		opts.Format = &styleID
+	}
+	return opts, nil
+}
+
+// conditionalRuleLocked converte uma regra lida do Excelize (chamar com c.mu)
+func (c *ExcelizeClient) conditionalRuleLocked(sqref string, opts excelize.ConditionalFormatOptions) ConditionalFormatRule {
+	rule := ConditionalFormatRule{
+		Type:       opts.Type,
+		Range:      strings.ReplaceAll(sqref, " ", ","),
+		Value:      opts.Value,
+		MinValue:   opts.MinValue,
+		MidValue:   opts.MidValue,
+		MaxValue:   opts.MaxValue,
+		Percent:    opts.Percent,
+		MinType:    opts.MinType,
+		MidType:    opts.MidType,
+		MaxType:    opts.MaxType,
+		MinColor:   hexColor(opts.MinColor),
+		MidColor:   hexColor(opts.MidColor),
+		MaxColor:   hexColor(opts.MaxColor),
+		BarColor:   hexColor(opts.BarColor),
+		BarOnly:    opts.BarOnly,
+		IconStyle:  opts.IconStyle,
+		Reverse:    opts.ReverseIcons,
+		IconsOnly:  opts.IconsOnly,
+		StopIfTrue: opts.StopIfTrue,
+	}
+	// Os extremos min e max não usam valor
+	if rule.MinType == "min" {
+		rule.MinValue = ""
+	}
+	if rule.MaxType == "max" {
+		rule.MaxValue = ""
+	}
+	for name, xtype := range conditionalTypes {
+		if xtype == opts.Type && name != "belowAverage" && name != "aboveAverage" {
+			rule.Type = name
+		}
+	}
+
+	switch opts.Type {
+	case "cell":
+		rule.Criteria = criteriaName(cellValueCriteria, opts.Criteria)
+	case "text":
+		rule.Criteria = criteriaName(textCriteria, opts.Criteria)
+	case "formula":
+		rule.Formula = opts.Criteria
+	case "average":
+		rule.Type = "belowAverage"
+		if opts.AboveAverage {
+			rule.Type = "aboveAverage"
+		}
+	case "2_color_scale", "3_color_scale":
+		rule.Type = "colorScale"
+	case "time_period":
+		rule.Type, rule.Criteria = "timePeriod", opts.Criteria
+	}
+
+	if opts.Format != nil {
+		if style, err := c.file.GetConditionalStyle(*opts.Format); err == nil {
+			format := c.formatFromStyle(style)
+			rule.Format = &format
+		}
+	}
+	return rule
+}
+
+// criteriaName nome do operador a partir do nome usado pelo Excelize
+func criteriaName(criteria map[string]string, excelizeName string) string {
+	for name, value := range criteria {
+		if value == excelizeName {
+			return name
+		}
+	}
+	return excelizeName
+}
+
+// conditionalOperand valores de texto precisam de aspas para não serem lidos
+// como nomes; números, referências e fórmulas seguem como estão
+func conditionalOperand(value string) string {
+	if strings.HasPrefix(value, "=") {
+		return strings.TrimPrefix(value, "=")
+	}
+	if _, ok := parseCellRef("", value); ok || strings.HasPrefix(value, `"`) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+// rangeAreasLocked normaliza o intervalo (endereços, nomes definidos ou tabelas)
+// no formato usado pelo Excelize ("A1:A10 C1:C10") e devolve a planilha e as áreas
+func (c *ExcelizeClient) rangeAreasLocked(sheet, rng string) (string, string, []cellRef, error) {
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+	var areas []cellRef
+	for _, text := range strings.Split(strings.ReplaceAll(rng, " ", ","), ",") {
+		if text == "" {
+			continue
+		}
+		area, ok := parseCellRef(sheet, text)
+		if !ok {
+			return "", "", nil, fmt.Errorf("invalid range: %s", rng)
+		}
+		sheet = area.Sheet
+		areas = append(areas, area)
+	}
+	if len(areas) == 0 {
+		return "", "", nil, fmt.Errorf("invalid range: %s", rng)
+	}
+	return sheet, sqrefText(areas), areas, nil
+}
+
+// sqrefText formata áreas como sqref, sem o nome da planilha ("A1:A10 C1")
+func sqrefText(areas []cellRef) string {
+	parts := make([]string, 0, len(areas))
+	for _, area := range areas {
+		start := indicesToCell(area.StartRow, area.StartCol)
+		if area.StartRow == area.EndRow && area.StartCol == area.EndCol {
+			parts = append(parts, start)
+		} else {
+			parts = append(parts, start+":"+indicesToCell(area.EndRow, area.EndCol))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// sqrefAreas áreas de um sqref ("A1:A10 C1:C10")
+func sqrefAreas(sheet, sqref string) []cellRef {
+	var areas []cellRef
+	for _, text := range strings.Fields(sqref) {
+		if area, ok := parseCellRef(sheet, text); ok {
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+// intersectsAny verifica se alguma área de a se sobrepõe a alguma de b
+func intersectsAny(a, b []cellRef) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.intersects(y) {
+				return true
+			}
+		}
+	}
+	return false
+}
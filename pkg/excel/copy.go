@@ -0,0 +1,798 @@
+package excel
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	// sheetObjectRel relação da planilha com comentários, desenhos ou tabelas
+	sheetObjectRel = regexp.MustCompile(`<Relationship\s[^>]*?Type="[^"]*/(?:comments|vmlDrawing|drawing|table)"[^>]*?(?:/>|></Relationship>)`)
+	// legacyDrawingTag desenho VML que exibe os comentários da planilha
+	legacyDrawingTag = regexp.MustCompile(`<legacyDrawing\s[^>]*?(?:/>|></legacyDrawing>)`)
+)
+
+// rangeClip conteúdo copiado de um intervalo: células, mesclagens e comentários
+type rangeClip struct {
+	area     cellRef
+	cells    [][]cellContent
+	merges   []cellRef
+	comments []excelize.Comment
+}
+
+// CopyRange copia o intervalo src para a célula inicial de dest (que pode estar em
+// outra planilha). No modo padrão são copiados valores, fórmulas (com referências
+// relativas ajustadas ao destino), estilos, mesclagens e comentários. Retorna o
+// intervalo de destino.
+func (c *ExcelizeClient) CopyRange(sheet, src, dest string, opts PasteOptions) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch opts.Mode {
+	case "", "all", "values", "formulas", "formats":
+	default:
+		return "", fmt.Errorf("invalid paste mode: %s", opts.Mode)
+	}
+	area, ok := c.areaLocked(sheet, src)
+	if !ok {
+		return "", fmt.Errorf("invalid range: %s", src)
+	}
+	c.clampToUsedAreaLocked(&area)
+	to, ok := c.areaLocked(sheet, dest)
+	if !ok {
+		return "", fmt.Errorf("invalid range: %s", dest)
+	}
+
+	clip, err := c.clipLocked(area)
+	if err != nil {
+		return "", err
+	}
+	target, err := clip.destArea(to, opts.Transpose)
+	if err != nil {
+		return "", err
+	}
+	err = c.pasteLocked(clip, target, opts, func(formula string, i, j int) string {
+		row, col := clip.target(target, i, j, opts.Transpose)
+		return shiftFormula(formula, row-area.StartRow-i, col-area.StartCol-j)
+	})
+	if err != nil {
+		return "", err
+	}
+	return target.String(), nil
+}
+
+// MoveRange move o intervalo src para a célula inicial de dest, como recortar e
+// colar: as fórmulas movidas continuam apontando para as mesmas células e as
+// referências ao intervalo, em toda a pasta de trabalho, passam a apontar para o
+// destino. Retorna o intervalo de destino.
+func (c *ExcelizeClient) MoveRange(sheet, src, dest string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.areaLocked(sheet, src)
+	if !ok {
+		return "", fmt.Errorf("invalid range: %s", src)
+	}
+	c.clampToUsedAreaLocked(&area)
+	to, ok := c.areaLocked(sheet, dest)
+	if !ok {
+		return "", fmt.Errorf("invalid range: %s", dest)
+	}
+	target, err := c.moveAreaLocked(area, to)
+	if err != nil {
+		return "", err
+	}
+	return target.String(), nil
+}
+
+// moveAreaLocked move a área para a célula inicial de to, atualizando as fórmulas
+// e os nomes que leem as células movidas (chamar com c.mu)
+func (c *ExcelizeClient) moveAreaLocked(area, to cellRef) (cellRef, error) {
+	clip, err := c.clipLocked(area)
+	if err != nil {
+		return cellRef{}, err
+	}
+	target, err := clip.destArea(to, false)
+	if err != nil {
+		return cellRef{}, err
+	}
+	dRow, dCol := target.StartRow-area.StartRow, target.StartCol-area.StartCol
+	if dRow == 0 && dCol == 0 && strings.EqualFold(target.Sheet, area.Sheet) {
+		return target, nil
+	}
+	if _, err := c.destMergesLocked(target, clip.merges); err != nil {
+		return cellRef{}, err
+	}
+	move := func(formula, home, newHome string) string {
+		return movedFormula(formula, home, newHome, area, target.Sheet, dRow, dCol)
+	}
+
+	// Fórmulas fora do intervalo que leem células movidas
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return cellRef{}, err
+	}
+	graph, err := c.buildFormulaGraphLocked(pkg)
+	if err != nil {
+		return cellRef{}, err
+	}
+	updates := make(map[*formulaCell]string)
+	sheets := map[string]bool{area.Sheet: true}
+	for _, n := range graph.Cells {
+		if area.contains(n.Sheet, n.Row, n.Col) || target.contains(n.Sheet, n.Row, n.Col) {
+			continue
+		}
+		if formula := move(n.Formula, n.Sheet, n.Sheet); formula != n.Formula {
+			updates[n] = formula
+			sheets[n.Sheet] = true
+		}
+	}
+	for s := range sheets {
+		if err := c.unshareFormulasLocked(s); err != nil {
+			return cellRef{}, err
+		}
+	}
+
+	// Esvazia a origem; as células que também são destino são regravadas abaixo
+	for _, merge := range clip.merges {
+		if err := c.file.UnmergeCell(area.Sheet, indicesToCell(merge.StartRow, merge.StartCol), indicesToCell(merge.EndRow, merge.EndCol)); err != nil {
+			return cellRef{}, err
+		}
+	}
+	for _, comment := range clip.comments {
+		if err := c.file.DeleteComment(area.Sheet, comment.Cell); err != nil {
+			return cellRef{}, err
+		}
+	}
+	for row := area.StartRow; row <= area.EndRow; row++ {
+		for col := area.StartCol; col <= area.EndCol; col++ {
+			if err := c.writeCellLocked(area.Sheet, indicesToCell(row, col), cellContent{}); err != nil {
+				return cellRef{}, err
+			}
+		}
+	}
+
+	err = c.pasteLocked(clip, target, PasteOptions{}, func(formula string, _, _ int) string {
+		return move(formula, area.Sheet, target.Sheet)
+	})
+	if err != nil {
+		return cellRef{}, err
+	}
+	for n, formula := range updates {
+		if err := c.file.SetCellFormula(n.Sheet, n.Cell, formula); err != nil {
+			return cellRef{}, err
+		}
+	}
+
+	for _, dn := range c.file.GetDefinedName() {
+		refersTo := move(dn.RefersTo, "", "")
+		if refersTo == dn.RefersTo {
+			continue
+		}
+		if dn.Scope == workbookScope {
+			dn.Scope = ""
+		}
+		if err := c.file.DeleteDefinedName(&dn); err != nil {
+			return cellRef{}, err
+		}
+		dn.RefersTo = refersTo
+		if err := c.file.SetDefinedName(&dn); err != nil {
+			return cellRef{}, err
+		}
+	}
+	return target, nil
+}
+
+// CopySheet duplica a planilha src na mesma pasta de trabalho com valores, fórmulas,
+// estilos, mesclagens, validações, formatação condicional, comentários, imagens,
+// tabelas (com novos nomes) e nomes locais. Com name vazio usa "src (2)", como o
+// Excel. Retorna o nome da cópia.
+func (c *ExcelizeClient) CopySheet(src, name string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from, err := c.file.GetSheetIndex(src)
+	if err != nil || from < 0 {
+		return "", fmt.Errorf("sheet %s does not exist", src)
+	}
+	src = c.file.GetSheetName(from)
+	if name, err = c.copyNameLocked(src, name); err != nil {
+		return "", err
+	}
+
+	// O Excelize copia o arquivo de relações da origem: as relações pendentes precisam
+	// estar gravadas antes da cópia
+	if err := c.file.Write(io.Discard); err != nil {
+		return "", fmt.Errorf("failed to serialize workbook: %w", err)
+	}
+	c.flushSheetsLocked()
+	to, err := c.file.NewSheet(name)
+	if err != nil {
+		return "", err
+	}
+	if err := c.file.CopySheet(from, to); err != nil {
+		return "", err
+	}
+	if err := c.detachCopiedObjectsLocked(name); err != nil {
+		return "", err
+	}
+	if err := c.copySheetObjectsLocked(c, src, name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// CopySheetFrom copia a planilha src de outra pasta de trabalho como name. Os estilos
+// são recriados nesta pasta; fórmulas que citam outras planilhas são mantidas como
+// estão. As duas pastas ficam bloqueadas durante a cópia. Retorna o nome da cópia.
+func (c *ExcelizeClient) CopySheetFrom(from *ExcelizeClient, src, name string) (string, error) {
+	if from == c {
+		return c.CopySheet(src, name)
+	}
+	from.mu.Lock()
+	defer from.mu.Unlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index, err := from.file.GetSheetIndex(src)
+	if err != nil || index < 0 {
+		return "", fmt.Errorf("sheet %s does not exist", src)
+	}
+	src = from.file.GetSheetName(index)
+	if index, _ := c.file.GetSheetIndex(src); name == "" && index < 0 {
+		name = src
+	}
+	if name, err = c.copyNameLocked(src, name); err != nil {
+		return "", err
+	}
+	if _, err := c.file.NewSheet(name); err != nil {
+		return "", err
+	}
+	if err := c.copySheetCellsLocked(from, src, name); err != nil {
+		return "", err
+	}
+	if err := c.copySheetObjectsLocked(from, src, name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// clipLocked lê o conteúdo do intervalo (chamar com c.mu)
+func (c *ExcelizeClient) clipLocked(area cellRef) (*rangeClip, error) {
+	clip := &rangeClip{area: area, cells: make([][]cellContent, area.EndRow-area.StartRow+1)}
+	for i := range clip.cells {
+		clip.cells[i] = make([]cellContent, area.EndCol-area.StartCol+1)
+		for j := range clip.cells[i] {
+			cell := indicesToCell(area.StartRow+i, area.StartCol+j)
+			content, err := c.readCellLocked(area.Sheet, cell)
+			if err != nil {
+				return nil, err
+			}
+			// Fórmulas ainda não calculadas não têm resultado para colar como valor
+			if content.Formula != "" && content.Value == "" {
+				if value, err := c.file.CalcCellValue(area.Sheet, cell, excelize.Options{RawCellValue: true}); err == nil {
+					content.Value, content.Type = value, excelize.CellTypeUnset
+				}
+			}
+			clip.cells[i][j] = content
+		}
+	}
+
+	merges, err := c.file.GetMergeCells(area.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	for _, merge := range merges {
+		ref, ok := parseCellRef(area.Sheet, merge.GetStartAxis()+":"+merge.GetEndAxis())
+		if !ok || !ref.intersects(area) {
+			continue
+		}
+		if !area.covers(ref) {
+			return nil, fmt.Errorf("cannot copy part of merged cell %s", ref)
+		}
+		clip.merges = append(clip.merges, ref)
+	}
+
+	comments, err := c.file.GetComments(area.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		if row, col := cellToIndices(comment.Cell); area.contains(area.Sheet, row, col) {
+			clip.comments = append(clip.comments, comment)
+		}
+	}
+	return clip, nil
+}
+
+// destArea intervalo ocupado pela colagem a partir da primeira célula de to
+func (clip *rangeClip) destArea(to cellRef, transpose bool) (cellRef, error) {
+	rows, cols := clip.area.EndRow-clip.area.StartRow, clip.area.EndCol-clip.area.StartCol
+	if transpose {
+		rows, cols = cols, rows
+	}
+	target := cellRef{Sheet: to.Sheet, StartRow: to.StartRow, StartCol: to.StartCol, EndRow: to.StartRow + rows, EndCol: to.StartCol + cols}
+	if target.EndRow >= excelize.TotalRows || target.EndCol >= excelize.MaxColumns {
+		return cellRef{}, fmt.Errorf("destination %s exceeds sheet limits", indicesToCell(to.StartRow, to.StartCol))
+	}
+	return target, nil
+}
+
+// target posição da célula (i, j) do recorte colada em dest
+func (clip *rangeClip) target(dest cellRef, i, j int, transpose bool) (int, int) {
+	if transpose {
+		return dest.StartRow + j, dest.StartCol + i
+	}
+	return dest.StartRow + i, dest.StartCol + j
+}
+
+// pasteLocked grava o recorte em target conforme o modo de colagem; adjust ajusta a
+// fórmula da célula (i, j) do recorte ao destino (chamar com c.mu)
+func (c *ExcelizeClient) pasteLocked(clip *rangeClip, target cellRef, opts PasteOptions, adjust func(formula string, i, j int) string) error {
+	mode := opts.Mode
+	if mode == "" {
+		mode = "all"
+	}
+	withFormats := mode == "all" || mode == "formats"
+
+	var merged []cellRef
+	if withFormats {
+		var err error
+		if merged, err = c.destMergesLocked(target, nil); err != nil {
+			return err
+		}
+	}
+
+	// O destino é lido antes da gravação: origem e destino podem se sobrepor
+	existing := make([][]cellContent, len(clip.cells))
+	if mode != "all" {
+		for i, row := range clip.cells {
+			existing[i] = make([]cellContent, len(row))
+			for j := range row {
+				r, col := clip.target(target, i, j, opts.Transpose)
+				content, err := c.readCellLocked(target.Sheet, indicesToCell(r, col))
+				if err != nil {
+					return err
+				}
+				existing[i][j] = content
+			}
+		}
+	}
+	if err := c.unshareFormulasLocked(target.Sheet); err != nil {
+		return err
+	}
+	for _, merge := range merged {
+		if err := c.file.UnmergeCell(target.Sheet, indicesToCell(merge.StartRow, merge.StartCol), indicesToCell(merge.EndRow, merge.EndCol)); err != nil {
+			return err
+		}
+	}
+
+	for i, row := range clip.cells {
+		for j, content := range row {
+			switch mode {
+			case "values":
+				// O resultado da fórmula vira valor fixo
+				content.Formula = ""
+				if content.Type == excelize.CellTypeFormula {
+					content.Type = excelize.CellTypeUnset
+				}
+				content.Style = existing[i][j].Style
+			case "formulas":
+				content.Formula = adjust(content.Formula, i, j)
+				content.Style = existing[i][j].Style
+			case "formats":
+				style := content.Style
+				content = existing[i][j]
+				content.Style = style
+			default:
+				content.Formula = adjust(content.Formula, i, j)
+			}
+			r, col := clip.target(target, i, j, opts.Transpose)
+			if err := c.writeCellLocked(target.Sheet, indicesToCell(r, col), content); err != nil {
+				return err
+			}
+		}
+	}
+
+	if withFormats {
+		for _, merge := range clip.merges {
+			startRow, startCol := clip.target(target, merge.StartRow-clip.area.StartRow, merge.StartCol-clip.area.StartCol, opts.Transpose)
+			endRow, endCol := clip.target(target, merge.EndRow-clip.area.StartRow, merge.EndCol-clip.area.StartCol, opts.Transpose)
+			if err := c.file.MergeCell(target.Sheet, indicesToCell(startRow, startCol), indicesToCell(endRow, endCol)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if mode == "all" {
+		comments, err := c.file.GetComments(target.Sheet)
+		if err != nil {
+			return err
+		}
+		for _, comment := range comments {
+			if row, col := cellToIndices(comment.Cell); target.contains(target.Sheet, row, col) {
+				if err := c.file.DeleteComment(target.Sheet, comment.Cell); err != nil {
+					return err
+				}
+			}
+		}
+		for _, comment := range clip.comments {
+			row, col := cellToIndices(comment.Cell)
+			row, col = clip.target(target, row-clip.area.StartRow, col-clip.area.StartCol, opts.Transpose)
+			comment.Cell = indicesToCell(row, col)
+			if err := c.file.AddComment(target.Sheet, comment); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// destMergesLocked mesclagens que a colagem em target substitui; uma mesclagem
+// apenas parcialmente dentro do destino impede a colagem. Mesclagens em ignore
+// (as da própria origem de uma movimentação) não são consideradas (chamar com c.mu).
+func (c *ExcelizeClient) destMergesLocked(target cellRef, ignore []cellRef) ([]cellRef, error) {
+	merges, err := c.file.GetMergeCells(target.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	var result []cellRef
+	for _, merge := range merges {
+		ref, ok := parseCellRef(target.Sheet, merge.GetStartAxis()+":"+merge.GetEndAxis())
+		if !ok || !ref.intersects(target) {
+			continue
+		}
+		skip := false
+		for _, other := range ignore {
+			skip = skip || other == ref
+		}
+		if skip {
+			continue
+		}
+		if !target.covers(ref) {
+			return nil, fmt.Errorf("cannot change part of merged cell %s", ref)
+		}
+		result = append(result, ref)
+	}
+	return result, nil
+}
+
+// movedFormula ajusta uma fórmula da planilha home, que passa a ficar em newHome,
+// à movimentação de area para destSheet: referências inteiras dentro da área
+// acompanham as células (inclusive as absolutas); as demais continuam apontando
+// para o mesmo lugar
+func movedFormula(formula, home, newHome string, area cellRef, destSheet string, dRow, dCol int) string {
+	if formula == "" {
+		return formula
+	}
+	return rewriteFormulaRefs(formula, func(sheet, ref string) string {
+		refSheet := sheet
+		if refSheet == "" {
+			refSheet = home
+		}
+		target := refSheet
+		if r, ok := parseCellRef(refSheet, ref); ok && area.covers(r) {
+			ref = offsetRef(ref, dRow, dCol)
+			target = destSheet
+		}
+		if sheet == "" && strings.EqualFold(target, newHome) {
+			return ref
+		}
+		return quoteSheetName(target) + "!" + ref
+	})
+}
+
+// offsetRef desloca todas as partes de uma referência A1, absolutas ou relativas
+func offsetRef(ref string, dRow, dCol int) string {
+	parts := strings.Split(ref, ":")
+	for i, part := range parts {
+		m := refEndpoint.FindStringSubmatch(part)
+		if m == nil {
+			return ref
+		}
+		col, row := m[2], m[4]
+		if col != "" {
+			n, _ := excelize.ColumnNameToNumber(col)
+			col, _ = excelize.ColumnNumberToName(n + dCol)
+		}
+		if row != "" {
+			n, _ := strconv.Atoi(row)
+			row = strconv.Itoa(n + dRow)
+		}
+		parts[i] = m[1] + col + m[3] + row
+	}
+	return strings.Join(parts, ":")
+}
+
+// copyNameLocked valida o nome da cópia de src; vazio gera "src (2)", "src (3)", ...
+// (chamar com c.mu)
+func (c *ExcelizeClient) copyNameLocked(src, name string) (string, error) {
+	if name == "" {
+		for n := 2; ; n++ {
+			name = fmt.Sprintf("%s (%d)", src, n)
+			if index, _ := c.file.GetSheetIndex(name); index < 0 {
+				return name, nil
+			}
+		}
+	}
+	if index, _ := c.file.GetSheetIndex(name); index >= 0 {
+		return "", fmt.Errorf("sheet %s already exists", name)
+	}
+	return name, nil
+}
+
+// detachCopiedObjectsLocked remove da planilha copiada pelo Excelize as relações com
+// os comentários, desenhos e tabelas da origem, que são recriados para a cópia
+// (chamar com c.mu)
+func (c *ExcelizeClient) detachCopiedObjectsLocked(sheet string) error {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+
+	rels := path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+	if content, ok := c.file.Pkg.Load(rels); ok {
+		c.file.Pkg.Store(rels, sheetObjectRel.ReplaceAll(content.([]byte), nil))
+		c.file.Relationships.Delete(rels)
+	}
+	if content, ok := c.file.Pkg.Load(part); ok {
+		c.file.Pkg.Store(part, legacyDrawingTag.ReplaceAll(content.([]byte), nil))
+		// Descarta a versão carregada para que a próxima leitura use o XML alterado
+		c.file.Sheet.Delete(part)
+	}
+	return nil
+}
+
+// copySheetCellsLocked copia células, estilos, larguras, alturas, mesclagens,
+// hiperlinks, validações, formatação condicional e painéis congelados de outra
+// pasta de trabalho (chamar com from.mu e c.mu)
+func (c *ExcelizeClient) copySheetCellsLocked(from *ExcelizeClient, src, name string) error {
+	styles := map[int]int{0: 0}
+	style := func(id int) (int, error) {
+		if mapped, ok := styles[id]; ok {
+			return mapped, nil
+		}
+		s, err := from.file.GetStyle(id)
+		if err != nil {
+			return 0, err
+		}
+		mapped, err := c.file.NewStyle(s)
+		styles[id] = mapped
+		return mapped, err
+	}
+
+	used := cellRef{Sheet: src, EndRow: excelize.TotalRows - 1, EndCol: excelize.MaxColumns - 1}
+	from.clampToUsedAreaLocked(&used)
+	for row := 0; row <= used.EndRow; row++ {
+		for col := 0; col <= used.EndCol; col++ {
+			cell := indicesToCell(row, col)
+			content, err := from.readCellLocked(src, cell)
+			if err != nil {
+				return err
+			}
+			if content.Value == "" && content.Formula == "" && content.Style == 0 {
+				continue
+			}
+			if content.Style, err = style(content.Style); err != nil {
+				return err
+			}
+			if err := c.writeCellLocked(name, cell, content); err != nil {
+				return err
+			}
+			if ok, link, _ := from.file.GetCellHyperLink(src, cell); ok {
+				linkType := "Location"
+				if strings.Contains(link, "://") || strings.HasPrefix(link, "mailto:") {
+					linkType = "External"
+				}
+				if err := c.file.SetCellHyperLink(name, cell, link, linkType); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for col := 1; col <= used.EndCol+1; col++ {
+		letter, _ := excelize.ColumnNumberToName(col)
+		width, _ := from.file.GetColWidth(src, letter)
+		if current, _ := c.file.GetColWidth(name, letter); width != current {
+			if err := c.file.SetColWidth(name, letter, letter, width); err != nil {
+				return err
+			}
+		}
+		if visible, _ := from.file.GetColVisible(src, letter); !visible {
+			if err := c.file.SetColVisible(name, letter, false); err != nil {
+				return err
+			}
+		}
+	}
+	for row := 1; row <= used.EndRow+1; row++ {
+		height, _ := from.file.GetRowHeight(src, row)
+		if current, _ := c.file.GetRowHeight(name, row); height != current {
+			if err := c.file.SetRowHeight(name, row, height); err != nil {
+				return err
+			}
+		}
+		if visible, _ := from.file.GetRowVisible(src, row); !visible {
+			if err := c.file.SetRowVisible(name, row, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	merges, err := from.file.GetMergeCells(src)
+	if err != nil {
+		return err
+	}
+	for _, merge := range merges {
+		if err := c.file.MergeCell(name, merge.GetStartAxis(), merge.GetEndAxis()); err != nil {
+			return err
+		}
+	}
+
+	validations, err := from.file.GetDataValidations(src)
+	if err != nil {
+		return err
+	}
+	for _, dv := range validations {
+		if err := c.file.AddDataValidation(name, dv); err != nil {
+			return err
+		}
+	}
+
+	formats, err := from.file.GetConditionalFormats(src)
+	if err != nil {
+		return err
+	}
+	for sqref, rules := range formats {
+		for i, rule := range rules {
+			if rule.Format == nil {
+				continue
+			}
+			s, err := from.file.GetConditionalStyle(*rule.Format)
+			if err != nil {
+				return err
+			}
+			id, err := c.file.NewConditionalStyle(s)
+			if err != nil {
+				return err
+			}
+			rules[i].Format = &id
+		}
+		if err := c.file.SetConditionalFormat(name, sqref, rules); err != nil {
+			return err
+		}
+	}
+
+	if panes, err := from.file.GetPanes(src); err == nil && (panes.Freeze || panes.Split) {
+		return c.file.SetPanes(name, &panes)
+	}
+	return nil
+}
+
+// copySheetObjectsLocked recria na cópia os comentários, imagens, tabelas e nomes
+// locais da planilha src; from pode ser a própria pasta (chamar com from.mu e c.mu)
+func (c *ExcelizeClient) copySheetObjectsLocked(from *ExcelizeClient, src, name string) error {
+	comments, err := from.file.GetComments(src)
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		if err := c.file.AddComment(name, comment); err != nil {
+			return err
+		}
+	}
+
+	cells, err := from.file.GetPictureCells(src)
+	if err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		pictures, err := from.file.GetPictures(src, cell)
+		if err != nil {
+			return err
+		}
+		for i := range pictures {
+			if err := c.file.AddPictureFromBytes(name, cell, &pictures[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	tables, err := from.file.GetTables(src)
+	if err != nil {
+		return err
+	}
+	sourceDocs := from.tableDocsLocked()
+	for _, t := range tables {
+		docs := c.tableDocsLocked()
+		tableName := t.Name
+		for n := 2; docs[strings.ToLower(tableName)] != nil; n++ {
+			tableName = t.Name + strconv.Itoa(n)
+		}
+		err := c.file.AddTable(name, &excelize.Table{
+			Range:             t.Range,
+			Name:              tableName,
+			StyleName:         t.StyleName,
+			ShowColumnStripes: t.ShowColumnStripes,
+			ShowFirstColumn:   t.ShowFirstColumn,
+			ShowHeaderRow:     t.ShowHeaderRow,
+			ShowLastColumn:    t.ShowLastColumn,
+			ShowRowStripes:    t.ShowRowStripes,
+		})
+		if err != nil {
+			return err
+		}
+		if tableName != t.Name {
+			if err := c.renameTableRefsLocked(name, t.Name, tableName); err != nil {
+				return err
+			}
+		}
+
+		// O Excelize não cria a linha de totais
+		doc, ok := sourceDocs[strings.ToLower(t.Name)]
+		if !ok || doc.TotalsRowCount == 0 {
+			continue
+		}
+		totals := make(map[string]TableTotal)
+		for _, col := range doc.Columns {
+			totals[strings.ToLower(col.Name)] = TableTotal{Column: col.Name, Function: col.TotalsRowFunction, Label: col.TotalsRowLabel}
+		}
+		area, _ := parseCellRef(name, t.Range)
+		if err := c.rewriteTableLocked(c.tableDocsLocked()[strings.ToLower(tableName)], area, true, totals); err != nil {
+			return err
+		}
+	}
+
+	for _, dn := range from.file.GetDefinedName() {
+		if !strings.EqualFold(dn.Scope, src) {
+			continue
+		}
+		dn.Scope = name
+		dn.RefersTo = rewriteFormulaRefs(dn.RefersTo, func(sheet, ref string) string {
+			if sheet == "" {
+				return ref
+			}
+			if strings.EqualFold(sheet, src) {
+				sheet = name
+			}
+			return quoteSheetName(sheet) + "!" + ref
+		})
+		if err := c.file.SetDefinedName(&dn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameTableRefsLocked troca as referências estruturadas à tabela oldName pela
+// tabela newName nas fórmulas da planilha (chamar com c.mu)
+func (c *ExcelizeClient) renameTableRefsLocked(sheet, oldName, newName string) error {
+	pattern := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}_.\\])` + regexp.QuoteMeta(oldName) + `\[`)
+	used := cellRef{Sheet: sheet, EndRow: excelize.TotalRows - 1, EndCol: excelize.MaxColumns - 1}
+	c.clampToUsedAreaLocked(&used)
+	for row := 0; row <= used.EndRow; row++ {
+		for col := 0; col <= used.EndCol; col++ {
+			cell := indicesToCell(row, col)
+			formula, err := c.file.GetCellFormula(sheet, cell)
+			if err != nil || formula == "" {
+				continue
+			}
+			if renamed := pattern.ReplaceAllString(formula, "${1}"+newName+"["); renamed != formula {
+				if err := c.file.SetCellFormula(sheet, cell, renamed); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package excel
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxImpactCells limita a lista de células devolvida por GetChangeImpact
+const maxImpactCells = 50
+
+// formulaCell nó do grafo de dependências entre fórmulas
+type formulaCell struct {
+	Sheet      string
+	Cell       string
+	Formula    string
+	Row        int
+	Col        int
+	Refs       []cellRef      // Intervalos lidos pela fórmula
+	Precedents []*formulaCell // Fórmulas dentro desses intervalos
+	Dependents []*formulaCell // Fórmulas que leem esta célula
+
+	index   int // Estado do algoritmo de Tarjan
+	low     int
+	onStack bool
+}
+
+// key identificação da célula no formato Planilha!A1
+func (n *formulaCell) key() string {
+	return n.Sheet + "!" + n.Cell
+}
+
+// formulaGraph grafo de dependências das fórmulas da pasta de trabalho
+type formulaGraph struct {
+	Cells    []*formulaCell
+	resolver *refResolver
+	bySheet  map[string][]*formulaCell // Nome da planilha em minúsculas
+	byCell   map[string]*formulaCell   // planilha!célula em minúsculas
+}
+
+// at retorna a fórmula de uma célula (nil se a célula não tiver fórmula)
+func (g *formulaGraph) at(sheet string, row, col int) *formulaCell {
+	return g.byCell[strings.ToLower(sheet+"!"+indicesToCell(row, col))]
+}
+
+// within retorna as fórmulas contidas em um intervalo
+func (g *formulaGraph) within(ref cellRef) []*formulaCell {
+	if ref.StartRow == ref.EndRow && ref.StartCol == ref.EndCol {
+		if n := g.at(ref.Sheet, ref.StartRow, ref.StartCol); n != nil {
+			return []*formulaCell{n}
+		}
+		return nil
+	}
+
+	var result []*formulaCell
+	for _, n := range g.bySheet[strings.ToLower(ref.Sheet)] {
+		if ref.contains(n.Sheet, n.Row, n.Col) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// buildFormulaGraphLocked localiza as fórmulas de todas as planilhas e liga cada
+// uma às fórmulas que ela referencia (chamar com c.mu)
+func (c *ExcelizeClient) buildFormulaGraphLocked(pkg *ooxmlPackage) (*formulaGraph, error) {
+	graph := &formulaGraph{
+		resolver: c.newRefResolverLocked(),
+		bySheet:  make(map[string][]*formulaCell),
+		byCell:   make(map[string]*formulaCell),
+	}
+
+	for _, sheet := range c.file.GetSheetList() {
+		part, err := pkg.sheetPart(sheet)
+		if err != nil {
+			return nil, err
+		}
+
+		var ws struct {
+			Cells []struct {
+				R string    `xml:"r,attr"`
+				F *struct{} `xml:"f"`
+			} `xml:"sheetData>row>c"`
+		}
+		if err := pkg.decode(part, &ws); err != nil {
+			return nil, fmt.Errorf("failed to read sheet %s: %w", sheet, err)
+		}
+
+		for _, cell := range ws.Cells {
+			if cell.F == nil {
+				continue
+			}
+			// GetCellFormula também traduz as células de fórmulas compartilhadas
+			formula, err := c.file.GetCellFormula(sheet, cell.R)
+			if err != nil || formula == "" {
+				continue
+			}
+			row, col := cellToIndices(cell.R)
+			node := &formulaCell{
+				Sheet:   sheet,
+				Cell:    cell.R,
+				Formula: formula,
+				Row:     row,
+				Col:     col,
+			}
+			node.Refs = graph.resolver.formulaRefs(sheet, row, col, formula)
+			graph.Cells = append(graph.Cells, node)
+			graph.bySheet[strings.ToLower(sheet)] = append(graph.bySheet[strings.ToLower(sheet)], node)
+			graph.byCell[strings.ToLower(node.key())] = node
+		}
+	}
+
+	for _, node := range graph.Cells {
+		seen := make(map[*formulaCell]bool)
+		for _, ref := range node.Refs {
+			for _, precedent := range graph.within(ref) {
+				if !seen[precedent] {
+					seen[precedent] = true
+					node.Precedents = append(node.Precedents, precedent)
+					precedent.Dependents = append(precedent.Dependents, node)
+				}
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// evaluationOrder ordena as fórmulas de forma que cada uma venha depois das que
+// ela referencia (Tarjan). Componentes com mais de uma célula, ou com
+// autorreferência, são devolvidos como ciclos.
+func (g *formulaGraph) evaluationOrder() (order []*formulaCell, cycles [][]*formulaCell) {
+	for _, n := range g.Cells {
+		n.index, n.onStack = -1, false
+	}
+
+	index := 0
+	var stack []*formulaCell
+	var visit func(n *formulaCell)
+	visit = func(n *formulaCell) {
+		n.index, n.low = index, index
+		index++
+		stack = append(stack, n)
+		n.onStack = true
+
+		selfRef := false
+		for _, p := range n.Precedents {
+			switch {
+			case p == n:
+				selfRef = true
+			case p.index < 0:
+				visit(p)
+				n.low = min(n.low, p.low)
+			case p.onStack:
+				n.low = min(n.low, p.index)
+			}
+		}
+
+		if n.low != n.index {
+			return
+		}
+		var component []*formulaCell
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			top.onStack = false
+			component = append([]*formulaCell{top}, component...)
+			if top == n {
+				break
+			}
+		}
+		if len(component) > 1 || selfRef {
+			cycles = append(cycles, component)
+		} else {
+			order = append(order, n)
+		}
+	}
+
+	for _, n := range g.Cells {
+		if n.index < 0 {
+			visit(n)
+		}
+	}
+	return order, cycles
+}
+
+// dependencyTargetLocked monta o grafo e resolve o intervalo consultado (chamar com c.mu)
+func (c *ExcelizeClient) dependencyTargetLocked(sheet, rng string) (*formulaGraph, []cellRef, error) {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return nil, nil, err
+	}
+	graph, err := c.buildFormulaGraphLocked(pkg)
+	if err != nil {
+		return nil, nil, err
+	}
+	targets := graph.resolver.resolve(sheet, rng)
+	if len(targets) == 0 {
+		return nil, nil, fmt.Errorf("invalid range: %s", rng)
+	}
+	return graph, targets, nil
+}
+
+// readers fórmulas que leem diretamente algum dos intervalos
+func (g *formulaGraph) readers(targets []cellRef) []*formulaCell {
+	var result []*formulaCell
+	for _, n := range g.Cells {
+	refs:
+		for _, ref := range n.Refs {
+			for _, target := range targets {
+				if ref.intersects(target) {
+					result = append(result, n)
+					break refs
+				}
+			}
+		}
+	}
+	return result
+}
+
+// GetPrecedents lista os intervalos lidos por cada fórmula do intervalo
+func (c *ExcelizeClient) GetPrecedents(sheet, rng string) ([]FormulaPrecedents, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	graph, targets, err := c.dependencyTargetLocked(sheet, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]FormulaPrecedents, 0)
+	for _, target := range targets {
+		for _, n := range graph.within(target) {
+			refs := make([]string, 0, len(n.Refs))
+			for _, ref := range n.Refs {
+				refs = append(refs, ref.String())
+			}
+			result = append(result, FormulaPrecedents{Cell: n.key(), Formula: n.Formula, Precedents: refs})
+		}
+	}
+	return result, nil
+}
+
+// GetDependents lista as fórmulas que leem diretamente o intervalo
+func (c *ExcelizeClient) GetDependents(sheet, rng string) ([]FormulaDependent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	graph, targets, err := c.dependencyTargetLocked(sheet, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]FormulaDependent, 0)
+	for _, n := range graph.readers(targets) {
+		result = append(result, FormulaDependent{Cell: n.key(), Formula: n.Formula})
+	}
+	return result, nil
+}
+
+// GetChangeImpact conta as fórmulas afetadas, direta ou indiretamente, por uma
+// alteração no intervalo
+func (c *ExcelizeClient) GetChangeImpact(sheet, rng string) (*ChangeImpact, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	graph, targets, err := c.dependencyTargetLocked(sheet, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	direct := graph.readers(targets)
+	impact := &ChangeImpact{
+		Range:   rng,
+		Direct:  len(direct),
+		BySheet: make(map[string]int),
+		Cells:   make([]string, 0),
+	}
+
+	// Busca em largura pelas fórmulas que dependem das leitoras diretas
+	visited := make(map[*formulaCell]bool)
+	queue := append([]*formulaCell{}, direct...)
+	for _, n := range queue {
+		visited[n] = true
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		impact.Total++
+		impact.BySheet[n.Sheet]++
+		if len(impact.Cells) < maxImpactCells {
+			impact.Cells = append(impact.Cells, n.key())
+		}
+		for _, dependent := range n.Dependents {
+			if !visited[dependent] {
+				visited[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return impact, nil
+}
+
+// Summary descreve o impacto em uma frase (ex: "alterar B2 afeta 340 células: Resumo 300, Dados 40")
+func (i *ChangeImpact) Summary() string {
+	if i.Total == 0 {
+		return fmt.Sprintf("alterar %s não afeta nenhuma fórmula", i.Range)
+	}
+	sheets := make([]string, 0, len(i.BySheet))
+	for sheet := range i.BySheet {
+		sheets = append(sheets, sheet)
+	}
+	sort.Slice(sheets, func(a, b int) bool {
+		if i.BySheet[sheets[a]] != i.BySheet[sheets[b]] {
+			return i.BySheet[sheets[a]] > i.BySheet[sheets[b]]
+		}
+		return sheets[a] < sheets[b]
+	})
+	parts := make([]string, 0, len(sheets))
+	for _, sheet := range sheets {
+		parts = append(parts, fmt.Sprintf("%s %d", sheet, i.BySheet[sheet]))
+	}
+	return fmt.Sprintf("alterar %s afeta %d células: %s", i.Range, i.Total, strings.Join(parts, ", "))
+}
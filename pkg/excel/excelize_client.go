@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
 
@@ -107,6 +106,7 @@ func (c *ExcelizeClient) GetCellValue(sheet, cell string) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, cell = c.resolveCellLocked(sheet, cell)
 	value, err := c.file.GetCellValue(sheet, cell)
 	if err != nil {
 		return "", fmt.Errorf("failed to get cell value: %w", err)
@@ -119,13 +119,16 @@ func (c *ExcelizeClient) SetCellValue(sheet, cell string, value interface{}) err
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, cell = c.resolveCellLocked(sheet, cell)
 	return c.file.SetCellValue(sheet, cell, value)
 }
 
-// GetRangeValues retorna os valores de um range
+// GetRangeValues retorna os valores de um range (endereço, nome definido ou tabela)
 func (c *ExcelizeClient) GetRangeValues(sheet, rng string) ([][]string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
 	return c.getRangeValuesLocked(sheet, rng)
 }
 
@@ -147,8 +150,7 @@ func (c *ExcelizeClient) getRangeValuesLocked(sheet, rng string) ([][]string, er
 		row := make([]string, 0)
 		for col := startCol; col <= endCol; col++ {
 			cell := indicesToCell(r, col)
-			val, _ := c.file.GetCellValue(sheet, cell)
-			row = append(row, val)
+			row = append(row, c.cellValueLocked(sheet, cell))
 		}
 		result = append(result, row)
 	}
@@ -161,6 +163,8 @@ func (c *ExcelizeClient) WriteRange(sheet, startCell string, data [][]interface{
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, startCell = c.resolveCellLocked(sheet, startCell)
+
 	// Excelize não tem um método direto para escrever um range inteiro
 	// Precisamos escrever célula por célula
 	startRow, startCol := cellToIndices(startCell)
@@ -182,6 +186,8 @@ func (c *ExcelizeClient) ClearRange(sheet, rng string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+
 	// OTIMIZAÇÃO: Não precisamos ler os valores para limpar, apenas calcular o tamanho do range
 	startCell, endCell, err := parseRange(rng)
 	if err != nil {
@@ -201,32 +207,6 @@ func (c *ExcelizeClient) ClearRange(sheet, rng string) error {
 	return nil
 }
 
-// FormatRange formata um range (implementação básica)
-func (c *ExcelizeClient) FormatRange(sheet, rng string, format Format) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	styleID, err := c.file.NewStyle(&excelize.Style{
-		Font: &excelize.Font{
-			Bold:   format.Bold,
-			Italic: format.Italic,
-			Size:   float64(format.FontSize),
-			Color:  format.FontColor,
-		},
-		Fill: excelize.Fill{
-			Type:    "pattern",
-			Color:   []string{format.BgColor},
-			Pattern: 1,
-		},
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to create style: %w", err)
-	}
-
-	return c.file.SetCellStyle(sheet, rng, rng, styleID)
-}
-
 // SetColumnWidth define a largura de uma coluna
 func (c *ExcelizeClient) SetColumnWidth(sheet, col string, width float64) error {
 	c.mu.Lock()
@@ -253,6 +233,8 @@ func (c *ExcelizeClient) MergeCells(sheet, rng string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+
 	// Excelize.MergeCell requer sheet, topLeft, bottomRight
 	startCell, endCell, err := parseRange(rng)
 	if err != nil {
@@ -267,6 +249,8 @@ func (c *ExcelizeClient) UnmergeCells(sheet, rng string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+
 	// Excelize.UnmergeCell requer sheet, topLeft, bottomRight
 	startCell, endCell, err := parseRange(rng)
 	if err != nil {
@@ -302,15 +286,9 @@ func (c *ExcelizeClient) SetBorders(sheet, rng, style string) error {
 		return fmt.Errorf("unsupported border style: %s", style)
 	}
 
-	styleID, err := c.file.NewStyle(&excelize.Style{
-		Border: borderType,
+	return c.updateStyleLocked(sheet, rng, func(style *excelize.Style) {
+		style.Border = borderType
 	})
-
-	if err != nil {
-		return err
-	}
-
-	return c.file.SetCellStyle(sheet, rng, rng, styleID)
 }
 
 // AutoFitColumns ajusta automaticamente a largura das colunas
@@ -318,6 +296,8 @@ func (c *ExcelizeClient) AutoFitColumns(sheet, rng string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+
 	// Usar versão internal para evitar deadlock
 	data, err := c.getRangeValuesLocked(sheet, rng)
 	if err != nil {
@@ -378,56 +358,12 @@ func (c *ExcelizeClient) DeleteRows(sheet string, row, count int) error {
 	return c.file.RemoveRow(sheet, row+count-1)
 }
 
-// CreateChart cria um gráfico
-func (c *ExcelizeClient) CreateChart(sheet, rng, chartType, title string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Simplificado: criação básica de gráfico
-	// Excelize tem APIs complexas para gráficos, esta é uma implementação mínima
-
-	var excelizeType excelize.ChartType
-	switch strings.ToLower(chartType) {
-	case "bar", "column":
-		excelizeType = excelize.Col
-	case "line":
-		excelizeType = excelize.Line
-	case "pie":
-		excelizeType = excelize.Pie
-	case "scatter":
-		excelizeType = excelize.Scatter
-	default:
-		excelizeType = excelize.Col
-	}
-
-	if err := c.file.AddChart(sheet, "Chart1", &excelize.Chart{
-		Type:   excelizeType,
-		Series: []excelize.ChartSeries{{Name: title, Categories: rng, Values: rng}},
-	}); err != nil {
-		return fmt.Errorf("failed to create chart: %w", err)
-	}
-
-	return nil
-}
-
-// DeleteChart deleta um gráfico
-func (c *ExcelizeClient) DeleteChart(sheet, name string) error {
-	// Excelize não tem método direto para deletar chart por nome
-	// Isso é uma limitação conhecida
-	return fmt.Errorf("delete chart not fully implemented in Excelize")
-}
-
-// ListCharts lista gráficos (limitação do Excelize)
-func (c *ExcelizeClient) ListCharts(sheet string) ([]string, error) {
-	// Excelize não tem método para listar charts
-	return []string{}, nil
-}
-
 // CreateTable cria uma tabela
 func (c *ExcelizeClient) CreateTable(sheet, rng, name, style string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
 	if err := c.file.AddTable(sheet, &excelize.Table{
 		Range:     rng,
 		Name:      name,
@@ -466,67 +402,15 @@ func (c *ExcelizeClient) ListTables(sheet string) ([]string, error) {
 	return names, nil
 }
 
-// CreatePivotTable cria uma tabela dinâmica usando Excelize
+// CreatePivotTable cria uma tabela dinâmica sem campos (configurados depois)
 func (c *ExcelizeClient) CreatePivotTable(srcSheet, srcRange, destSheet, destCell, name string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Construir referência de dados no formato correto: Sheet1!A1:E10
-	dataRange := fmt.Sprintf("%s!%s", srcSheet, srcRange)
-
-	// Definir opções da Pivot Table
-	opts := &excelize.PivotTableOptions{
-		DataRange:       dataRange,
-		PivotTableRange: fmt.Sprintf("%s!%s", destSheet, destCell),
-		Name:            name,
-		// Configuração padrão - campos podem ser configurados depois
-		RowGrandTotals:    true,
-		ColGrandTotals:    true,
-		ShowDrill:         true,
-		UseAutoFormatting: true,
-		PageOverThenDown:  true,
-		ShowRowHeaders:    true,
-		ShowColHeaders:    true,
-		ShowLastColumn:    true,
-	}
-
-	return c.file.AddPivotTable(opts)
-}
-
-// CreatePivotTableWithFields cria uma pivot table com campos específicos
-func (c *ExcelizeClient) CreatePivotTableWithFields(srcSheet, srcRange, destSheet, destCell, name string,
-	rowFields, colFields []string, dataFields []excelize.PivotTableField) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	dataRange := fmt.Sprintf("%s!%s", srcSheet, srcRange)
-
-	// Converter row fields
-	rows := make([]excelize.PivotTableField, len(rowFields))
-	for i, f := range rowFields {
-		rows[i] = excelize.PivotTableField{Data: f, DefaultSubtotal: true}
-	}
-
-	// Converter column fields
-	cols := make([]excelize.PivotTableField, len(colFields))
-	for i, f := range colFields {
-		cols[i] = excelize.PivotTableField{Data: f, DefaultSubtotal: true}
-	}
-
-	opts := &excelize.PivotTableOptions{
-		DataRange:         dataRange,
-		PivotTableRange:   fmt.Sprintf("%s!%s", destSheet, destCell),
-		Name:              name,
-		Rows:              rows,
-		Columns:           cols,
-		Data:              dataFields,
-		RowGrandTotals:    true,
-		ColGrandTotals:    true,
-		ShowDrill:         true,
-		UseAutoFormatting: true,
-	}
-
-	return c.file.AddPivotTable(opts)
+	return c.CreatePivotTableWithFields(PivotSpec{
+		Name:        name,
+		SourceSheet: srcSheet,
+		SourceRange: srcRange,
+		DestSheet:   destSheet,
+		DestCell:    destCell,
+	})
 }
 
 // ListPivotTables lista tabelas dinâmicas de uma planilha
@@ -554,89 +438,6 @@ func (c *ExcelizeClient) DeletePivotTable(sheet, name string) error {
 	return c.file.DeletePivotTable(sheet, name)
 }
 
-// ApplyFilter aplica um filtro
-func (c *ExcelizeClient) ApplyFilter(sheet, rng string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	return c.file.AutoFilter(sheet, rng, []excelize.AutoFilterOptions{})
-}
-
-// ClearFilters remove filtros de uma planilha
-func (c *ExcelizeClient) ClearFilters(sheet string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Excelize suporta remoção de AutoFilter desde v2.6
-	// Workaround: definir AutoFilter vazio remove o filtro
-	return c.file.AutoFilter(sheet, "", nil)
-}
-
-// HasFilter verifica se há filtro (limitação Excelize - sempre retorna false)
-func (c *ExcelizeClient) HasFilter(sheet string) (bool, error) {
-	// Excelize não expõe método para verificar filtros diretamente
-	// Retornar false por padrão
-	return false, nil
-}
-
-// SortRange ordena um range com suporte a valores numéricos
-func (c *ExcelizeClient) SortRange(sheet, rng string, col int, ascending bool) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Usar versão internal para evitar deadlock
-	data, err := c.getRangeValuesLocked(sheet, rng)
-	if err != nil {
-		return err
-	}
-
-	if len(data) == 0 || col >= len(data[0]) {
-		return nil
-	}
-
-	// Ordenar as linhas baseado na coluna especificada
-	// Detectar se a coluna é numérica para ordenação correta
-	sort.Slice(data, func(i, j int) bool {
-		valI := data[i][col]
-		valJ := data[j][col]
-
-		// Tentar converter para float para ordenação numérica
-		numI, errI := strconv.ParseFloat(strings.TrimSpace(valI), 64)
-		numJ, errJ := strconv.ParseFloat(strings.TrimSpace(valJ), 64)
-
-		// Se ambos são números, ordenar numericamente
-		if errI == nil && errJ == nil {
-			if ascending {
-				return numI < numJ
-			}
-			return numI > numJ
-		}
-
-		// Senão, ordenar alfabeticamente (case-insensitive)
-		if ascending {
-			return strings.ToLower(valI) < strings.ToLower(valJ)
-		}
-		return strings.ToLower(valI) > strings.ToLower(valJ)
-	})
-
-	// Usar versão internal para evitar deadlock
-	startCell, _, err := parseRange(rng)
-	if err != nil {
-		return err
-	}
-
-	startRow, startCol := cellToIndices(startCell)
-
-	for r, row := range data {
-		for colIdx, value := range row {
-			cell := indicesToCell(startRow+r, startCol+colIdx)
-			c.file.SetCellValue(sheet, cell, value)
-		}
-	}
-
-	return nil
-}
-
 // GetUsedRange retorna o range utilizado
 func (c *ExcelizeClient) GetUsedRange(sheet string) (string, error) {
 	c.mu.Lock()
@@ -713,6 +514,7 @@ func (c *ExcelizeClient) GetCellFormula(sheet, cell string) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, cell = c.resolveCellLocked(sheet, cell)
 	formula, err := c.file.GetCellFormula(sheet, cell)
 	if err != nil {
 		return "", fmt.Errorf("failed to get cell formula: %w", err)
@@ -725,7 +527,11 @@ func (c *ExcelizeClient) SaveAs(path string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	return c.file.SaveAs(path)
+	if err := c.file.SaveAs(path); err != nil {
+		return err
+	}
+	c.flushSheetsLocked()
+	return nil
 }
 
 // Write escreve os dados para um writer
@@ -737,6 +543,7 @@ func (c *ExcelizeClient) Write() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.flushSheetsLocked()
 
 	return buffer.Bytes(), nil
 }
@@ -769,124 +576,6 @@ func indicesToCell(row, col int) string {
 
 // ==================== ADVANCED FEATURES ====================
 
-// AddConditionalFormat adiciona formatação condicional a um range
-func (c *ExcelizeClient) AddConditionalFormat(sheet, rng string, formatType string, criteria string, value string, format *excelize.Style) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Criar estilo para a formatação
-	styleID, err := c.file.NewStyle(format)
-	if err != nil {
-		return fmt.Errorf("erro ao criar estilo: %w", err)
-	}
-
-	// Mapear tipo de formatação
-	var cfType string
-	switch formatType {
-	case "greaterThan":
-		cfType = "cellIs"
-	case "lessThan":
-		cfType = "cellIs"
-	case "equal":
-		cfType = "cellIs"
-	case "between":
-		cfType = "cellIs"
-	case "containsText":
-		cfType = "containsText"
-	case "duplicate":
-		cfType = "duplicateValues"
-	case "unique":
-		cfType = "uniqueValues"
-	case "top10":
-		cfType = "top10"
-	case "colorScale":
-		cfType = "colorScale"
-	case "dataBar":
-		cfType = "dataBar"
-	default:
-		cfType = "cellIs"
-	}
-
-	opts := []excelize.ConditionalFormatOptions{{
-		Type:     cfType,
-		Criteria: criteria,
-		Format:   &styleID,
-		Value:    value,
-	}}
-
-	return c.file.SetConditionalFormat(sheet, rng, opts)
-}
-
-// AddSimpleConditionalFormat versão simplificada para formatação condicional comum
-func (c *ExcelizeClient) AddSimpleConditionalFormat(sheet, rng string, criteria string, value string, bgColor string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	style := &excelize.Style{
-		Fill: excelize.Fill{
-			Type:    "pattern",
-			Pattern: 1,
-			Color:   []string{bgColor},
-		},
-	}
-
-	styleID, err := c.file.NewStyle(style)
-	if err != nil {
-		return err
-	}
-
-	opts := []excelize.ConditionalFormatOptions{{
-		Type:     "cellIs",
-		Criteria: criteria,
-		Format:   &styleID,
-		Value:    value,
-	}}
-
-	return c.file.SetConditionalFormat(sheet, rng, opts)
-}
-
-// AddDataValidation adiciona validação de dados (dropdown, números, etc)
-func (c *ExcelizeClient) AddDataValidation(sheet, rng string, validationType string, options []string) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	dv := excelize.NewDataValidation(true)
-	dv.Sqref = rng
-
-	switch validationType {
-	case "list":
-		// Dropdown list
-		dv.SetDropList(options)
-	case "whole":
-		// Número inteiro
-		if len(options) >= 2 {
-			dv.SetRange(options[0], options[1], excelize.DataValidationTypeWhole, excelize.DataValidationOperatorBetween)
-		}
-	case "decimal":
-		// Número decimal
-		if len(options) >= 2 {
-			dv.SetRange(options[0], options[1], excelize.DataValidationTypeDecimal, excelize.DataValidationOperatorBetween)
-		}
-	case "date":
-		// Data
-		if len(options) >= 2 {
-			dv.SetRange(options[0], options[1], excelize.DataValidationTypeDate, excelize.DataValidationOperatorBetween)
-		}
-	case "textLength":
-		// Comprimento do texto
-		if len(options) >= 2 {
-			dv.SetRange(options[0], options[1], excelize.DataValidationTypeTextLength, excelize.DataValidationOperatorBetween)
-		}
-	}
-
-	return c.file.AddDataValidation(sheet, dv)
-}
-
-// AddDropdownList adiciona uma lista dropdown a uma célula/range
-func (c *ExcelizeClient) AddDropdownList(sheet, rng string, options []string) error {
-	return c.AddDataValidation(sheet, rng, "list", options)
-}
-
 // AddCellComment adiciona um comentário a uma célula
 func (c *ExcelizeClient) AddCellComment(sheet, cell, author, text string) error {
 	c.mu.Lock()
@@ -993,27 +682,12 @@ func (c *ExcelizeClient) SetCellLocked(sheet, cell string, locked bool) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	style := &excelize.Style{
-		Protection: &excelize.Protection{
-			Locked: locked,
-		},
-	}
-
-	styleID, err := c.file.NewStyle(style)
-	if err != nil {
-		return err
-	}
-
-	return c.file.SetCellStyle(sheet, cell, cell, styleID)
-}
-
-// CalculateFormulas calcula todas as fórmulas do arquivo
-func (c *ExcelizeClient) CalculateFormulas() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Excelize suporta cálculo de fórmulas desde v2.8
-	return c.file.UpdateLinkedValue()
+	return c.updateStyleLocked(sheet, cell, func(style *excelize.Style) {
+		if style.Protection == nil {
+			style.Protection = &excelize.Protection{}
+		}
+		style.Protection.Locked = locked
+	})
 }
 
 // SetCellFormula define uma fórmula em uma célula
@@ -1021,6 +695,7 @@ func (c *ExcelizeClient) SetCellFormula(sheet, cell, formula string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	sheet, cell = c.resolveCellLocked(sheet, cell)
 	return c.file.SetCellFormula(sheet, cell, formula)
 }
 
@@ -1045,7 +720,7 @@ func (c *ExcelizeClient) HideSheet(sheet string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	return c.file.SetSheetVisible(sheet, false)
+	return c.hideSheetLocked(sheet, false)
 }
 
 // ShowSheet exibe uma planilha oculta
@@ -1088,12 +763,51 @@ func (c *ExcelizeClient) GroupRows(sheet string, startRow, endRow int) error {
 	return c.file.SetRowOutlineLevel(sheet, endRow, 1)
 }
 
-// GroupColumns agrupa colunas
+// GroupColumns agrupa as colunas de startCol a endCol (letras ou cabeçalhos)
 func (c *ExcelizeClient) GroupColumns(sheet, startCol, endCol string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	return c.file.SetColOutlineLevel(sheet, endCol, 1)
+	return c.setColumnsOutlineLocked(sheet, startCol, endCol, 1)
+}
+
+// UngroupColumns desfaz o agrupamento das colunas de startCol a endCol
+func (c *ExcelizeClient) UngroupColumns(sheet, startCol, endCol string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.setColumnsOutlineLocked(sheet, startCol, endCol, 0)
+}
+
+// setColumnsOutlineLocked define o nível de estrutura de cada coluna do intervalo (chamar com c.mu)
+func (c *ExcelizeClient) setColumnsOutlineLocked(sheet, startCol, endCol string, level uint8) error {
+	if _, err := c.sheetIndexLocked(sheet); err != nil {
+		return err
+	}
+	if endCol == "" {
+		endCol = startCol
+	}
+	first, _, err := c.findColumnsLocked(sheet, startCol)
+	if err != nil {
+		return err
+	}
+	_, last, err := c.findColumnsLocked(sheet, endCol)
+	if err != nil {
+		return err
+	}
+	if first > last {
+		first, last = last, first
+	}
+	if level == 0 {
+		return c.clearColumnsOutlineLocked(sheet, first, last)
+	}
+	for col := first; col <= last; col++ {
+		name, _ := excelize.ColumnNumberToName(col + 1)
+		if err := c.file.SetColOutlineLevel(sheet, name, level); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SetPrintArea define a área de impressão
@@ -1101,11 +815,12 @@ func (c *ExcelizeClient) SetPrintArea(sheet, rng string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	return c.file.SetDefinedName(&excelize.DefinedName{
-		Name:     "_xlnm.Print_Area",
-		RefersTo: fmt.Sprintf("'%s'!%s", sheet, rng),
-		Scope:    sheet,
-	})
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+	area, err := c.printAreaLocked(sheet, rng)
+	if err != nil {
+		return err
+	}
+	return c.setSheetNameLocked(sheet, printAreaName, area)
 }
 
 // AddHyperlink adiciona um hyperlink a uma célula
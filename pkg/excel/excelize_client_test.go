@@ -0,0 +1,84 @@
+package excel
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// newTestClient cria um cliente sobre uma pasta de trabalho vazia
+func newTestClient(t *testing.T) *ExcelizeClient {
+	t.Helper()
+	buffer, err := excelize.NewFile().WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewExcelizeClient(buffer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// wantCells confere os valores de células de uma planilha
+func wantCells(t *testing.T, c *ExcelizeClient, sheet string, want map[string]string) {
+	t.Helper()
+	for cell, value := range want {
+		got, err := c.GetCellValue(sheet, cell)
+		if err != nil {
+			t.Fatalf("%s!%s: %v", sheet, cell, err)
+		}
+		if got != value {
+			t.Errorf("%s!%s = %q, esperado %q", sheet, cell, got, value)
+		}
+	}
+}
+
+func TestSaveThenEdit(t *testing.T) {
+	tests := []struct {
+		name string
+		save func(t *testing.T, c *ExcelizeClient)
+	}{
+		{"Write", func(t *testing.T, c *ExcelizeClient) {
+			if _, err := c.Write(); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"SaveAs", func(t *testing.T, c *ExcelizeClient) {
+			if err := c.SaveAs(filepath.Join(t.TempDir(), "pasta.xlsx")); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t)
+			if err := c.CreateSheet("Nova"); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.SetCellValue("Nova", "C1", "Total"); err != nil {
+				t.Fatal(err)
+			}
+			tt.save(t, c)
+			// Célula à esquerda da que já existe na linha, gravada depois de salvar
+			if err := c.SetCellValue("Nova", "A1", "Nome"); err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{"A1": "Nome", "B1": "", "C1": "Total"}
+			wantCells(t, c, "Nova", want)
+
+			data, err := c.Write()
+			if err != nil {
+				t.Fatal(err)
+			}
+			reopened, err := NewExcelizeClient(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reopened.Close()
+			wantCells(t, reopened, "Nova", want)
+		})
+	}
+}
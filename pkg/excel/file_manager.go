@@ -75,6 +75,24 @@ func (fm *FileManager) GetClient(sessionID string) (*ExcelizeClient, error) {
 	return client, nil
 }
 
+// CopySheet copia a planilha srcSheet da sessão srcSession para a sessão
+// destSession, que pode ser a mesma. Retorna o nome da cópia.
+func (fm *FileManager) CopySheet(srcSession, srcSheet, destSession, name string) (string, error) {
+	// O lock exclusivo serializa as cópias, que bloqueiam os dois clientes
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	from, ok := fm.sessions[srcSession]
+	if !ok || from == nil {
+		return "", fmt.Errorf("session not found: %s", srcSession)
+	}
+	to, ok := fm.sessions[destSession]
+	if !ok || to == nil {
+		return "", fmt.Errorf("session not found: %s", destSession)
+	}
+	return to.CopySheetFrom(from, srcSheet, name)
+}
+
 // Export exporta o arquivo de uma sessão para bytes
 func (fm *FileManager) Export(sessionID string) ([]byte, error) {
 	client, err := fm.GetClient(sessionID)
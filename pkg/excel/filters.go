@@ -0,0 +1,617 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// filterDatabase nome interno que guarda o intervalo do filtro automático
+const filterDatabase = "_xlnm._FilterDatabase"
+
+// filterCell valor de uma célula avaliado pelos critérios de filtro
+type filterCell struct {
+	display string
+	num     float64
+	isNum   bool
+}
+
+// filterRule critério compilado: expressão gravada no arquivo e teste das linhas
+type filterRule struct {
+	col        int    // Coluna relativa ao intervalo
+	expression string // Expressão do Excelize ("" quando não pode ser gravada)
+	match      func(filterCell) bool
+}
+
+// filterOperators operadores de comparação das expressões do Excelize
+var filterOperators = map[string]string{
+	"equals":             "==",
+	"notEquals":          "!=",
+	"contains":           "==",
+	"notContains":        "!=",
+	"beginsWith":         "==",
+	"endsWith":           "==",
+	"greaterThan":        ">",
+	"greaterThanOrEqual": ">=",
+	"lessThan":           "<",
+	"lessThanOrEqual":    "<=",
+}
+
+// wildcardEscaper protege os curingas do Excel (* ? ~) em valores literais
+var wildcardEscaper = strings.NewReplacer("~", "~~", "*", "~*", "?", "~?")
+
+// ApplyFilter ativa o filtro automático no intervalo, sem critérios
+func (c *ExcelizeClient) ApplyFilter(sheet, rng string) error {
+	_, err := c.ApplyAutoFilter(sheet, rng, nil)
+	return err
+}
+
+// ApplyAutoFilter aplica o filtro automático ao intervalo (primeira linha como
+// cabeçalho) e oculta as linhas que não atendem aos critérios de todas as colunas.
+// Retorna o número de linhas de dados visíveis.
+func (c *ExcelizeClient) ApplyAutoFilter(sheet, rng string, criteria []FilterCriterion) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.areaLocked(sheet, rng)
+	if !ok {
+		return 0, fmt.Errorf("invalid range: %s", rng)
+	}
+	// Uma única célula filtra a região usada da planilha, como no Excel
+	if area.StartRow == area.EndRow && area.StartCol == area.EndCol {
+		area = cellRef{Sheet: area.Sheet, EndRow: excelize.TotalRows - 1, EndCol: excelize.MaxColumns - 1}
+	}
+	c.clampToUsedAreaLocked(&area)
+	// A linha de totais de uma tabela não é filtrada
+	if t, ok := c.newRefResolverLocked().tableAt(area.Sheet, area.StartRow, area.StartCol); ok && t.Totals && area.EndRow == t.Area.EndRow {
+		area.EndRow--
+	}
+
+	headers := make([]string, area.EndCol-area.StartCol+1)
+	for i := range headers {
+		headers[i], _ = c.file.GetCellValue(area.Sheet, indicesToCell(area.StartRow, area.StartCol+i))
+	}
+	rows := make([][]filterCell, area.EndRow-area.StartRow)
+	for i := range rows {
+		rows[i] = make([]filterCell, len(headers))
+		for j := range rows[i] {
+			rows[i][j] = c.filterCellLocked(area.Sheet, indicesToCell(area.StartRow+1+i, area.StartCol+j))
+		}
+	}
+
+	rules := make([]filterRule, 0, len(criteria))
+	for _, criterion := range criteria {
+		col, err := filterColumn(area, headers, criterion.Column)
+		if err != nil {
+			return 0, err
+		}
+		column := make([]filterCell, len(rows))
+		for i := range rows {
+			column[i] = rows[i][col]
+		}
+		rule, err := compileFilter(criterion, column, time.Now())
+		if err != nil {
+			return 0, err
+		}
+		rule.col = col
+		rules = append(rules, rule)
+	}
+
+	// Tabelas têm filtro próprio, que o Excelize não grava; nelas os critérios são
+	// aplicados apenas ocultando as linhas
+	inTable := false
+	for _, t := range c.newRefResolverLocked().tables {
+		inTable = inTable || t.Area.intersects(area)
+	}
+	if !inTable {
+		options := make([]excelize.AutoFilterOptions, 0, len(rules))
+		for _, rule := range rules {
+			if rule.expression == "" {
+				continue
+			}
+			name, _ := excelize.ColumnNumberToName(area.StartCol + rule.col + 1)
+			options = append(options, excelize.AutoFilterOptions{Column: name, Expression: rule.expression})
+		}
+		ref := indicesToCell(area.StartRow, area.StartCol) + ":" + indicesToCell(area.EndRow, area.EndCol)
+		if err := c.file.AutoFilter(area.Sheet, ref, options); err != nil {
+			return 0, err
+		}
+	}
+
+	visible := 0
+	for i, row := range rows {
+		show := true
+		for _, rule := range rules {
+			show = show && rule.match(row[rule.col])
+		}
+		if show {
+			visible++
+		}
+		if err := c.file.SetRowVisible(area.Sheet, area.StartRow+2+i, show); err != nil {
+			return 0, err
+		}
+	}
+	return visible, nil
+}
+
+// ClearFilters remove os critérios do filtro automático e das tabelas da planilha e
+// mostra as linhas ocultadas por eles. As setas do filtro são mantidas: o Excelize
+// não remove o filtro automático de uma planilha.
+func (c *ExcelizeClient) ClearFilters(sheet string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var areas []cellRef
+	if area, ok := c.filterAreaLocked(sheet); ok {
+		ref := indicesToCell(area.StartRow, area.StartCol) + ":" + indicesToCell(area.EndRow, area.EndCol)
+		if err := c.file.AutoFilter(area.Sheet, ref, nil); err != nil {
+			return err
+		}
+		areas = append(areas, area)
+	}
+	for _, t := range c.newRefResolverLocked().tables {
+		if strings.EqualFold(t.Area.Sheet, sheet) {
+			areas = append(areas, t.Area)
+		}
+	}
+	for _, area := range areas {
+		for row := area.StartRow + 1; row <= area.EndRow; row++ {
+			if err := c.file.SetRowVisible(area.Sheet, row+1, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// HasFilter verifica se a planilha tem filtro automático
+func (c *ExcelizeClient) HasFilter(sheet string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.filterAreaLocked(sheet)
+	return ok, nil
+}
+
+// GetAutoFilter retorna o filtro automático da planilha, com os critérios gravados
+// no arquivo e a contagem de linhas visíveis (nil se não houver filtro)
+func (c *ExcelizeClient) GetAutoFilter(sheet string) (*AutoFilterInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.filterAreaLocked(sheet)
+	if !ok {
+		return nil, nil
+	}
+	info := &AutoFilterInfo{
+		Range:   indicesToCell(area.StartRow, area.StartCol) + ":" + indicesToCell(area.EndRow, area.EndCol),
+		Columns: []FilterColumnInfo{},
+	}
+	for row := area.StartRow + 1; row <= area.EndRow; row++ {
+		if visible, _ := c.file.GetRowVisible(area.Sheet, row+1); visible {
+			info.VisibleRows++
+		} else {
+			info.HiddenRows++
+		}
+	}
+
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return nil, err
+	}
+	part, err := pkg.sheetPart(area.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	var ws struct {
+		AutoFilter *struct {
+			FilterColumns []struct {
+				ColID   int `xml:"colId,attr"`
+				Filters *struct {
+					Blank  bool     `xml:"blank,attr"`
+					Values []xmlVal `xml:"filter"`
+				} `xml:"filters"`
+				CustomFilters *struct {
+					And     bool `xml:"and,attr"`
+					Filters []struct {
+						Operator string `xml:"operator,attr"`
+						Val      string `xml:"val,attr"`
+					} `xml:"customFilter"`
+				} `xml:"customFilters"`
+				Top10 *struct {
+					Top     *bool   `xml:"top,attr"`
+					Percent bool    `xml:"percent,attr"`
+					Val     float64 `xml:"val,attr"`
+				} `xml:"top10"`
+				Dynamic *struct {
+					Type string `xml:"type,attr"`
+				} `xml:"dynamicFilter"`
+			} `xml:"filterColumn"`
+		} `xml:"autoFilter"`
+	}
+	if err := pkg.decode(part, &ws); err != nil {
+		return nil, err
+	}
+	if ws.AutoFilter == nil {
+		return info, nil
+	}
+
+	for _, fc := range ws.AutoFilter.FilterColumns {
+		name, _ := excelize.ColumnNumberToName(area.StartCol + fc.ColID + 1)
+		column := FilterColumnInfo{Column: name}
+		column.Header, _ = c.file.GetCellValue(area.Sheet, indicesToCell(area.StartRow, area.StartCol+fc.ColID))
+		switch {
+		case fc.Filters != nil:
+			for _, v := range fc.Filters.Values {
+				column.Values = append(column.Values, v.Val)
+			}
+			if fc.Filters.Blank {
+				column.Conditions = append(column.Conditions, FilterCondition{Operator: "blanks"})
+			}
+		case fc.CustomFilters != nil:
+			for _, f := range fc.CustomFilters.Filters {
+				column.Conditions = append(column.Conditions, FilterCondition{Operator: customFilterOperator(f.Operator), Value: f.Val})
+			}
+			if len(column.Conditions) > 1 {
+				column.Join = "or"
+				if fc.CustomFilters.And {
+					column.Join = "and"
+				}
+			}
+		case fc.Top10 != nil:
+			operator := "top"
+			if fc.Top10.Top != nil && !*fc.Top10.Top {
+				operator = "bottom"
+			}
+			value := strconv.FormatFloat(fc.Top10.Val, 'f', -1, 64)
+			if fc.Top10.Percent {
+				value += "%"
+			}
+			column.Conditions = []FilterCondition{{Operator: operator, Value: value}}
+		case fc.Dynamic != nil:
+			column.Conditions = []FilterCondition{{Operator: "datePeriod", Value: fc.Dynamic.Type}}
+		}
+		info.Columns = append(info.Columns, column)
+	}
+	return info, nil
+}
+
+// GetVisibleRangeValues retorna os valores das linhas visíveis de um range (as
+// linhas ocultas por filtros ou manualmente são omitidas)
+func (c *ExcelizeClient) GetVisibleRangeValues(sheet, rng string) ([][]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+	values, err := c.getRangeValuesLocked(sheet, rng)
+	if err != nil {
+		return nil, err
+	}
+	startCell, _, _ := parseRange(rng)
+	startRow, _ := cellToIndices(startCell)
+
+	visible := make([][]string, 0, len(values))
+	for i, row := range values {
+		if shown, err := c.file.GetRowVisible(sheet, startRow+i+1); err == nil && !shown {
+			continue
+		}
+		visible = append(visible, row)
+	}
+	return visible, nil
+}
+
+// filterAreaLocked intervalo do filtro automático da planilha (chamar com c.mu)
+func (c *ExcelizeClient) filterAreaLocked(sheet string) (cellRef, bool) {
+	for _, dn := range c.file.GetDefinedName() {
+		if dn.Name == filterDatabase && strings.EqualFold(dn.Scope, sheet) {
+			return parseCellRef(sheet, strings.TrimPrefix(dn.RefersTo, "="))
+		}
+	}
+	return cellRef{}, false
+}
+
+// filterCellLocked lê o valor exibido e o valor numérico de uma célula (chamar com c.mu)
+func (c *ExcelizeClient) filterCellLocked(sheet, cell string) filterCell {
+	display, _ := c.file.GetCellValue(sheet, cell)
+	fc := filterCell{display: strings.TrimSpace(display)}
+	raw := strings.TrimSpace(c.rawCellValueLocked(sheet, cell))
+	if num, err := strconv.ParseFloat(raw, 64); err == nil {
+		fc.num, fc.isNum = num, true
+	} else if serial, ok := dateSerial(raw); ok {
+		fc.num, fc.isNum = serial, true
+	}
+	return fc
+}
+
+// filterColumn localiza a coluna do critério pelo cabeçalho ou pela letra
+func filterColumn(area cellRef, headers []string, column string) (int, error) {
+	column = strings.TrimSpace(column)
+	for i, header := range headers {
+		if strings.EqualFold(strings.TrimSpace(header), column) {
+			return i, nil
+		}
+	}
+	if n, err := excelize.ColumnNameToNumber(column); err == nil && n-1 >= area.StartCol && n-1 <= area.EndCol {
+		return n - 1 - area.StartCol, nil
+	}
+	return 0, fmt.Errorf("filter column not found: %s", column)
+}
+
+// compileFilter converte um critério na expressão do Excelize e no teste das linhas.
+// Os 10 primeiros e os períodos de data são gravados como intervalos equivalentes,
+// calculados com os dados atuais: o Excelize só grava filtros por valor e customizados.
+func compileFilter(criterion FilterCriterion, column []filterCell, now time.Time) (filterRule, error) {
+	switch criterion.Operator {
+	case "equals":
+		values := criterion.Values
+		if len(values) == 0 {
+			values = []string{criterion.Value}
+		}
+		conditions := make([]FilterCondition, len(values))
+		for i, v := range values {
+			conditions[i] = FilterCondition{Operator: "equals", Value: v}
+		}
+		rule, err := compileConditions(conditions, "or")
+		if err != nil {
+			return rule, err
+		}
+		// Mais de dois valores não cabem na expressão do Excelize
+		if len(values) > 2 {
+			rule.expression = ""
+		}
+		return rule, nil
+
+	case "notEquals", "contains", "notContains", "beginsWith", "endsWith",
+		"greaterThan", "greaterThanOrEqual", "lessThan", "lessThanOrEqual":
+		return compileConditions([]FilterCondition{{Operator: criterion.Operator, Value: criterion.Value}}, "and")
+
+	case "between":
+		return compileConditions([]FilterCondition{
+			{Operator: "greaterThanOrEqual", Value: criterion.MinValue},
+			{Operator: "lessThanOrEqual", Value: criterion.MaxValue},
+		}, "and")
+
+	case "custom":
+		if len(criterion.Conditions) == 0 || len(criterion.Conditions) > 2 {
+			return filterRule{}, fmt.Errorf("custom filter requires one or two conditions")
+		}
+		join := criterion.Join
+		if join == "" {
+			join = "and"
+		}
+		if join != "and" && join != "or" {
+			return filterRule{}, fmt.Errorf("invalid filter join: %s", join)
+		}
+		return compileConditions(criterion.Conditions, join)
+
+	case "blanks":
+		return filterRule{expression: "x == Blanks", match: func(fc filterCell) bool { return fc.display == "" }}, nil
+	case "nonBlanks":
+		return filterRule{expression: "x != Blanks", match: func(fc filterCell) bool { return fc.display != "" }}, nil
+
+	case "top", "bottom":
+		var numbers []float64
+		for _, fc := range column {
+			if fc.isNum {
+				numbers = append(numbers, fc.num)
+			}
+		}
+		if len(numbers) == 0 {
+			return filterRule{match: func(filterCell) bool { return false }}, nil
+		}
+		count := criterion.Count
+		if count <= 0 {
+			count = 10
+		}
+		if criterion.Percent {
+			count = int(math.Ceil(float64(len(numbers)) * float64(count) / 100))
+		}
+		count = min(max(count, 1), len(numbers))
+		sort.Float64s(numbers)
+		if criterion.Operator == "top" {
+			threshold := numbers[len(numbers)-count]
+			return filterRule{
+				expression: "x >= " + strconv.FormatFloat(threshold, 'f', -1, 64),
+				match:      func(fc filterCell) bool { return fc.isNum && fc.num >= threshold },
+			}, nil
+		}
+		threshold := numbers[count-1]
+		return filterRule{
+			expression: "x <= " + strconv.FormatFloat(threshold, 'f', -1, 64),
+			match:      func(fc filterCell) bool { return fc.isNum && fc.num <= threshold },
+		}, nil
+
+	case "datePeriod":
+		start, end, ok := datePeriod(criterion.Period, now)
+		if !ok {
+			return filterRule{}, fmt.Errorf("invalid date period: %s", criterion.Period)
+		}
+		from := start.Sub(excelEpoch).Hours() / 24
+		to := end.Sub(excelEpoch).Hours() / 24
+		return filterRule{
+			expression: fmt.Sprintf("x >= %g and x < %g", from, to),
+			match:      func(fc filterCell) bool { return fc.isNum && fc.num >= from && fc.num < to },
+		}, nil
+	}
+	return filterRule{}, fmt.Errorf("invalid filter operator: %s", criterion.Operator)
+}
+
+// compileConditions combina uma ou duas condições simples (ou vários valores de
+// equals) com and/or
+func compileConditions(conditions []FilterCondition, join string) (filterRule, error) {
+	matches := make([]func(filterCell) bool, len(conditions))
+	expressions := make([]string, len(conditions))
+	for i, condition := range conditions {
+		operator, ok := filterOperators[condition.Operator]
+		if !ok {
+			return filterRule{}, fmt.Errorf("invalid filter operator: %s", condition.Operator)
+		}
+		value := strings.TrimSpace(condition.Value)
+		if value == "" {
+			return filterRule{}, fmt.Errorf("filter operator %s requires a value", condition.Operator)
+		}
+
+		switch condition.Operator {
+		case "equals", "notEquals", "contains", "notContains", "beginsWith", "endsWith":
+			pattern := wildcardEscaper.Replace(value)
+			switch condition.Operator {
+			case "contains", "notContains":
+				pattern = "*" + pattern + "*"
+			case "beginsWith":
+				pattern = pattern + "*"
+			case "endsWith":
+				pattern = "*" + pattern
+			}
+			re := wildcardPattern(pattern)
+			num, numErr := strconv.ParseFloat(value, 64)
+			negate := condition.Operator == "notEquals" || condition.Operator == "notContains"
+			exact := condition.Operator == "equals" || condition.Operator == "notEquals"
+			matches[i] = func(fc filterCell) bool {
+				matched := re.MatchString(fc.display)
+				if exact && numErr == nil && fc.isNum {
+					matched = matched || fc.num == num
+				}
+				return matched != negate
+			}
+			// Espaços e aspas separariam a expressão: viram o curinga de um caractere
+			expressions[i] = "x " + operator + " " + strings.NewReplacer(" ", "?", `"`, "?").Replace(pattern)
+
+		default:
+			operand := value
+			num, numErr := strconv.ParseFloat(value, 64)
+			if numErr != nil {
+				if serial, ok := dateSerial(value); ok {
+					num, numErr = serial, nil
+					operand = strconv.FormatFloat(serial, 'f', -1, 64)
+				}
+			}
+			text := strings.ToLower(value)
+			compare := func(cmp int) bool {
+				switch condition.Operator {
+				case "greaterThan":
+					return cmp > 0
+				case "greaterThanOrEqual":
+					return cmp >= 0
+				case "lessThan":
+					return cmp < 0
+				}
+				return cmp <= 0
+			}
+			matches[i] = func(fc filterCell) bool {
+				switch {
+				case numErr == nil && fc.isNum:
+					if fc.num == num {
+						return compare(0)
+					}
+					if fc.num > num {
+						return compare(1)
+					}
+					return compare(-1)
+				case numErr != nil && !fc.isNum && fc.display != "":
+					return compare(strings.Compare(strings.ToLower(fc.display), text))
+				}
+				return false
+			}
+			expressions[i] = "x " + operator + " " + strings.NewReplacer(" ", "?", `"`, "?").Replace(operand)
+		}
+	}
+
+	return filterRule{
+		expression: strings.Join(expressions, " "+join+" "),
+		match: func(fc filterCell) bool {
+			for _, match := range matches {
+				if match(fc) == (join == "or") {
+					return join == "or"
+				}
+			}
+			return join != "or"
+		},
+	}, nil
+}
+
+// wildcardPattern converte um padrão com curingas do Excel em expressão regular
+func wildcardPattern(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '~':
+			escaped = true
+		case r == '*':
+			expr.WriteString(".*")
+		case r == '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// datePeriod intervalo [início, fim) de um período relativo à data atual; semanas
+// começam no domingo, como no Excel
+func datePeriod(period string, now time.Time) (time.Time, time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	week := today.AddDate(0, 0, -int(today.Weekday()))
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	quarter := time.Date(today.Year(), time.Month((int(today.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	year := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case "today":
+		return today, today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), true
+	case "thisWeek":
+		return week, week.AddDate(0, 0, 7), true
+	case "lastWeek":
+		return week.AddDate(0, 0, -7), week, true
+	case "nextWeek":
+		return week.AddDate(0, 0, 7), week.AddDate(0, 0, 14), true
+	case "thisMonth":
+		return month, month.AddDate(0, 1, 0), true
+	case "lastMonth":
+		return month.AddDate(0, -1, 0), month, true
+	case "nextMonth":
+		return month.AddDate(0, 1, 0), month.AddDate(0, 2, 0), true
+	case "thisQuarter":
+		return quarter, quarter.AddDate(0, 3, 0), true
+	case "lastQuarter":
+		return quarter.AddDate(0, -3, 0), quarter, true
+	case "nextQuarter":
+		return quarter.AddDate(0, 3, 0), quarter.AddDate(0, 6, 0), true
+	case "thisYear":
+		return year, year.AddDate(1, 0, 0), true
+	case "lastYear":
+		return year.AddDate(-1, 0, 0), year, true
+	case "nextYear":
+		return year.AddDate(1, 0, 0), year.AddDate(2, 0, 0), true
+	case "yearToDate":
+		return year, today.AddDate(0, 0, 1), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// customFilterOperator nome do operador de um customFilter gravado no arquivo
+func customFilterOperator(operator string) string {
+	switch operator {
+	case "", "equal":
+		return "equals"
+	case "notEqual":
+		return "notEquals"
+	}
+	return operator
+}
@@ -0,0 +1,235 @@
+package excel
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCompileFilter(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 30, 0, 0, time.UTC)
+	people := []string{"Ana", "ana", "Bruno", "Mariana", "", "10", "10.0"}
+	numbers := []string{"1", "5", "3", "9", "texto", ""}
+	dates := []string{"45412", "45413", "45443", "45444", "maio"}
+
+	tests := []struct {
+		name       string
+		criterion  FilterCriterion
+		cells      []string
+		want       []string // células que continuam visíveis
+		expression string
+		wantErr    bool
+	}{
+		{
+			name:       "equals sem diferenciar maiúsculas",
+			criterion:  FilterCriterion{Operator: "equals", Value: "ana"},
+			cells:      people,
+			want:       []string{"Ana", "ana"},
+			expression: "x == ana",
+		},
+		{
+			name:       "equals numérico compara o valor",
+			criterion:  FilterCriterion{Operator: "equals", Value: "10"},
+			cells:      people,
+			want:       []string{"10", "10.0"},
+			expression: "x == 10",
+		},
+		{
+			name:       "equals com mais de dois valores não grava expressão",
+			criterion:  FilterCriterion{Operator: "equals", Values: []string{"Ana", "Bruno", "10"}},
+			cells:      people,
+			want:       []string{"Ana", "ana", "Bruno", "10", "10.0"},
+			expression: "",
+		},
+		{
+			name:       "contains",
+			criterion:  FilterCriterion{Operator: "contains", Value: "an"},
+			cells:      people,
+			want:       []string{"Ana", "ana", "Mariana"},
+			expression: "x == *an*",
+		},
+		{
+			name:       "notContains mantém vazios",
+			criterion:  FilterCriterion{Operator: "notContains", Value: "an"},
+			cells:      people,
+			want:       []string{"Bruno", "", "10", "10.0"},
+			expression: "x != *an*",
+		},
+		{
+			name:       "beginsWith",
+			criterion:  FilterCriterion{Operator: "beginsWith", Value: "Br"},
+			cells:      people,
+			want:       []string{"Bruno"},
+			expression: "x == Br*",
+		},
+		{
+			name:       "curinga literal é escapado",
+			criterion:  FilterCriterion{Operator: "equals", Value: "a*"},
+			cells:      []string{"a*", "ab"},
+			want:       []string{"a*"},
+			expression: "x == a~*",
+		},
+		{
+			name:       "espaço vira curinga na expressão",
+			criterion:  FilterCriterion{Operator: "equals", Value: "São Paulo"},
+			cells:      []string{"São Paulo", "SãoPaulo"},
+			want:       []string{"São Paulo"},
+			expression: "x == São?Paulo",
+		},
+		{
+			name:       "greaterThan ignora textos",
+			criterion:  FilterCriterion{Operator: "greaterThan", Value: "3"},
+			cells:      numbers,
+			want:       []string{"5", "9"},
+			expression: "x > 3",
+		},
+		{
+			name:       "between inclui os limites",
+			criterion:  FilterCriterion{Operator: "between", MinValue: "3", MaxValue: "5"},
+			cells:      numbers,
+			want:       []string{"5", "3"},
+			expression: "x >= 3 and x <= 5",
+		},
+		{
+			name:       "data convertida em número de série",
+			criterion:  FilterCriterion{Operator: "greaterThanOrEqual", Value: "2024-05-31"},
+			cells:      dates,
+			want:       []string{"45443", "45444"},
+			expression: "x >= 45443",
+		},
+		{
+			name:       "custom com or",
+			criterion:  FilterCriterion{Operator: "custom", Join: "or", Conditions: []FilterCondition{{Operator: "lessThan", Value: "2"}, {Operator: "greaterThan", Value: "8"}}},
+			cells:      numbers,
+			want:       []string{"1", "9"},
+			expression: "x < 2 or x > 8",
+		},
+		{
+			name:       "blanks",
+			criterion:  FilterCriterion{Operator: "blanks"},
+			cells:      numbers,
+			want:       []string{""},
+			expression: "x == Blanks",
+		},
+		{
+			name:       "top 2",
+			criterion:  FilterCriterion{Operator: "top", Count: 2},
+			cells:      numbers,
+			want:       []string{"5", "9"},
+			expression: "x >= 5",
+		},
+		{
+			name:       "bottom 50 por cento",
+			criterion:  FilterCriterion{Operator: "bottom", Count: 50, Percent: true},
+			cells:      numbers,
+			want:       []string{"1", "3"},
+			expression: "x <= 3",
+		},
+		{
+			name:       "top maior que a coluna",
+			criterion:  FilterCriterion{Operator: "top", Count: 50},
+			cells:      numbers,
+			want:       []string{"1", "5", "3", "9"},
+			expression: "x >= 1",
+		},
+		{
+			name:      "top sem números",
+			criterion: FilterCriterion{Operator: "top"},
+			cells:     []string{"a", "b"},
+			want:      nil,
+		},
+		{
+			name:       "período de data",
+			criterion:  FilterCriterion{Operator: "datePeriod", Period: "thisMonth"},
+			cells:      dates,
+			want:       []string{"45413", "45443"},
+			expression: "x >= 45413 and x < 45444",
+		},
+		{name: "período inválido", criterion: FilterCriterion{Operator: "datePeriod", Period: "someday"}, wantErr: true},
+		{name: "operador inválido", criterion: FilterCriterion{Operator: "like", Value: "a"}, wantErr: true},
+		{name: "valor ausente", criterion: FilterCriterion{Operator: "greaterThan"}, wantErr: true},
+		{name: "custom com três condições", criterion: FilterCriterion{Operator: "custom", Conditions: make([]FilterCondition, 3)}, wantErr: true},
+		{name: "custom com junção inválida", criterion: FilterCriterion{Operator: "custom", Join: "xor", Conditions: []FilterCondition{{Operator: "equals", Value: "a"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := make([]filterCell, len(tt.cells))
+			for i, display := range tt.cells {
+				num, err := strconv.ParseFloat(display, 64)
+				column[i] = filterCell{display: display, num: num, isNum: err == nil}
+			}
+
+			rule, err := compileFilter(tt.criterion, column, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("esperava erro para %+v", tt.criterion)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compileFilter retornou erro: %v", err)
+			}
+
+			var got []string
+			for _, fc := range column {
+				if rule.match(fc) {
+					got = append(got, fc.display)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("visíveis = %q, esperado %q", got, tt.want)
+			}
+			if rule.expression != tt.expression {
+				t.Errorf("expressão = %q, esperado %q", rule.expression, tt.expression)
+			}
+		})
+	}
+}
+
+func TestDatePeriod(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	wednesday := time.Date(2024, 5, 15, 18, 45, 0, 0, time.UTC)
+	sunday := time.Date(2024, 5, 12, 9, 0, 0, 0, time.UTC)
+	january := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		period     string
+		now        time.Time
+		start, end time.Time
+	}{
+		{"today", wednesday, day(2024, 5, 15), day(2024, 5, 16)},
+		{"yesterday", wednesday, day(2024, 5, 14), day(2024, 5, 15)},
+		{"tomorrow", wednesday, day(2024, 5, 16), day(2024, 5, 17)},
+		{"thisWeek", wednesday, day(2024, 5, 12), day(2024, 5, 19)},
+		{"thisWeek", sunday, day(2024, 5, 12), day(2024, 5, 19)},
+		{"lastWeek", wednesday, day(2024, 5, 5), day(2024, 5, 12)},
+		{"nextWeek", wednesday, day(2024, 5, 19), day(2024, 5, 26)},
+		{"thisMonth", wednesday, day(2024, 5, 1), day(2024, 6, 1)},
+		{"lastMonth", january, day(2023, 12, 1), day(2024, 1, 1)},
+		{"nextMonth", wednesday, day(2024, 6, 1), day(2024, 7, 1)},
+		{"thisQuarter", wednesday, day(2024, 4, 1), day(2024, 7, 1)},
+		{"lastQuarter", january, day(2023, 10, 1), day(2024, 1, 1)},
+		{"nextQuarter", wednesday, day(2024, 7, 1), day(2024, 10, 1)},
+		{"thisYear", wednesday, day(2024, 1, 1), day(2025, 1, 1)},
+		{"lastYear", wednesday, day(2023, 1, 1), day(2024, 1, 1)},
+		{"nextYear", wednesday, day(2025, 1, 1), day(2026, 1, 1)},
+		{"yearToDate", wednesday, day(2024, 1, 1), day(2024, 5, 16)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period+" "+tt.now.Format("2006-01-02"), func(t *testing.T) {
+			start, end, ok := datePeriod(tt.period, tt.now)
+			if !ok || !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("datePeriod(%q) = %s, %s, %v; esperado %s, %s",
+					tt.period, start.Format("2006-01-02"), end.Format("2006-01-02"), ok,
+					tt.start.Format("2006-01-02"), tt.end.Format("2006-01-02"))
+			}
+		})
+	}
+
+	if _, _, ok := datePeriod("someday", wednesday); ok {
+		t.Error("período desconhecido deveria ser rejeitado")
+	}
+}
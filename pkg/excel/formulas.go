@@ -0,0 +1,244 @@
+package excel
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Códigos usados em FormulaError além dos erros nativos do Excel
+const (
+	formulaErrorCircular    = "CIRCULAR"
+	formulaErrorUnsupported = "UNSUPPORTED"
+)
+
+// excelErrorHints valores de erro do Excel e o significado informado ao agente
+var excelErrorHints = map[string]string{
+	"#DIV/0!": "divisão por zero",
+	"#REF!":   "referência inválida",
+	"#NAME?":  "nome, planilha ou função desconhecida",
+	"#VALUE!": "tipo de valor incorreto",
+	"#N/A":    "valor não disponível",
+	"#NUM!":   "número inválido",
+	"#NULL!":  "interseção vazia",
+}
+
+// formulaCellTag início de uma célula com fórmula no XML da planilha
+// (grupos: atributos antes de r, endereço, demais atributos, fórmula, valor em cache).
+// O Excel grava as células de uma fórmula compartilhada como <f t="shared" si="0"/>.
+var formulaCellTag = regexp.MustCompile(`<c( [^>]*?)? r="([A-Z]+[0-9]+)"([^>]*)>(<f[^>]*/>|<f[^>]*>[^<]*</f>)(<v[^>]*>[^<]*</v>)?`)
+
+// cellValueAttrs atributos que dependem do tipo do valor em cache
+var cellValueAttrs = regexp.MustCompile(` (?:t|xml:space)="[^"]*"`)
+
+// CalculateFormulas recalcula todas as fórmulas em ordem de dependência, grava
+// os resultados como valores em cache e devolve os erros encontrados por célula
+func (c *ExcelizeClient) CalculateFormulas() (*RecalcResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// O Excel refaz o cálculo ao abrir, cobrindo funções que o Excelize não avalia
+	fullCalc := true
+	if err := c.file.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &fullCalc}); err != nil {
+		return nil, fmt.Errorf("failed to set calculation properties: %w", err)
+	}
+
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return nil, err
+	}
+	graph, err := c.buildFormulaGraphLocked(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecalcResult{}
+	cached := make(map[string]map[string]string) // planilha -> célula -> valor
+	store := func(n *formulaCell, value string) {
+		if cached[n.Sheet] == nil {
+			cached[n.Sheet] = make(map[string]string)
+		}
+		cached[n.Sheet][n.Cell] = value
+	}
+
+	order, cycles := graph.evaluationOrder()
+	for _, cycle := range cycles {
+		keys := make([]string, 0, len(cycle))
+		for _, n := range cycle {
+			keys = append(keys, n.key())
+		}
+		result.Cycles = append(result.Cycles, keys)
+
+		path := strings.Join(append(keys, keys[0]), " → ")
+		for _, n := range cycle {
+			result.Errors = append(result.Errors, FormulaError{
+				Sheet:   n.Sheet,
+				Cell:    n.Cell,
+				Formula: n.Formula,
+				Error:   formulaErrorCircular,
+				Message: "referência circular: " + path,
+			})
+			// Sem cálculo iterativo o Excel exibe 0 nas células do ciclo
+			store(n, "0")
+		}
+	}
+
+	unsupported := make(map[*formulaCell]bool)
+	for _, n := range order {
+		value, err := c.file.CalcCellValue(n.Sheet, n.Cell, excelize.Options{RawCellValue: true})
+		if err == nil && excelErrorHints[value] == "" {
+			result.Evaluated++
+			store(n, value)
+			continue
+		}
+
+		code, message := classifyFormulaError(n.Formula, value, err)
+		// O erro de uma fórmula não suportada se propaga para quem a lê
+		for _, p := range n.Precedents {
+			if code != formulaErrorUnsupported && unsupported[p] {
+				code, message = formulaErrorUnsupported, "depende de fórmula não suportada: "+p.key()
+			}
+		}
+		if code == formulaErrorUnsupported {
+			unsupported[n] = true
+		}
+		result.Errors = append(result.Errors, FormulaError{
+			Sheet:   n.Sheet,
+			Cell:    n.Cell,
+			Formula: n.Formula,
+			Error:   code,
+			Message: message,
+		})
+		// Funções não suportadas mantêm o valor em cache calculado pelo Excel
+		if code != formulaErrorUnsupported {
+			result.Evaluated++
+			store(n, code)
+		}
+	}
+
+	for sheet, values := range cached {
+		if err := c.writeCachedValuesLocked(pkg, sheet, values); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// writeCachedValuesLocked grava os valores em cache das fórmulas de uma planilha.
+// O Excelize não permite definir o valor de uma célula sem apagar a fórmula, por
+// isso o XML já serializado por readPackageLocked é alterado diretamente.
+func (c *ExcelizeClient) writeCachedValuesLocked(pkg *ooxmlPackage, sheet string, values map[string]string) error {
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+	content, ok := c.file.Pkg.Load(part)
+	if !ok {
+		return fmt.Errorf("sheet part not found: %s", part)
+	}
+
+	patched := formulaCellTag.ReplaceAllFunc(content.([]byte), func(tag []byte) []byte {
+		m := formulaCellTag.FindSubmatch(tag)
+		value, ok := values[string(m[2])]
+		if !ok {
+			return tag
+		}
+		attrs := cellValueAttrs.ReplaceAll(append(append([]byte{}, m[1]...), m[3]...), nil)
+		typeAttr, valueElem := cachedValueXML(value)
+		return []byte(fmt.Sprintf(`<c r="%s"%s%s>%s%s`, m[2], attrs, typeAttr, m[4], valueElem))
+	})
+
+	c.file.Pkg.Store(part, patched)
+	// Descarta a versão carregada para que a próxima leitura use o XML alterado
+	c.file.Sheet.Delete(part)
+	return nil
+}
+
+// cachedValueXML tipo (atributo t) e elemento <v> de um valor calculado
+func cachedValueXML(value string) (string, string) {
+	if _, ok := excelErrorHints[value]; ok {
+		return ` t="e"`, "<v>" + value + "</v>"
+	}
+	switch value {
+	case "TRUE":
+		return ` t="b"`, "<v>1</v>"
+	case "FALSE":
+		return ` t="b"`, "<v>0</v>"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "", "<v>" + value + "</v>"
+	}
+
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(value))
+	typeAttr := ` t="str"`
+	if strings.TrimSpace(value) != value {
+		typeAttr += ` xml:space="preserve"`
+	}
+	return typeAttr, "<v>" + escaped.String() + "</v>"
+}
+
+// classifyFormulaError converte a falha de CalcCellValue em um código de erro do
+// Excel e uma explicação
+func classifyFormulaError(formula, value string, err error) (string, string) {
+	if err != nil && strings.HasPrefix(err.Error(), "not support") {
+		return formulaErrorUnsupported, "função não suportada pelo motor de cálculo: " + err.Error()
+	}
+	if structuredRef.MatchString(stringLiteral.ReplaceAllString(formula, `""`)) {
+		return formulaErrorUnsupported, "referências estruturadas de tabela não são avaliadas pelo motor de cálculo"
+	}
+	if hint, ok := excelErrorHints[value]; ok {
+		return value, hint
+	}
+	if err == nil {
+		return "#VALUE!", excelErrorHints["#VALUE!"]
+	}
+	if hint, ok := excelErrorHints[err.Error()]; ok {
+		return err.Error(), hint
+	}
+	if strings.Contains(strings.ToUpper(formula), "#REF!") {
+		return "#REF!", excelErrorHints["#REF!"]
+	}
+	return "#VALUE!", err.Error()
+}
+
+// cellValueLocked valor exibido de uma célula. Fórmulas são avaliadas na leitura
+// para não devolver um valor em cache desatualizado (chamar com c.mu).
+func (c *ExcelizeClient) cellValueLocked(sheet, cell string) string {
+	formula, _ := c.file.GetCellFormula(sheet, cell)
+	if formula == "" {
+		value, _ := c.file.GetCellValue(sheet, cell)
+		return value
+	}
+
+	value, err := c.file.CalcCellValue(sheet, cell)
+	if err == nil {
+		return value
+	}
+	// Em caso de falha vale o valor em cache: o recálculo grava ali os erros reais
+	// e preserva o resultado do Excel para fórmulas que o motor não avalia
+	if cached, _ := c.file.GetCellValue(sheet, cell); cached != "" {
+		return cached
+	}
+	if code, _ := classifyFormulaError(formula, value, err); code != formulaErrorUnsupported {
+		return code
+	}
+	return ""
+}
+
+// rawCellValueLocked valor bruto de uma célula, sem formato de número (datas como
+// número de série). Fórmulas são avaliadas na leitura (chamar com c.mu).
+func (c *ExcelizeClient) rawCellValueLocked(sheet, cell string) string {
+	if formula, _ := c.file.GetCellFormula(sheet, cell); formula != "" {
+		if value, err := c.file.CalcCellValue(sheet, cell, excelize.Options{RawCellValue: true}); err == nil {
+			return value
+		}
+	}
+	value, _ := c.file.GetCellValue(sheet, cell, excelize.Options{RawCellValue: true})
+	return value
+}
@@ -0,0 +1,173 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// newFormulaTestClient cria um cliente sobre uma pasta montada com o Excelize,
+// sem valores em cache nas fórmulas
+func newFormulaTestClient(t *testing.T, build func(f *excelize.File) error) *ExcelizeClient {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := build(f); err != nil {
+		t.Fatal(err)
+	}
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewExcelizeClient(buffer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// sheetCellXML devolve o elemento <c> de uma célula no XML da primeira planilha salva
+func sheetCellXML(t *testing.T, data []byte, cell string) string {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := archive.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return regexp.MustCompile(`<c r="` + cell + `"[^>]*>.*?</c>`).FindString(string(content))
+}
+
+// sharedFormulaSheet preenche A1:B3 e define C1:C3 como uma fórmula compartilhada
+func sharedFormulaSheet(f *excelize.File) error {
+	for i, row := range [][]interface{}{{2, 3}, {4, 5}, {6, 7}} {
+		if err := f.SetSheetRow("Sheet1", indicesToCell(i, 0), &row); err != nil {
+			return err
+		}
+	}
+	shared, ref := "shared", "C1:C3"
+	return f.SetCellFormula("Sheet1", "C1", "A1*B1", excelize.FormulaOpts{Type: &shared, Ref: &ref})
+}
+
+func TestCalculateFormulas(t *testing.T) {
+	tests := []struct {
+		name       string
+		build      func(f *excelize.File) error
+		excelXML   bool              // Grava as fórmulas compartilhadas como o Excel (<f .../>)
+		want       map[string]string // Valores em cache após recalcular
+		wantErrors map[string]string // Célula -> código do erro
+		wantCycles [][]string
+		wantXML    map[string]*regexp.Regexp // Célula -> elemento <c> salvo
+	}{
+		{
+			name:  "fórmula compartilhada",
+			build: sharedFormulaSheet,
+			want:  map[string]string{"C1": "6", "C2": "20", "C3": "42"},
+		},
+		{
+			name:     "fórmula compartilhada salva pelo Excel",
+			build:    sharedFormulaSheet,
+			excelXML: true,
+			want:     map[string]string{"C1": "6", "C2": "20", "C3": "42"},
+		},
+		{
+			name: "divisão por zero",
+			build: func(f *excelize.File) error {
+				if err := f.SetCellValue("Sheet1", "A1", 10); err != nil {
+					return err
+				}
+				return f.SetCellFormula("Sheet1", "B1", "A1/A2")
+			},
+			want:       map[string]string{"B1": "#DIV/0!"},
+			wantErrors: map[string]string{"B1": "#DIV/0!"},
+			wantXML:    map[string]*regexp.Regexp{"B1": regexp.MustCompile(`t="e".*<v>#DIV/0!</v>`)},
+		},
+		{
+			name: "referência circular",
+			build: func(f *excelize.File) error {
+				if err := f.SetCellFormula("Sheet1", "A1", "B1+1"); err != nil {
+					return err
+				}
+				if err := f.SetCellFormula("Sheet1", "B1", "A1+1"); err != nil {
+					return err
+				}
+				return f.SetCellFormula("Sheet1", "C1", "5*2")
+			},
+			want:       map[string]string{"A1": "0", "B1": "0", "C1": "10"},
+			wantErrors: map[string]string{"A1": formulaErrorCircular, "B1": formulaErrorCircular},
+			wantCycles: [][]string{{"Sheet1!A1", "Sheet1!B1"}},
+		},
+		{
+			name: "resultado texto",
+			build: func(f *excelize.File) error {
+				if err := f.SetCellValue("Sheet1", "A1", "Olá"); err != nil {
+					return err
+				}
+				return f.SetCellFormula("Sheet1", "B1", `A1&" mundo"`)
+			},
+			want:    map[string]string{"B1": "Olá mundo"},
+			wantXML: map[string]*regexp.Regexp{"B1": regexp.MustCompile(`t="str".*<f>.*</f><v>Olá mundo</v>`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFormulaTestClient(t, tt.build)
+			if tt.excelXML {
+				const part = "xl/worksheets/sheet1.xml"
+				content, _ := c.file.Pkg.Load(part)
+				c.file.Pkg.Store(part, bytes.ReplaceAll(content.([]byte), []byte(`"></f>`), []byte(`"/>`)))
+			}
+			result, err := c.CalculateFormulas()
+			if err != nil {
+				t.Fatalf("CalculateFormulas retornou erro: %v", err)
+			}
+
+			gotErrors := make(map[string]string)
+			for _, e := range result.Errors {
+				gotErrors[e.Cell] = e.Error
+			}
+			if len(gotErrors) != len(tt.wantErrors) || (len(tt.wantErrors) > 0 && !reflect.DeepEqual(gotErrors, tt.wantErrors)) {
+				t.Errorf("erros = %v, esperado %v", gotErrors, tt.wantErrors)
+			}
+			if len(result.Cycles) != len(tt.wantCycles) || (len(tt.wantCycles) > 0 && !reflect.DeepEqual(result.Cycles, tt.wantCycles)) {
+				t.Errorf("ciclos = %v, esperado %v", result.Cycles, tt.wantCycles)
+			}
+			wantCells(t, c, "Sheet1", tt.want)
+
+			// Os valores em cache e as fórmulas sobrevivem a salvar e reabrir
+			data, err := c.Write()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for cell, pattern := range tt.wantXML {
+				if got := sheetCellXML(t, data, cell); !pattern.MatchString(got) {
+					t.Errorf("XML de %s = %s, esperado %s", cell, got, pattern)
+				}
+			}
+			reopened, err := NewExcelizeClient(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer reopened.Close()
+			wantCells(t, reopened, "Sheet1", tt.want)
+			for cell := range tt.want {
+				if formula, err := reopened.GetCellFormula("Sheet1", cell); err != nil || formula == "" {
+					t.Errorf("fórmula de %s perdida ao reabrir (erro: %v)", cell, err)
+				}
+			}
+		})
+	}
+}
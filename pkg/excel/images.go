@@ -0,0 +1,534 @@
+package excel
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/gif"  // Dimensões de imagens GIF
+	_ "image/jpeg" // Dimensões de imagens JPEG
+	_ "image/png"  // Dimensões de imagens PNG
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// imageExtensions formatos de imagem aceitos pelo Excelize
+var imageExtensions = map[string]string{
+	"png": ".png", "jpg": ".jpg", "jpeg": ".jpeg", "gif": ".gif", "bmp": ".bmp",
+	"svg": ".svg", "tif": ".tif", "tiff": ".tiff", "ico": ".ico",
+	"emf": ".emf", "emz": ".emz", "wmf": ".wmf", "wmz": ".wmz",
+}
+
+// imageDataURI prefixo de uma imagem em data URI (data:image/png;base64,...)
+var imageDataURI = regexp.MustCompile(`^data:image/([\w.+-]+);base64,`)
+
+// shapeTypes geometrias predefinidas aceitas em formas (minúsculas -> nome OOXML)
+var shapeTypes = func() map[string]string {
+	types := make(map[string]string)
+	for _, name := range []string{
+		"rect", "roundRect", "snipRoundRect", "ellipse", "triangle", "rtTriangle", "diamond",
+		"parallelogram", "trapezoid", "pentagon", "hexagon", "octagon", "star5", "star6",
+		"plus", "heart", "cloud", "smileyFace", "homePlate", "chevron",
+		"rightArrow", "leftArrow", "upArrow", "downArrow", "leftRightArrow", "upDownArrow",
+		"wedgeRectCallout", "wedgeRoundRectCallout", "wedgeEllipseCallout", "cloudCallout",
+		"flowChartProcess", "flowChartDecision", "flowChartTerminator", "flowChartDocument",
+	} {
+		types[strings.ToLower(name)] = name
+	}
+	return types
+}()
+
+// drawingAnchorBlock âncora completa de um desenho
+var drawingAnchorBlock = regexp.MustCompile(`(?s)<(?:\w+:)?(?:twoCellAnchor|oneCellAnchor|absoluteAnchor)\b.*?</(?:\w+:)?(?:twoCellAnchor|oneCellAnchor|absoluteAnchor)>`)
+
+// InsertImage insere uma imagem a partir de um arquivo ou de conteúdo em base64
+func (c *ExcelizeClient) InsertImage(sheet string, spec ImageSpec) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cell, err := objectCell(spec.Cell)
+	if err != nil {
+		return err
+	}
+	dir := ""
+	if c.filePath != "" {
+		dir = filepath.Dir(c.filePath)
+	}
+	data, format, err := loadImage(spec, dir)
+	if err != nil {
+		return err
+	}
+	ext, ok := imageExtensions[format]
+	if !ok {
+		return fmt.Errorf("unsupported image format: %s", format)
+	}
+
+	lockAspect := spec.LockAspect == nil || *spec.LockAspect
+	opts := &excelize.GraphicOptions{
+		AltText:         spec.AltText,
+		LockAspectRatio: lockAspect,
+		OffsetX:         spec.OffsetX,
+		OffsetY:         spec.OffsetY,
+	}
+	switch strings.ToLower(strings.TrimSpace(spec.Positioning)) {
+	case "", "onecell":
+		opts.Positioning = "oneCell"
+	case "twocell":
+		opts.Positioning = "twoCell"
+	case "absolute":
+		opts.Positioning = "absolute"
+	default:
+		return fmt.Errorf("invalid positioning: %s (use oneCell, twoCell or absolute)", spec.Positioning)
+	}
+	if opts.ScaleX, opts.ScaleY, err = imageScale(data, spec, lockAspect); err != nil {
+		return err
+	}
+
+	if err := c.file.AddPictureFromBytes(sheet, cell, &excelize.Picture{
+		Extension: ext,
+		File:      data,
+		Format:    opts,
+	}); err != nil {
+		return fmt.Errorf("failed to insert image: %w", err)
+	}
+	return nil
+}
+
+// GetImages lista as imagens de uma planilha
+func (c *ExcelizeClient) GetImages(sheet string) ([]ImageInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cells, err := c.file.GetPictureCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		ri, ci := cellToIndices(cells[i])
+		rj, cj := cellToIndices(cells[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ci < cj
+	})
+
+	images := []ImageInfo{}
+	for _, cell := range cells {
+		pictures, err := c.file.GetPictures(sheet, cell)
+		if err != nil {
+			return nil, err
+		}
+		for _, picture := range pictures {
+			info := ImageInfo{
+				Cell:   cell,
+				Format: strings.TrimPrefix(picture.Extension, "."),
+				Size:   len(picture.File),
+			}
+			if picture.Format != nil {
+				info.AltText = picture.Format.AltText
+			}
+			if config, _, err := image.DecodeConfig(bytes.NewReader(picture.File)); err == nil {
+				info.Width, info.Height = config.Width, config.Height
+			}
+			images = append(images, info)
+		}
+	}
+	return images, nil
+}
+
+// DeleteImage remove as imagens ancoradas em uma célula
+func (c *ExcelizeClient) DeleteImage(sheet, cell string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cell, err := objectCell(cell)
+	if err != nil {
+		return err
+	}
+	pictures, err := c.file.GetPictures(sheet, cell)
+	if err != nil {
+		return err
+	}
+	if len(pictures) == 0 {
+		return fmt.Errorf("no image at %s!%s", sheet, cell)
+	}
+	return c.file.DeletePicture(sheet, cell)
+}
+
+// AddShape adiciona uma forma ou caixa de texto e retorna o nome atribuído
+func (c *ExcelizeClient) AddShape(sheet string, spec ShapeSpec) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cell, err := objectCell(spec.Cell)
+	if err != nil {
+		return "", err
+	}
+
+	// Caixa de texto: retângulo branco com borda discreta
+	fill, line := spec.FillColor, spec.LineColor
+	shapeType := strings.ToLower(strings.TrimSpace(spec.Type))
+	if shapeType == "" || shapeType == "textbox" {
+		shapeType = "rect"
+		if fill == "" {
+			fill = "FFFFFF"
+		}
+		if line == "" {
+			line = "BFBFBF"
+		}
+	}
+	prst, ok := shapeTypes[shapeType]
+	if !ok {
+		return "", fmt.Errorf("unsupported shape type: %s", spec.Type)
+	}
+
+	shape := &excelize.Shape{
+		Cell:   cell,
+		Type:   prst,
+		Width:  160,
+		Height: 60,
+		Line:   excelize.ShapeLine{Color: strings.TrimPrefix(line, "#")},
+	}
+	if spec.Width > 0 {
+		shape.Width = uint(spec.Width)
+	}
+	if spec.Height > 0 {
+		shape.Height = uint(spec.Height)
+	}
+	if fill != "" {
+		shape.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{strings.TrimPrefix(fill, "#")}}
+	}
+	if spec.LineWidth > 0 {
+		width := spec.LineWidth
+		shape.Line.Width = &width
+	}
+
+	if spec.Text != "" {
+		family, err := c.file.GetDefaultFont()
+		if err != nil {
+			return "", err
+		}
+		font := excelize.Font{
+			Family: family,
+			Size:   11,
+			Color:  "000000",
+			Bold:   spec.Bold,
+			Italic: spec.Italic,
+		}
+		if spec.FontSize > 0 {
+			font.Size = spec.FontSize
+		}
+		if spec.FontColor != "" {
+			font.Color = strings.TrimPrefix(spec.FontColor, "#")
+		}
+		for _, text := range strings.Split(strings.ReplaceAll(spec.Text, "\r\n", "\n"), "\n") {
+			runFont := font
+			shape.Paragraph = append(shape.Paragraph, excelize.RichTextRun{Text: text, Font: &runFont})
+		}
+	}
+
+	if err := c.file.AddShape(sheet, shape); err != nil {
+		return "", fmt.Errorf("failed to add shape: %w", err)
+	}
+
+	// O Excelize nomeia a forma pelo id no desenho; a nova é a última ancorada na célula
+	shapes, _, err := c.getShapesLocked(sheet)
+	if err != nil {
+		return "", err
+	}
+	for i := len(shapes) - 1; i >= 0; i-- {
+		if shapes[i].Cell == cell {
+			return shapes[i].Name, nil
+		}
+	}
+	return "", nil
+}
+
+// GetShapes lista as formas e caixas de texto de uma planilha
+func (c *ExcelizeClient) GetShapes(sheet string) ([]ShapeInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shapes, _, err := c.getShapesLocked(sheet)
+	return shapes, err
+}
+
+// DeleteShape remove uma forma pelo nome ou pela célula de ancoragem
+func (c *ExcelizeClient) DeleteShape(sheet, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shapes, drawingPart, err := c.getShapesLocked(sheet)
+	if err != nil {
+		return err
+	}
+
+	// Prioridade: nome, célula de ancoragem, texto
+	target := strings.TrimSpace(name)
+	var matches []ShapeInfo
+	for _, match := range []func(ShapeInfo) bool{
+		func(si ShapeInfo) bool { return strings.EqualFold(si.Name, target) },
+		func(si ShapeInfo) bool { return strings.EqualFold(si.Cell, strings.ReplaceAll(target, "$", "")) },
+		func(si ShapeInfo) bool { return si.Text != "" && strings.EqualFold(si.Text, target) },
+	} {
+		for _, shape := range shapes {
+			if match(shape) {
+				matches = append(matches, shape)
+			}
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("shape not found: %s", name)
+	case len(matches) > 1:
+		names := make([]string, len(matches))
+		for i, shape := range matches {
+			names[i] = shape.Name
+		}
+		return fmt.Errorf("more than one shape matches %q, use the shape name (%s)", name, strings.Join(names, ", "))
+	}
+	shape := matches[0]
+
+	content, ok := c.file.Pkg.Load(drawingPart)
+	if !ok {
+		return fmt.Errorf("part not found: %s", drawingPart)
+	}
+	removed := false
+	patched := drawingAnchorBlock.ReplaceAllFunc(content.([]byte), func(block []byte) []byte {
+		if removed {
+			return block
+		}
+		var anchor xmlDrawingAnchor
+		if err := xml.Unmarshal(block, &anchor); err != nil || anchor.Shape == nil {
+			return block
+		}
+		if anchor.Shape.CNvPr.Name != shape.Name || anchor.anchorCell() != shape.Cell {
+			return block
+		}
+		removed = true
+		return nil
+	})
+	if !removed {
+		return fmt.Errorf("shape not found: %s", name)
+	}
+
+	c.file.Pkg.Store(drawingPart, patched)
+	// Descarta o desenho carregado para que a próxima leitura use o XML alterado
+	c.file.Drawings.Delete(drawingPart)
+	return nil
+}
+
+// getShapesLocked lê as formas do desenho da planilha (chamar com c.mu)
+func (c *ExcelizeClient) getShapesLocked(sheet string) ([]ShapeInfo, string, error) {
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return nil, "", err
+	}
+	sheetPart, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return nil, "", err
+	}
+	drawingPart := pkg.drawingPart(sheetPart)
+	if drawingPart == "" {
+		return []ShapeInfo{}, "", nil
+	}
+	anchors, err := pkg.drawingAnchors(drawingPart)
+	if err != nil {
+		return nil, "", err
+	}
+
+	shapes := []ShapeInfo{}
+	for _, anchor := range anchors {
+		if anchor.Shape == nil {
+			continue
+		}
+		lines := make([]string, len(anchor.Shape.Paragraphs))
+		for i, paragraph := range anchor.Shape.Paragraphs {
+			lines[i] = strings.Join(paragraph.Runs, "")
+		}
+		shapes = append(shapes, ShapeInfo{
+			Name: anchor.Shape.CNvPr.Name,
+			Cell: anchor.anchorCell(),
+			Type: anchor.Shape.Geometry.Prst,
+			Text: strings.TrimSpace(strings.Join(lines, "\n")),
+		})
+	}
+	return shapes, drawingPart, nil
+}
+
+// objectCell normaliza a célula de ancoragem de um objeto (padrão A1)
+func objectCell(cell string) (string, error) {
+	cell = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(cell), "$", ""))
+	if cell == "" {
+		return "A1", nil
+	}
+	if _, _, err := excelize.CellNameToCoordinates(cell); err != nil {
+		return "", fmt.Errorf("invalid cell: %s", cell)
+	}
+	return cell, nil
+}
+
+// loadImage lê o conteúdo da imagem e identifica seu formato. Caminhos ficam
+// restritos à pasta do arquivo aberto (dir)
+func loadImage(spec ImageSpec, dir string) ([]byte, string, error) {
+	format := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(spec.Format), "."))
+
+	var data []byte
+	switch {
+	case spec.Path != "":
+		path, err := imagePath(spec.Path, dir)
+		if err != nil {
+			return nil, "", err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read image: %w", err)
+		}
+		data = content
+		if format == "" {
+			format = strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+		}
+	case spec.Base64 != "":
+		encoded := strings.TrimSpace(spec.Base64)
+		if m := imageDataURI.FindStringSubmatch(encoded); m != nil {
+			encoded = encoded[len(m[0]):]
+			if format == "" {
+				format = strings.ToLower(m[1])
+			}
+		}
+		encoded = strings.Join(strings.Fields(encoded), "")
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			if decoded, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+				return nil, "", fmt.Errorf("invalid base64 image: %w", err)
+			}
+		}
+		data = decoded
+	default:
+		return nil, "", fmt.Errorf("image requires path or base64")
+	}
+
+	detected := sniffImage(data)
+	if detected == "" {
+		return nil, "", fmt.Errorf("content is not a supported image")
+	}
+	if format == "" {
+		format = detected
+	}
+	return data, imageFormat(format), nil
+}
+
+// imagePath resolve o caminho da imagem (relativo à pasta do arquivo aberto) e
+// recusa arquivos fora dessa pasta, inclusive por links simbólicos
+func imagePath(path, dir string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("image path requires a saved workbook; send the image as base64")
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve workbook folder: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve workbook folder: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("image path must be inside the workbook folder: %s", dir)
+	}
+	return resolved, nil
+}
+
+// imageSignatures assinaturas dos formatos que image.DecodeConfig e
+// http.DetectContentType não reconhecem
+var imageSignatures = []struct {
+	offset int
+	magic  string
+	format string
+}{
+	{0, "II*\x00", "tiff"},
+	{0, "MM\x00*", "tiff"},
+	{0, "\xd7\xcd\xc6\x9a", "wmf"}, // Metafile com cabeçalho "placeable"
+	{0, "\x01\x00\x09\x00", "wmf"},
+	{0, "\x02\x00\x09\x00", "wmf"},
+	{40, " EMF", "emf"},
+	{0, "\x1f\x8b", "emz"}, // EMZ/WMZ: metafile compactado com gzip
+}
+
+// sniffImage identifica o formato pelo conteúdo ("" quando não é uma imagem)
+func sniffImage(data []byte) string {
+	if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return format
+	}
+	if contentType := http.DetectContentType(data); strings.HasPrefix(contentType, "image/") {
+		return strings.TrimPrefix(contentType, "image/")
+	}
+	for _, sig := range imageSignatures {
+		if len(data) >= sig.offset+len(sig.magic) && string(data[sig.offset:sig.offset+len(sig.magic)]) == sig.magic {
+			return sig.format
+		}
+	}
+	head := data
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	if bytes.Contains(bytes.ToLower(head), []byte("<svg")) {
+		return "svg"
+	}
+	return ""
+}
+
+// imageFormat normaliza tipos MIME (image/svg+xml, image/x-icon) na extensão
+func imageFormat(format string) string {
+	format = strings.TrimPrefix(strings.TrimSuffix(format, "+xml"), "x-")
+	if format == "icon" {
+		format = "ico"
+	}
+	return format
+}
+
+// imageScale converte largura/altura em pixels na escala usada pelo Excelize
+func imageScale(data []byte, spec ImageSpec, lockAspect bool) (float64, float64, error) {
+	if spec.Width <= 0 && spec.Height <= 0 {
+		scale := spec.Scale
+		if scale <= 0 {
+			scale = 1
+		}
+		return scale, scale, nil
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return 0, 0, fmt.Errorf("cannot read image dimensions, use scale instead of width/height")
+	}
+	scaleX := float64(spec.Width) / float64(config.Width)
+	scaleY := float64(spec.Height) / float64(config.Height)
+	switch {
+	case spec.Width <= 0 && lockAspect:
+		scaleX = scaleY
+	case spec.Width <= 0:
+		scaleX = 1
+	case spec.Height <= 0 && lockAspect:
+		scaleY = scaleX
+	case spec.Height <= 0:
+		scaleY = 1
+	}
+	return scaleX, scaleY, nil
+}
@@ -0,0 +1,81 @@
+package excel
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	pngData := buf.Bytes()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "planilhas")
+	files := map[string][]byte{
+		filepath.Join(dir, "logo.png"):         pngData,
+		filepath.Join(dir, "logo"):             pngData,
+		filepath.Join(dir, "notas.txt"):        []byte("apenas texto"),
+		filepath.Join(dir, "desenho.svg"):      []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"/>`),
+		filepath.Join(root, "segredo.png"):     pngData,
+		filepath.Join(dir, "sub", "icone.png"): pngData,
+	}
+	for path, data := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.Symlink(filepath.Join(root, "segredo.png"), filepath.Join(dir, "atalho.png")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		spec       ImageSpec
+		dir        string
+		wantFormat string
+		wantErr    bool
+	}{
+		{name: "caminho relativo", spec: ImageSpec{Path: "logo.png"}, dir: dir, wantFormat: "png"},
+		{name: "subpasta", spec: ImageSpec{Path: filepath.Join("sub", "icone.png")}, dir: dir, wantFormat: "png"},
+		{name: "caminho absoluto na pasta", spec: ImageSpec{Path: filepath.Join(dir, "logo.png")}, dir: dir, wantFormat: "png"},
+		{name: "formato pelo conteúdo", spec: ImageSpec{Path: "logo"}, dir: dir, wantFormat: "png"},
+		{name: "svg", spec: ImageSpec{Path: "desenho.svg"}, dir: dir, wantFormat: "svg"},
+		{name: "fora da pasta", spec: ImageSpec{Path: filepath.Join("..", "segredo.png")}, dir: dir, wantErr: true},
+		{name: "absoluto fora da pasta", spec: ImageSpec{Path: filepath.Join(root, "segredo.png")}, dir: dir, wantErr: true},
+		{name: "link simbólico para fora da pasta", spec: ImageSpec{Path: "atalho.png"}, dir: dir, wantErr: true},
+		{name: "sem arquivo aberto", spec: ImageSpec{Path: filepath.Join(dir, "logo.png")}, wantErr: true},
+		{name: "arquivo que não é imagem", spec: ImageSpec{Path: "notas.txt"}, dir: dir, wantErr: true},
+		{name: "base64", spec: ImageSpec{Base64: base64.StdEncoding.EncodeToString(pngData)}, wantFormat: "png"},
+		{name: "data URI", spec: ImageSpec{Base64: "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(files[filepath.Join(dir, "desenho.svg")])}, wantFormat: "svg"},
+		{name: "base64 que não é imagem", spec: ImageSpec{Base64: base64.StdEncoding.EncodeToString([]byte("apenas texto"))}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, format, err := loadImage(tt.spec, tt.dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("esperava erro para %+v", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadImage retornou erro: %v", err)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("formato = %q, esperado %q", format, tt.wantFormat)
+			}
+		})
+	}
+}
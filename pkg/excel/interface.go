@@ -8,20 +8,36 @@ type ExcelClient interface {
 	CreateSheet(name string) error
 	DeleteSheet(name string) error
 	RenameSheet(oldName, newName string) error
+	CopySheet(src, name string) (string, error)
 	HideSheet(sheet string) error
 	ShowSheet(sheet string) error
+	GetSheets() ([]SheetInfo, error)
+	MoveSheet(sheet string, position int) error
+	SetActiveSheet(sheet string) error
+	SetSheetVisibility(sheet, state string) error
+	SetTabColor(sheet, color string) error
+	SetSheetView(sheet string, view SheetView) error
 
 	// ==================== DATA ====================
 	GetCellValue(sheet, cell string) (string, error)
 	SetCellValue(sheet, cell string, value interface{}) error
 	GetRangeValues(sheet, rng string) ([][]string, error)
+	GetVisibleRangeValues(sheet, rng string) ([][]string, error)
 	WriteRange(sheet, startCell string, data [][]interface{}) error
 	ClearRange(sheet, rng string) error
+	CopyRange(sheet, src, dest string, opts PasteOptions) (string, error)
+	MoveRange(sheet, src, dest string) (string, error)
 
 	// ==================== FORMATTING ====================
 	FormatRange(sheet, rng string, format Format) error
+	GetFormat(sheet, rng string) (*Format, error)
+	GetRangeFormats(sheet, rng string) (*RangeFormats, error)
+	GetCellStyles(sheet, rng string) (*StyleSnapshot, error)
+	SetCellStyles(sheet string, snapshot StyleSnapshot) error
 	SetColumnWidth(sheet, col string, width float64) error
+	GetColumnWidth(sheet, col string) (float64, error)
 	SetRowHeight(sheet, row string, height float64) error
+	GetRowHeight(sheet, row string) (float64, error)
 	MergeCells(sheet, rng string) error
 	UnmergeCells(sheet, rng string) error
 	SetBorders(sheet, rng, style string) error
@@ -29,6 +45,9 @@ type ExcelClient interface {
 
 	// Conditional Formatting
 	AddSimpleConditionalFormat(sheet, rng, criteria, value, bgColor string) error
+	AddConditionalFormat(sheet, rng string, rule ConditionalFormatRule) error
+	GetConditionalFormats(sheet, rng string) ([]ConditionalFormatRule, error)
+	RemoveConditionalFormats(sheet, rng string) (int, error)
 
 	// ==================== STRUCTURE ====================
 	InsertRows(sheet string, row, count int) error
@@ -36,28 +55,61 @@ type ExcelClient interface {
 	FreezePane(sheet, cell string, freezeRows, freezeCols int) error
 	UnfreezePane(sheet string) error
 	GroupRows(sheet string, startRow, endRow int) error
+	InsertColumns(sheet string, spec ColumnInsert) (string, error)
+	DeleteColumns(sheet, column string, count int) (string, error)
+	MoveColumns(sheet, column, before, after string) (string, error)
+	ColumnRange(sheet, column string, count int) (string, error)
 	GroupColumns(sheet, startCol, endCol string) error
+	UngroupColumns(sheet, startCol, endCol string) error
 
 	// ==================== OBJECTS ====================
 	CreateChart(sheet, rng, chartType, title string) error
+	CreateChartSpec(sheet string, spec ChartSpec) error
 	DeleteChart(sheet, name string) error
 	ListCharts(sheet string) ([]string, error)
+	GetCharts(sheet string) ([]ChartInfo, error)
+	InsertImage(sheet string, spec ImageSpec) error
+	GetImages(sheet string) ([]ImageInfo, error)
+	DeleteImage(sheet, cell string) error
+	AddShape(sheet string, spec ShapeSpec) (string, error)
+	GetShapes(sheet string) ([]ShapeInfo, error)
+	DeleteShape(sheet, name string) error
 	CreateTable(sheet, rng, name, style string) error
 	DeleteTable(sheet, name string) error
 	ListTables(sheet string) ([]string, error)
+	GetTables(sheet string) ([]TableInfo, error)
+	ResizeTable(name, rng string) (string, error)
+	SetTableTotals(name string, show bool, totals []TableTotal) error
+	AppendTableRows(name string, rows []map[string]interface{}) (string, error)
 	CreatePivotTable(srcSheet, srcRange, destSheet, destCell, name string) error
+	CreatePivotTableWithFields(spec PivotSpec) error
+	GetPivotTables(sheet string) ([]PivotSpec, error)
+	UpdatePivotTable(sheet, name string, changes PivotSpec) error
 	ListPivotTables(sheet string) ([]string, error)
 	DeletePivotTable(sheet, name string) error
 
 	// ==================== FILTERS & SORT ====================
 	ApplyFilter(sheet, rng string) error
+	ApplyAutoFilter(sheet, rng string, criteria []FilterCriterion) (int, error)
 	ClearFilters(sheet string) error
 	HasFilter(sheet string) (bool, error)
-	SortRange(sheet, rng string, col int, ascending bool) error
+	GetAutoFilter(sheet string) (*AutoFilterInfo, error)
+	SortRange(sheet, rng string, opts SortOptions) ([]int, error)
+	ReorderRows(sheet, rng string, order []int) error
+
+	// ==================== DEFINED NAMES ====================
+	GetDefinedNames() ([]DefinedName, error)
+	CreateDefinedName(sheet string, name DefinedName) error
+	UpdateDefinedName(sheet, name, scope string, changes DefinedName) error
+	DeleteDefinedName(name, scope string) error
 
 	// ==================== VALIDATION ====================
 	AddDataValidation(sheet, rng, validationType string, options []string) error
 	AddDropdownList(sheet, rng string, options []string) error
+	AddValidation(sheet, rng string, rule DataValidationRule) error
+	GetValidations(sheet, rng string) ([]DataValidationRule, error)
+	RemoveValidations(sheet, rng string) (int, error)
+	FindInvalidCells(sheet, rng string) ([]ValidationViolation, error)
 
 	// ==================== COMMENTS ====================
 	AddCellComment(sheet, cell, author, text string) error
@@ -77,7 +129,10 @@ type ExcelClient interface {
 	// ==================== FORMULAS ====================
 	SetCellFormula(sheet, cell, formula string) error
 	GetCellFormula(sheet, cell string) (string, error)
-	CalculateFormulas() error
+	CalculateFormulas() (*RecalcResult, error)
+	GetPrecedents(sheet, rng string) ([]FormulaPrecedents, error)
+	GetDependents(sheet, rng string) ([]FormulaDependent, error)
+	GetChangeImpact(sheet, rng string) (*ChangeImpact, error)
 
 	// ==================== QUERY ====================
 	GetUsedRange(sheet string) (string, error)
@@ -87,6 +142,16 @@ type ExcelClient interface {
 
 	// ==================== PRINT ====================
 	SetPrintArea(sheet, rng string) error
+	SetPageSetup(sheet string, setup PageSetup) error
+	GetPageSetup(sheet string) (*PageSetup, error)
+	InsertPageBreak(sheet, cell string) error
+	RemovePageBreak(sheet, cell string) error
+
+	// ==================== PROPERTIES ====================
+	GetWorkbookProperties() (*WorkbookProperties, error)
+	SetWorkbookProperties(props WorkbookProperties) error
+	StampProvenance(p Provenance) error
+	GetWorkbookInfo() (*WorkbookInfo, error)
 
 	// ==================== LIFECYCLE ====================
 	Close()
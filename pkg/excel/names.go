@@ -0,0 +1,260 @@
+package excel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// definedNamePattern nomes válidos: começam com letra, _ ou \ e não têm espaços
+var definedNamePattern = regexp.MustCompile(`^[\p{L}_\\][\p{L}\p{N}_.\\]*$`)
+
+// r1c1Name nomes confundidos com referências R1C1 (R, C, R2C3...), rejeitados pelo Excel
+var r1c1Name = regexp.MustCompile(`^(?i)(r[0-9]*c?[0-9]*|c[0-9]*)$`)
+
+// workbookScope escopo devolvido pelo Excelize para nomes da pasta de trabalho
+const workbookScope = "Workbook"
+
+// GetDefinedNames lista os nomes definidos da pasta e das planilhas; nomes internos
+// do Excel (área de impressão, filtros) não são listados
+func (c *ExcelizeClient) GetDefinedNames() ([]DefinedName, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := []DefinedName{}
+	for _, dn := range c.file.GetDefinedName() {
+		if strings.HasPrefix(strings.ToLower(dn.Name), "_xlnm.") {
+			continue
+		}
+		names = append(names, definedNameFrom(dn))
+	}
+	return names, nil
+}
+
+// CreateDefinedName cria um nome definido. Referências sem planilha em RefersTo
+// apontam para a planilha do escopo ou, em nomes da pasta, para sheet.
+func (c *ExcelizeClient) CreateDefinedName(sheet string, name DefinedName) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dn, err := c.definedNameLocked(sheet, name)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.findDefinedNameLocked(dn.Name, dn.Scope); ok {
+		return fmt.Errorf("defined name already exists: %s", dn.Name)
+	}
+	return c.file.SetDefinedName(dn)
+}
+
+// UpdateDefinedName altera o nome, a referência ou o comentário de um nome definido
+// (scope vazio: nome da pasta). Ao renomear, as fórmulas que usam o nome são atualizadas.
+func (c *ExcelizeClient) UpdateDefinedName(sheet, name, scope string, changes DefinedName) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, ok := c.findDefinedNameLocked(name, scope)
+	if !ok {
+		return fmt.Errorf("defined name not found: %s", name)
+	}
+
+	updated := definedNameFrom(current)
+	if changes.Name != "" {
+		updated.Name = changes.Name
+	}
+	if changes.RefersTo != "" {
+		updated.RefersTo = changes.RefersTo
+	}
+	if changes.Comment != "" {
+		updated.Comment = changes.Comment
+	}
+	dn, err := c.definedNameLocked(sheet, updated)
+	if err != nil {
+		return err
+	}
+	renamed := !strings.EqualFold(dn.Name, current.Name)
+	if renamed {
+		if _, exists := c.findDefinedNameLocked(dn.Name, dn.Scope); exists {
+			return fmt.Errorf("defined name already exists: %s", dn.Name)
+		}
+	}
+
+	if err := c.file.DeleteDefinedName(&excelize.DefinedName{Name: current.Name, Scope: dn.Scope}); err != nil {
+		return err
+	}
+	if err := c.file.SetDefinedName(dn); err != nil {
+		return err
+	}
+	if renamed {
+		return c.renameInFormulasLocked(current.Name, dn.Name, dn.Scope)
+	}
+	return nil
+}
+
+// DeleteDefinedName remove um nome definido (scope vazio: nome da pasta). Fórmulas
+// que usam o nome passam a exibir #NAME?.
+func (c *ExcelizeClient) DeleteDefinedName(name, scope string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, ok := c.findDefinedNameLocked(name, scope)
+	if !ok {
+		return fmt.Errorf("defined name not found: %s", name)
+	}
+	if current.Scope == workbookScope {
+		current.Scope = ""
+	}
+	return c.file.DeleteDefinedName(&current)
+}
+
+// findDefinedNameLocked localiza um nome definido sem diferenciar maiúsculas
+func (c *ExcelizeClient) findDefinedNameLocked(name, scope string) (excelize.DefinedName, bool) {
+	if scope == "" {
+		scope = workbookScope
+	}
+	for _, dn := range c.file.GetDefinedName() {
+		if strings.EqualFold(dn.Name, name) && strings.EqualFold(dn.Scope, scope) {
+			return dn, true
+		}
+	}
+	return excelize.DefinedName{}, false
+}
+
+// definedNameLocked valida o nome e qualifica as referências de RefersTo com a
+// planilha, em forma absoluta ('Plan1'!$A$1:$B$10)
+func (c *ExcelizeClient) definedNameLocked(sheet string, name DefinedName) (*excelize.DefinedName, error) {
+	if !definedNamePattern.MatchString(name.Name) || len(name.Name) > 255 || r1c1Name.MatchString(name.Name) {
+		return nil, fmt.Errorf("invalid defined name: %s", name.Name)
+	}
+	if _, _, err := excelize.CellNameToCoordinates(name.Name); err == nil {
+		return nil, fmt.Errorf("invalid defined name (looks like a cell reference): %s", name.Name)
+	}
+
+	sheets := c.file.GetSheetList()
+	canonical := func(s string) (string, bool) {
+		for _, existing := range sheets {
+			if strings.EqualFold(existing, s) {
+				return existing, true
+			}
+		}
+		return "", false
+	}
+
+	scope := ""
+	if name.Scope != "" && !strings.EqualFold(name.Scope, workbookScope) {
+		var ok bool
+		if scope, ok = canonical(name.Scope); !ok {
+			return nil, fmt.Errorf("sheet %s does not exist", name.Scope)
+		}
+		sheet = scope
+	}
+
+	refersTo := strings.TrimPrefix(strings.TrimSpace(name.RefersTo), "=")
+	if refersTo == "" {
+		return nil, fmt.Errorf("defined name %s requires refersTo", name.Name)
+	}
+	var missing string
+	refersTo = rewriteFormulaRefs(refersTo, func(refSheet, ref string) string {
+		if refSheet == "" {
+			refSheet = sheet
+		}
+		existing, ok := canonical(refSheet)
+		if !ok {
+			missing = refSheet
+			return ref
+		}
+		return quoteSheetName(existing) + "!" + absoluteA1(ref)
+	})
+	if missing != "" {
+		return nil, fmt.Errorf("sheet %s does not exist", missing)
+	}
+
+	return &excelize.DefinedName{Name: name.Name, RefersTo: refersTo, Scope: scope, Comment: name.Comment}, nil
+}
+
+// renameInFormulasLocked troca o nome definido nas fórmulas das células e dos
+// demais nomes; com scope, apenas nas fórmulas da planilha do escopo
+func (c *ExcelizeClient) renameInFormulasLocked(oldName, newName, scope string) error {
+	pattern := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}_.\\!\[\]'])` + regexp.QuoteMeta(oldName) + `($|[^\p{L}\p{N}_.\\(!\[])`)
+	rename := func(formula string) string {
+		var out strings.Builder
+		last := 0
+		for _, lit := range append(stringLiteral.FindAllStringIndex(formula, -1), []int{len(formula), len(formula)}) {
+			segment := formula[last:lit[0]]
+			// As bordas do padrão consomem um caractere: a segunda passada cobre nomes
+			// separados por um único operador (Taxa*Taxa)
+			for range 2 {
+				segment = pattern.ReplaceAllString(segment, "${1}"+newName+"${2}")
+			}
+			out.WriteString(segment)
+			out.WriteString(formula[lit[0]:lit[1]])
+			last = lit[1]
+		}
+		return out.String()
+	}
+
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	graph, err := c.buildFormulaGraphLocked(pkg)
+	if err != nil {
+		return err
+	}
+	for _, n := range graph.Cells {
+		if scope != "" && !strings.EqualFold(n.Sheet, scope) {
+			continue
+		}
+		if formula := rename(n.Formula); formula != n.Formula {
+			if err := c.file.SetCellFormula(n.Sheet, n.Cell, formula); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, dn := range c.file.GetDefinedName() {
+		refersTo := rename(dn.RefersTo)
+		if refersTo == dn.RefersTo || (scope != "" && !strings.EqualFold(dn.Scope, scope)) {
+			continue
+		}
+		if dn.Scope == workbookScope {
+			dn.Scope = ""
+		}
+		if err := c.file.DeleteDefinedName(&dn); err != nil {
+			return err
+		}
+		dn.RefersTo = refersTo
+		if err := c.file.SetDefinedName(&dn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// definedNameFrom converte o nome do Excelize (escopo "Workbook" vira vazio)
+func definedNameFrom(dn excelize.DefinedName) DefinedName {
+	scope := dn.Scope
+	if scope == workbookScope {
+		scope = ""
+	}
+	return DefinedName{Name: dn.Name, RefersTo: dn.RefersTo, Scope: scope, Comment: dn.Comment}
+}
+
+// absoluteA1 fixa linhas e colunas de uma referência A1 (B2:C5 vira $B$2:$C$5)
+func absoluteA1(ref string) string {
+	parts := strings.Split(ref, ":")
+	for i, part := range parts {
+		if m := refEndpoint.FindStringSubmatch(part); m != nil {
+			parts[i] = ""
+			if m[2] != "" {
+				parts[i] += "$" + strings.ToUpper(m[2])
+			}
+			if m[4] != "" {
+				parts[i] += "$" + m[4]
+			}
+		}
+	}
+	return strings.Join(parts, ":")
+}
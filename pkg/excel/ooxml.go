@@ -0,0 +1,188 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ooxmlPackage partes XML do arquivo serializado. Usado para ler estruturas que o
+// Excelize mantém em tipos não exportados (desenhos, gráficos, ...).
+type ooxmlPackage struct {
+	parts map[string][]byte
+}
+
+// readPackageLocked serializa o arquivo atual e indexa suas partes (chamar com c.mu)
+func (c *ExcelizeClient) readPackageLocked() (*ooxmlPackage, error) {
+	buffer, err := c.file.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize workbook: %w", err)
+	}
+	c.flushSheetsLocked()
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workbook package: %w", err)
+	}
+
+	pkg := &ooxmlPackage{parts: make(map[string][]byte, len(reader.File))}
+	for _, file := range reader.File {
+		if !strings.HasSuffix(file.Name, ".xml") && !strings.HasSuffix(file.Name, ".rels") {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		pkg.parts[file.Name] = data
+	}
+	return pkg, nil
+}
+
+// flushSheetsLocked descarta as planilhas carregadas em memória depois de uma
+// serialização (chamar com c.mu). A serialização remove as células vazias das linhas
+// carregadas; planilhas criadas nesta sessão continuariam em memória sem as posições
+// que o Excelize espera e as próximas gravações cairiam na célula errada. Elas são
+// relidas do XML gravado no próximo acesso.
+func (c *ExcelizeClient) flushSheetsLocked() {
+	c.file.Sheet.Range(func(part, _ interface{}) bool {
+		c.file.Sheet.Delete(part)
+		return true
+	})
+}
+
+// decode faz o unmarshal de uma parte
+func (p *ooxmlPackage) decode(part string, v interface{}) error {
+	data, ok := p.parts[part]
+	if !ok {
+		return fmt.Errorf("part not found: %s", part)
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// rels retorna as relações de uma parte (id -> caminho absoluto no pacote)
+func (p *ooxmlPackage) rels(part string) map[string]string {
+	relsPath := path.Join(path.Dir(part), "_rels", path.Base(part)+".rels")
+
+	var doc struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	result := make(map[string]string)
+	if err := p.decode(relsPath, &doc); err != nil {
+		return result
+	}
+	for _, rel := range doc.Relationships {
+		if strings.HasPrefix(rel.Target, "/") {
+			result[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+		} else {
+			result[rel.ID] = path.Join(path.Dir(part), rel.Target)
+		}
+	}
+	return result
+}
+
+// sheetPart retorna o caminho da parte XML de uma planilha
+func (p *ooxmlPackage) sheetPart(sheet string) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := p.decode("xl/workbook.xml", &workbook); err != nil {
+		return "", err
+	}
+
+	rels := p.rels("xl/workbook.xml")
+	for _, s := range workbook.Sheets {
+		if strings.EqualFold(s.Name, sheet) {
+			if target, ok := rels[s.RID]; ok {
+				return target, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("sheet %s does not exist", sheet)
+}
+
+// drawingPart retorna o desenho associado à planilha ("" se não houver)
+func (p *ooxmlPackage) drawingPart(sheetPart string) string {
+	var ws struct {
+		Drawing *struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"drawing"`
+	}
+	if err := p.decode(sheetPart, &ws); err != nil || ws.Drawing == nil {
+		return ""
+	}
+	return p.rels(sheetPart)[ws.Drawing.RID]
+}
+
+// xmlVal elemento com atributo val
+type xmlVal struct {
+	Val string `xml:"val,attr"`
+}
+
+// xmlDrawingAnchor âncora de objeto em um desenho (twoCellAnchor, oneCellAnchor, ...)
+type xmlDrawingAnchor struct {
+	XMLName xml.Name
+	From    *struct {
+		Col int `xml:"col"`
+		Row int `xml:"row"`
+	} `xml:"from"`
+	GraphicFrame *struct {
+		CNvPr struct {
+			ID    int    `xml:"id,attr"`
+			Name  string `xml:"name,attr"`
+			Descr string `xml:"descr,attr"`
+		} `xml:"nvGraphicFramePr>cNvPr"`
+		Chart *struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"graphic>graphicData>chart"`
+	} `xml:"graphicFrame"`
+	Shape *struct {
+		CNvPr struct {
+			ID   int    `xml:"id,attr"`
+			Name string `xml:"name,attr"`
+		} `xml:"nvSpPr>cNvPr"`
+		Geometry   xmlPresetGeometry `xml:"spPr>prstGeom"`
+		Paragraphs []struct {
+			Runs []string `xml:"r>t"`
+		} `xml:"txBody>p"`
+	} `xml:"sp"`
+}
+
+// xmlPresetGeometry geometria predefinida de uma forma (rect, ellipse, ...)
+type xmlPresetGeometry struct {
+	Prst string `xml:"prst,attr"`
+}
+
+// anchorCell célula de ancoragem ("" para absoluteAnchor)
+func (a xmlDrawingAnchor) anchorCell() string {
+	if a.From == nil {
+		return ""
+	}
+	return indicesToCell(a.From.Row, a.From.Col)
+}
+
+// drawingAnchors lê as âncoras de um desenho
+func (p *ooxmlPackage) drawingAnchors(drawingPart string) ([]xmlDrawingAnchor, error) {
+	var doc struct {
+		Anchors []xmlDrawingAnchor `xml:",any"`
+	}
+	if err := p.decode(drawingPart, &doc); err != nil {
+		return nil, err
+	}
+	return doc.Anchors, nil
+}
@@ -0,0 +1,597 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Nomes internos da configuração de impressão
+const (
+	printAreaName   = "_xlnm.Print_Area"
+	printTitlesName = "_xlnm.Print_Titles"
+)
+
+// cmPerInch conversão das margens (o arquivo guarda polegadas)
+const cmPerInch = 2.54
+
+// paperSizes tamanhos de papel aceitos (nome -> código OOXML)
+var paperSizes = map[string]int{
+	"letter": 1, "tabloid": 3, "legal": 5, "executive": 7,
+	"a3": 8, "a4": 9, "a5": 11, "b4": 12, "b5": 13,
+}
+
+// headerFooterTokens atalhos aceitos em cabeçalhos e rodapés
+var headerFooterTokens = strings.NewReplacer(
+	"{page}", "&P", "{pages}", "&N", "{date}", "&D", "{time}", "&T", "{file}", "&F", "{sheet}", "&A",
+)
+
+var (
+	// titleRowsPattern linhas repetidas (ex: 1:2, $1:$1, 3)
+	titleRowsPattern = regexp.MustCompile(`^\$?(\d+)(?::\$?(\d+))?$`)
+	// titleColumnsPattern colunas repetidas (ex: A:B, $A:$A, C)
+	titleColumnsPattern = regexp.MustCompile(`^\$?([A-Za-z]{1,3})(?::\$?([A-Za-z]{1,3}))?$`)
+	// printOptionsTag etiqueta de abertura de printOptions
+	printOptionsTag = regexp.MustCompile(`<printOptions\b[^>]*>`)
+	// rowBreaksBlock e colBreaksBlock quebras de página manuais
+	rowBreaksBlock = regexp.MustCompile(`(?s)<rowBreaks\b[^>]*?(?:/>|>.*?</rowBreaks>)`)
+	colBreaksBlock = regexp.MustCompile(`(?s)<colBreaks\b[^>]*?(?:/>|>.*?</colBreaks>)`)
+	// pageBreakTag, pageBreakID e pageBreakManual quebra individual (brk)
+	pageBreakTag    = regexp.MustCompile(`<brk\b[^>]*?(?:/>|>\s*</brk>)`)
+	pageBreakID     = regexp.MustCompile(`\sid="(\d+)"`)
+	pageBreakManual = regexp.MustCompile(`\sman="(?:1|true)"`)
+)
+
+// SetPageSetup altera a configuração de impressão de uma planilha
+func (c *ExcelizeClient) SetPageSetup(sheet string, setup PageSetup) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.file.GetSheetIndex(sheet); err != nil {
+		return err
+	}
+
+	// Valida tudo antes de alterar para não aplicar a configuração pela metade
+	var layout excelize.PageLayoutOptions
+	if setup.Orientation != "" {
+		orientation := strings.ToLower(strings.TrimSpace(setup.Orientation))
+		if orientation != "portrait" && orientation != "landscape" {
+			return fmt.Errorf("invalid orientation: %s (use portrait or landscape)", setup.Orientation)
+		}
+		layout.Orientation = &orientation
+	}
+	if setup.PaperSize != "" {
+		size, ok := paperSizes[strings.ToLower(strings.TrimSpace(setup.PaperSize))]
+		if !ok {
+			return fmt.Errorf("unsupported paper size: %s", setup.PaperSize)
+		}
+		layout.Size = &size
+	}
+	if setup.Scale != 0 {
+		if setup.Scale < 10 || setup.Scale > 400 {
+			return fmt.Errorf("invalid scale: %d (use 10 to 400)", setup.Scale)
+		}
+		if setup.FitToWidth != nil || setup.FitToHeight != nil {
+			return fmt.Errorf("use either scale or fitToWidth/fitToHeight")
+		}
+		scale := uint(setup.Scale)
+		layout.AdjustTo = &scale
+	}
+	for _, pages := range []*int{setup.FitToWidth, setup.FitToHeight} {
+		if pages != nil && *pages < 0 {
+			return fmt.Errorf("invalid number of pages: %d", *pages)
+		}
+	}
+	layout.FitToWidth, layout.FitToHeight = setup.FitToWidth, setup.FitToHeight
+	layout.BlackAndWhite = setup.BlackAndWhite
+	if setup.FirstPageNumber > 0 {
+		first := uint(setup.FirstPageNumber)
+		layout.FirstPageNumber = &first
+	}
+	if setup.PageOrder != "" {
+		order := strings.TrimSpace(setup.PageOrder)
+		switch strings.ToLower(order) {
+		case "downthenover":
+			order = "downThenOver"
+		case "overthendown":
+			order = "overThenDown"
+		default:
+			return fmt.Errorf("invalid page order: %s (use downThenOver or overThenDown)", setup.PageOrder)
+		}
+		layout.PageOrder = &order
+	}
+
+	margins := excelize.PageLayoutMarginsOptions{
+		Horizontally: setup.CenterHorizontally,
+		Vertically:   setup.CenterVertically,
+	}
+	if m := setup.Margins; m != nil {
+		// O Excelize zera as margens não informadas quando o elemento ainda não existe
+		current, err := c.file.GetPageMargins(sheet)
+		if err != nil {
+			return err
+		}
+		margins.Top, margins.Bottom, margins.Left = current.Top, current.Bottom, current.Left
+		margins.Right, margins.Header, margins.Footer = current.Right, current.Header, current.Footer
+		for _, field := range []struct {
+			cm   *float64
+			dest **float64
+		}{
+			{m.Top, &margins.Top}, {m.Bottom, &margins.Bottom}, {m.Left, &margins.Left},
+			{m.Right, &margins.Right}, {m.Header, &margins.Header}, {m.Footer, &margins.Footer},
+		} {
+			if field.cm == nil {
+				continue
+			}
+			if *field.cm < 0 {
+				return fmt.Errorf("invalid margin: %g", *field.cm)
+			}
+			inches := *field.cm / cmPerInch
+			*field.dest = &inches
+		}
+	}
+
+	var printArea, titles *string
+	if setup.PrintArea != nil {
+		area := ""
+		if text := strings.TrimSpace(*setup.PrintArea); text != "" {
+			var err error
+			if area, err = c.printAreaLocked(sheet, text); err != nil {
+				return err
+			}
+		}
+		printArea = &area
+	}
+	if setup.TitleRows != nil || setup.TitleColumns != nil {
+		current := c.pageSetupNamesLocked(sheet)
+		rows, cols := current.TitleRows, current.TitleColumns
+		if setup.TitleRows != nil {
+			rows = setup.TitleRows
+		}
+		if setup.TitleColumns != nil {
+			cols = setup.TitleColumns
+		}
+		refersTo, err := printTitles(sheet, rows, cols)
+		if err != nil {
+			return err
+		}
+		titles = &refersTo
+	}
+
+	// Aplica
+	if err := c.file.SetPageLayout(sheet, &layout); err != nil {
+		return err
+	}
+	// O ajuste por páginas só vale com fitToPage; a escala, sem ele
+	switch {
+	case setup.FitToWidth != nil || setup.FitToHeight != nil:
+		if err := c.file.SetSheetProps(sheet, &excelize.SheetPropsOptions{FitToPage: boolPtr(true)}); err != nil {
+			return err
+		}
+	case setup.Scale != 0:
+		if err := c.file.SetSheetProps(sheet, &excelize.SheetPropsOptions{FitToPage: boolPtr(false)}); err != nil {
+			return err
+		}
+	}
+	if err := c.file.SetPageMargins(sheet, &margins); err != nil {
+		return err
+	}
+
+	if setup.Header != nil || setup.Footer != nil {
+		headerFooter, err := c.file.GetHeaderFooter(sheet)
+		if err != nil {
+			return err
+		}
+		if headerFooter == nil {
+			headerFooter = &excelize.HeaderFooterOptions{}
+		}
+		if setup.Header != nil {
+			headerFooter.OddHeader = headerFooterText(*setup.Header)
+		}
+		if setup.Footer != nil {
+			headerFooter.OddFooter = headerFooterText(*setup.Footer)
+		}
+		if headerFooter.OddHeader == "" && headerFooter.OddFooter == "" && !headerFooter.DifferentFirst && !headerFooter.DifferentOddEven {
+			headerFooter = nil
+		}
+		if err := c.file.SetHeaderFooter(sheet, headerFooter); err != nil {
+			return fmt.Errorf("invalid header/footer: %w", err)
+		}
+	}
+
+	if printArea != nil {
+		if err := c.setSheetNameLocked(sheet, printAreaName, *printArea); err != nil {
+			return err
+		}
+	}
+	if titles != nil {
+		if err := c.setSheetNameLocked(sheet, printTitlesName, *titles); err != nil {
+			return err
+		}
+	}
+
+	if setup.Gridlines != nil || setup.Headings != nil {
+		return c.setPrintOptionsLocked(sheet, setup.Gridlines, setup.Headings)
+	}
+	return nil
+}
+
+// GetPageSetup retorna a configuração de impressão de uma planilha
+func (c *ExcelizeClient) GetPageSetup(sheet string) (*PageSetup, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	layout, err := c.file.GetPageLayout(sheet)
+	if err != nil {
+		return nil, err
+	}
+	props, err := c.file.GetSheetProps(sheet)
+	if err != nil {
+		return nil, err
+	}
+	margins, err := c.file.GetPageMargins(sheet)
+	if err != nil {
+		return nil, err
+	}
+	headerFooter, err := c.file.GetHeaderFooter(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	setup := c.pageSetupNamesLocked(sheet)
+	setup.Orientation = *layout.Orientation
+	for name, size := range paperSizes {
+		if layout.Size != nil && *layout.Size == size {
+			setup.PaperSize = strings.ToUpper(name[:1]) + name[1:]
+		}
+	}
+	if props.FitToPage != nil && *props.FitToPage {
+		// Sem valor gravado, o Excel usa uma página
+		width, height := 1, 1
+		if layout.FitToWidth != nil {
+			width = *layout.FitToWidth
+		}
+		if layout.FitToHeight != nil {
+			height = *layout.FitToHeight
+		}
+		setup.FitToWidth, setup.FitToHeight = &width, &height
+	} else {
+		setup.Scale = int(*layout.AdjustTo)
+	}
+	if layout.FirstPageNumber != nil && *layout.FirstPageNumber > 1 {
+		setup.FirstPageNumber = int(*layout.FirstPageNumber)
+	}
+	if layout.PageOrder != nil {
+		setup.PageOrder = *layout.PageOrder
+	}
+	setup.BlackAndWhite = layout.BlackAndWhite
+
+	cm := func(inches *float64) *float64 {
+		if inches == nil {
+			return nil
+		}
+		value := math.Round(*inches*cmPerInch*100) / 100
+		return &value
+	}
+	setup.Margins = &PageMargins{
+		Top: cm(margins.Top), Bottom: cm(margins.Bottom), Left: cm(margins.Left),
+		Right: cm(margins.Right), Header: cm(margins.Header), Footer: cm(margins.Footer),
+	}
+	setup.CenterHorizontally = boolPtr(margins.Horizontally != nil && *margins.Horizontally)
+	setup.CenterVertically = boolPtr(margins.Vertically != nil && *margins.Vertically)
+	if headerFooter != nil {
+		if headerFooter.OddHeader != "" {
+			setup.Header = &headerFooter.OddHeader
+		}
+		if headerFooter.OddFooter != "" {
+			setup.Footer = &headerFooter.OddFooter
+		}
+	}
+
+	// Linhas de grade, títulos de linha/coluna e quebras não têm leitura no Excelize
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return nil, err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return nil, err
+	}
+	var ws xmlPrintSettings
+	if err := pkg.decode(part, &ws); err != nil {
+		return nil, err
+	}
+	setup.Gridlines, setup.Headings = boolPtr(false), boolPtr(false)
+	if ws.PrintOptions != nil {
+		setup.Gridlines, setup.Headings = boolPtr(ws.PrintOptions.GridLines), boolPtr(ws.PrintOptions.Headings)
+	}
+	for _, brk := range ws.RowBreaks {
+		setup.RowBreaks = append(setup.RowBreaks, brk.ID+1)
+	}
+	sort.Ints(setup.RowBreaks)
+	sort.Slice(ws.ColBreaks, func(i, j int) bool { return ws.ColBreaks[i].ID < ws.ColBreaks[j].ID })
+	for _, brk := range ws.ColBreaks {
+		name, _ := excelize.ColumnNumberToName(brk.ID + 1)
+		setup.ColumnBreaks = append(setup.ColumnBreaks, name)
+	}
+	return &setup, nil
+}
+
+// InsertPageBreak insere quebras de página antes da linha e/ou coluna da célula
+func (c *ExcelizeClient) InsertPageBreak(sheet, cell string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cell = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(cell), "$", ""))
+	if cell == "A1" {
+		return fmt.Errorf("page break cannot start before A1")
+	}
+	if err := c.file.InsertPageBreak(sheet, cell); err != nil {
+		return fmt.Errorf("failed to insert page break: %w", err)
+	}
+	return nil
+}
+
+// RemovePageBreak remove as quebras de página da linha e da coluna da célula ("" remove todas as
+// quebras manuais). As quebras são reescritas no XML porque o Excelize deixa contagens inválidas.
+func (c *ExcelizeClient) RemovePageBreak(sheet, cell string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	row, col := -1, -1
+	if cell = strings.ReplaceAll(strings.TrimSpace(cell), "$", ""); cell != "" {
+		colNum, rowNum, err := excelize.CellNameToCoordinates(cell)
+		if err != nil {
+			return fmt.Errorf("invalid cell: %s", cell)
+		}
+		row, col = rowNum-1, colNum-1
+	}
+
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+	content, ok := c.file.Pkg.Load(part)
+	if !ok {
+		return fmt.Errorf("part not found: %s", part)
+	}
+
+	patched := string(content.([]byte))
+	for _, breaks := range []struct {
+		pattern *regexp.Regexp
+		tag     string
+		target  int
+	}{
+		{rowBreaksBlock, "rowBreaks", row},
+		{colBreaksBlock, "colBreaks", col},
+	} {
+		patched = breaks.pattern.ReplaceAllStringFunc(patched, func(block string) string {
+			var kept []string
+			manual := 0
+			for _, brk := range pageBreakTag.FindAllString(block, -1) {
+				id := 0
+				if m := pageBreakID.FindStringSubmatch(brk); m != nil {
+					id, _ = strconv.Atoi(m[1])
+				}
+				if cell == "" || (breaks.target > 0 && id == breaks.target) {
+					continue
+				}
+				kept = append(kept, brk)
+				if pageBreakManual.MatchString(brk) {
+					manual++
+				}
+			}
+			if len(kept) == 0 {
+				return ""
+			}
+			return fmt.Sprintf(`<%s count="%d" manualBreakCount="%d">%s</%s>`,
+				breaks.tag, len(kept), manual, strings.Join(kept, ""), breaks.tag)
+		})
+	}
+
+	c.file.Pkg.Store(part, []byte(patched))
+	// Descarta a versão carregada para que a próxima leitura use o XML alterado
+	c.file.Sheet.Delete(part)
+	return nil
+}
+
+// xmlPrintSettings opções de impressão e quebras de página da planilha
+type xmlPrintSettings struct {
+	PrintOptions *struct {
+		GridLines bool `xml:"gridLines,attr"`
+		Headings  bool `xml:"headings,attr"`
+	} `xml:"printOptions"`
+	RowBreaks []struct {
+		ID int `xml:"id,attr"`
+	} `xml:"rowBreaks>brk"`
+	ColBreaks []struct {
+		ID int `xml:"id,attr"`
+	} `xml:"colBreaks>brk"`
+}
+
+// setPrintOptionsLocked liga ou desliga a impressão de linhas de grade e títulos (chamar com c.mu)
+func (c *ExcelizeClient) setPrintOptionsLocked(sheet string, gridlines, headings *bool) error {
+	// Garante o elemento printOptions, que o Excelize cria ao centralizar a página
+	margins, err := c.file.GetPageMargins(sheet)
+	if err != nil {
+		return err
+	}
+	centered := margins.Horizontally != nil && *margins.Horizontally
+	if err := c.file.SetPageMargins(sheet, &excelize.PageLayoutMarginsOptions{Horizontally: &centered}); err != nil {
+		return err
+	}
+
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+	content, ok := c.file.Pkg.Load(part)
+	if !ok {
+		return fmt.Errorf("part not found: %s", part)
+	}
+
+	patched := printOptionsTag.ReplaceAllStringFunc(string(content.([]byte)), func(tag string) string {
+		if gridlines != nil {
+			tag = setXMLAttr(tag, "gridLines", strconv.Itoa(boolInt(*gridlines)))
+		}
+		if headings != nil {
+			tag = setXMLAttr(tag, "headings", strconv.Itoa(boolInt(*headings)))
+		}
+		return tag
+	})
+
+	c.file.Pkg.Store(part, []byte(patched))
+	// Descarta a versão carregada para que a próxima leitura use o XML alterado
+	c.file.Sheet.Delete(part)
+	return nil
+}
+
+// pageSetupNamesLocked lê a área de impressão e os títulos da planilha (chamar com c.mu)
+func (c *ExcelizeClient) pageSetupNamesLocked(sheet string) PageSetup {
+	var setup PageSetup
+	for _, dn := range c.file.GetDefinedName() {
+		if !strings.EqualFold(dn.Scope, sheet) {
+			continue
+		}
+		refersTo := strings.TrimPrefix(dn.RefersTo, "=")
+		switch dn.Name {
+		case printAreaName:
+			parts := strings.Split(refersTo, ",")
+			for i, part := range parts {
+				_, parts[i] = splitSheetRef(sheet, part)
+				parts[i] = strings.ReplaceAll(parts[i], "$", "")
+			}
+			area := strings.Join(parts, ",")
+			setup.PrintArea = &area
+		case printTitlesName:
+			for _, part := range strings.Split(refersTo, ",") {
+				_, area := splitSheetRef(sheet, part)
+				area = strings.ReplaceAll(area, "$", "")
+				if titleRowsPattern.MatchString(area) {
+					setup.TitleRows = &area
+				} else if titleColumnsPattern.MatchString(area) {
+					setup.TitleColumns = &area
+				}
+			}
+		}
+	}
+	return setup
+}
+
+// setSheetNameLocked substitui (ou remove, com refersTo vazio) um nome interno da planilha (chamar com c.mu)
+func (c *ExcelizeClient) setSheetNameLocked(sheet, name, refersTo string) error {
+	for _, dn := range c.file.GetDefinedName() {
+		if dn.Name == name && strings.EqualFold(dn.Scope, sheet) {
+			if err := c.file.DeleteDefinedName(&excelize.DefinedName{Name: name, Scope: dn.Scope}); err != nil {
+				return err
+			}
+		}
+	}
+	if refersTo == "" {
+		return nil
+	}
+	return c.file.SetDefinedName(&excelize.DefinedName{Name: name, RefersTo: refersTo, Scope: sheet})
+}
+
+// printAreaLocked resolve a área de impressão em referências absolutas da planilha (chamar com c.mu)
+func (c *ExcelizeClient) printAreaLocked(sheet, rng string) (string, error) {
+	areaSheet, resolved := c.resolveRangeLocked(sheet, rng)
+	if !strings.EqualFold(areaSheet, sheet) {
+		return "", fmt.Errorf("print area must be on sheet %s: %s", sheet, rng)
+	}
+	parts := strings.Split(resolved, ",")
+	for i, part := range parts {
+		ref, ok := parseCellRef(sheet, strings.TrimSpace(part))
+		if !ok {
+			return "", fmt.Errorf("invalid range: %s", part)
+		}
+		parts[i] = quoteSheetName(sheet) + "!" + absoluteArea(ref)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// printTitles monta a referência das linhas e colunas repetidas em cada página
+func printTitles(sheet string, rows, cols *string) (string, error) {
+	var parts []string
+	if cols != nil && strings.TrimSpace(*cols) != "" {
+		m := titleColumnsPattern.FindStringSubmatch(strings.TrimSpace(*cols))
+		if m == nil {
+			return "", fmt.Errorf("invalid title columns: %s (use e.g. 'A:B')", *cols)
+		}
+		last := m[2]
+		if last == "" {
+			last = m[1]
+		}
+		parts = append(parts, fmt.Sprintf("%s!$%s:$%s", quoteSheetName(sheet), strings.ToUpper(m[1]), strings.ToUpper(last)))
+	}
+	if rows != nil && strings.TrimSpace(*rows) != "" {
+		m := titleRowsPattern.FindStringSubmatch(strings.TrimSpace(*rows))
+		if m == nil || m[1] == "0" {
+			return "", fmt.Errorf("invalid title rows: %s (use e.g. '1:2')", *rows)
+		}
+		last := m[2]
+		if last == "" {
+			last = m[1]
+		}
+		parts = append(parts, fmt.Sprintf("%s!$%s:$%s", quoteSheetName(sheet), m[1], last))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// absoluteArea escreve um intervalo com referências absolutas ($A$1:$B$2)
+func absoluteArea(ref cellRef) string {
+	start := indicesToCell(ref.StartRow, ref.StartCol)
+	end := indicesToCell(ref.EndRow, ref.EndCol)
+	absolute := func(cell string) string {
+		split := strings.IndexAny(cell, "0123456789")
+		return "$" + cell[:split] + "$" + cell[split:]
+	}
+	return absolute(start) + ":" + absolute(end)
+}
+
+// headerFooterText converte o texto do cabeçalho/rodapé nos códigos do Excel.
+// Textos iniciados por '&' já estão no formato do Excel; nos demais, {page},
+// {pages}, {date}, {time}, {file} e {sheet} viram campos e 'esquerda|centro|direita'
+// distribui o texto pelas seções (sem barras, o texto fica centralizado).
+func headerFooterText(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.HasPrefix(text, "&") {
+		return text
+	}
+
+	sections := strings.Split(text, "|")
+	if len(sections) > 3 {
+		sections = append(sections[:2], strings.Join(sections[2:], "|"))
+	}
+	if len(sections) == 1 {
+		sections = []string{"", sections[0], ""}
+	}
+	var b strings.Builder
+	for i, section := range sections {
+		section = strings.TrimSpace(section)
+		if section == "" {
+			continue
+		}
+		b.WriteString([]string{"&L", "&C", "&R"}[i])
+		b.WriteString(headerFooterTokens.Replace(strings.ReplaceAll(section, "&", "&&")))
+	}
+	return b.String()
+}
+
+// boolPtr ponteiro para um valor booleano
+func boolPtr(b bool) *bool {
+	return &b
+}
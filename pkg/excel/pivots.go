@@ -0,0 +1,481 @@
+package excel
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Funções de agregação aceitas (nome -> subtotal do Excelize)
+var pivotFunctions = map[string]string{
+	"sum":     "Sum",
+	"count":   "Count",
+	"average": "Average",
+	"avg":     "Average",
+	"max":     "Max",
+	"min":     "Min",
+}
+
+// Rótulos padrão dos campos de valores, como o Excel em português
+var pivotFunctionLabels = map[string]string{
+	"Sum":     "Soma de",
+	"Count":   "Contagem de",
+	"Average": "Média de",
+	"Max":     "Máx de",
+	"Min":     "Mín de",
+}
+
+// Modos "mostrar valores como" (nome -> atributo showDataAs)
+var pivotShowAs = map[string]string{
+	"percent_of_total":  "percentOfTotal",
+	"percent_of_row":    "percentOfRow",
+	"percent_of_column": "percentOfCol",
+	"percent_of_col":    "percentOfCol",
+}
+
+// Formato embutido 0.00%
+const percentNumFmt = 10
+
+var pivotDataFieldTag = regexp.MustCompile(`<dataField\s[^>]*?(/?>)`)
+
+// UnmarshalJSON aceita tanto {"field": "Vendas", ...} quanto apenas "Vendas"
+func (d *PivotDataField) UnmarshalJSON(data []byte) error {
+	var field string
+	if err := json.Unmarshal(data, &field); err == nil {
+		*d = PivotDataField{Field: field}
+		return nil
+	}
+	type plain PivotDataField
+	return json.Unmarshal(data, (*plain)(d))
+}
+
+// CreatePivotTableWithFields cria uma tabela dinâmica com linhas, colunas, filtros e valores
+func (c *ExcelizeClient) CreatePivotTableWithFields(spec PivotSpec) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.createPivotLocked(spec)
+}
+
+func (c *ExcelizeClient) createPivotLocked(spec PivotSpec) error {
+	srcSheet, srcRange := c.resolveRangeLocked(spec.SourceSheet, spec.SourceRange)
+	srcRange, err := c.expandColumnRange(srcSheet, srcRange)
+	if err != nil {
+		return err
+	}
+	destSheet, destCell := splitSheetRef(spec.DestSheet, spec.DestCell)
+	if destSheet == "" {
+		destSheet = srcSheet
+	}
+
+	if err := c.checkPivotFields(srcSheet, srcRange, spec); err != nil {
+		return err
+	}
+
+	data := make([]excelize.PivotTableField, 0, len(spec.DataFields))
+	for _, df := range spec.DataFields {
+		field, err := pivotDataField(df)
+		if err != nil {
+			return err
+		}
+		data = append(data, field)
+	}
+
+	pivotRange, err := pivotTableRange(destCell, srcRange, len(spec.Rows), len(data))
+	if err != nil {
+		return err
+	}
+
+	name := spec.Name
+	if name == "" {
+		name = fmt.Sprintf("PivotTable%d", len(c.pivotParts())+1)
+	}
+
+	opts := &excelize.PivotTableOptions{
+		DataRange:           fmt.Sprintf("%s!%s", srcSheet, srcRange),
+		PivotTableRange:     fmt.Sprintf("%s!%s", destSheet, pivotRange),
+		Name:                name,
+		Rows:                pivotFields(spec.Rows, true),
+		Columns:             pivotFields(spec.Columns, true),
+		Filter:              pivotFields(spec.Filters, false),
+		Data:                data,
+		RowGrandTotals:      spec.RowGrandTotals == nil || *spec.RowGrandTotals,
+		ColGrandTotals:      spec.ColGrandTotals == nil || *spec.ColGrandTotals,
+		ShowDrill:           true,
+		UseAutoFormatting:   true,
+		PageOverThenDown:    true,
+		ShowRowHeaders:      true,
+		ShowColHeaders:      true,
+		ShowLastColumn:      true,
+		PivotTableStyleName: spec.Style,
+	}
+
+	before := c.pivotParts()
+	if err := c.file.AddPivotTable(opts); err != nil {
+		return fmt.Errorf("failed to create pivot table: %w", err)
+	}
+
+	// O Excelize não expõe showDataAs: ajustar o XML da tabela recém-criada
+	for part := range c.pivotParts() {
+		if !before[part] {
+			return c.patchPivotShowAs(part, spec.DataFields)
+		}
+	}
+	return nil
+}
+
+// checkPivotFields confere se todos os campos existem no cabeçalho da origem
+// (o Excelize ignora campos desconhecidos)
+func (c *ExcelizeClient) checkPivotFields(sheet, rng string, spec PivotSpec) error {
+	start, end, err := parseRange(rng)
+	if err != nil {
+		return err
+	}
+	col1, row, err := excelize.CellNameToCoordinates(strings.ReplaceAll(start, "$", ""))
+	if err != nil {
+		return err
+	}
+	col2, _, err := excelize.CellNameToCoordinates(strings.ReplaceAll(end, "$", ""))
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]bool)
+	for col := col1; col <= col2; col++ {
+		cell, _ := excelize.CoordinatesToCellName(col, row)
+		value, _ := c.file.GetCellValue(sheet, cell)
+		headers[value] = true
+	}
+
+	fields := append(append(append([]string{}, spec.Rows...), spec.Columns...), spec.Filters...)
+	for _, df := range spec.DataFields {
+		fields = append(fields, df.Field)
+	}
+	for _, field := range fields {
+		if !headers[field] {
+			return fmt.Errorf("pivot field not found in source headers: %s", field)
+		}
+	}
+	return nil
+}
+
+// pivotDataField converte um campo de valores para o formato Excelize
+func pivotDataField(df PivotDataField) (excelize.PivotTableField, error) {
+	function := strings.ToLower(strings.TrimSpace(df.Function))
+	if function == "" {
+		function = "sum"
+	}
+	subtotal, ok := pivotFunctions[function]
+	if !ok {
+		return excelize.PivotTableField{}, fmt.Errorf("unsupported pivot function: %s", df.Function)
+	}
+	if df.ShowAs != "" {
+		if _, ok := pivotShowAs[strings.ToLower(df.ShowAs)]; !ok {
+			return excelize.PivotTableField{}, fmt.Errorf("unsupported pivot showAs: %s", df.ShowAs)
+		}
+	}
+
+	name := df.Name
+	if name == "" {
+		name = pivotFunctionLabels[subtotal] + " " + df.Field
+	}
+	numFmt := df.NumFmt
+	if numFmt == 0 && df.ShowAs != "" {
+		numFmt = percentNumFmt
+	}
+	return excelize.PivotTableField{Data: df.Field, Name: name, Subtotal: subtotal, NumFmt: numFmt}, nil
+}
+
+func pivotFields(names []string, subtotal bool) []excelize.PivotTableField {
+	fields := make([]excelize.PivotTableField, 0, len(names))
+	for _, name := range names {
+		fields = append(fields, excelize.PivotTableField{Data: name, DefaultSubtotal: subtotal})
+	}
+	return fields
+}
+
+// pivotTableRange aceita um intervalo ou só a célula inicial; nesse caso estima a área
+// ocupada (o Excel recalcula o layout ao atualizar a tabela)
+func pivotTableRange(destCell, srcRange string, rowFields, dataFields int) (string, error) {
+	if strings.Contains(destCell, ":") {
+		return destCell, nil
+	}
+	col, row, err := excelize.CellNameToCoordinates(strings.ReplaceAll(destCell, "$", ""))
+	if err != nil {
+		return "", fmt.Errorf("invalid pivot destination: %s", destCell)
+	}
+
+	height := 2
+	if start, end, err := parseRange(srcRange); err == nil {
+		_, r1, err1 := excelize.CellNameToCoordinates(strings.ReplaceAll(start, "$", ""))
+		_, r2, err2 := excelize.CellNameToCoordinates(strings.ReplaceAll(end, "$", ""))
+		if err1 == nil && err2 == nil {
+			height = r2 - r1 + 2
+		}
+	}
+	width := 1 + dataFields
+	if rowFields == 0 || width < 2 {
+		width = 2
+	}
+
+	endCell, err := excelize.CoordinatesToCellName(col+width-1, row+height-1)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(destCell, "$", "") + ":" + endCell, nil
+}
+
+// expandColumnRange converte 'A:F' em 'A1:F<última linha>'
+func (c *ExcelizeClient) expandColumnRange(sheet, rng string) (string, error) {
+	start, end, err := parseRange(rng)
+	if err != nil {
+		return "", err
+	}
+	if strings.ContainsAny(start+end, "0123456789") {
+		return rng, nil
+	}
+	rows, err := c.file.GetRows(sheet)
+	if err != nil {
+		return "", err
+	}
+	lastRow := len(rows)
+	if lastRow < 2 {
+		lastRow = 2
+	}
+	return fmt.Sprintf("%s1:%s%d", start, end, lastRow), nil
+}
+
+// pivotParts conjunto das partes xl/pivotTables/pivotTableN.xml existentes
+func (c *ExcelizeClient) pivotParts() map[string]bool {
+	parts := make(map[string]bool)
+	c.file.Pkg.Range(func(key, _ interface{}) bool {
+		if name := key.(string); strings.HasPrefix(name, "xl/pivotTables/pivotTable") {
+			parts[name] = true
+		}
+		return true
+	})
+	return parts
+}
+
+// patchPivotShowAs adiciona showDataAs aos campos de valores, na ordem de criação
+func (c *ExcelizeClient) patchPivotShowAs(part string, dataFields []PivotDataField) error {
+	needed := false
+	for _, df := range dataFields {
+		if df.ShowAs != "" {
+			needed = true
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	content, ok := c.file.Pkg.Load(part)
+	if !ok {
+		return fmt.Errorf("pivot table part not found: %s", part)
+	}
+	idx := 0
+	patched := pivotDataFieldTag.ReplaceAllFunc(content.([]byte), func(tag []byte) []byte {
+		defer func() { idx++ }()
+		if idx >= len(dataFields) || dataFields[idx].ShowAs == "" {
+			return tag
+		}
+		showAs := pivotShowAs[strings.ToLower(dataFields[idx].ShowAs)]
+		m := pivotDataFieldTag.FindSubmatchIndex(tag)
+		closing := string(tag[m[2]:m[3]])
+		return []byte(fmt.Sprintf(`%s showDataAs="%s" baseField="0" baseItem="0"%s`, tag[:m[2]], showAs, closing))
+	})
+	c.file.Pkg.Store(part, patched)
+	return nil
+}
+
+// GetPivotTables lê a configuração das tabelas dinâmicas da planilha
+func (c *ExcelizeClient) GetPivotTables(sheet string) ([]PivotSpec, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.getPivotTablesLocked(sheet)
+}
+
+func (c *ExcelizeClient) getPivotTablesLocked(sheet string) ([]PivotSpec, error) {
+	tables, err := c.file.GetPivotTables(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]PivotSpec, 0, len(tables))
+	if len(tables) == 0 {
+		return specs, nil
+	}
+	showAsByName := c.pivotShowAsByName(sheet)
+	for _, table := range tables {
+		srcSheet, srcRange := splitSheetRef("", table.DataRange)
+		_, location := splitSheetRef(sheet, table.PivotTableRange)
+		destCell, _, _ := parseRange(location)
+		rowTotals, colTotals := table.RowGrandTotals, table.ColGrandTotals
+
+		spec := PivotSpec{
+			Name:           table.Name,
+			SourceSheet:    srcSheet,
+			SourceRange:    srcRange,
+			DestSheet:      sheet,
+			DestCell:       destCell,
+			Rows:           pivotFieldNames(table.Rows),
+			Columns:        pivotFieldNames(table.Columns),
+			Filters:        pivotFieldNames(table.Filter),
+			DataFields:     []PivotDataField{},
+			RowGrandTotals: &rowTotals,
+			ColGrandTotals: &colTotals,
+			Style:          table.PivotTableStyleName,
+		}
+
+		showAs := showAsByName[table.Name]
+		for i, field := range table.Data {
+			df := PivotDataField{
+				Field:    field.Data,
+				Function: strings.ToLower(field.Subtotal),
+				Name:     field.Name,
+				NumFmt:   field.NumFmt,
+			}
+			if df.Function == "" {
+				df.Function = "sum"
+			}
+			if i < len(showAs) {
+				df.ShowAs = showAs[i]
+			}
+			spec.DataFields = append(spec.DataFields, df)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func pivotFieldNames(fields []excelize.PivotTableField) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Data)
+	}
+	return names
+}
+
+// pivotShowAsByName lê showDataAs dos campos de valores das tabelas ligadas à planilha.
+// O Excelize não remove a parte XML ao excluir uma tabela, então só as relações da
+// planilha indicam as tabelas vigentes.
+func (c *ExcelizeClient) pivotShowAsByName(sheet string) map[string][]string {
+	result := make(map[string][]string)
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return result
+	}
+	sheetPart, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return result
+	}
+	for _, part := range pkg.rels(sheetPart) {
+		if !strings.Contains(part, "pivotTables/") {
+			continue
+		}
+		var def struct {
+			Name       string `xml:"name,attr"`
+			DataFields []struct {
+				ShowDataAs string `xml:"showDataAs,attr"`
+			} `xml:"dataFields>dataField"`
+		}
+		if err := pkg.decode(part, &def); err != nil {
+			continue
+		}
+		for _, df := range def.DataFields {
+			result[def.Name] = append(result[def.Name], pivotShowAsName(df.ShowDataAs))
+		}
+	}
+	return result
+}
+
+// pivotShowAsName converte showDataAs de volta para o nome usado em PivotDataField
+func pivotShowAsName(showDataAs string) string {
+	switch showDataAs {
+	case "", "normal":
+		return ""
+	case "percentOfTotal":
+		return "percent_of_total"
+	case "percentOfRow":
+		return "percent_of_row"
+	case "percentOfCol":
+		return "percent_of_column"
+	}
+	return showDataAs
+}
+
+// UpdatePivotTable altera uma tabela dinâmica existente. Campos nulos em changes mantêm
+// a configuração atual; listas vazias removem os campos. A tabela é recriada no mesmo lugar.
+func (c *ExcelizeClient) UpdatePivotTable(sheet, name string, changes PivotSpec) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	specs, err := c.getPivotTablesLocked(sheet)
+	if err != nil {
+		return err
+	}
+	var current *PivotSpec
+	for i := range specs {
+		if strings.EqualFold(specs[i].Name, name) {
+			current = &specs[i]
+			break
+		}
+	}
+	if current == nil {
+		return fmt.Errorf("pivot table not found: %s", name)
+	}
+
+	updated := *current
+	if changes.Name != "" {
+		updated.Name = changes.Name
+	}
+	if changes.SourceRange != "" {
+		updated.SourceRange = changes.SourceRange
+		if changes.SourceSheet != "" {
+			updated.SourceSheet = changes.SourceSheet
+		}
+	}
+	if changes.DestCell != "" {
+		updated.DestCell = changes.DestCell
+		if changes.DestSheet != "" {
+			updated.DestSheet = changes.DestSheet
+		}
+	}
+	if changes.Rows != nil {
+		updated.Rows = changes.Rows
+	}
+	if changes.Columns != nil {
+		updated.Columns = changes.Columns
+	}
+	if changes.Filters != nil {
+		updated.Filters = changes.Filters
+	}
+	if changes.DataFields != nil {
+		updated.DataFields = changes.DataFields
+	}
+	if changes.RowGrandTotals != nil {
+		updated.RowGrandTotals = changes.RowGrandTotals
+	}
+	if changes.ColGrandTotals != nil {
+		updated.ColGrandTotals = changes.ColGrandTotals
+	}
+	if changes.Style != "" {
+		updated.Style = changes.Style
+	}
+
+	if err := c.file.DeletePivotTable(sheet, current.Name); err != nil {
+		return fmt.Errorf("failed to update pivot table: %w", err)
+	}
+	if err := c.createPivotLocked(updated); err != nil {
+		// Restaurar a configuração anterior
+		if restoreErr := c.createPivotLocked(*current); restoreErr != nil {
+			return fmt.Errorf("failed to update pivot table: %w (restore failed: %v)", err, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Propriedades personalizadas que registram a origem de arquivos gerados pela IA
+const (
+	provenanceGeneratorProp    = "AIGeneratedBy"
+	provenanceConversationProp = "AIConversationID"
+	provenanceModelProp        = "AIModel"
+	provenanceGeneratedAtProp  = "AIGeneratedAt"
+)
+
+// DefaultGenerator nome gravado como autor da origem quando nenhum é informado
+const DefaultGenerator = "excel-ai"
+
+// GetWorkbookProperties retorna as propriedades do documento e as personalizadas
+func (c *ExcelizeClient) GetWorkbookProperties() (*WorkbookProperties, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.workbookPropertiesLocked()
+}
+
+// SetWorkbookProperties altera as propriedades do documento (campos nil não mudam)
+func (c *ExcelizeClient) SetWorkbookProperties(props WorkbookProperties) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Valida as personalizadas antes de alterar para não gravar pela metade
+	custom := make([]excelize.CustomProperty, 0, len(props.Custom))
+	for name, value := range props.Custom {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("custom property name is required")
+		}
+		converted, err := customPropertyValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for custom property %s: %w", name, err)
+		}
+		custom = append(custom, excelize.CustomProperty{Name: name, Value: converted})
+	}
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+
+	// O Excelize grava todos os campos, então parte dos valores atuais
+	doc, err := c.file.GetDocProps()
+	if err != nil {
+		return err
+	}
+	for _, field := range []struct {
+		value *string
+		dest  *string
+	}{
+		{props.Title, &doc.Title}, {props.Subject, &doc.Subject}, {props.Author, &doc.Creator},
+		{props.Keywords, &doc.Keywords}, {props.Description, &doc.Description},
+		{props.Category, &doc.Category}, {props.LastModifiedBy, &doc.LastModifiedBy},
+	} {
+		if field.value != nil {
+			*field.dest = strings.TrimSpace(*field.value)
+		}
+	}
+	if err := c.file.SetDocProps(doc); err != nil {
+		return fmt.Errorf("failed to set document properties: %w", err)
+	}
+	if props.Company != nil {
+		app, err := c.file.GetAppProps()
+		if err != nil {
+			return err
+		}
+		app.Company = strings.TrimSpace(*props.Company)
+		if err := c.file.SetAppProps(app); err != nil {
+			return fmt.Errorf("failed to set company: %w", err)
+		}
+	}
+	return c.setCustomPropsLocked(custom)
+}
+
+// StampProvenance registra nas propriedades que o arquivo foi gerado pela IA
+// (gerador, conversa, modelo e data) e atualiza o último autor e a data de modificação
+func (c *ExcelizeClient) StampProvenance(p Provenance) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p.Generator = strings.TrimSpace(p.Generator); p.Generator == "" {
+		p.Generator = DefaultGenerator
+	}
+	generatedAt := time.Now().UTC()
+	if p.GeneratedAt != "" {
+		parsed, err := time.Parse(time.RFC3339, p.GeneratedAt)
+		if err != nil {
+			return fmt.Errorf("invalid generation time: %s (use RFC 3339)", p.GeneratedAt)
+		}
+		generatedAt = parsed.UTC()
+	}
+	generatedAt = generatedAt.Truncate(time.Second)
+
+	doc, err := c.file.GetDocProps()
+	if err != nil {
+		return err
+	}
+	if doc.Creator == "" {
+		doc.Creator = p.Generator
+	}
+	doc.LastModifiedBy = p.Generator
+	doc.Modified = generatedAt.Format(time.RFC3339)
+	if err := c.file.SetDocProps(doc); err != nil {
+		return fmt.Errorf("failed to set document properties: %w", err)
+	}
+
+	// Valores vazios removem o registro anterior para não misturar origens
+	return c.setCustomPropsLocked([]excelize.CustomProperty{
+		{Name: provenanceGeneratorProp, Value: p.Generator},
+		{Name: provenanceConversationProp, Value: optionalString(p.ConversationID)},
+		{Name: provenanceModelProp, Value: optionalString(p.Model)},
+		{Name: provenanceGeneratedAtProp, Value: generatedAt},
+	})
+}
+
+// GetWorkbookInfo resume a pasta de trabalho: arquivo, abas, propriedades e origem
+func (c *ExcelizeClient) GetWorkbookInfo() (*WorkbookInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	props, err := c.workbookPropertiesLocked()
+	if err != nil {
+		return nil, err
+	}
+	info := &WorkbookInfo{Sheets: c.file.GetSheetList(), Properties: *props}
+	if c.filePath != "" {
+		info.File = filepath.Base(c.filePath)
+	}
+
+	if generator, ok := props.Custom[provenanceGeneratorProp].(string); ok {
+		info.Provenance = &Provenance{Generator: generator}
+		info.Provenance.ConversationID, _ = props.Custom[provenanceConversationProp].(string)
+		info.Provenance.Model, _ = props.Custom[provenanceModelProp].(string)
+		if at, ok := props.Custom[provenanceGeneratedAtProp].(time.Time); ok {
+			info.Provenance.GeneratedAt = at.UTC().Format(time.RFC3339)
+		}
+	}
+	return info, nil
+}
+
+// workbookPropertiesLocked lê as propriedades do documento (chamar com c.mu)
+func (c *ExcelizeClient) workbookPropertiesLocked() (*WorkbookProperties, error) {
+	doc, err := c.file.GetDocProps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document properties: %w", err)
+	}
+	app, err := c.file.GetAppProps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read application properties: %w", err)
+	}
+	custom, err := c.file.GetCustomProps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom properties: %w", err)
+	}
+
+	text := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	props := &WorkbookProperties{
+		Title:          text(doc.Title),
+		Subject:        text(doc.Subject),
+		Author:         text(doc.Creator),
+		Keywords:       text(doc.Keywords),
+		Description:    text(doc.Description),
+		Category:       text(doc.Category),
+		Company:        text(app.Company),
+		LastModifiedBy: text(doc.LastModifiedBy),
+		Created:        doc.Created,
+		Modified:       doc.Modified,
+		Application:    app.Application,
+	}
+	if len(custom) > 0 {
+		props.Custom = make(map[string]interface{}, len(custom))
+		for _, prop := range custom {
+			props.Custom[prop.Name] = prop.Value
+		}
+	}
+	return props, nil
+}
+
+// setCustomPropsLocked grava propriedades personalizadas; valor nil remove (chamar com c.mu)
+func (c *ExcelizeClient) setCustomPropsLocked(props []excelize.CustomProperty) error {
+	current, err := c.file.GetCustomProps()
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(current))
+	for _, prop := range current {
+		existing[prop.Name] = true
+	}
+	for _, prop := range props {
+		// O Excelize cria a parte de propriedades mesmo ao remover uma que não existe
+		if prop.Value == nil && !existing[prop.Name] {
+			continue
+		}
+		if err := c.file.SetCustomProps(prop); err != nil {
+			return fmt.Errorf("failed to set custom property %s: %w", prop.Name, err)
+		}
+	}
+	return nil
+}
+
+// customPropertyValue converte um valor para os tipos aceitos em propriedades
+// personalizadas (nil remove a propriedade). Números inteiros viram vt:i4.
+func customPropertyValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, string, bool, int32, time.Time:
+		return v, nil
+	case int:
+		return customPropertyValue(float64(v))
+	case int64:
+		return customPropertyValue(float64(v))
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+			return int32(v), nil
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T (use text, number, boolean or date)", value)
+	}
+}
+
+// optionalString nil para textos vazios (remove a propriedade personalizada)
+func optionalString(value string) interface{} {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	return value
+}
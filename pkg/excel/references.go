@@ -0,0 +1,535 @@
+package excel
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/efp"
+	"github.com/xuri/excelize/v2"
+)
+
+// stringLiteral textos entre aspas de uma fórmula
+var stringLiteral = regexp.MustCompile(`"(?:[^"]|"")*"`)
+
+// structuredRef referência estruturada de tabela (ex: Vendas[Valor], Vendas[[#This Row],[Valor]], [@Valor])
+var structuredRef = regexp.MustCompile(`([\p{L}_\\][\p{L}\p{N}_.]*)?\[((?:[^\[\]]|\[[^\[\]]*\])*)\]`)
+
+// structuredItem especificadores dentro de uma referência estruturada ([#Headers], [Valor], ...)
+var structuredItem = regexp.MustCompile(`\[([^\[\]]*)\]`)
+
+// cellRef intervalo referenciado por uma fórmula (índices base 0, inclusivos)
+type cellRef struct {
+	Sheet    string
+	StartRow int
+	StartCol int
+	EndRow   int
+	EndCol   int
+}
+
+// contains verifica se a célula está dentro do intervalo
+func (r cellRef) contains(sheet string, row, col int) bool {
+	return strings.EqualFold(r.Sheet, sheet) &&
+		row >= r.StartRow && row <= r.EndRow && col >= r.StartCol && col <= r.EndCol
+}
+
+// intersects verifica se dois intervalos têm células em comum
+func (r cellRef) intersects(other cellRef) bool {
+	return strings.EqualFold(r.Sheet, other.Sheet) &&
+		r.StartRow <= other.EndRow && other.StartRow <= r.EndRow &&
+		r.StartCol <= other.EndCol && other.StartCol <= r.EndCol
+}
+
+// covers verifica se other está inteiro dentro do intervalo
+func (r cellRef) covers(other cellRef) bool {
+	return strings.EqualFold(r.Sheet, other.Sheet) &&
+		other.StartRow >= r.StartRow && other.EndRow <= r.EndRow &&
+		other.StartCol >= r.StartCol && other.EndCol <= r.EndCol
+}
+
+// overlap parte comum de dois intervalos
+func (r cellRef) overlap(other cellRef) (cellRef, bool) {
+	if !r.intersects(other) {
+		return cellRef{}, false
+	}
+	return cellRef{
+		Sheet:    r.Sheet,
+		StartRow: max(r.StartRow, other.StartRow),
+		StartCol: max(r.StartCol, other.StartCol),
+		EndRow:   min(r.EndRow, other.EndRow),
+		EndCol:   min(r.EndCol, other.EndCol),
+	}, true
+}
+
+// subtract partes do intervalo que ficam fora de other (até quatro retângulos)
+func (r cellRef) subtract(other cellRef) []cellRef {
+	if !r.intersects(other) {
+		return []cellRef{r}
+	}
+	var parts []cellRef
+	if other.StartRow > r.StartRow {
+		top := r
+		top.EndRow = other.StartRow - 1
+		parts = append(parts, top)
+	}
+	if other.EndRow < r.EndRow {
+		bottom := r
+		bottom.StartRow = other.EndRow + 1
+		parts = append(parts, bottom)
+	}
+	middle := r
+	middle.StartRow, middle.EndRow = max(r.StartRow, other.StartRow), min(r.EndRow, other.EndRow)
+	if other.StartCol > r.StartCol {
+		left := middle
+		left.EndCol = other.StartCol - 1
+		parts = append(parts, left)
+	}
+	if other.EndCol < r.EndCol {
+		right := middle
+		right.StartCol = other.EndCol + 1
+		parts = append(parts, right)
+	}
+	return parts
+}
+
+// subtractAll partes das áreas que ficam fora de todos os alvos
+func subtractAll(areas, targets []cellRef) []cellRef {
+	var rest []cellRef
+	for _, area := range areas {
+		parts := []cellRef{area}
+		for _, target := range targets {
+			var next []cellRef
+			for _, part := range parts {
+				next = append(next, part.subtract(target)...)
+			}
+			parts = next
+		}
+		rest = append(rest, parts...)
+	}
+	return rest
+}
+
+// String formata o intervalo como Planilha!A1:B2 (colunas e linhas inteiras como A:A e 1:1)
+func (r cellRef) String() string {
+	var area string
+	switch {
+	case r.StartRow == 0 && r.EndRow == excelize.TotalRows-1:
+		start, _ := excelize.ColumnNumberToName(r.StartCol + 1)
+		end, _ := excelize.ColumnNumberToName(r.EndCol + 1)
+		area = start + ":" + end
+	case r.StartCol == 0 && r.EndCol == excelize.MaxColumns-1:
+		area = strconv.Itoa(r.StartRow+1) + ":" + strconv.Itoa(r.EndRow+1)
+	case r.StartRow == r.EndRow && r.StartCol == r.EndCol:
+		area = indicesToCell(r.StartRow, r.StartCol)
+	default:
+		area = indicesToCell(r.StartRow, r.StartCol) + ":" + indicesToCell(r.EndRow, r.EndCol)
+	}
+	return quoteSheetName(r.Sheet) + "!" + area
+}
+
+// tableArea tabela usada para resolver referências estruturadas
+type tableArea struct {
+	Name    string
+	Area    cellRef // Intervalo completo, incluindo o cabeçalho e a linha de totais
+	Headers []string
+	Totals  bool
+}
+
+// body linhas de dados da tabela (sem o cabeçalho e a linha de totais)
+func (t tableArea) body() cellRef {
+	ref := t.Area
+	if t.Totals && ref.EndRow > ref.StartRow {
+		ref.EndRow--
+	}
+	if ref.EndRow > ref.StartRow {
+		ref.StartRow++
+	}
+	return ref
+}
+
+// refResolver resolve referências de células, nomes definidos e tabelas
+type refResolver struct {
+	names  map[string]string // "planilha!nome" (escopo local) ou "nome", em minúsculas
+	tables []tableArea
+}
+
+// newRefResolverLocked carrega nomes definidos e tabelas da pasta (chamar com c.mu)
+func (c *ExcelizeClient) newRefResolverLocked() *refResolver {
+	r := &refResolver{names: make(map[string]string)}
+	for _, dn := range c.file.GetDefinedName() {
+		key := strings.ToLower(dn.Name)
+		if dn.Scope != "" && dn.Scope != "Workbook" {
+			key = strings.ToLower(dn.Scope) + "!" + key
+		}
+		r.names[key] = strings.TrimPrefix(dn.RefersTo, "=")
+	}
+
+	docs := c.tableDocsLocked()
+	for _, sheet := range c.file.GetSheetList() {
+		tables, err := c.file.GetTables(sheet)
+		if err != nil {
+			continue
+		}
+		for _, table := range tables {
+			area, ok := parseCellRef(sheet, table.Range)
+			if !ok {
+				continue
+			}
+			headers := make([]string, 0, area.EndCol-area.StartCol+1)
+			for col := area.StartCol; col <= area.EndCol; col++ {
+				header, _ := c.file.GetCellValue(sheet, indicesToCell(area.StartRow, col))
+				headers = append(headers, header)
+			}
+			totals := false
+			if doc, ok := docs[strings.ToLower(table.Name)]; ok {
+				totals = doc.TotalsRowCount > 0
+			}
+			r.tables = append(r.tables, tableArea{Name: table.Name, Area: area, Headers: headers, Totals: totals})
+		}
+	}
+	return r
+}
+
+// table localiza uma tabela pelo nome
+func (r *refResolver) table(name string) (tableArea, bool) {
+	for _, t := range r.tables {
+		if strings.EqualFold(t.Name, name) {
+			return t, true
+		}
+	}
+	return tableArea{}, false
+}
+
+// tableAt localiza a tabela que contém uma célula
+func (r *refResolver) tableAt(sheet string, row, col int) (tableArea, bool) {
+	for _, t := range r.tables {
+		if t.Area.contains(sheet, row, col) {
+			return t, true
+		}
+	}
+	return tableArea{}, false
+}
+
+// resolve converte um endereço, nome definido ou nome de tabela em intervalos
+func (r *refResolver) resolve(sheet, text string) []cellRef {
+	text = strings.TrimSpace(strings.TrimPrefix(text, "="))
+	if ref, ok := parseCellRef(sheet, text); ok {
+		return []cellRef{ref}
+	}
+
+	target, ok := r.names[strings.ToLower(sheet+"!"+text)]
+	if !ok {
+		target, ok = r.names[strings.ToLower(text)]
+	}
+	if ok {
+		var refs []cellRef
+		for _, area := range strings.Split(target, ",") {
+			if ref, ok := parseCellRef(sheet, area); ok {
+				refs = append(refs, ref)
+			}
+		}
+		return refs
+	}
+
+	if t, ok := r.table(text); ok {
+		return []cellRef{t.body()}
+	}
+	return nil
+}
+
+// resolveRangeLocked converte um nome definido, tabela ou referência estruturada
+// (Vendas[Valor]) no intervalo A1 e na planilha onde ele está. O nome de uma tabela
+// inclui o cabeçalho e colunas ou linhas inteiras são limitadas à área usada.
+// Endereços A1 são devolvidos sem alteração, apenas sem o nome da planilha, e
+// textos que não correspondem a nenhum nome seguem adiante (chamar com c.mu).
+func (c *ExcelizeClient) resolveRangeLocked(sheet, rng string) (string, string) {
+	text := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rng), "="))
+	if text == "" {
+		return sheet, rng
+	}
+	if ref, ok := parseCellRef(sheet, text); ok {
+		_, area := splitSheetRef(sheet, text)
+		return ref.Sheet, area
+	}
+
+	// Vários intervalos separados por vírgula (ex: 'Vendas,Custos')
+	if parts := strings.Split(text, ","); len(parts) > 1 {
+		resolved := make([]string, len(parts))
+		resolvedSheet := sheet
+		for i, part := range parts {
+			var partSheet string
+			partSheet, resolved[i] = c.resolveRangeLocked(sheet, part)
+			if i == 0 {
+				resolvedSheet = partSheet
+			}
+		}
+		return resolvedSheet, strings.Join(resolved, ",")
+	}
+
+	resolver := c.newRefResolverLocked()
+	var refs []cellRef
+	if t, ok := resolver.table(text); ok {
+		refs = []cellRef{t.Area}
+	} else if m := structuredRef.FindStringSubmatch(text); m != nil && m[0] == text && m[1] != "" {
+		if ref, ok := resolver.structured(sheet, 0, 0, m[1], m[2]); ok {
+			refs = []cellRef{ref}
+		}
+	} else {
+		refs = resolver.resolve(sheet, text)
+	}
+	if len(refs) == 0 {
+		return sheet, rng
+	}
+
+	areas := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		c.clampToUsedAreaLocked(&ref)
+		areas = append(areas, indicesToCell(ref.StartRow, ref.StartCol)+":"+indicesToCell(ref.EndRow, ref.EndCol))
+	}
+	return refs[0].Sheet, strings.Join(areas, ",")
+}
+
+// areaLocked resolve um endereço, nome definido ou tabela em uma única área (chamar com c.mu)
+func (c *ExcelizeClient) areaLocked(sheet, rng string) (cellRef, bool) {
+	sheet, rng = c.resolveRangeLocked(sheet, rng)
+	return parseCellRef(sheet, rng)
+}
+
+// resolveCellLocked célula superior esquerda de um endereço ou nome definido (chamar com c.mu)
+func (c *ExcelizeClient) resolveCellLocked(sheet, cell string) (string, string) {
+	sheet, rng := c.resolveRangeLocked(sheet, cell)
+	start, _, _ := strings.Cut(rng, ":")
+	return sheet, start
+}
+
+// formulaRefs extrai os intervalos lidos pela fórmula de uma célula
+func (r *refResolver) formulaRefs(sheet string, row, col int, formula string) []cellRef {
+	var refs []cellRef
+
+	// Referências estruturadas são tratadas antes do tokenizador, que as separa nas vírgulas
+	text := stringLiteral.ReplaceAllString(formula, `""`)
+	text = structuredRef.ReplaceAllStringFunc(text, func(match string) string {
+		m := structuredRef.FindStringSubmatch(match)
+		if m[1] == "" {
+			if _, err := strconv.Atoi(m[2]); err == nil {
+				return match // Vínculo externo ([1]Planilha!A1)
+			}
+		}
+		if ref, ok := r.structured(sheet, row, col, m[1], m[2]); ok {
+			refs = append(refs, ref)
+		}
+		return "0"
+	})
+
+	parser := efp.ExcelParser()
+	for _, token := range parser.Parse(text) {
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange {
+			refs = append(refs, r.resolve(sheet, token.TValue)...)
+		}
+	}
+	return refs
+}
+
+// structured resolve uma referência estruturada; sem nome de tabela vale a tabela
+// que contém a célula da fórmula
+func (r *refResolver) structured(sheet string, row, col int, name, spec string) (cellRef, bool) {
+	var t tableArea
+	var ok bool
+	if name == "" {
+		t, ok = r.tableAt(sheet, row, col)
+	} else {
+		t, ok = r.table(name)
+	}
+	if !ok {
+		return cellRef{}, false
+	}
+
+	ref := t.body()
+	thisRow := strings.HasPrefix(spec, "@")
+	spec = strings.TrimPrefix(spec, "@")
+
+	items := []string{spec}
+	if strings.Contains(spec, "[") {
+		items = items[:0]
+		for _, m := range structuredItem.FindAllStringSubmatch(spec, -1) {
+			items = append(items, m[1])
+		}
+	}
+
+	var columns []int
+	for _, item := range items {
+		switch strings.ToLower(strings.TrimSpace(item)) {
+		case "":
+		case "#all":
+			ref.StartRow, ref.EndRow = t.Area.StartRow, t.Area.EndRow
+		case "#data":
+			ref.StartRow, ref.EndRow = t.body().StartRow, t.body().EndRow
+		case "#headers":
+			ref.StartRow, ref.EndRow = t.Area.StartRow, t.Area.StartRow
+		case "#totals":
+			ref.StartRow, ref.EndRow = t.Area.EndRow, t.Area.EndRow
+		case "#this row":
+			thisRow = true
+		default:
+			for i, header := range t.Headers {
+				if strings.EqualFold(header, item) {
+					columns = append(columns, t.Area.StartCol+i)
+					break
+				}
+			}
+		}
+	}
+
+	if thisRow {
+		ref.StartRow, ref.EndRow = row, row
+	}
+	if len(columns) > 0 {
+		ref.StartCol, ref.EndCol = columns[0], columns[0]
+		for _, c := range columns[1:] {
+			ref.StartCol, ref.EndCol = min(ref.StartCol, c), max(ref.EndCol, c)
+		}
+	}
+	return ref, true
+}
+
+// parseCellRef interpreta referências como A1, $A$1:B5, Plan2!A:A ou 'Minha Planilha'!3:3
+func parseCellRef(sheet, text string) (cellRef, bool) {
+	refSheet, ref := splitSheetRef(sheet, strings.TrimSpace(text))
+	parts := strings.Split(strings.ReplaceAll(ref, "$", ""), ":")
+	if len(parts) > 2 {
+		return cellRef{}, false
+	}
+
+	startRow, startCol, ok := refBound(parts[0])
+	if !ok || (len(parts) == 1 && (startRow < 0 || startCol < 0)) {
+		return cellRef{}, false
+	}
+	endRow, endCol := startRow, startCol
+	if len(parts) == 2 {
+		if endRow, endCol, ok = refBound(parts[1]); !ok {
+			return cellRef{}, false
+		}
+		// Não mistura coluna inteira com linha inteira (ex: A:3)
+		if (startRow < 0) != (endRow < 0) || (startCol < 0) != (endCol < 0) {
+			return cellRef{}, false
+		}
+	}
+
+	result := cellRef{
+		Sheet:    refSheet,
+		StartRow: min(startRow, endRow),
+		StartCol: min(startCol, endCol),
+		EndRow:   max(startRow, endRow),
+		EndCol:   max(startCol, endCol),
+	}
+	if startRow < 0 {
+		result.StartRow, result.EndRow = 0, excelize.TotalRows-1
+	}
+	if startCol < 0 {
+		result.StartCol, result.EndCol = 0, excelize.MaxColumns-1
+	}
+	return result, true
+}
+
+// refBound converte um extremo de referência ("B3", "B" ou "3") em índices base 0;
+// -1 indica coluna ou linha inteira
+func refBound(s string) (row, col int, ok bool) {
+	if c, r, err := excelize.CellNameToCoordinates(s); err == nil {
+		return r - 1, c - 1, true
+	}
+	if n, err := excelize.ColumnNameToNumber(s); err == nil {
+		return -1, n - 1, true
+	}
+	if n, err := strconv.Atoi(s); err == nil && n > 0 && n <= excelize.TotalRows {
+		return n - 1, -1, true
+	}
+	return 0, 0, false
+}
+
+// formulaRefToken referência A1 em uma fórmula, com planilha opcional
+// (grupos: planilha, endereço)
+var formulaRefToken = regexp.MustCompile(`(?:('(?:[^']|'')+'|[\p{L}_][\p{L}\p{N}_.]*)!)?(\$?[A-Za-z]{1,3}\$?[0-9]+(?::\$?[A-Za-z]{1,3}\$?[0-9]+)?|\$?[A-Za-z]{1,3}:\$?[A-Za-z]{1,3}|\$?[0-9]+:\$?[0-9]+)`)
+
+// refEndpoint extremo de uma referência A1 ("$B3", "B", "$3")
+var refEndpoint = regexp.MustCompile(`^(\$?)([A-Za-z]*)(\$?)([0-9]*)$`)
+
+// rewriteFormulaRefs substitui cada referência A1 da fórmula, fora de textos entre
+// aspas, pelo retorno de replace. sheet é a planilha explícita na referência ou "".
+func rewriteFormulaRefs(formula string, replace func(sheet, ref string) string) string {
+	var out strings.Builder
+	last := 0
+	for _, lit := range append(stringLiteral.FindAllStringIndex(formula, -1), []int{len(formula), len(formula)}) {
+		segment := formula[last:lit[0]]
+		pos := 0
+		for _, m := range formulaRefToken.FindAllStringSubmatchIndex(segment, -1) {
+			start, end := m[0], m[1]
+			// Ignora partes de nomes, funções (LOG10), tabelas e vínculos externos
+			if start > 0 && isNameChar(rune(segment[start-1]), "$.!]'") {
+				continue
+			}
+			if end < len(segment) && isNameChar(rune(segment[end]), "(![") {
+				continue
+			}
+			sheet := ""
+			if m[2] >= 0 {
+				sheet = strings.ReplaceAll(strings.Trim(segment[m[2]:m[3]], "'"), "''", "'")
+			}
+			out.WriteString(segment[pos:start])
+			out.WriteString(replace(sheet, segment[m[4]:m[5]]))
+			pos = end
+		}
+		out.WriteString(segment[pos:])
+		out.WriteString(formula[lit[0]:lit[1]])
+		last = lit[1]
+	}
+	return out.String()
+}
+
+// isNameChar verifica se o caractere continua um nome (letra, dígito, _) ou está em extra
+func isNameChar(r rune, extra string) bool {
+	return r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+		r > 127 || strings.ContainsRune(extra, r)
+}
+
+// shiftRef desloca as partes relativas de uma referência A1 (sem planilha);
+// referências que saem da planilha viram #REF!
+func shiftRef(ref string, dRow, dCol int) string {
+	parts := strings.Split(ref, ":")
+	for i, part := range parts {
+		m := refEndpoint.FindStringSubmatch(part)
+		if m == nil {
+			return ref
+		}
+		col, row := m[2], m[4]
+		if col != "" && m[1] == "" && dCol != 0 {
+			n, err := excelize.ColumnNameToNumber(col)
+			if err != nil || n+dCol < 1 || n+dCol > excelize.MaxColumns {
+				return "#REF!"
+			}
+			col, _ = excelize.ColumnNumberToName(n + dCol)
+		}
+		if row != "" && m[3] == "" && dRow != 0 {
+			n, _ := strconv.Atoi(row)
+			if n+dRow < 1 || n+dRow > excelize.TotalRows {
+				return "#REF!"
+			}
+			row = strconv.Itoa(n + dRow)
+		}
+		parts[i] = m[1] + col + m[3] + row
+	}
+	return strings.Join(parts, ":")
+}
+
+// shiftFormula desloca as referências relativas de uma fórmula
+func shiftFormula(formula string, dRow, dCol int) string {
+	if formula == "" || (dRow == 0 && dCol == 0) {
+		return formula
+	}
+	return rewriteFormulaRefs(formula, func(sheet, ref string) string {
+		shifted := shiftRef(ref, dRow, dCol)
+		if sheet == "" || shifted == "#REF!" {
+			return shifted
+		}
+		return quoteSheetName(sheet) + "!" + shifted
+	})
+}
@@ -0,0 +1,171 @@
+package excel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	lastRow = excelize.TotalRows - 1
+	lastCol = excelize.MaxColumns - 1
+)
+
+func TestShiftRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		ref        string
+		dRow, dCol int
+		want       string
+	}{
+		{"célula relativa", "B3", 2, 1, "C5"},
+		{"intervalo relativo", "A1:B2", 1, 1, "B2:C3"},
+		{"absoluta não muda", "$B$3", 5, 5, "$B$3"},
+		{"coluna absoluta", "$B3", 1, 1, "$B4"},
+		{"linha absoluta", "B$3", 1, 1, "C$3"},
+		{"coluna inteira", "C:D", 10, -1, "B:C"},
+		{"linha inteira", "2:4", -1, 3, "1:3"},
+		{"para antes da coluna A", "A1", 0, -1, "#REF!"},
+		{"para antes da linha 1", "B1:B5", -1, 0, "#REF!"},
+		{"depois da última coluna", "XFD1", 0, 1, "#REF!"},
+		{"depois da última linha", "A1048576", 1, 0, "#REF!"},
+		{"absoluta na borda", "$A$1", -1, -1, "$A$1"},
+		{"texto que não é referência", "#REF!", 1, 1, "#REF!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shiftRef(tt.ref, tt.dRow, tt.dCol); got != tt.want {
+				t.Errorf("shiftRef(%q, %d, %d) = %q, esperado %q", tt.ref, tt.dRow, tt.dCol, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteFormulaRefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		want    []string // "planilha|referência" na ordem em que aparecem
+	}{
+		{"células e intervalo", "=A1+SUM(B2:C3)", []string{"|A1", "|B2:C3"}},
+		{"absolutas", "=$A$1*B$2", []string{"|$A$1", "|B$2"}},
+		{"planilha simples", "=Plan2!A1", []string{"Plan2|A1"}},
+		{"planilha entre aspas", "='Minha Planilha'!$C:$C", []string{"Minha Planilha|$C:$C"}},
+		{"apóstrofo escapado", "='O''Brien'!1:1", []string{"O'Brien|1:1"}},
+		{"planilha numérica", "='2024'!A1*2", []string{"2024|A1"}},
+		{"ignora texto entre aspas", `=IF(A1="B2",C3,"")`, []string{"|A1", "|C3"}},
+		{"ignora funções e nomes", "=LOG10(A1)+Taxa2024", []string{"|A1"}},
+		{"ignora referência estruturada", "=SUM(Tabela1[Valor])+D4", []string{"|D4"}},
+		{"#REF! não é referência", "=#REF!+A2", []string{"|A2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			out := rewriteFormulaRefs(tt.formula, func(sheet, ref string) string {
+				got = append(got, sheet+"|"+ref)
+				return ref
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("referências de %q = %v, esperado %v", tt.formula, got, tt.want)
+			}
+			if !strings.Contains(tt.formula, "!") && out != tt.formula {
+				t.Errorf("reescrita sem alteração mudou a fórmula: %q", out)
+			}
+		})
+	}
+}
+
+func TestShiftFormula(t *testing.T) {
+	tests := []struct {
+		name       string
+		formula    string
+		dRow, dCol int
+		want       string
+	}{
+		{"relativas e absolutas", "=A1+$B$2+C$3", 1, 1, "=B2+$B$2+D$3"},
+		{"planilha entre aspas preservada", "='Minha Planilha'!A1", 1, 0, "='Minha Planilha'!A2"},
+		{"planilha numérica mantém aspas", "='2024'!A1", 0, 1, "='2024'!B1"},
+		{"saída da planilha vira #REF!", "=A1+B2", -1, 0, "=#REF!+B1"},
+		{"texto não muda", `="A1"&A1`, 1, 0, `="A1"&A2`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shiftFormula(tt.formula, tt.dRow, tt.dCol); got != tt.want {
+				t.Errorf("shiftFormula(%q) = %q, esperado %q", tt.formula, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCellRef(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want cellRef
+		ok   bool
+	}{
+		{"célula", "B3", cellRef{"Plan1", 2, 1, 2, 1}, true},
+		{"absoluta", "$A$1:$B$5", cellRef{"Plan1", 0, 0, 4, 1}, true},
+		{"extremos invertidos", "D5:B2", cellRef{"Plan1", 1, 1, 4, 3}, true},
+		{"outra planilha", "Plan2!C2", cellRef{"Plan2", 1, 2, 1, 2}, true},
+		{"planilha entre aspas", "'Minha Planilha'!A1", cellRef{"Minha Planilha", 0, 0, 0, 0}, true},
+		{"coluna inteira", "A:B", cellRef{"Plan1", 0, 0, lastRow, 1}, true},
+		{"linha inteira", "'Dados 2024'!3:3", cellRef{"Dados 2024", 2, 0, 2, lastCol}, true},
+		{"coluna sozinha", "A", cellRef{}, false},
+		{"coluna com linha", "A:3", cellRef{}, false},
+		{"três partes", "A1:B2:C3", cellRef{}, false},
+		{"linha fora do limite", "A0", cellRef{}, false},
+		{"#REF!", "#REF!", cellRef{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCellRef("Plan1", tt.text)
+			if ok != tt.ok || (ok && got != tt.want) {
+				t.Errorf("parseCellRef(%q) = %+v, %v; esperado %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	area := func(ref string) cellRef {
+		r, ok := parseCellRef("Plan1", ref)
+		if !ok {
+			t.Fatalf("referência inválida no teste: %s", ref)
+		}
+		return r
+	}
+	tests := []struct {
+		name    string
+		areas   []string
+		targets []string
+		want    string
+	}{
+		{"sem sobreposição", []string{"A1:A4"}, []string{"C1"}, "A1:A4"},
+		{"célula no meio da coluna", []string{"A1:A4"}, []string{"A2"}, "A1 A3:A4"},
+		{"primeira célula", []string{"A1:A4"}, []string{"A1"}, "A2:A4"},
+		{"última célula", []string{"A1:A4"}, []string{"A4"}, "A1:A3"},
+		{"cobre tudo", []string{"A1:B2"}, []string{"A1:C3"}, ""},
+		{"centro do bloco", []string{"A1:C3"}, []string{"B2"}, "A1:C1 A3:C3 A2 C2"},
+		{"vários alvos", []string{"A1:A10"}, []string{"A2", "A5:A6"}, "A1 A3:A4 A7:A10"},
+		{"várias áreas", []string{"A1:A3", "C1:C3"}, []string{"A1:C1"}, "A2:A3 C2:C3"},
+		{"coluna inteira menos cabeçalho", []string{"B:B"}, []string{"B1"}, "B2:B1048576"},
+		{"linha inteira", []string{"A1:C3"}, []string{"2:2"}, "A1:C1 A3:C3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var areas, targets []cellRef
+			for _, ref := range tt.areas {
+				areas = append(areas, area(ref))
+			}
+			for _, ref := range tt.targets {
+				targets = append(targets, area(ref))
+			}
+			if got := sqrefText(subtractAll(areas, targets)); got != tt.want {
+				t.Errorf("subtração = %q, esperado %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package excel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// tabColorTag cor da aba na parte XML da planilha
+var tabColorTag = regexp.MustCompile(`<tabColor\b[^>]*?(?:/>|>\s*</tabColor>)`)
+
+// GetSheets lista as planilhas com posição, visibilidade, cor da aba e exibição
+func (c *ExcelizeClient) GetSheets() ([]SheetInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := c.file.GetActiveSheetIndex()
+	sheets := []SheetInfo{}
+	for i, name := range c.file.GetSheetList() {
+		info := SheetInfo{
+			Name:       name,
+			Position:   i + 1,
+			Visibility: c.sheetStateLocked(i),
+			Active:     i == active,
+		}
+		if props, err := c.file.GetSheetProps(name); err == nil && props.TabColorRGB != nil {
+			info.TabColor = hexColor(*props.TabColorRGB)
+		}
+		view, err := c.file.GetSheetView(name, 0)
+		if err != nil {
+			return nil, err
+		}
+		info.View = SheetView{
+			Zoom:        int(*view.ZoomScale),
+			Gridlines:   view.ShowGridLines,
+			Headings:    view.ShowRowColHeaders,
+			ShowZeros:   view.ShowZeros,
+			RightToLeft: view.RightToLeft,
+			View:        *view.View,
+		}
+		if view.TopLeftCell != nil {
+			info.View.TopLeftCell = *view.TopLeftCell
+		}
+		sheets = append(sheets, info)
+	}
+	return sheets, nil
+}
+
+// MoveSheet move uma planilha para a posição indicada (1 = primeira aba)
+func (c *ExcelizeClient) MoveSheet(sheet string, position int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from, err := c.sheetIndexLocked(sheet)
+	if err != nil {
+		return err
+	}
+	count := len(c.file.GetSheetList())
+	if position < 1 || position > count {
+		return fmt.Errorf("invalid position: %d (use 1 to %d)", position, count)
+	}
+	to := position - 1
+	if from == to {
+		return nil
+	}
+	active := c.file.GetSheetName(c.file.GetActiveSheetIndex())
+
+	// O Excelize só insere antes de outra aba e não atualiza os nomes locais (área
+	// de impressão, filtros, nomes da planilha), que guardam a posição da aba
+	wb := c.file.WorkBook
+	tabs := wb.Sheets.Sheet
+	for i := from; i < to; i++ {
+		tabs[i], tabs[i+1] = tabs[i+1], tabs[i]
+	}
+	for i := from; i > to; i-- {
+		tabs[i], tabs[i-1] = tabs[i-1], tabs[i]
+	}
+	if wb.DefinedNames != nil {
+		for i, dn := range wb.DefinedNames.DefinedName {
+			if dn.LocalSheetID == nil {
+				continue
+			}
+			id := *dn.LocalSheetID
+			switch {
+			case id == from:
+				id = to
+			case from < to && id > from && id <= to:
+				id--
+			case to < from && id >= to && id < from:
+				id++
+			}
+			wb.DefinedNames.DefinedName[i].LocalSheetID = &id
+		}
+	}
+
+	index, _ := c.file.GetSheetIndex(active)
+	c.file.SetActiveSheet(index)
+	return nil
+}
+
+// SetActiveSheet define a aba exibida ao abrir o arquivo
+func (c *ExcelizeClient) SetActiveSheet(sheet string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index, err := c.sheetIndexLocked(sheet)
+	if err != nil {
+		return err
+	}
+	if c.sheetStateLocked(index) != "visible" {
+		return fmt.Errorf("sheet %s is hidden, show it before activating", sheet)
+	}
+	c.file.SetActiveSheet(index)
+	return nil
+}
+
+// SetSheetVisibility exibe ou oculta uma planilha (visible, hidden ou veryHidden).
+// Planilhas veryHidden só podem ser reexibidas por código ou pelo editor de VBA.
+func (c *ExcelizeClient) SetSheetVisibility(sheet, state string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch strings.ToLower(strings.TrimSpace(state)) {
+	case "visible", "show":
+		if _, err := c.sheetIndexLocked(sheet); err != nil {
+			return err
+		}
+		return c.file.SetSheetVisible(sheet, true)
+	case "hidden", "hide":
+		return c.hideSheetLocked(sheet, false)
+	case "veryhidden":
+		return c.hideSheetLocked(sheet, true)
+	default:
+		return fmt.Errorf("invalid visibility: %s (use visible, hidden or veryHidden)", state)
+	}
+}
+
+// SetTabColor define a cor da aba ("" ou "none" remove)
+func (c *ExcelizeClient) SetTabColor(sheet, color string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.sheetIndexLocked(sheet); err != nil {
+		return err
+	}
+	color = strings.TrimSpace(color)
+	if color != "" && !strings.EqualFold(color, "none") {
+		rgb := hexColor(color)
+		if rgb == "" {
+			return fmt.Errorf("invalid color: %s", color)
+		}
+		argb := "FF" + strings.TrimPrefix(rgb, "#")
+		return c.file.SetSheetProps(sheet, &excelize.SheetPropsOptions{TabColorRGB: &argb})
+	}
+
+	// O Excelize não remove a cor da aba
+	pkg, err := c.readPackageLocked()
+	if err != nil {
+		return err
+	}
+	part, err := pkg.sheetPart(sheet)
+	if err != nil {
+		return err
+	}
+	content, ok := c.file.Pkg.Load(part)
+	if !ok {
+		return fmt.Errorf("part not found: %s", part)
+	}
+	c.file.Pkg.Store(part, tabColorTag.ReplaceAll(content.([]byte), nil))
+	// Descarta a versão carregada para que a próxima leitura use o XML alterado
+	c.file.Sheet.Delete(part)
+	return nil
+}
+
+// SetSheetView altera zoom, linhas de grade, cabeçalhos e modo de exibição da planilha
+func (c *ExcelizeClient) SetSheetView(sheet string, view SheetView) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.sheetIndexLocked(sheet); err != nil {
+		return err
+	}
+	opts := excelize.ViewOptions{
+		ShowGridLines:     view.Gridlines,
+		ShowRowColHeaders: view.Headings,
+		ShowZeros:         view.ShowZeros,
+		RightToLeft:       view.RightToLeft,
+	}
+	if view.Zoom != 0 {
+		if view.Zoom < 10 || view.Zoom > 400 {
+			return fmt.Errorf("invalid zoom: %d (use 10 to 400)", view.Zoom)
+		}
+		zoom := float64(view.Zoom)
+		opts.ZoomScale = &zoom
+	}
+	if view.View != "" {
+		mode := strings.TrimSpace(view.View)
+		switch strings.ToLower(mode) {
+		case "normal":
+			mode = "normal"
+		case "pagelayout":
+			mode = "pageLayout"
+		case "pagebreakpreview":
+			mode = "pageBreakPreview"
+		default:
+			return fmt.Errorf("invalid view: %s (use normal, pageLayout or pageBreakPreview)", view.View)
+		}
+		opts.View = &mode
+	}
+	if view.TopLeftCell != "" {
+		cell := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(view.TopLeftCell), "$", ""))
+		if _, _, err := excelize.CellNameToCoordinates(cell); err != nil {
+			return fmt.Errorf("invalid cell: %s", view.TopLeftCell)
+		}
+		opts.TopLeftCell = &cell
+	}
+	return c.file.SetSheetView(sheet, 0, &opts)
+}
+
+// hideSheetLocked oculta uma planilha, ativando outra se for a aba ativa (chamar com c.mu)
+func (c *ExcelizeClient) hideSheetLocked(sheet string, veryHidden bool) error {
+	index, err := c.sheetIndexLocked(sheet)
+	if err != nil {
+		return err
+	}
+
+	// O Excelize ignora o pedido quando a planilha é a aba ativa ou a última visível
+	next := -1
+	for i := range c.file.GetSheetList() {
+		if i != index && c.sheetStateLocked(i) == "visible" {
+			next = i
+			break
+		}
+	}
+	if next < 0 {
+		return fmt.Errorf("cannot hide %s: a workbook needs at least one visible sheet", sheet)
+	}
+	if c.file.GetActiveSheetIndex() == index {
+		c.file.SetActiveSheet(next)
+	}
+	return c.file.SetSheetVisible(sheet, false, veryHidden)
+}
+
+// sheetIndexLocked posição da planilha na pasta (chamar com c.mu)
+func (c *ExcelizeClient) sheetIndexLocked(sheet string) (int, error) {
+	index, err := c.file.GetSheetIndex(sheet)
+	if err != nil {
+		return -1, err
+	}
+	if index < 0 {
+		return -1, fmt.Errorf("sheet %s does not exist", sheet)
+	}
+	return index, nil
+}
+
+// sheetStateLocked visibilidade da planilha na posição indicada (chamar com c.mu)
+func (c *ExcelizeClient) sheetStateLocked(index int) string {
+	switch c.file.WorkBook.Sheets.Sheet[index].State {
+	case "hidden":
+		return "hidden"
+	case "veryHidden":
+		return "veryHidden"
+	default:
+		return "visible"
+	}
+}
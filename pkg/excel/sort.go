@@ -0,0 +1,279 @@
+package excel
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Classes de valores na ordem crescente do Excel; vazias ficam sempre no final
+const (
+	sortNumber = iota
+	sortText
+	sortBool
+	sortError
+	sortBlank
+)
+
+// sortValue valor de uma célula preparado para comparação
+type sortValue struct {
+	class int
+	num   float64
+	text  string
+}
+
+// accentFolder remove acentos para que "Álvaro" fique junto de "Alvaro"
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// SortRange ordena as linhas de um intervalo pelas chaves de opts, de forma estável.
+// Valores, fórmulas (com referências relativas ajustadas) e estilos acompanham a
+// linha. Retorna, para cada linha do intervalo, a posição que ela ocupava antes.
+func (c *ExcelizeClient) SortRange(sheet, rng string, opts SortOptions) ([]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.areaLocked(sheet, rng)
+	if !ok {
+		return nil, fmt.Errorf("invalid range: %s", rng)
+	}
+	c.clampToUsedAreaLocked(&area)
+	if len(opts.Keys) == 0 {
+		return nil, fmt.Errorf("sort requires at least one key")
+	}
+	width := area.EndCol - area.StartCol + 1
+	for _, key := range opts.Keys {
+		if key.Column < 1 || key.Column > width {
+			return nil, fmt.Errorf("sort column %d is outside range %s", key.Column, rng)
+		}
+		switch key.Order {
+		case "", "asc", "desc":
+		default:
+			return nil, fmt.Errorf("invalid sort order: %s", key.Order)
+		}
+		switch key.Type {
+		case "", "auto", "number", "date", "text":
+		case "custom":
+			if len(key.CustomList) == 0 {
+				return nil, fmt.Errorf("custom sort requires customList")
+			}
+		default:
+			return nil, fmt.Errorf("invalid sort type: %s", key.Type)
+		}
+	}
+
+	header := false
+	t, inTable := c.newRefResolverLocked().tableAt(area.Sheet, area.StartRow, area.StartCol)
+	if opts.Header != nil {
+		header = *opts.Header
+	} else if inTable {
+		header = t.Area.StartRow == area.StartRow
+	}
+
+	body := area
+	if header {
+		body.StartRow++
+	}
+	// A linha de totais de uma tabela fica no lugar
+	if inTable && t.Totals && body.EndRow == t.Area.EndRow {
+		body.EndRow--
+	}
+	rows := body.EndRow - body.StartRow + 1
+	order := make([]int, rows)
+	for i := range order {
+		order[i] = i
+	}
+
+	if rows > 1 {
+		merges, _ := c.file.GetMergeCells(area.Sheet)
+		for _, merge := range merges {
+			if ref, ok := parseCellRef(area.Sheet, merge.GetStartAxis()+":"+merge.GetEndAxis()); ok && ref.intersects(body) {
+				return nil, fmt.Errorf("cannot sort range with merged cells: %s", ref)
+			}
+		}
+
+		values := make([][]sortValue, rows)
+		for i := range values {
+			values[i] = make([]sortValue, len(opts.Keys))
+			for k, key := range opts.Keys {
+				cell := indicesToCell(body.StartRow+i, body.StartCol+key.Column-1)
+				values[i][k] = c.sortValueLocked(area.Sheet, cell, key)
+			}
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			a, b := values[order[i]], values[order[j]]
+			for k, key := range opts.Keys {
+				if cmp := compareSortValues(a[k], b[k], key.Order == "desc"); cmp != 0 {
+					return cmp < 0
+				}
+			}
+			return false
+		})
+
+		if err := c.reorderRowsLocked(body, order); err != nil {
+			return nil, err
+		}
+	}
+
+	if header {
+		for i := range order {
+			order[i]++
+		}
+		order = append([]int{0}, order...)
+	}
+	if len(order) < area.EndRow-area.StartRow+1 {
+		order = append(order, len(order))
+	}
+	return order, nil
+}
+
+// ReorderRows reposiciona as linhas de um intervalo: a linha order[i] passa para a
+// posição i. Usado para desfazer uma ordenação.
+func (c *ExcelizeClient) ReorderRows(sheet, rng string, order []int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.areaLocked(sheet, rng)
+	if !ok {
+		return fmt.Errorf("invalid range: %s", rng)
+	}
+	c.clampToUsedAreaLocked(&area)
+	if len(order) != area.EndRow-area.StartRow+1 {
+		return fmt.Errorf("row order has %d rows, range %s has %d", len(order), rng, area.EndRow-area.StartRow+1)
+	}
+	seen := make([]bool, len(order))
+	for _, i := range order {
+		if i < 0 || i >= len(order) || seen[i] {
+			return fmt.Errorf("invalid row order")
+		}
+		seen[i] = true
+	}
+	return c.reorderRowsLocked(area, order)
+}
+
+// reorderRowsLocked move o conteúdo das linhas da área conforme order (chamar com c.mu)
+func (c *ExcelizeClient) reorderRowsLocked(area cellRef, order []int) error {
+	contents := make([][]cellContent, len(order))
+	hasFormula := false
+	for i := range contents {
+		contents[i] = make([]cellContent, area.EndCol-area.StartCol+1)
+		for j := range contents[i] {
+			content, err := c.readCellLocked(area.Sheet, indicesToCell(area.StartRow+i, area.StartCol+j))
+			if err != nil {
+				return err
+			}
+			contents[i][j] = content
+			hasFormula = hasFormula || content.Formula != ""
+		}
+	}
+	if hasFormula {
+		if err := c.unshareFormulasLocked(area.Sheet); err != nil {
+			return err
+		}
+	}
+
+	for i, from := range order {
+		if from == i {
+			continue
+		}
+		for j, content := range contents[from] {
+			content.Formula = shiftFormula(content.Formula, i-from, 0)
+			if err := c.writeCellLocked(area.Sheet, indicesToCell(area.StartRow+i, area.StartCol+j), content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortValueLocked prepara o valor de uma célula para a chave de ordenação (chamar com c.mu)
+func (c *ExcelizeClient) sortValueLocked(sheet, cell string, key SortKey) sortValue {
+	raw := strings.TrimSpace(c.rawCellValueLocked(sheet, cell))
+	if raw == "" {
+		return sortValue{class: sortBlank}
+	}
+	display, _ := c.file.GetCellValue(sheet, cell)
+	text := accentFolder.Replace(strings.ToLower(strings.TrimSpace(display)))
+	num, numErr := strconv.ParseFloat(raw, 64)
+
+	switch key.Type {
+	case "text":
+		return sortValue{class: sortText, text: text}
+	case "number":
+		if numErr == nil {
+			return sortValue{class: sortNumber, num: num}
+		}
+		return sortValue{class: sortText, text: text}
+	case "date":
+		if numErr == nil {
+			return sortValue{class: sortNumber, num: num}
+		}
+		if serial, ok := dateSerial(raw); ok {
+			return sortValue{class: sortNumber, num: serial}
+		}
+		return sortValue{class: sortText, text: text}
+	case "custom":
+		for i, item := range key.CustomList {
+			if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(display)) {
+				return sortValue{class: sortNumber, num: float64(i)}
+			}
+		}
+		// Valores fora da lista vêm depois, em ordem alfabética
+		return sortValue{class: sortText, text: text}
+	}
+
+	cellType, _ := c.file.GetCellType(sheet, cell)
+	switch {
+	case cellType == excelize.CellTypeBool:
+		return sortValue{class: sortBool, text: text}
+	case cellType == excelize.CellTypeError || strings.HasPrefix(raw, "#"):
+		return sortValue{class: sortError, text: text}
+	case numErr == nil:
+		// Números gravados como texto também são comparados como números
+		return sortValue{class: sortNumber, num: num}
+	}
+	return sortValue{class: sortText, text: text}
+}
+
+// compareSortValues compara dois valores; em ordem decrescente as classes e os
+// valores são invertidos, mas as células vazias continuam no final
+func compareSortValues(a, b sortValue, desc bool) int {
+	if a.class == sortBlank || b.class == sortBlank {
+		switch {
+		case a.class == b.class:
+			return 0
+		case a.class == sortBlank:
+			return 1
+		default:
+			return -1
+		}
+	}
+
+	cmp := a.class - b.class
+	if cmp == 0 {
+		if a.class == sortNumber {
+			switch {
+			case a.num < b.num:
+				cmp = -1
+			case a.num > b.num:
+				cmp = 1
+			}
+		} else {
+			cmp = strings.Compare(a.text, b.text)
+		}
+	}
+	if desc {
+		return -cmp
+	}
+	return cmp
+}
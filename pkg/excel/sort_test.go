@@ -0,0 +1,48 @@
+package excel
+
+import "testing"
+
+func TestCompareSortValues(t *testing.T) {
+	num := func(n float64) sortValue { return sortValue{class: sortNumber, num: n} }
+	text := func(s string) sortValue { return sortValue{class: sortText, text: s} }
+	blank := sortValue{class: sortBlank}
+
+	tests := []struct {
+		name string
+		a, b sortValue
+		desc bool
+		want int
+	}{
+		{"números crescente", num(2), num(10), false, -1},
+		{"números decrescente", num(2), num(10), true, 1},
+		{"números iguais", num(3), num(3), false, 0},
+		{"negativos", num(-5), num(-1), false, -1},
+		{"textos", text("abacaxi"), text("banana"), false, -1},
+		{"textos decrescente", text("abacaxi"), text("banana"), true, 1},
+		{"número antes de texto", num(100), text("a"), false, -1},
+		{"texto antes de número no decrescente", num(100), text("a"), true, 1},
+		{"texto antes de lógico", text("z"), sortValue{class: sortBool, text: "false"}, false, -1},
+		{"lógico antes de erro", sortValue{class: sortBool, text: "true"}, sortValue{class: sortError, text: "#n/a"}, false, -1},
+		{"vazio no final", blank, num(1), false, 1},
+		{"vazio no final no decrescente", blank, num(1), true, 1},
+		{"valor antes do vazio no decrescente", text("a"), blank, true, -1},
+		{"vazios iguais", blank, blank, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareSortValues(tt.a, tt.b, tt.desc); sign(got) != tt.want {
+				t.Errorf("compareSortValues(%+v, %+v, %v) = %d, esperado %d", tt.a, tt.b, tt.desc, got, tt.want)
+			}
+		})
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
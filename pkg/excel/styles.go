@@ -0,0 +1,532 @@
+package excel
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// numberFormatPresets atalhos aceitos em Format.NumberFormat (formatos brasileiros)
+var numberFormatPresets = map[string]string{
+	"general":    "General",
+	"number":     "#,##0.00",
+	"integer":    "#,##0",
+	"currency":   `"R$" #,##0.00;[Red]-"R$" #,##0.00`,
+	"accounting": `_-"R$" * #,##0.00_-;-"R$" * #,##0.00_-;_-"R$" * "-"??_-;_-@_-`,
+	"percent":    "0.00%",
+	"percent0":   "0%",
+	"date":       "dd/mm/yyyy",
+	"datetime":   "dd/mm/yyyy hh:mm",
+	"time":       "hh:mm:ss",
+	"scientific": "0.00E+00",
+	"text":       "@",
+}
+
+// builtInNumberFormats códigos com identificador nativo no Excel
+var builtInNumberFormats = map[string]int{
+	"General":  0,
+	"0":        1,
+	"0.00":     2,
+	"#,##0":    3,
+	"#,##0.00": 4,
+	"0%":       9,
+	"0.00%":    10,
+	"0.00E+00": 11,
+	"mm-dd-yy": 14,
+	"d-mmm-yy": 15,
+	"h:mm":     20,
+	"h:mm:ss":  21,
+	"@":        49,
+}
+
+var (
+	horizontalAlignments = map[string]bool{"left": true, "center": true, "right": true, "justify": true, "fill": true, "distributed": true, "centerContinuous": true}
+	verticalAlignments   = map[string]bool{"top": true, "center": true, "bottom": true, "justify": true, "distributed": true}
+	underlineStyles      = map[string]bool{"single": true, "double": true, "none": true}
+)
+
+// FormatRange aplica a formatação a um intervalo, preservando o restante do
+// estilo de cada célula
+func (c *ExcelizeClient) FormatRange(sheet, rng string, format Format) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := format.validate(); err != nil {
+		return err
+	}
+	return c.updateStyleLocked(sheet, rng, func(style *excelize.Style) {
+		format.apply(style)
+	})
+}
+
+// updateStyleLocked altera o estilo de cada célula do intervalo a partir do estilo
+// atual dela (chamar com c.mu)
+func (c *ExcelizeClient) updateStyleLocked(sheet, rng string, change func(style *excelize.Style)) error {
+	area, ok := c.areaLocked(sheet, rng)
+	if !ok {
+		return fmt.Errorf("invalid range: %s", rng)
+	}
+	c.clampToUsedAreaLocked(&area)
+
+	// Células com o mesmo estilo de origem compartilham o novo estilo
+	merged := make(map[int]int)
+	for row := area.StartRow; row <= area.EndRow; row++ {
+		for col := area.StartCol; col <= area.EndCol; col++ {
+			cell := indicesToCell(row, col)
+			current, err := c.file.GetCellStyle(area.Sheet, cell)
+			if err != nil {
+				return fmt.Errorf("failed to read style of %s: %w", cell, err)
+			}
+
+			styleID, ok := merged[current]
+			if !ok {
+				style, err := c.file.GetStyle(current)
+				if err != nil {
+					return fmt.Errorf("failed to read style of %s: %w", cell, err)
+				}
+				change(style)
+				if styleID, err = c.file.NewStyle(style); err != nil {
+					return fmt.Errorf("failed to create style: %w", err)
+				}
+				merged[current] = styleID
+			}
+
+			if err := c.file.SetCellStyle(area.Sheet, cell, cell, styleID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// clampToUsedAreaLocked limita colunas e linhas inteiras (A:A, 1:1) à área usada da planilha
+func (c *ExcelizeClient) clampToUsedAreaLocked(area *cellRef) {
+	if area.EndRow < excelize.TotalRows-1 && area.EndCol < excelize.MaxColumns-1 {
+		return
+	}
+
+	// A dimensão gravada no arquivo não acompanha células escritas nesta sessão
+	var used cellRef
+	if dimension, err := c.file.GetSheetDimension(area.Sheet); err == nil {
+		used, _ = parseCellRef(area.Sheet, dimension)
+	}
+	if rows, err := c.file.GetRows(area.Sheet); err == nil {
+		used.EndRow = max(used.EndRow, len(rows)-1)
+		for _, row := range rows {
+			used.EndCol = max(used.EndCol, len(row)-1)
+		}
+	}
+	area.EndRow = min(area.EndRow, max(used.EndRow, area.StartRow))
+	area.EndCol = min(area.EndCol, max(used.EndCol, area.StartCol))
+}
+
+// validate verifica os valores enumerados da formatação
+func (f Format) validate() error {
+	if f.HorizontalAlign != "" && !horizontalAlignments[f.HorizontalAlign] {
+		return fmt.Errorf("invalid horizontal alignment: %s", f.HorizontalAlign)
+	}
+	if f.VerticalAlign != "" && !verticalAlignments[f.VerticalAlign] {
+		return fmt.Errorf("invalid vertical alignment: %s", f.VerticalAlign)
+	}
+	if f.Underline != "" && !underlineStyles[f.Underline] {
+		return fmt.Errorf("invalid underline style: %s", f.Underline)
+	}
+	if f.Indent != nil && (*f.Indent < 0 || *f.Indent > 250) {
+		return fmt.Errorf("indent must be between 0 and 250")
+	}
+	if f.TextRotation != nil && *f.TextRotation != 255 && (*f.TextRotation < -90 || *f.TextRotation > 90) {
+		return fmt.Errorf("text rotation must be between -90 and 90, or 255 for vertical text")
+	}
+	if f.FontSize != 0 && (f.FontSize < excelize.MinFontSize || f.FontSize > excelize.MaxFontSize) {
+		return fmt.Errorf("font size must be between %d and %d", excelize.MinFontSize, excelize.MaxFontSize)
+	}
+	return nil
+}
+
+// apply sobrepõe os campos preenchidos da formatação ao estilo
+func (f Format) apply(style *excelize.Style) {
+	if f.Bold != nil || f.Italic != nil || f.Underline != "" || f.Strikethrough != nil ||
+		f.FontFamily != "" || f.FontSize != 0 || f.FontColor != "" {
+		if style.Font == nil {
+			style.Font = &excelize.Font{}
+		}
+		if f.Bold != nil {
+			style.Font.Bold = *f.Bold
+		}
+		if f.Italic != nil {
+			style.Font.Italic = *f.Italic
+		}
+		if f.Strikethrough != nil {
+			style.Font.Strike = *f.Strikethrough
+		}
+		switch f.Underline {
+		case "":
+		case "none":
+			style.Font.Underline = ""
+		default:
+			style.Font.Underline = f.Underline
+		}
+		if f.FontFamily != "" {
+			style.Font.Family = f.FontFamily
+		}
+		if f.FontSize != 0 {
+			style.Font.Size = f.FontSize
+		}
+		if f.FontColor != "" {
+			style.Font.Color = strings.TrimPrefix(f.FontColor, "#")
+			style.Font.ColorTheme = nil
+			style.Font.ColorIndexed = 0
+		}
+	}
+
+	switch strings.ToLower(f.BgColor) {
+	case "":
+	case "none":
+		style.Fill = excelize.Fill{}
+	default:
+		style.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{f.BgColor}}
+	}
+
+	if f.NumberFormat != "" {
+		code := f.NumberFormat
+		if preset, ok := numberFormatPresets[strings.ToLower(code)]; ok {
+			code = preset
+		}
+		if id, ok := builtInNumberFormats[code]; ok {
+			style.NumFmt, style.CustomNumFmt = id, nil
+		} else {
+			style.NumFmt, style.CustomNumFmt = 0, &code
+		}
+		style.DecimalPlaces = nil
+	}
+
+	if f.HorizontalAlign != "" || f.VerticalAlign != "" || f.WrapText != nil || f.Indent != nil || f.TextRotation != nil {
+		if style.Alignment == nil {
+			style.Alignment = &excelize.Alignment{}
+		}
+		if f.HorizontalAlign != "" {
+			style.Alignment.Horizontal = f.HorizontalAlign
+		}
+		if f.VerticalAlign != "" {
+			style.Alignment.Vertical = f.VerticalAlign
+		}
+		if f.WrapText != nil {
+			style.Alignment.WrapText = *f.WrapText
+		}
+		if f.Indent != nil {
+			style.Alignment.Indent = *f.Indent
+		}
+		if f.TextRotation != nil {
+			// No Excel, ângulos negativos são gravados como 91-180
+			rotation := *f.TextRotation
+			if rotation < 0 {
+				rotation = 90 - rotation
+			}
+			style.Alignment.TextRotation = rotation
+		}
+	}
+}
+
+// GetFormat lê a formatação da primeira célula de um intervalo
+func (c *ExcelizeClient) GetFormat(sheet, rng string) (*Format, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.areaLocked(sheet, rng)
+	if !ok {
+		return nil, fmt.Errorf("invalid range: %s", rng)
+	}
+	styleID, err := c.file.GetCellStyle(area.Sheet, indicesToCell(area.StartRow, area.StartCol))
+	if err != nil {
+		return nil, err
+	}
+	style, err := c.file.GetStyle(styleID)
+	if err != nil {
+		return nil, err
+	}
+	format := c.formatFromStyle(style)
+	return &format, nil
+}
+
+// GetRangeFormats agrupa as células do intervalo por formatação e inclui
+// larguras de coluna e alturas de linha personalizadas
+func (c *ExcelizeClient) GetRangeFormats(sheet, rng string) (*RangeFormats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.areaLocked(sheet, rng)
+	if !ok {
+		return nil, fmt.Errorf("invalid range: %s", rng)
+	}
+	c.clampToUsedAreaLocked(&area)
+
+	ids, err := c.cellStylesLocked(area)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocos retangulares com o mesmo estilo: sequências na linha que se repetem
+	// nas linhas seguintes são estendidas para baixo
+	type block struct {
+		id  int
+		ref cellRef
+	}
+	var blocks []block
+	open := make(map[[3]int]int)
+	for i, row := range ids {
+		for start := 0; start < len(row); {
+			end := start
+			for end+1 < len(row) && row[end+1] == row[start] {
+				end++
+			}
+			key := [3]int{row[start], start, end}
+			if idx, ok := open[key]; ok && blocks[idx].ref.EndRow == area.StartRow+i-1 {
+				blocks[idx].ref.EndRow++
+			} else {
+				open[key] = len(blocks)
+				blocks = append(blocks, block{id: row[start], ref: cellRef{
+					Sheet:    area.Sheet,
+					StartRow: area.StartRow + i,
+					StartCol: area.StartCol + start,
+					EndRow:   area.StartRow + i,
+					EndCol:   area.StartCol + end,
+				}})
+			}
+			start = end + 1
+		}
+	}
+
+	// Estilos diferentes com a mesma formatação visível formam um único grupo
+	result := &RangeFormats{Range: area.String(), Styles: []StyledCells{}}
+	groups := make(map[string]int)
+	described := make(map[int]StyledCells)
+	for _, b := range blocks {
+		styled, ok := described[b.id]
+		if !ok {
+			style, err := c.file.GetStyle(b.id)
+			if err != nil {
+				return nil, err
+			}
+			styled = StyledCells{Format: c.formatFromStyle(style), Borders: describeBorders(style.Border)}
+			described[b.id] = styled
+		}
+
+		key, _ := json.Marshal(styled)
+		idx, ok := groups[string(key)]
+		if !ok {
+			idx = len(result.Styles)
+			groups[string(key)] = idx
+			result.Styles = append(result.Styles, styled)
+		}
+		ref := b.ref
+		ref.Sheet = ""
+		result.Styles[idx].Ranges = append(result.Styles[idx].Ranges, strings.TrimPrefix(ref.String(), "!"))
+	}
+
+	result.ColumnWidths = make(map[string]float64)
+	for col := area.StartCol; col <= area.EndCol; col++ {
+		name, _ := excelize.ColumnNumberToName(col + 1)
+		if width, err := c.file.GetColWidth(area.Sheet, name); err == nil {
+			result.ColumnWidths[name] = width
+		}
+	}
+
+	defaultHeight := 15.0
+	if props, err := c.file.GetSheetProps(area.Sheet); err == nil && props.DefaultRowHeight != nil {
+		defaultHeight = *props.DefaultRowHeight
+	}
+	for row := area.StartRow + 1; row <= area.EndRow+1; row++ {
+		height, err := c.file.GetRowHeight(area.Sheet, row)
+		if err != nil || height == defaultHeight {
+			continue
+		}
+		if result.RowHeights == nil {
+			result.RowHeights = make(map[int]float64)
+		}
+		result.RowHeights[row] = height
+	}
+	return result, nil
+}
+
+// GetCellStyles guarda os estilos de cada célula de um intervalo
+func (c *ExcelizeClient) GetCellStyles(sheet, rng string) (*StyleSnapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	area, ok := c.areaLocked(sheet, rng)
+	if !ok {
+		return nil, fmt.Errorf("invalid range: %s", rng)
+	}
+	c.clampToUsedAreaLocked(&area)
+
+	ids, err := c.cellStylesLocked(area)
+	if err != nil {
+		return nil, err
+	}
+	return &StyleSnapshot{Sheet: area.Sheet, Cell: indicesToCell(area.StartRow, area.StartCol), Styles: ids}, nil
+}
+
+// SetCellStyles restaura estilos guardados por GetCellStyles
+func (c *ExcelizeClient) SetCellStyles(sheet string, snapshot StyleSnapshot) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, _, err := excelize.CellNameToCoordinates(snapshot.Cell); err != nil {
+		return fmt.Errorf("invalid cell: %s", snapshot.Cell)
+	}
+	if snapshot.Sheet != "" {
+		sheet = snapshot.Sheet
+	}
+	startRow, startCol := cellToIndices(snapshot.Cell)
+	for i, row := range snapshot.Styles {
+		for j, styleID := range row {
+			cell := indicesToCell(startRow+i, startCol+j)
+			if err := c.file.SetCellStyle(sheet, cell, cell, styleID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetColumnWidth retorna a largura de uma coluna
+func (c *ExcelizeClient) GetColumnWidth(sheet, col string) (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.file.GetColWidth(sheet, col)
+}
+
+// GetRowHeight retorna a altura de uma linha
+func (c *ExcelizeClient) GetRowHeight(sheet, row string) (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rowNum, err := strconv.Atoi(row)
+	if err != nil {
+		return 0, fmt.Errorf("invalid row number: %w", err)
+	}
+
+	return c.file.GetRowHeight(sheet, rowNum)
+}
+
+// cellStylesLocked identificadores de estilo das células de um intervalo (chamar com c.mu)
+func (c *ExcelizeClient) cellStylesLocked(area cellRef) ([][]int, error) {
+	ids := make([][]int, 0, area.EndRow-area.StartRow+1)
+	for row := area.StartRow; row <= area.EndRow; row++ {
+		line := make([]int, 0, area.EndCol-area.StartCol+1)
+		for col := area.StartCol; col <= area.EndCol; col++ {
+			styleID, err := c.file.GetCellStyle(area.Sheet, indicesToCell(row, col))
+			if err != nil {
+				return nil, err
+			}
+			line = append(line, styleID)
+		}
+		ids = append(ids, line)
+	}
+	return ids, nil
+}
+
+// formatFromStyle converte um estilo do Excelize em Format, no formato aceito por FormatRange
+func (c *ExcelizeClient) formatFromStyle(style *excelize.Style) Format {
+	var format Format
+	enabled := func(v bool) *bool {
+		if !v {
+			return nil
+		}
+		return &v
+	}
+
+	if font := style.Font; font != nil {
+		format.Bold = enabled(font.Bold)
+		format.Italic = enabled(font.Italic)
+		format.Strikethrough = enabled(font.Strike)
+		format.Underline = font.Underline
+		format.FontFamily = font.Family
+		format.FontSize = font.Size
+
+		// Cores de tema e indexadas são convertidas para RGB
+		color := font.Color
+		if color == "" && (font.ColorTheme != nil || font.ColorIndexed != 0) {
+			if base := c.file.GetBaseColor("", font.ColorIndexed, font.ColorTheme); base != "" {
+				color = excelize.ThemeColor(base, font.ColorTint)
+			}
+		}
+		format.FontColor = hexColor(color)
+	}
+
+	if style.Fill.Type == "pattern" && style.Fill.Pattern == 1 && len(style.Fill.Color) > 0 {
+		format.BgColor = hexColor(style.Fill.Color[0])
+	}
+
+	if style.CustomNumFmt != nil {
+		format.NumberFormat = *style.CustomNumFmt
+	} else if style.NumFmt != 0 {
+		for code, id := range builtInNumberFormats {
+			if id == style.NumFmt {
+				format.NumberFormat = code
+				break
+			}
+		}
+		if format.NumberFormat == "" {
+			format.NumberFormat = strconv.Itoa(style.NumFmt)
+		}
+	}
+	// Formatos que correspondem a um atalho são informados pelo atalho
+	for preset, code := range numberFormatPresets {
+		if code == format.NumberFormat && preset != "general" {
+			format.NumberFormat = preset
+			break
+		}
+	}
+
+	if align := style.Alignment; align != nil {
+		format.HorizontalAlign = align.Horizontal
+		format.VerticalAlign = align.Vertical
+		format.WrapText = enabled(align.WrapText)
+		if align.Indent > 0 {
+			format.Indent = &align.Indent
+		}
+		if rotation := align.TextRotation; rotation != 0 {
+			if rotation > 90 && rotation <= 180 {
+				rotation = 90 - rotation
+			}
+			format.TextRotation = &rotation
+		}
+	}
+	return format
+}
+
+// describeBorders resume as bordas de um estilo nos nomes aceitos por SetBorders
+func describeBorders(borders []excelize.Border) string {
+	names := map[int]string{1: "thin", 2: "medium", 3: "dashed", 4: "dotted", 5: "thick", 6: "double"}
+	sides := make([]string, 0, len(borders))
+	uniform := len(borders) == 4
+	for _, b := range borders {
+		name, ok := names[b.Style]
+		if !ok {
+			name = "style" + strconv.Itoa(b.Style)
+		}
+		sides = append(sides, b.Type+":"+name)
+		uniform = uniform && b.Style == borders[0].Style
+	}
+	if uniform {
+		return strings.SplitN(sides[0], ":", 2)[1]
+	}
+	return strings.Join(sides, ",")
+}
+
+// hexColor normaliza cores RGB/ARGB para #RRGGBB
+func hexColor(color string) string {
+	color = strings.TrimPrefix(color, "#")
+	if len(color) == 8 {
+		color = color[2:]
+	}
+	if len(color) != 6 {
+		return ""
+	}
+	return "#" + strings.ToUpper(color)
+}
@@ -0,0 +1,536 @@
+package excel
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	// tableRootTag etiqueta de abertura do elemento <table>
+	tableRootTag = regexp.MustCompile(`<table\s[^>]*>`)
+	// tableAutoFilterTag etiqueta de abertura do filtro da tabela
+	tableAutoFilterTag = regexp.MustCompile(`<autoFilter\s[^>]*?/?>`)
+	// tableColumnsBlock lista de colunas da tabela
+	tableColumnsBlock = regexp.MustCompile(`(?s)<tableColumns\b[^>]*>.*?</tableColumns>`)
+	// tableColumnElem coluna da tabela, com ou sem elementos filhos
+	tableColumnElem = regexp.MustCompile(`(?s)<tableColumn\s[^>]*?(?:/>|>.*?</tableColumn>)`)
+)
+
+// totalsFunctions códigos de SUBTOTAL das funções da linha de totais
+var totalsFunctions = map[string]int{
+	"average":   101,
+	"countNums": 102,
+	"count":     103,
+	"max":       104,
+	"min":       105,
+	"stdDev":    107,
+	"sum":       109,
+	"var":       110,
+}
+
+// xmlAttrEscaper escapa valores de atributos XML
+var xmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// xmlAttrUnescaper desfaz o escape de valores de atributos XML
+var xmlAttrUnescaper = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'")
+
+// tableDoc definição de uma tabela lida de xl/tables/tableN.xml
+type tableDoc struct {
+	part           string
+	Name           string `xml:"name,attr"`
+	Ref            string `xml:"ref,attr"`
+	TotalsRowCount int    `xml:"totalsRowCount,attr"`
+	Columns        []struct {
+		Name              string `xml:"name,attr"`
+		TotalsRowFunction string `xml:"totalsRowFunction,attr"`
+		TotalsRowLabel    string `xml:"totalsRowLabel,attr"`
+	} `xml:"tableColumns>tableColumn"`
+	Style *struct {
+		Name string `xml:"name,attr"`
+	} `xml:"tableStyleInfo"`
+}
+
+// tableDocsLocked lê as definições das tabelas do arquivo, indexadas pelo nome em
+// minúsculas. O Excelize mantém as tabelas apenas como XML (chamar com c.mu).
+func (c *ExcelizeClient) tableDocsLocked() map[string]*tableDoc {
+	docs := make(map[string]*tableDoc)
+	c.file.Pkg.Range(func(key, value interface{}) bool {
+		name := key.(string)
+		if !strings.HasPrefix(name, "xl/tables/table") {
+			return true
+		}
+		doc := &tableDoc{part: name}
+		if content, ok := value.([]byte); ok && xml.Unmarshal(content, doc) == nil {
+			docs[strings.ToLower(doc.Name)] = doc
+		}
+		return true
+	})
+	return docs
+}
+
+// GetTables descreve as tabelas da planilha (todas as planilhas se sheet for vazio)
+func (c *ExcelizeClient) GetTables(sheet string) ([]TableInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs := c.tableDocsLocked()
+	tables := []TableInfo{}
+	for _, t := range c.newRefResolverLocked().tables {
+		if sheet != "" && !strings.EqualFold(t.Area.Sheet, sheet) {
+			continue
+		}
+		body := t.body()
+		info := TableInfo{
+			Name:      t.Name,
+			Sheet:     t.Area.Sheet,
+			Range:     indicesToCell(t.Area.StartRow, t.Area.StartCol) + ":" + indicesToCell(t.Area.EndRow, t.Area.EndCol),
+			Headers:   t.Headers,
+			TotalsRow: t.Totals,
+		}
+		if body.StartRow > t.Area.StartRow {
+			info.DataRows = body.EndRow - body.StartRow + 1
+		}
+		if doc, ok := docs[strings.ToLower(t.Name)]; ok {
+			if doc.Style != nil {
+				info.Style = doc.Style.Name
+			}
+			if t.Totals {
+				for _, col := range doc.Columns {
+					if col.TotalsRowFunction != "" || col.TotalsRowLabel != "" {
+						info.Totals = append(info.Totals, TableTotal{Column: col.Name, Function: col.TotalsRowFunction, Label: col.TotalsRowLabel})
+					}
+				}
+			}
+		}
+		tables = append(tables, info)
+	}
+	return tables, nil
+}
+
+// ResizeTable altera o intervalo de uma tabela. O cabeçalho continua na mesma linha;
+// com rng vazio, a tabela é estendida até os dados preenchidos logo abaixo e à direita.
+// Retorna o novo intervalo.
+func (c *ExcelizeClient) ResizeTable(name, rng string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, doc, err := c.tableLocked(name)
+	if err != nil {
+		return "", err
+	}
+
+	area := t.Area
+	if strings.TrimSpace(rng) == "" {
+		area = c.expandTableLocked(t)
+	} else {
+		var ok bool
+		if area, ok = c.areaLocked(t.Area.Sheet, rng); !ok {
+			return "", fmt.Errorf("invalid range: %s", rng)
+		}
+		if !strings.EqualFold(area.Sheet, t.Area.Sheet) || area.StartRow != t.Area.StartRow {
+			return "", fmt.Errorf("table %s must keep its header row %d", t.Name, t.Area.StartRow+1)
+		}
+		if area.EndRow-area.StartRow < 1+boolInt(t.Totals) {
+			return "", fmt.Errorf("table %s needs at least one data row", t.Name)
+		}
+	}
+	for _, other := range c.newRefResolverLocked().tables {
+		if !strings.EqualFold(other.Name, t.Name) && other.Area.intersects(area) {
+			return "", fmt.Errorf("range overlaps table %s", other.Name)
+		}
+	}
+
+	if err := c.rewriteTableLocked(doc, area, t.Totals, nil); err != nil {
+		return "", err
+	}
+	return indicesToCell(area.StartRow, area.StartCol) + ":" + indicesToCell(area.EndRow, area.EndCol), nil
+}
+
+// SetTableTotals mostra ou oculta a linha de totais. Colunas sem total em totals
+// mantêm o total atual; sem nenhum total, a primeira coluna recebe o rótulo "Total"
+// e a última a soma.
+func (c *ExcelizeClient) SetTableTotals(name string, show bool, totals []TableTotal) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, doc, err := c.tableLocked(name)
+	if err != nil {
+		return err
+	}
+	sheet := t.Area.Sheet
+	totalsRow := t.Area.EndRow + 1
+	if t.Totals {
+		totalsRow = t.Area.EndRow
+	}
+
+	if !show {
+		if !t.Totals {
+			return nil
+		}
+		for col := t.Area.StartCol; col <= t.Area.EndCol; col++ {
+			if err := c.writeCellLocked(sheet, indicesToCell(totalsRow, col), cellContent{}); err != nil {
+				return err
+			}
+		}
+		area := t.Area
+		area.EndRow--
+		return c.rewriteTableLocked(doc, area, false, map[string]TableTotal{})
+	}
+
+	// Totais atuais, substituídos pelos informados
+	byColumn := make(map[string]TableTotal)
+	if t.Totals {
+		for _, col := range doc.Columns {
+			if col.TotalsRowFunction != "" || col.TotalsRowLabel != "" {
+				byColumn[strings.ToLower(col.Name)] = TableTotal{Column: col.Name, Function: col.TotalsRowFunction, Label: col.TotalsRowLabel}
+			}
+		}
+	}
+	for _, total := range totals {
+		header, ok := tableHeader(t, total.Column)
+		if !ok {
+			return fmt.Errorf("column %s not found in table %s (columns: %s)", total.Column, t.Name, strings.Join(t.Headers, ", "))
+		}
+		if _, ok := totalsFunctions[total.Function]; !ok && total.Function != "" && total.Function != "none" {
+			return fmt.Errorf("invalid totals function: %s", total.Function)
+		}
+		total.Column = header
+		byColumn[strings.ToLower(header)] = total
+	}
+	if len(byColumn) == 0 && len(t.Headers) > 0 {
+		byColumn[strings.ToLower(t.Headers[0])] = TableTotal{Column: t.Headers[0], Label: "Total"}
+		if last := t.Headers[len(t.Headers)-1]; len(t.Headers) > 1 {
+			byColumn[strings.ToLower(last)] = TableTotal{Column: last, Function: "sum"}
+		}
+	}
+
+	if !t.Totals {
+		for col := t.Area.StartCol; col <= t.Area.EndCol; col++ {
+			if value, _ := c.file.GetCellValue(sheet, indicesToCell(totalsRow, col)); value != "" {
+				return fmt.Errorf("row %d below table %s must be empty to add the totals row", totalsRow+1, t.Name)
+			}
+		}
+	}
+
+	for i, header := range t.Headers {
+		cell := indicesToCell(totalsRow, t.Area.StartCol+i)
+		total := byColumn[strings.ToLower(header)]
+		var err error
+		switch code, ok := totalsFunctions[total.Function]; {
+		case ok:
+			err = c.file.SetCellValue(sheet, cell, nil)
+			if err == nil {
+				err = c.file.SetCellFormula(sheet, cell, fmt.Sprintf("SUBTOTAL(%d,%s[%s])", code, t.Name, structuredColumn(header)))
+			}
+		case total.Label != "":
+			err = c.file.SetCellStr(sheet, cell, total.Label)
+		default:
+			err = c.file.SetCellValue(sheet, cell, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	area := t.Area
+	area.EndRow = totalsRow
+	return c.rewriteTableLocked(doc, area, true, byColumn)
+}
+
+// AppendTableRows acrescenta linhas ao final dos dados da tabela, com os valores
+// indicados pelo cabeçalho (textos iniciados por '=' são fórmulas). Colunas omitidas
+// repetem a fórmula da última linha, como as colunas calculadas do Excel; o estilo
+// da última linha é copiado. Retorna o intervalo das linhas gravadas.
+func (c *ExcelizeClient) AppendTableRows(name string, rows []map[string]interface{}) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, doc, err := c.tableLocked(name)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("no rows to append to table %s", t.Name)
+	}
+	sheet := t.Area.Sheet
+
+	columns := make([]map[int]interface{}, len(rows))
+	for i, row := range rows {
+		columns[i] = make(map[int]interface{}, len(row))
+		for key, value := range row {
+			header, ok := tableHeader(t, key)
+			if !ok {
+				return "", fmt.Errorf("column %s not found in table %s (columns: %s)", key, t.Name, strings.Join(t.Headers, ", "))
+			}
+			for j, h := range t.Headers {
+				if h == header {
+					columns[i][j] = value
+				}
+			}
+		}
+	}
+
+	// Uma tabela recém-criada tem uma linha de dados vazia, que é reaproveitada
+	body := t.body()
+	last := body.EndRow
+	if body.StartRow == body.EndRow && body.StartRow > t.Area.StartRow && c.rowEmptyLocked(sheet, body.StartRow, t.Area.StartCol, t.Area.EndCol) {
+		last--
+	}
+	first := last + 1
+	extra := first + len(rows) - 1 - body.EndRow
+
+	// As linhas abaixo da tabela precisam estar livres
+	below := t.Area.EndRow + 1
+	for row := below; row < below+extra; row++ {
+		if !c.rowEmptyLocked(sheet, row, t.Area.StartCol, t.Area.EndCol) {
+			return "", fmt.Errorf("row %d below table %s is not empty; insert rows before appending", row+1, t.Name)
+		}
+	}
+
+	// A linha de totais desce junto com o fim dos dados
+	if t.Totals && extra > 0 {
+		for col := t.Area.StartCol; col <= t.Area.EndCol; col++ {
+			content, err := c.readCellLocked(sheet, indicesToCell(t.Area.EndRow, col))
+			if err != nil {
+				return "", err
+			}
+			if err := c.writeCellLocked(sheet, indicesToCell(t.Area.EndRow+extra, col), content); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	template := make([]cellContent, len(t.Headers))
+	if last > t.Area.StartRow {
+		for j := range template {
+			if template[j], err = c.readCellLocked(sheet, indicesToCell(last, t.Area.StartCol+j)); err != nil {
+				return "", err
+			}
+		}
+	} else if body.StartRow > t.Area.StartRow {
+		// Linha vazia reaproveitada: mantém o estilo dela
+		for j := range template {
+			template[j].Style, _ = c.file.GetCellStyle(sheet, indicesToCell(body.StartRow, t.Area.StartCol+j))
+		}
+	}
+
+	for i := range rows {
+		row := first + i
+		for j := range t.Headers {
+			cell := indicesToCell(row, t.Area.StartCol+j)
+			content := cellContent{Style: template[j].Style}
+			if template[j].Formula != "" {
+				content.Formula = shiftFormula(template[j].Formula, row-last, 0)
+			}
+			if err := c.writeCellLocked(sheet, cell, content); err != nil {
+				return "", err
+			}
+			value, ok := columns[i][j]
+			if !ok {
+				continue
+			}
+			if text, isText := value.(string); isText && strings.HasPrefix(text, "=") {
+				err = c.file.SetCellFormula(sheet, cell, text[1:])
+			} else {
+				err = c.file.SetCellValue(sheet, cell, value)
+			}
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if extra > 0 {
+		area := t.Area
+		area.EndRow += extra
+		if err := c.rewriteTableLocked(doc, area, t.Totals, nil); err != nil {
+			return "", err
+		}
+	}
+	return indicesToCell(first, t.Area.StartCol) + ":" + indicesToCell(first+len(rows)-1, t.Area.EndCol), nil
+}
+
+// tableLocked localiza uma tabela e sua definição XML (chamar com c.mu)
+func (c *ExcelizeClient) tableLocked(name string) (tableArea, *tableDoc, error) {
+	t, ok := c.newRefResolverLocked().table(name)
+	if !ok {
+		return tableArea{}, nil, fmt.Errorf("table not found: %s", name)
+	}
+	doc, ok := c.tableDocsLocked()[strings.ToLower(t.Name)]
+	if !ok {
+		return tableArea{}, nil, fmt.Errorf("table part not found: %s", name)
+	}
+	return t, doc, nil
+}
+
+// expandTableLocked estende a tabela pelas linhas preenchidas logo abaixo dos dados
+// e pelos cabeçalhos preenchidos à direita (chamar com c.mu)
+func (c *ExcelizeClient) expandTableLocked(t tableArea) cellRef {
+	area := t.Area
+	for area.EndCol+1 < excelize.MaxColumns {
+		if header, _ := c.file.GetCellValue(area.Sheet, indicesToCell(area.StartRow, area.EndCol+1)); header == "" {
+			break
+		}
+		area.EndCol++
+	}
+	// Com linha de totais o Excel não estende a tabela automaticamente
+	if !t.Totals {
+		used := cellRef{Sheet: area.Sheet, EndRow: excelize.TotalRows - 1, EndCol: excelize.MaxColumns - 1}
+		c.clampToUsedAreaLocked(&used)
+		for area.EndRow < used.EndRow && !c.rowEmptyLocked(area.Sheet, area.EndRow+1, area.StartCol, area.EndCol) {
+			area.EndRow++
+		}
+	}
+	return area
+}
+
+// rowEmptyLocked verifica se as células da linha entre as colunas estão vazias (chamar com c.mu)
+func (c *ExcelizeClient) rowEmptyLocked(sheet string, row, startCol, endCol int) bool {
+	for col := startCol; col <= endCol; col++ {
+		cell := indicesToCell(row, col)
+		if value, _ := c.file.GetCellValue(sheet, cell); value != "" {
+			return false
+		}
+		if formula, _ := c.file.GetCellFormula(sheet, cell); formula != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// rewriteTableLocked grava o novo intervalo da tabela no XML, refazendo a lista de
+// colunas a partir dos cabeçalhos. Colunas existentes mantêm seus atributos; com
+// totals não nulo, os totais das colunas são substituídos (chamar com c.mu).
+func (c *ExcelizeClient) rewriteTableLocked(doc *tableDoc, area cellRef, totalsRow bool, totals map[string]TableTotal) error {
+	content, ok := c.file.Pkg.Load(doc.part)
+	if !ok {
+		return fmt.Errorf("table part not found: %s", doc.part)
+	}
+	data := string(content.([]byte))
+
+	existing := make(map[string]string)
+	for _, elem := range tableColumnElem.FindAllString(data, -1) {
+		if m := regexp.MustCompile(`\sname="([^"]*)"`).FindStringSubmatch(openingTag(elem)); m != nil {
+			existing[strings.ToLower(xmlAttrUnescaper.Replace(m[1]))] = elem
+		}
+	}
+
+	var columns strings.Builder
+	used := make(map[string]bool)
+	for col := area.StartCol; col <= area.EndCol; col++ {
+		i := col - area.StartCol + 1
+		cell := indicesToCell(area.StartRow, col)
+		header, _ := c.file.GetCellValue(area.Sheet, cell)
+		header = strings.TrimSpace(header)
+		if header == "" {
+			header = "Coluna" + strconv.Itoa(i)
+		}
+		// Nomes de coluna precisam ser únicos na tabela
+		for base, n := header, 2; used[strings.ToLower(header)]; n++ {
+			header = base + strconv.Itoa(n)
+		}
+		used[strings.ToLower(header)] = true
+		if current, _ := c.file.GetCellValue(area.Sheet, cell); current != header {
+			if err := c.file.SetCellStr(area.Sheet, cell, header); err != nil {
+				return err
+			}
+		}
+
+		elem, ok := existing[strings.ToLower(header)]
+		if !ok {
+			elem = `<tableColumn id="" name=""/>`
+		}
+		tag := setXMLAttr(openingTag(elem), "id", strconv.Itoa(i))
+		tag = setXMLAttr(tag, "name", xmlAttrEscaper.Replace(header))
+		if totals != nil || !totalsRow {
+			total := totals[strings.ToLower(header)]
+			function := total.Function
+			if function == "none" {
+				function = ""
+			}
+			tag = setXMLAttr(tag, "totalsRowFunction", function)
+			tag = setXMLAttr(tag, "totalsRowLabel", xmlAttrEscaper.Replace(total.Label))
+		}
+		columns.WriteString(tag + elem[len(openingTag(elem)):])
+	}
+
+	ref := indicesToCell(area.StartRow, area.StartCol) + ":" + indicesToCell(area.EndRow, area.EndCol)
+	filterRef := ref
+	if totalsRow {
+		filterRef = indicesToCell(area.StartRow, area.StartCol) + ":" + indicesToCell(area.EndRow-1, area.EndCol)
+	}
+
+	data = tableRootTag.ReplaceAllStringFunc(data, func(tag string) string {
+		tag = setXMLAttr(tag, "ref", ref)
+		if totalsRow {
+			tag = setXMLAttr(tag, "totalsRowCount", "1")
+			return setXMLAttr(tag, "totalsRowShown", "")
+		}
+		return setXMLAttr(tag, "totalsRowCount", "")
+	})
+	data = tableAutoFilterTag.ReplaceAllStringFunc(data, func(tag string) string {
+		return setXMLAttr(tag, "ref", filterRef)
+	})
+	data = tableColumnsBlock.ReplaceAllLiteralString(data,
+		fmt.Sprintf(`<tableColumns count="%d">%s</tableColumns>`, area.EndCol-area.StartCol+1, columns.String()))
+
+	c.file.Pkg.Store(doc.part, []byte(data))
+	return nil
+}
+
+// tableHeader localiza o cabeçalho da tabela sem diferenciar maiúsculas
+func tableHeader(t tableArea, column string) (string, bool) {
+	for _, header := range t.Headers {
+		if strings.EqualFold(strings.TrimSpace(header), strings.TrimSpace(column)) {
+			return header, true
+		}
+	}
+	return "", false
+}
+
+// structuredColumn escapa os caracteres especiais de um cabeçalho em referências
+// estruturadas (Tabela[Preço 'Unit.'])
+func structuredColumn(header string) string {
+	return strings.NewReplacer("'", "''", "[", "'[", "]", "']", "#", "'#").Replace(header)
+}
+
+// openingTag etiqueta de abertura de um elemento XML
+func openingTag(elem string) string {
+	if end := strings.Index(elem, ">"); end >= 0 {
+		return elem[:end+1]
+	}
+	return elem
+}
+
+// setXMLAttr define (ou remove, com valor vazio) um atributo da etiqueta de
+// abertura; o valor já deve estar escapado
+func setXMLAttr(tag, name, value string) string {
+	attr := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `="[^"]*"`)
+	if attr.MatchString(tag) {
+		if value == "" {
+			return attr.ReplaceAllLiteralString(tag, "")
+		}
+		return attr.ReplaceAllLiteralString(tag, " "+name+`="`+value+`"`)
+	}
+	if value == "" {
+		return tag
+	}
+	end := len(tag) - 1
+	if strings.HasSuffix(tag, "/>") {
+		end--
+	}
+	return tag[:end] + " " + name + `="` + value + `"` + tag[end:]
+}
+
+// boolInt converte verdadeiro em 1
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -36,11 +36,434 @@ type SheetData struct {
 	Rows    [][]CellData `json:"rows"`
 }
 
-// Format representa formatação de célula/range
+// Format representa formatação de célula/range. Campos vazios (nil, "" ou 0)
+// mantêm o estilo atual da célula.
 type Format struct {
-	Bold      bool   `json:"bold"`
-	Italic    bool   `json:"italic"`
-	FontSize  int    `json:"fontSize"`
-	FontColor string `json:"fontColor"` // Hex
-	BgColor   string `json:"bgColor"`   // Hex
+	Bold            *bool   `json:"bold,omitempty"`
+	Italic          *bool   `json:"italic,omitempty"`
+	Underline       string  `json:"underline,omitempty"` // single, double ou none
+	Strikethrough   *bool   `json:"strikethrough,omitempty"`
+	FontFamily      string  `json:"fontFamily,omitempty"`
+	FontSize        float64 `json:"fontSize,omitempty"`
+	FontColor       string  `json:"fontColor,omitempty"`       // Hex
+	BgColor         string  `json:"bgColor,omitempty"`         // Hex ou "none"
+	NumberFormat    string  `json:"numberFormat,omitempty"`    // Código (ex: '#,##0.00') ou atalho: currency, percent, date...
+	HorizontalAlign string  `json:"horizontalAlign,omitempty"` // left, center, right, justify, fill, distributed, centerContinuous
+	VerticalAlign   string  `json:"verticalAlign,omitempty"`   // top, center, bottom, justify, distributed
+	WrapText        *bool   `json:"wrapText,omitempty"`
+	Indent          *int    `json:"indent,omitempty"`
+	TextRotation    *int    `json:"textRotation,omitempty"` // -90 a 90 graus, 255 para texto vertical
+}
+
+// StyledCells células de um intervalo que compartilham a mesma formatação
+type StyledCells struct {
+	Ranges  []string `json:"ranges"`
+	Format  Format   `json:"format"`
+	Borders string   `json:"borders,omitempty"` // thin, medium ou lados (ex: "bottom:thin")
+}
+
+// RangeFormats formatação existente de um intervalo
+type RangeFormats struct {
+	Range        string             `json:"range"`
+	Styles       []StyledCells      `json:"styles"`
+	ColumnWidths map[string]float64 `json:"columnWidths,omitempty"`
+	RowHeights   map[int]float64    `json:"rowHeights,omitempty"` // Apenas alturas personalizadas
+}
+
+// StyleSnapshot estilos de um intervalo, usados para desfazer formatações
+type StyleSnapshot struct {
+	Sheet  string  `json:"sheet,omitempty"`
+	Cell   string  `json:"cell"` // Célula superior esquerda
+	Styles [][]int `json:"styles"`
+}
+
+// ConditionalFormatRule regra de formatação condicional
+type ConditionalFormatRule struct {
+	Type       string  `json:"type"`                 // cellValue, text, formula, top, bottom, aboveAverage, belowAverage, duplicate, unique, blanks, noBlanks, errors, noErrors, colorScale, dataBar, iconSet
+	Range      string  `json:"range,omitempty"`      // Preenchido na leitura
+	Criteria   string  `json:"criteria,omitempty"`   // cellValue: greaterThan, lessThan, between...; text: containsText, notContains, beginsWith, endsWith
+	Value      string  `json:"value,omitempty"`      // Valor ou texto comparado; quantidade em top/bottom
+	MinValue   string  `json:"minValue,omitempty"`   // between/notBetween e ponto mínimo de escalas e barras
+	MidValue   string  `json:"midValue,omitempty"`   // Ponto médio da escala de 3 cores
+	MaxValue   string  `json:"maxValue,omitempty"`   // between/notBetween e ponto máximo de escalas e barras
+	Formula    string  `json:"formula,omitempty"`    // Regra formula, relativa à primeira célula (ex: '$C2>$D2')
+	Percent    bool    `json:"percent,omitempty"`    // top/bottom em percentual
+	Format     *Format `json:"format,omitempty"`     // Destaque aplicado pelas regras de célula
+	MinType    string  `json:"minType,omitempty"`    // min, num, percent, percentile, formula
+	MidType    string  `json:"midType,omitempty"`    // num, percent, percentile, formula
+	MaxType    string  `json:"maxType,omitempty"`    // max, num, percent, percentile, formula
+	MinColor   string  `json:"minColor,omitempty"`   // Escala de cores
+	MidColor   string  `json:"midColor,omitempty"`   // Preenchido: escala de 3 cores
+	MaxColor   string  `json:"maxColor,omitempty"`   // Escala de cores
+	BarColor   string  `json:"barColor,omitempty"`   // Barra de dados
+	BarOnly    bool    `json:"barOnly,omitempty"`    // Oculta o valor da célula
+	IconStyle  string  `json:"iconStyle,omitempty"`  // 3Arrows, 3TrafficLights1, 3Symbols, 4Rating, 5Arrows...
+	Reverse    bool    `json:"reverse,omitempty"`    // Inverte a ordem dos ícones
+	IconsOnly  bool    `json:"iconsOnly,omitempty"`  // Oculta o valor da célula
+	StopIfTrue bool    `json:"stopIfTrue,omitempty"` // Não avalia as regras seguintes quando verdadeira
+}
+
+// DataValidationRule regra de validação de dados
+type DataValidationRule struct {
+	Type         string   `json:"type"`                 // list, whole, decimal, date, time, textLength, custom
+	Range        string   `json:"range,omitempty"`      // Preenchido na leitura
+	Operator     string   `json:"operator,omitempty"`   // between (padrão), notBetween, equal, notEqual, greaterThan, greaterThanOrEqual, lessThan, lessThanOrEqual
+	Value        string   `json:"value,omitempty"`      // Operando dos operadores de um valor; número, data (2024-01-31), hora (14:30) ou fórmula
+	MinValue     string   `json:"minValue,omitempty"`   // between/notBetween
+	MaxValue     string   `json:"maxValue,omitempty"`   // between/notBetween
+	Options      []string `json:"options,omitempty"`    // list: opções fixas
+	Source       string   `json:"source,omitempty"`     // list: intervalo, nome definido ou tabela com as opções (ex: 'Listas!A2:A20', 'Categorias', 'Produtos[Nome]')
+	Formula      string   `json:"formula,omitempty"`    // custom: fórmula relativa à primeira célula (ex: 'COUNTIF($A:$A,A2)=1')
+	AllowBlank   *bool    `json:"allowBlank,omitempty"` // Aceita células vazias (padrão: true)
+	InputTitle   string   `json:"inputTitle,omitempty"` // Título e texto exibidos ao selecionar a célula
+	InputMessage string   `json:"inputMessage,omitempty"`
+	ErrorTitle   string   `json:"errorTitle,omitempty"` // Título e texto do alerta de valor inválido
+	ErrorMessage string   `json:"errorMessage,omitempty"`
+	ErrorStyle   string   `json:"errorStyle,omitempty"` // stop (padrão), warning, information
+}
+
+// TableInfo tabela formatada (ListObject)
+type TableInfo struct {
+	Name      string       `json:"name"`
+	Sheet     string       `json:"sheet"`
+	Range     string       `json:"range"` // Inclui o cabeçalho e a linha de totais
+	Headers   []string     `json:"headers"`
+	Style     string       `json:"style,omitempty"`
+	DataRows  int          `json:"dataRows"`
+	TotalsRow bool         `json:"totalsRow"`
+	Totals    []TableTotal `json:"totals,omitempty"`
+}
+
+// TableTotal total de uma coluna na linha de totais da tabela
+type TableTotal struct {
+	Column   string `json:"column"`             // Cabeçalho da coluna
+	Function string `json:"function,omitempty"` // sum, average, count, countNums, max, min, stdDev, var, none
+	Label    string `json:"label,omitempty"`    // Texto fixo no lugar de uma função (ex: 'Total')
+}
+
+// SortKey critério de ordenação
+type SortKey struct {
+	Column     int      `json:"column"`               // Coluna relativa ao intervalo (1-based)
+	Order      string   `json:"order,omitempty"`      // asc (padrão) ou desc
+	Type       string   `json:"type,omitempty"`       // auto (padrão), number, date, text, custom
+	CustomList []string `json:"customList,omitempty"` // custom: ordem dos valores (ex: Baixa, Média, Alta)
+}
+
+// SortOptions opções de ordenação de um intervalo
+type SortOptions struct {
+	Keys   []SortKey `json:"keys"`             // Aplicadas em sequência; empates mantêm a ordem original
+	Header *bool     `json:"header,omitempty"` // Primeira linha é cabeçalho (padrão: true para tabelas)
+}
+
+// PasteOptions modo de colagem de uma cópia de intervalo
+type PasteOptions struct {
+	Mode      string `json:"mode,omitempty"`      // all (padrão), values, formulas, formats
+	Transpose bool   `json:"transpose,omitempty"` // Linhas viram colunas
+}
+
+// ColumnInsert inserção de colunas. Before e After aceitam a letra ou o cabeçalho
+// da coluna; a fórmula é a da primeira linha de dados, já com as colunas inseridas.
+type ColumnInsert struct {
+	Before  string   `json:"before,omitempty"`
+	After   string   `json:"after,omitempty"`
+	Count   int      `json:"count,omitempty"`   // Padrão: 1 ou a quantidade de cabeçalhos
+	Headers []string `json:"headers,omitempty"` // Cabeçalhos das novas colunas
+	Formula string   `json:"formula,omitempty"` // Preenchida em todas as linhas de dados
+}
+
+// FilterCondition condição simples de um filtro
+type FilterCondition struct {
+	Operator string `json:"operator"` // equals, notEquals, contains, notContains, beginsWith, endsWith, greaterThan, greaterThanOrEqual, lessThan, lessThanOrEqual
+	Value    string `json:"value"`
+}
+
+// FilterCriterion critério do filtro automático para uma coluna
+type FilterCriterion struct {
+	Column     string            `json:"column"`               // Letra da coluna (ex: 'C') ou texto do cabeçalho
+	Operator   string            `json:"operator"`             // equals, notEquals, contains, notContains, beginsWith, endsWith, greaterThan, greaterThanOrEqual, lessThan, lessThanOrEqual, between, blanks, nonBlanks, top, bottom, datePeriod, custom
+	Values     []string          `json:"values,omitempty"`     // equals: valores aceitos (qualquer um)
+	Value      string            `json:"value,omitempty"`      // Operadores de um valor; número, data (2024-01-31) ou texto
+	MinValue   string            `json:"minValue,omitempty"`   // between
+	MaxValue   string            `json:"maxValue,omitempty"`   // between
+	Count      int               `json:"count,omitempty"`      // top/bottom: quantidade de itens (padrão 10)
+	Percent    bool              `json:"percent,omitempty"`    // top/bottom: Count é porcentagem
+	Period     string            `json:"period,omitempty"`     // datePeriod: today, yesterday, tomorrow, thisWeek, lastWeek, nextWeek, thisMonth, lastMonth, nextMonth, thisQuarter, lastQuarter, nextQuarter, thisYear, lastYear, nextYear, yearToDate
+	Conditions []FilterCondition `json:"conditions,omitempty"` // custom: até duas condições
+	Join       string            `json:"join,omitempty"`       // custom: and (padrão) ou or
+}
+
+// AutoFilterInfo filtro automático de uma planilha
+type AutoFilterInfo struct {
+	Range       string             `json:"range"`
+	Columns     []FilterColumnInfo `json:"columns"` // Colunas com critério
+	VisibleRows int                `json:"visibleRows"`
+	HiddenRows  int                `json:"hiddenRows"`
+}
+
+// FilterColumnInfo critério gravado no arquivo para uma coluna do filtro
+type FilterColumnInfo struct {
+	Column     string            `json:"column"`
+	Header     string            `json:"header,omitempty"`
+	Values     []string          `json:"values,omitempty"`
+	Conditions []FilterCondition `json:"conditions,omitempty"`
+	Join       string            `json:"join,omitempty"`
+}
+
+// ValidationViolation célula cujo valor não atende à sua validação
+type ValidationViolation struct {
+	Cell   string `json:"cell"`
+	Value  string `json:"value"`
+	Range  string `json:"range"`  // Intervalo da validação
+	Reason string `json:"reason"` // Regra descumprida
+}
+
+// DefinedName nome definido (intervalo nomeado)
+type DefinedName struct {
+	Name     string `json:"name"`
+	RefersTo string `json:"refersTo"`        // Ex: 'Plan1'!$A$2:$A$100 ou uma fórmula/constante
+	Scope    string `json:"scope,omitempty"` // Planilha do nome local; vazio para a pasta de trabalho
+	Comment  string `json:"comment,omitempty"`
+}
+
+// ChartSeriesSpec representa uma série de gráfico
+type ChartSeriesSpec struct {
+	Name          string `json:"name,omitempty"`          // Texto ou referência (ex: 'Plan1!$B$1')
+	Categories    string `json:"categories,omitempty"`    // ex: 'Plan1!$A$2:$A$10'
+	Values        string `json:"values"`                  // ex: 'Plan1!$B$2:$B$10'
+	Sizes         string `json:"sizes,omitempty"`         // Tamanho das bolhas (bubble)
+	Type          string `json:"type,omitempty"`          // Tipo desta série em gráficos combinados
+	SecondaryAxis bool   `json:"secondaryAxis,omitempty"` // Plotar no eixo vertical secundário
+}
+
+// ChartSpec especificação completa de um gráfico
+type ChartSpec struct {
+	Type               string            `json:"chartType"`
+	Title              string            `json:"title,omitempty"`
+	Range              string            `json:"range,omitempty"` // Atalho: 1ª coluna = categorias, demais = séries
+	Series             []ChartSeriesSpec `json:"series,omitempty"`
+	Anchor             string            `json:"anchor,omitempty"` // Célula do canto superior esquerdo
+	Width              uint              `json:"width,omitempty"`  // Pixels
+	Height             uint              `json:"height,omitempty"` // Pixels
+	XAxisTitle         string            `json:"xAxisTitle,omitempty"`
+	YAxisTitle         string            `json:"yAxisTitle,omitempty"`
+	SecondaryAxisTitle string            `json:"secondaryAxisTitle,omitempty"`
+	Legend             string            `json:"legend,omitempty"` // top, bottom, left, right, top_right, none
+	DataLabels         bool              `json:"dataLabels,omitempty"`
+	Stacked            bool              `json:"stacked,omitempty"`
+	Percent            bool              `json:"percent,omitempty"` // Empilhado 100%
+}
+
+// ChartInfo gráfico existente em uma planilha
+type ChartInfo struct {
+	Name    string            `json:"name"`
+	Sheet   string            `json:"sheet"`
+	Anchor  string            `json:"anchor"`
+	Type    string            `json:"chartType"` // "combo" quando há mais de um tipo
+	Title   string            `json:"title,omitempty"`
+	Stacked bool              `json:"stacked,omitempty"`
+	Percent bool              `json:"percent,omitempty"`
+	Series  []ChartSeriesSpec `json:"series"`
+}
+
+// ImageSpec imagem a inserir em uma planilha
+type ImageSpec struct {
+	Cell        string  `json:"cell"`                  // Célula do canto superior esquerdo
+	Path        string  `json:"path,omitempty"`        // Arquivo na pasta da planilha aberta
+	Base64      string  `json:"base64,omitempty"`      // Conteúdo em base64 (aceita data URI)
+	Format      string  `json:"format,omitempty"`      // png, jpeg, gif, bmp, svg, ... (padrão: extensão ou conteúdo)
+	Width       int     `json:"width,omitempty"`       // Pixels; com lockAspect a outra dimensão acompanha
+	Height      int     `json:"height,omitempty"`      // Pixels
+	Scale       float64 `json:"scale,omitempty"`       // 1 = 100%, quando width/height não são informados
+	OffsetX     int     `json:"offsetX,omitempty"`     // Deslocamento em pixels dentro da célula
+	OffsetY     int     `json:"offsetY,omitempty"`     // Deslocamento em pixels dentro da célula
+	AltText     string  `json:"altText,omitempty"`     // Texto alternativo
+	LockAspect  *bool   `json:"lockAspect,omitempty"`  // Mantém a proporção (padrão: true)
+	Positioning string  `json:"positioning,omitempty"` // oneCell (padrão), twoCell, absolute
+}
+
+// ImageInfo imagem existente em uma planilha
+type ImageInfo struct {
+	Cell    string `json:"cell"`
+	Format  string `json:"format"`
+	Width   int    `json:"width,omitempty"`  // Pixels da imagem original
+	Height  int    `json:"height,omitempty"` // Pixels da imagem original
+	AltText string `json:"altText,omitempty"`
+	Size    int    `json:"size"` // Bytes
+}
+
+// SheetView exibição de uma planilha na tela. Em alterações, campos ausentes
+// mantêm o valor atual.
+type SheetView struct {
+	Zoom        int    `json:"zoom,omitempty"`        // 10-400 (%)
+	Gridlines   *bool  `json:"gridlines,omitempty"`   // Linhas de grade
+	Headings    *bool  `json:"headings,omitempty"`    // Cabeçalhos de linha e coluna
+	ShowZeros   *bool  `json:"showZeros,omitempty"`   // Exibir valores zero
+	RightToLeft *bool  `json:"rightToLeft,omitempty"` // Planilha da direita para a esquerda
+	View        string `json:"view,omitempty"`        // normal, pageLayout, pageBreakPreview
+	TopLeftCell string `json:"topLeftCell,omitempty"` // Primeira célula visível
+}
+
+// SheetInfo planilha da pasta de trabalho
+type SheetInfo struct {
+	Name       string    `json:"name"`
+	Position   int       `json:"position"`   // 1 = primeira aba
+	Visibility string    `json:"visibility"` // visible, hidden, veryHidden
+	Active     bool      `json:"active,omitempty"`
+	TabColor   string    `json:"tabColor,omitempty"`
+	View       SheetView `json:"view"`
+}
+
+// WorkbookProperties propriedades do documento. Em alterações, campos ausentes
+// mantêm o valor atual e propriedades personalizadas com valor null são removidas.
+type WorkbookProperties struct {
+	Title          *string                `json:"title,omitempty"`
+	Subject        *string                `json:"subject,omitempty"`
+	Author         *string                `json:"author,omitempty"`
+	Keywords       *string                `json:"keywords,omitempty"`
+	Description    *string                `json:"description,omitempty"`
+	Category       *string                `json:"category,omitempty"`
+	Company        *string                `json:"company,omitempty"`
+	LastModifiedBy *string                `json:"lastModifiedBy,omitempty"`
+	Created        string                 `json:"created,omitempty"`     // Somente leitura
+	Modified       string                 `json:"modified,omitempty"`    // Somente leitura
+	Application    string                 `json:"application,omitempty"` // Somente leitura
+	Custom         map[string]interface{} `json:"custom,omitempty"`      // Texto, número, booleano ou data
+}
+
+// Provenance origem de um arquivo gerado pela IA, gravada nas propriedades personalizadas
+type Provenance struct {
+	Generator      string `json:"generator"`
+	ConversationID string `json:"conversationId,omitempty"`
+	Model          string `json:"model,omitempty"`
+	GeneratedAt    string `json:"generatedAt,omitempty"` // RFC 3339
+}
+
+// WorkbookInfo resumo da pasta de trabalho: propriedades, abas e origem
+type WorkbookInfo struct {
+	File       string             `json:"file,omitempty"`
+	Sheets     []string           `json:"sheets"`
+	Properties WorkbookProperties `json:"properties"`
+	Provenance *Provenance        `json:"provenance,omitempty"` // Presente em arquivos gerados pela IA
+}
+
+// PageSetup configuração de impressão de uma planilha. Em alterações, campos
+// ausentes mantêm o valor atual; textos vazios removem a área, títulos ou cabeçalho.
+type PageSetup struct {
+	Orientation        string       `json:"orientation,omitempty"`        // portrait, landscape
+	PaperSize          string       `json:"paperSize,omitempty"`          // A4, A3, A5, B4, B5, letter, legal, tabloid, executive
+	Scale              int          `json:"scale,omitempty"`              // 10-400 (%); desativa o ajuste por páginas
+	FitToWidth         *int         `json:"fitToWidth,omitempty"`         // Páginas de largura (0 = automático)
+	FitToHeight        *int         `json:"fitToHeight,omitempty"`        // Páginas de altura (0 = automático)
+	Margins            *PageMargins `json:"margins,omitempty"`            // Centímetros
+	CenterHorizontally *bool        `json:"centerHorizontally,omitempty"` // Centralizar na página
+	CenterVertically   *bool        `json:"centerVertically,omitempty"`
+	Gridlines          *bool        `json:"gridlines,omitempty"`     // Imprimir linhas de grade
+	Headings           *bool        `json:"headings,omitempty"`      // Imprimir cabeçalhos de linha e coluna
+	BlackAndWhite      *bool        `json:"blackAndWhite,omitempty"` // Imprimir em preto e branco
+	FirstPageNumber    int          `json:"firstPageNumber,omitempty"`
+	PageOrder          string       `json:"pageOrder,omitempty"`    // downThenOver, overThenDown
+	PrintArea          *string      `json:"printArea,omitempty"`    // Ex: 'A1:H40'
+	TitleRows          *string      `json:"titleRows,omitempty"`    // Linhas repetidas no topo (ex: '1:2')
+	TitleColumns       *string      `json:"titleColumns,omitempty"` // Colunas repetidas à esquerda (ex: 'A:B')
+	Header             *string      `json:"header,omitempty"`       // Aceita {page}, {pages}, {date}, {time}, {file}, {sheet} e 'esquerda|centro|direita'
+	Footer             *string      `json:"footer,omitempty"`
+	RowBreaks          []int        `json:"rowBreaks,omitempty"`    // Linhas que iniciam uma nova página (somente leitura)
+	ColumnBreaks       []string     `json:"columnBreaks,omitempty"` // Colunas que iniciam uma nova página (somente leitura)
+}
+
+// PageMargins margens da página em centímetros
+type PageMargins struct {
+	Top    *float64 `json:"top,omitempty"`
+	Bottom *float64 `json:"bottom,omitempty"`
+	Left   *float64 `json:"left,omitempty"`
+	Right  *float64 `json:"right,omitempty"`
+	Header *float64 `json:"header,omitempty"`
+	Footer *float64 `json:"footer,omitempty"`
+}
+
+// ShapeSpec forma ou caixa de texto
+type ShapeSpec struct {
+	Cell      string  `json:"cell"`                // Célula do canto superior esquerdo
+	Type      string  `json:"type,omitempty"`      // textbox (padrão), rect, roundRect, ellipse, triangle, rightArrow, ...
+	Text      string  `json:"text,omitempty"`      // Quebras de linha viram parágrafos
+	Width     int     `json:"width,omitempty"`     // Pixels (padrão 160)
+	Height    int     `json:"height,omitempty"`    // Pixels (padrão 60)
+	FillColor string  `json:"fillColor,omitempty"` // Hex (ex: #FFF2CC)
+	LineColor string  `json:"lineColor,omitempty"` // Hex
+	LineWidth float64 `json:"lineWidth,omitempty"` // Pontos
+	FontSize  float64 `json:"fontSize,omitempty"`
+	FontColor string  `json:"fontColor,omitempty"`
+	Bold      bool    `json:"bold,omitempty"`
+	Italic    bool    `json:"italic,omitempty"`
+}
+
+// ShapeInfo forma existente em uma planilha
+type ShapeInfo struct {
+	Name string `json:"name"`
+	Cell string `json:"cell"`
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+// PivotDataField campo de valores de uma tabela dinâmica
+type PivotDataField struct {
+	Field    string `json:"field"`              // Cabeçalho da coluna de origem
+	Function string `json:"function,omitempty"` // sum, count, average, max, min
+	Name     string `json:"name,omitempty"`     // Rótulo exibido
+	ShowAs   string `json:"showAs,omitempty"`   // percent_of_total, percent_of_row, percent_of_column
+	NumFmt   int    `json:"numFmt,omitempty"`   // ID de formato numérico embutido
+}
+
+// PivotSpec configuração de uma tabela dinâmica
+type PivotSpec struct {
+	Name           string           `json:"tableName"`
+	SourceSheet    string           `json:"sourceSheet"`
+	SourceRange    string           `json:"sourceRange"`
+	DestSheet      string           `json:"destSheet"`
+	DestCell       string           `json:"destCell"`
+	Rows           []string         `json:"rows,omitempty"`
+	Columns        []string         `json:"columns,omitempty"`
+	Filters        []string         `json:"filters,omitempty"`
+	DataFields     []PivotDataField `json:"dataFields,omitempty"`
+	RowGrandTotals *bool            `json:"rowGrandTotals,omitempty"` // Padrão: true
+	ColGrandTotals *bool            `json:"colGrandTotals,omitempty"` // Padrão: true
+	Style          string           `json:"style,omitempty"`
+}
+
+// FormulaError erro encontrado ao recalcular uma fórmula
+type FormulaError struct {
+	Sheet   string `json:"sheet"`
+	Cell    string `json:"cell"`
+	Formula string `json:"formula"`
+	Error   string `json:"error"` // #DIV/0!, #REF!, #NAME?, ... ou CIRCULAR
+	Message string `json:"message,omitempty"`
+}
+
+// RecalcResult resultado de um recálculo da pasta de trabalho
+type RecalcResult struct {
+	Evaluated int            `json:"evaluated"`
+	Errors    []FormulaError `json:"errors,omitempty"`
+	Cycles    [][]string     `json:"cycles,omitempty"` // Células de cada referência circular (ex: 'Plan1!B2')
+}
+
+// FormulaPrecedents intervalos lidos pela fórmula de uma célula
+type FormulaPrecedents struct {
+	Cell       string   `json:"cell"` // Planilha!A1
+	Formula    string   `json:"formula"`
+	Precedents []string `json:"precedents"`
+}
+
+// FormulaDependent fórmula que lê um intervalo
+type FormulaDependent struct {
+	Cell    string `json:"cell"`
+	Formula string `json:"formula"`
+}
+
+// ChangeImpact fórmulas afetadas, direta ou indiretamente, por uma alteração
+type ChangeImpact struct {
+	Range   string         `json:"range"`
+	Direct  int            `json:"direct"` // Fórmulas que leem o intervalo
+	Total   int            `json:"total"`  // Incluindo as que dependem delas
+	BySheet map[string]int `json:"bySheet"`
+	Cells   []string       `json:"cells"` // Primeiras células afetadas
 }
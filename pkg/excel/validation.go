@@ -0,0 +1,644 @@
+package excel
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// validationTypes tipos de validação aceitos (mesmos nomes do Excel)
+var validationTypes = map[string]bool{
+	"list": true, "whole": true, "decimal": true, "date": true, "time": true, "textLength": true, "custom": true,
+}
+
+// validationOperators operadores das validações de valor e a descrição usada nas violações
+var validationOperators = map[string]string{
+	"between":            "entre %s e %s",
+	"notBetween":         "fora do intervalo de %s a %s",
+	"equal":              "igual a %s",
+	"notEqual":           "diferente de %s",
+	"greaterThan":        "maior que %s",
+	"greaterThanOrEqual": "maior ou igual a %s",
+	"lessThan":           "menor que %s",
+	"lessThanOrEqual":    "menor ou igual a %s",
+}
+
+// validationErrorStyles estilos do alerta exibido para valores inválidos
+var validationErrorStyles = map[string]excelize.DataValidationErrorStyle{
+	"stop":        excelize.DataValidationErrorStyleStop,
+	"warning":     excelize.DataValidationErrorStyleWarning,
+	"information": excelize.DataValidationErrorStyleInformation,
+}
+
+// validationFormulaEscaper o Excelize grava as fórmulas de validação sem escapar o XML
+var validationFormulaEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// excelEpoch data zero dos números de série do Excel (sistema 1900)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// AddValidation aplica uma regra de validação ao intervalo, substituindo as
+// validações existentes nessas células
+func (c *ExcelizeClient) AddValidation(sheet, rng string, rule DataValidationRule) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sheet, sqref, areas, err := c.rangeAreasLocked(sheet, rng)
+	if err != nil {
+		return err
+	}
+	dv, err := c.dataValidationLocked(sheet, rule)
+	if err != nil {
+		return err
+	}
+	dv.Sqref = sqref
+
+	if _, err := c.clearValidationsLocked(sheet, areas); err != nil {
+		return err
+	}
+	return c.file.AddDataValidation(sheet, dv)
+}
+
+// AddDataValidation adiciona validação de dados (dropdown, números, etc).
+// Para list, options são as opções; para os demais tipos, o mínimo e o máximo.
+func (c *ExcelizeClient) AddDataValidation(sheet, rng string, validationType string, options []string) error {
+	rule := DataValidationRule{Type: validationType}
+	if validationType == "list" {
+		rule.Options = options
+	} else if len(options) >= 2 {
+		rule.MinValue, rule.MaxValue = options[0], options[1]
+	}
+	return c.AddValidation(sheet, rng, rule)
+}
+
+// AddDropdownList adiciona uma lista dropdown a uma célula/range
+func (c *ExcelizeClient) AddDropdownList(sheet, rng string, options []string) error {
+	return c.AddDataValidation(sheet, rng, "list", options)
+}
+
+// GetValidations lista as validações da planilha; com rng, apenas as que se
+// sobrepõem ao intervalo
+func (c *ExcelizeClient) GetValidations(sheet, rng string) ([]DataValidationRule, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var targets []cellRef
+	if rng != "" {
+		var err error
+		if sheet, _, targets, err = c.rangeAreasLocked(sheet, rng); err != nil {
+			return nil, err
+		}
+	}
+
+	dvs, err := c.file.GetDataValidations(sheet)
+	if err != nil {
+		return nil, err
+	}
+	rules := []DataValidationRule{}
+	for _, dv := range dvs {
+		// Validações da extensão x14 não informam o intervalo
+		if dv.Sqref == "" {
+			continue
+		}
+		if targets == nil || intersectsAny(sqrefAreas(sheet, dv.Sqref), targets) {
+			rules = append(rules, validationRule(dv))
+		}
+	}
+	return rules, nil
+}
+
+// RemoveValidations remove a validação das células do intervalo e retorna
+// quantas validações foram alteradas
+func (c *ExcelizeClient) RemoveValidations(sheet, rng string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sheet, _, targets, err := c.rangeAreasLocked(sheet, rng)
+	if err != nil {
+		return 0, err
+	}
+	return c.clearValidationsLocked(sheet, targets)
+}
+
+// FindInvalidCells lista as células preenchidas cujo valor não atende à validação;
+// com rng, apenas as células do intervalo. Regras que o motor de cálculo não
+// consegue avaliar são ignoradas.
+func (c *ExcelizeClient) FindInvalidCells(sheet, rng string) ([]ValidationViolation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var targets []cellRef
+	if rng != "" {
+		var err error
+		if sheet, _, targets, err = c.rangeAreasLocked(sheet, rng); err != nil {
+			return nil, err
+		}
+	}
+	dvs, err := c.file.GetDataValidations(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &validationChecker{c: c, sheet: sheet, resolver: c.newRefResolverLocked()}
+	defer checker.close()
+
+	violations := []ValidationViolation{}
+	for _, dv := range dvs {
+		areas := sqrefAreas(sheet, dv.Sqref)
+		if len(areas) == 0 {
+			continue
+		}
+		rule := validationRule(dv)
+		anchor := areas[0]
+
+		for _, area := range areas {
+			parts := []cellRef{area}
+			if targets != nil {
+				parts = parts[:0]
+				for _, target := range targets {
+					if part, ok := area.overlap(target); ok {
+						parts = append(parts, part)
+					}
+				}
+			}
+			for _, part := range parts {
+				c.clampToUsedAreaLocked(&part)
+				for row := part.StartRow; row <= part.EndRow; row++ {
+					for col := part.StartCol; col <= part.EndCol; col++ {
+						cell := indicesToCell(row, col)
+						value := c.rawCellValueLocked(sheet, cell)
+						if value == "" {
+							continue
+						}
+						reason := checker.check(dv, rule, value, row-anchor.StartRow, col-anchor.StartCol)
+						if reason != "" {
+							violations = append(violations, ValidationViolation{Cell: cell, Value: value, Range: dv.Sqref, Reason: reason})
+						}
+					}
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+// dataValidationLocked converte a regra na validação do Excelize (sem o intervalo)
+func (c *ExcelizeClient) dataValidationLocked(sheet string, rule DataValidationRule) (*excelize.DataValidation, error) {
+	if !validationTypes[rule.Type] {
+		return nil, fmt.Errorf("unsupported validation type: %s", rule.Type)
+	}
+	dv := excelize.NewDataValidation(rule.AllowBlank == nil || *rule.AllowBlank)
+	dv.Type = rule.Type
+
+	switch rule.Type {
+	case "list":
+		switch {
+		case rule.Source != "":
+			source, err := c.listSourceLocked(sheet, rule.Source)
+			if err != nil {
+				return nil, err
+			}
+			dv.SetSqrefDropList(validationFormulaEscaper.Replace(source))
+		case len(rule.Options) > 0:
+			if err := dv.SetDropList(rule.Options); err != nil {
+				return nil, fmt.Errorf("list options exceed 255 characters, use a source range instead: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("list validation requires options or source")
+		}
+
+	case "custom":
+		formula := strings.TrimPrefix(strings.TrimSpace(rule.Formula), "=")
+		if formula == "" {
+			return nil, fmt.Errorf("custom validation requires a formula")
+		}
+		dv.Formula1 = validationFormulaEscaper.Replace(formula)
+
+	default:
+		operator := rule.Operator
+		if operator == "" {
+			operator = "between"
+		}
+		if _, ok := validationOperators[operator]; !ok {
+			return nil, fmt.Errorf("unsupported validation operator: %s", operator)
+		}
+		dv.Operator = operator
+		if operator == "between" || operator == "notBetween" {
+			if rule.MinValue == "" || rule.MaxValue == "" {
+				return nil, fmt.Errorf("operator %s requires minValue and maxValue", operator)
+			}
+			dv.Formula1 = validationFormulaEscaper.Replace(validationOperand(rule.Type, rule.MinValue))
+			dv.Formula2 = validationFormulaEscaper.Replace(validationOperand(rule.Type, rule.MaxValue))
+		} else {
+			if rule.Value == "" {
+				return nil, fmt.Errorf("operator %s requires value", operator)
+			}
+			dv.Formula1 = validationFormulaEscaper.Replace(validationOperand(rule.Type, rule.Value))
+		}
+	}
+
+	if rule.InputTitle != "" || rule.InputMessage != "" {
+		dv.SetInput(rule.InputTitle, rule.InputMessage)
+	}
+	style := excelize.DataValidationErrorStyleStop
+	if rule.ErrorStyle != "" {
+		var ok bool
+		if style, ok = validationErrorStyles[rule.ErrorStyle]; !ok {
+			return nil, fmt.Errorf("unsupported error style: %s", rule.ErrorStyle)
+		}
+	}
+	if rule.ErrorTitle != "" || rule.ErrorMessage != "" || rule.ErrorStyle != "" {
+		dv.SetError(style, rule.ErrorTitle, rule.ErrorMessage)
+	}
+	// Sem o alerta o Excel aceita qualquer valor digitado
+	dv.ShowErrorMessage = true
+	return dv, nil
+}
+
+// listSourceLocked referência usada como origem de uma lista: intervalo absoluto,
+// nome definido (mantido, para acompanhar alterações do nome) ou coluna de tabela
+func (c *ExcelizeClient) listSourceLocked(sheet, source string) (string, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(source), "=")
+	resolver := c.newRefResolverLocked()
+
+	ref, ok := parseCellRef(sheet, text)
+	if !ok {
+		if m := structuredRef.FindStringSubmatch(text); m != nil && m[0] == text && m[1] != "" {
+			// Referências estruturadas não são aceitas em validações
+			if ref, ok = resolver.structured(sheet, 0, 0, m[1], m[2]); !ok {
+				return "", fmt.Errorf("table not found: %s", m[1])
+			}
+		} else if _, isName := resolver.names[strings.ToLower(text)]; isName {
+			return text, nil
+		} else if _, isName := resolver.names[strings.ToLower(sheet+"!"+text)]; isName {
+			return text, nil
+		} else if t, isTable := resolver.table(text); isTable {
+			ref = t.body()
+			ref.EndCol = ref.StartCol
+		} else {
+			return "", fmt.Errorf("invalid list source: %s", source)
+		}
+	}
+
+	if ref.StartRow != ref.EndRow && ref.StartCol != ref.EndCol {
+		return "", fmt.Errorf("list source must be a single row or column: %s", source)
+	}
+	return absoluteRef(ref.Sheet, ref.StartCol+1, ref.StartRow+1, ref.EndCol+1, ref.EndRow+1), nil
+}
+
+// clearValidationsLocked remove a validação das células em targets, mantendo as
+// demais células de cada validação, e retorna quantas validações foram alteradas
+func (c *ExcelizeClient) clearValidationsLocked(sheet string, targets []cellRef) (int, error) {
+	dvs, err := c.file.GetDataValidations(sheet)
+	if err != nil {
+		return 0, err
+	}
+
+	var kept []*excelize.DataValidation
+	changed := 0
+	for _, dv := range dvs {
+		// DeleteDataValidation sem intervalo preserva as validações da extensão x14
+		if dv.Sqref == "" {
+			continue
+		}
+		areas := sqrefAreas(sheet, dv.Sqref)
+		if !intersectsAny(areas, targets) {
+			kept = append(kept, dv)
+			continue
+		}
+		changed++
+
+		rest := subtractAll(areas, targets)
+		if len(rest) == 0 {
+			continue
+		}
+
+		// Referências relativas das fórmulas partem da primeira célula do intervalo
+		dRow, dCol := rest[0].StartRow-areas[0].StartRow, rest[0].StartCol-areas[0].StartCol
+		dv.Formula1 = shiftFormula(dv.Formula1, dRow, dCol)
+		dv.Formula2 = shiftFormula(dv.Formula2, dRow, dCol)
+		dv.Sqref = sqrefText(rest)
+		kept = append(kept, dv)
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+
+	// A remoção por células do Excelize percorre célula a célula, o que é inviável
+	// em colunas inteiras; as validações restantes são gravadas novamente
+	if err := c.file.DeleteDataValidation(sheet); err != nil {
+		return 0, err
+	}
+	for _, dv := range kept {
+		dv.Formula1, dv.Formula2 = validationFormulaXML(dv.Formula1), validationFormulaXML(dv.Formula2)
+		if err := c.file.AddDataValidation(sheet, dv); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// validationFormulaXML escapa novamente uma fórmula lida por GetDataValidations,
+// que também desfaz as aspas duplicadas de listas fixas
+func validationFormulaXML(formula string) string {
+	if len(formula) >= 2 && strings.HasPrefix(formula, `"`) && strings.HasSuffix(formula, `"`) {
+		formula = `"` + strings.ReplaceAll(formula[1:len(formula)-1], `"`, `""`) + `"`
+	}
+	return validationFormulaEscaper.Replace(formula)
+}
+
+// validationRule converte a validação do Excelize na regra exposta ao agente
+func validationRule(dv *excelize.DataValidation) DataValidationRule {
+	allowBlank := dv.AllowBlank
+	rule := DataValidationRule{Type: dv.Type, Range: dv.Sqref, AllowBlank: &allowBlank}
+
+	switch dv.Type {
+	case "list":
+		if len(dv.Formula1) >= 2 && strings.HasPrefix(dv.Formula1, `"`) {
+			rule.Options = strings.Split(dv.Formula1[1:len(dv.Formula1)-1], ",")
+		} else {
+			rule.Source = dv.Formula1
+		}
+	case "custom":
+		rule.Formula = dv.Formula1
+	default:
+		rule.Operator = dv.Operator
+		if rule.Operator == "" {
+			rule.Operator = "between"
+		}
+		if rule.Operator == "between" || rule.Operator == "notBetween" {
+			rule.MinValue = validationOperandText(dv.Type, dv.Formula1)
+			rule.MaxValue = validationOperandText(dv.Type, dv.Formula2)
+		} else {
+			rule.Value = validationOperandText(dv.Type, dv.Formula1)
+		}
+	}
+
+	if dv.ShowInputMessage {
+		rule.InputTitle, rule.InputMessage = derefString(dv.PromptTitle), derefString(dv.Prompt)
+	}
+	if dv.ShowErrorMessage {
+		rule.ErrorTitle, rule.ErrorMessage = derefString(dv.ErrorTitle), derefString(dv.Error)
+		rule.ErrorStyle = derefString(dv.ErrorStyle)
+	}
+	return rule
+}
+
+// dateSerial converte uma data em texto (2024-01-31, 31/01/2024) no número de série
+func dateSerial(value string) (float64, bool) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006", "2006-01-02 15:04", "2006-01-02T15:04:05", "02/01/2006 15:04"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t.Sub(excelEpoch).Hours() / 24, true
+		}
+	}
+	return 0, false
+}
+
+// validationOperand converte datas (2024-01-31, 31/01/2024) e horas (14:30) em
+// números de série; números e fórmulas são mantidos
+func validationOperand(validationType, value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "=") {
+		return value[1:]
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	switch validationType {
+	case "date":
+		if serial, ok := dateSerial(value); ok {
+			return strconv.FormatFloat(serial, 'f', -1, 64)
+		}
+	case "time":
+		for _, layout := range []string{"15:04", "15:04:05"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				seconds := t.Hour()*3600 + t.Minute()*60 + t.Second()
+				return strconv.FormatFloat(float64(seconds)/86400, 'f', -1, 64)
+			}
+		}
+	}
+	return value
+}
+
+// validationOperandText operando legível: números de série viram data ou hora
+func validationOperandText(validationType, formula string) string {
+	serial, err := strconv.ParseFloat(formula, 64)
+	if err != nil || serial < 0 {
+		return formula
+	}
+	switch validationType {
+	case "date":
+		t := excelEpoch.Add(time.Duration(math.Round(serial*86400)) * time.Second)
+		if serial == math.Trunc(serial) {
+			return t.Format("2006-01-02")
+		}
+		return t.Format("2006-01-02 15:04")
+	case "time":
+		t := excelEpoch.Add(time.Duration(math.Round(serial*86400)) * time.Second)
+		if t.Second() != 0 {
+			return t.Format("15:04:05")
+		}
+		return t.Format("15:04")
+	}
+	return formula
+}
+
+// derefString valor de um ponteiro opcional
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// validationChecker verifica valores contra as validações de uma planilha. Fórmulas
+// são avaliadas em uma planilha temporária, removida por close.
+type validationChecker struct {
+	c        *ExcelizeClient
+	sheet    string
+	resolver *refResolver
+	lists    map[*excelize.DataValidation]map[string]bool
+	scratch  string
+	active   int
+}
+
+// check retorna o motivo da violação ou "" quando o valor é válido ou a regra não
+// pode ser avaliada. dRow e dCol são a distância até a primeira célula da validação.
+func (v *validationChecker) check(dv *excelize.DataValidation, rule DataValidationRule, value string, dRow, dCol int) string {
+	switch dv.Type {
+	case "list":
+		items := v.listItems(dv)
+		if items == nil || items[strings.ToLower(value)] {
+			return ""
+		}
+		if n, err := strconv.ParseFloat(value, 64); err == nil && items[strconv.FormatFloat(n, 'f', -1, 64)] {
+			return ""
+		}
+		return "valor fora da lista"
+
+	case "custom":
+		result, err := v.eval(dv.Formula1, dRow, dCol)
+		if err != nil || result == "TRUE" {
+			return ""
+		}
+		if n, err := strconv.ParseFloat(result, 64); err == nil && n != 0 {
+			return ""
+		}
+		return "não atende à fórmula " + rule.Formula
+	}
+
+	var n float64
+	subject := "valor"
+	if dv.Type == "textLength" {
+		n, subject = float64(utf8.RuneCountInString(value)), "comprimento"
+	} else {
+		var err error
+		if n, err = strconv.ParseFloat(value, 64); err != nil {
+			switch dv.Type {
+			case "date":
+				return "não é uma data"
+			case "time":
+				return "não é uma hora"
+			}
+			return "não é um número"
+		}
+		if dv.Type == "whole" && n != math.Trunc(n) {
+			return "não é um número inteiro"
+		}
+	}
+
+	operator := rule.Operator
+	low, ok := v.operand(dv.Formula1, dRow, dCol)
+	if !ok {
+		return ""
+	}
+	var valid bool
+	switch operator {
+	case "between", "notBetween":
+		high, ok := v.operand(dv.Formula2, dRow, dCol)
+		if !ok {
+			return ""
+		}
+		valid = (n >= low && n <= high) == (operator == "between")
+		return violationReason(valid, subject, operator, rule.MinValue, rule.MaxValue)
+	case "equal":
+		valid = n == low
+	case "notEqual":
+		valid = n != low
+	case "greaterThan":
+		valid = n > low
+	case "greaterThanOrEqual":
+		valid = n >= low
+	case "lessThan":
+		valid = n < low
+	case "lessThanOrEqual":
+		valid = n <= low
+	default:
+		valid = true
+	}
+	return violationReason(valid, subject, operator, rule.Value)
+}
+
+// violationReason descreve a condição descumprida ("valor deve ser maior que 10")
+func violationReason(valid bool, subject, operator string, operands ...any) string {
+	if valid {
+		return ""
+	}
+	return subject + " deve ser " + fmt.Sprintf(validationOperators[operator], operands...)
+}
+
+// operand valor numérico de um limite da validação (número ou fórmula)
+func (v *validationChecker) operand(formula string, dRow, dCol int) (float64, bool) {
+	if n, err := strconv.ParseFloat(formula, 64); err == nil {
+		return n, true
+	}
+	result, err := v.eval(formula, dRow, dCol)
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(result, 64)
+	return n, err == nil
+}
+
+// listItems opções aceitas por uma lista, em minúsculas; nil quando a origem não
+// pode ser resolvida (ex: INDIRECT)
+func (v *validationChecker) listItems(dv *excelize.DataValidation) map[string]bool {
+	if items, ok := v.lists[dv]; ok {
+		return items
+	}
+	var items map[string]bool
+	add := func(item string) {
+		items[strings.ToLower(strings.TrimSpace(item))] = true
+		if n, err := strconv.ParseFloat(item, 64); err == nil {
+			items[strconv.FormatFloat(n, 'f', -1, 64)] = true
+		}
+	}
+
+	if strings.HasPrefix(dv.Formula1, `"`) {
+		items = make(map[string]bool)
+		for _, item := range strings.Split(strings.Trim(dv.Formula1, `"`), ",") {
+			add(item)
+		}
+	} else if refs := v.resolver.resolve(v.sheet, dv.Formula1); len(refs) > 0 {
+		items = make(map[string]bool)
+		for _, ref := range refs {
+			v.c.clampToUsedAreaLocked(&ref)
+			for row := ref.StartRow; row <= ref.EndRow; row++ {
+				for col := ref.StartCol; col <= ref.EndCol; col++ {
+					add(v.c.rawCellValueLocked(ref.Sheet, indicesToCell(row, col)))
+				}
+			}
+		}
+	}
+
+	if v.lists == nil {
+		v.lists = make(map[*excelize.DataValidation]map[string]bool)
+	}
+	v.lists[dv] = items
+	return items
+}
+
+// eval avalia uma fórmula da validação para a célula a (dRow, dCol) da primeira
+func (v *validationChecker) eval(formula string, dRow, dCol int) (string, error) {
+	if v.scratch == "" {
+		name := "_validacao"
+		for i := 2; slices.Contains(v.c.file.GetSheetList(), name); i++ {
+			name = fmt.Sprintf("_validacao%d", i)
+		}
+		v.active = v.c.file.GetActiveSheetIndex()
+		if _, err := v.c.file.NewSheet(name); err != nil {
+			return "", err
+		}
+		v.scratch = name
+	}
+
+	// Referências sem planilha apontam para a planilha da validação
+	qualified := rewriteFormulaRefs(formula, func(sheet, ref string) string {
+		shifted := shiftRef(ref, dRow, dCol)
+		if shifted == "#REF!" {
+			return shifted
+		}
+		if sheet == "" {
+			sheet = v.sheet
+		}
+		return quoteSheetName(sheet) + "!" + shifted
+	})
+	if err := v.c.file.SetCellFormula(v.scratch, "A1", qualified); err != nil {
+		return "", err
+	}
+	return v.c.file.CalcCellValue(v.scratch, "A1", excelize.Options{RawCellValue: true})
+}
+
+// close remove a planilha temporária e restaura a planilha ativa
+func (v *validationChecker) close() {
+	if v.scratch == "" {
+		return
+	}
+	_ = v.c.file.DeleteSheet(v.scratch)
+	v.c.file.SetActiveSheet(v.active)
+}
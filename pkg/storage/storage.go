@@ -64,6 +64,7 @@ type Config struct {
 	MaxRowsPreview  int    `json:"maxRowsPreview"`  // Máximo de linhas no preview
 	IncludeHeaders  bool   `json:"includeHeaders"`  // Incluir cabeçalhos no contexto
 	AskBeforeApply  bool   `json:"askBeforeApply"`  // Modo Seguro vs YOLO
+	StampProvenance bool   `json:"stampProvenance"` // Registrar conversa, modelo e data nas propriedades ao salvar
 	DetailLevel     string `json:"detailLevel"`     // "minimal", "normal", "detailed"
 	CustomPrompt    string `json:"customPrompt"`    // Prompt personalizado adicional
 	Language        string `json:"language"`        // Idioma das respostas
@@ -350,6 +351,7 @@ func (s *Storage) LoadConfig() (*Config, error) {
 				MaxRowsPreview:  100,
 				IncludeHeaders:  true,
 				AskBeforeApply:  true, // Modo seguro por padrão
+				StampProvenance: true,
 				DetailLevel:     "normal",
 				Language:        "pt-BR",
 			}, nil